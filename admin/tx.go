@@ -18,6 +18,8 @@ import (
 	"github.com/bnb-chain/node/plugins/tokens/seturi"
 	"github.com/bnb-chain/node/plugins/tokens/swap"
 	"github.com/bnb-chain/node/plugins/tokens/timelock"
+	"github.com/bnb-chain/node/plugins/tokens/transferfee"
+	"github.com/bnb-chain/node/plugins/tokens/transfermemo"
 )
 
 var transferOnlyModeBlackList = []string{
@@ -36,7 +38,12 @@ var transferOnlyModeBlackList = []string{
 	seturi.SetURIMsg{}.Type(),
 	list.ListMsg{}.Type(),
 	list.ListMiniMsg{}.Type(),
+	list.AddFeeExemptionMsg{}.Type(),
+	list.RemoveFeeExemptionMsg{}.Type(),
+	list.SetTokenTradingMsg{}.Type(),
 	ownership.TransferOwnershipMsg{}.Type(),
+	transfermemo.SetTransferMemoRequiredMsg{}.Type(),
+	transferfee.SetTransferFeeMsg{}.Type(),
 	swap.HTLTMsg{}.Type(),
 	swap.DepositHTLTMsg{}.Type(),
 	swap.ClaimHTLTMsg{}.Type(),
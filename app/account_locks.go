@@ -0,0 +1,51 @@
+package app
+
+import (
+	"encoding/hex"
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/tokens/swap"
+)
+
+// getAccountLocks returns the locks of the coins of the account by the reason: the frozen coins, the coins
+// reserved by each open order, each time lock, and each open atomic swap created by the account. The coins of
+// the time locks and swaps are held by their module accounts, while the others stay in the account.
+func (app *BinanceChain) getAccountLocks(ctx sdk.Context, addr sdk.AccAddress) []types.AccountLock {
+	locks := make([]types.AccountLock, 0)
+	if acc, ok := app.AccountKeeper.GetAccount(ctx, addr).(types.NamedAccount); ok && !acc.GetFrozenCoins().IsZero() {
+		locks = append(locks, types.AccountLock{
+			Reason: types.LockReasonFrozen,
+			Amount: acc.GetFrozenCoins(),
+		})
+	}
+
+	locks = append(locks, app.DexKeeper.GetOrderLocks(addr)...)
+
+	for _, record := range app.timeLockKeeper.GetTimeLockRecords(ctx, addr) {
+		locks = append(locks, types.AccountLock{
+			Reason:     types.LockReasonTimeLock,
+			Ref:        strconv.FormatInt(record.Id, 10),
+			Amount:     record.Amount,
+			UnlockTime: record.LockTime.Unix(),
+		})
+	}
+
+	iterator := app.swapKeeper.GetSwapCreatorIterator(ctx, addr)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		atomicSwap := app.swapKeeper.GetSwap(ctx, iterator.Value())
+		if atomicSwap == nil || atomicSwap.Status != swap.Open {
+			continue
+		}
+		locks = append(locks, types.AccountLock{
+			Reason:       types.LockReasonHTLC,
+			Ref:          hex.EncodeToString(iterator.Value()),
+			Amount:       atomicSwap.OutAmount,
+			UnlockHeight: atomicSwap.ExpireHeight,
+		})
+	}
+	return locks
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
+	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
+	"github.com/bnb-chain/node/plugins/tokens/swap"
+)
+
+func TestAccountLocks(t *testing.T) {
+	// the begin blockers of the upgrades registered by the app are not to be run for the apps of the other tests
+	beginBlockers := make(map[int64][]func(sdk.Context), len(upgrade.Mgr.Config.BeginBlockers))
+	for height, blockers := range upgrade.Mgr.Config.BeginBlockers {
+		beginBlockers[height] = blockers[:len(blockers):len(blockers)]
+	}
+	defer func() { upgrade.Mgr.Config.BeginBlockers = beginBlockers }()
+
+	_, require, app, buyerAcc, sellerAcc := setupAppTest(t)
+	ctx := app.DeliverState.Ctx
+	addr := buyerAcc.GetAddress()
+	require.Empty(app.getAccountLocks(ctx, addr))
+
+	acc := buyerAcc.(types.NamedAccount)
+	acc.SetFrozenCoins(sdk.Coins{sdk.NewCoin("XYZ-000", 500)})
+	app.AccountKeeper.SetAccount(ctx, acc)
+
+	buy := orderPkg.NewNewOrderMsg(addr, "1", orderPkg.Side.BUY, "XYZ-000_BNB", 102000, 3000000)
+	require.NoError(app.DexKeeper.AddOrder(orderPkg.OrderInfo{buy, 42, 0, 42, 0, 0, "", 0}, false))
+	sell := orderPkg.NewNewOrderMsg(addr, "2", orderPkg.Side.SELL, "ZCB-000_BNB", 102000, 2000000)
+	require.NoError(app.DexKeeper.AddOrder(orderPkg.OrderInfo{sell, 42, 0, 42, 0, 1000000, "", 0}, false))
+	other := orderPkg.NewNewOrderMsg(sellerAcc.GetAddress(), "3", orderPkg.Side.SELL, "XYZ-000_BNB", 102000, 1000000)
+	require.NoError(app.DexKeeper.AddOrder(orderPkg.OrderInfo{other, 42, 0, 42, 0, 0, "", 0}, false))
+
+	lockTime := time.Unix(2000000000, 0)
+	record, err := app.timeLockKeeper.TimeLock(ctx, addr, "vesting", sdk.Coins{sdk.NewCoin("BNB", 1000)}, lockTime)
+	require.NoError(err)
+
+	openSwapID := swap.SwapBytes([]byte("open swap"))
+	require.NoError(app.swapKeeper.CreateSwap(ctx, openSwapID, &swap.AtomicSwap{
+		From:         addr,
+		To:           sellerAcc.GetAddress(),
+		OutAmount:    sdk.Coins{sdk.NewCoin("BNB", 2000)},
+		ExpireHeight: 1042,
+		Index:        0,
+		Status:       swap.Open,
+	}))
+	require.NoError(app.swapKeeper.CreateSwap(ctx, swap.SwapBytes([]byte("completed swap")), &swap.AtomicSwap{
+		From:         addr,
+		To:           sellerAcc.GetAddress(),
+		OutAmount:    sdk.Coins{sdk.NewCoin("BNB", 3000)},
+		ExpireHeight: 1042,
+		Index:        1,
+		Status:       swap.Completed,
+	}))
+
+	require.Equal([]types.AccountLock{
+		{Reason: types.LockReasonFrozen, Amount: sdk.Coins{sdk.NewCoin("XYZ-000", 500)}},
+		{Reason: types.LockReasonOrder, Ref: "1", Amount: sdk.Coins{sdk.NewCoin("BNB", 3060)}},
+		{Reason: types.LockReasonOrder, Ref: "2", Amount: sdk.Coins{sdk.NewCoin("ZCB-000", 1000000)}},
+		{Reason: types.LockReasonTimeLock, Ref: "0", Amount: record.Amount, UnlockTime: lockTime.Unix()},
+		{Reason: types.LockReasonHTLC, Ref: hex.EncodeToString(openSwapID), Amount: sdk.Coins{sdk.NewCoin("BNB", 2000)}, UnlockHeight: 1042},
+	}, app.getAccountLocks(ctx, addr))
+
+	// the locks of the other account
+	require.Equal([]types.AccountLock{
+		{Reason: types.LockReasonOrder, Ref: "3", Amount: sdk.Coins{sdk.NewCoin("XYZ-000", 1000000)}},
+	}, app.getAccountLocks(ctx, sellerAcc.GetAddress()))
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"compress/gzip"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -42,8 +43,11 @@ import (
 	"github.com/bnb-chain/node/app/pub"
 	appsub "github.com/bnb-chain/node/app/pub/sub"
 	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/audit"
+	bncfees "github.com/bnb-chain/node/common/fees"
 	"github.com/bnb-chain/node/common/runtime"
 	"github.com/bnb-chain/node/common/tx"
+	"github.com/bnb-chain/node/common/txstatus"
 	"github.com/bnb-chain/node/common/types"
 	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/common/utils"
@@ -60,6 +64,8 @@ import (
 	"github.com/bnb-chain/node/plugins/tokens/seturi"
 	"github.com/bnb-chain/node/plugins/tokens/swap"
 	"github.com/bnb-chain/node/plugins/tokens/timelock"
+	"github.com/bnb-chain/node/plugins/tokens/transferfee"
+	"github.com/bnb-chain/node/plugins/tokens/transfermemo"
 	"github.com/bnb-chain/node/wire"
 	cStake "github.com/cosmos/cosmos-sdk/x/stake/cross_stake"
 )
@@ -106,6 +112,7 @@ type BinanceChain struct {
 	bridgeKeeper   bridge.Keeper
 	ibcKeeper      ibc.Keeper
 	scKeeper       sidechain.Keeper
+	feeSplitKeeper bncfees.SplitKeeper
 	// keeper to process param store and update
 	ParamHub *param.Keeper
 
@@ -113,6 +120,7 @@ type BinanceChain struct {
 	upgradeConfig      *config.UpgradeConfig
 	crossChainConfig   *config.CrossChainConfig
 	abciQueryBlackList map[string]bool
+	logQueryPanics     bool
 	publicationConfig  *config.PublicationConfig
 	publisher          pub.MarketDataPublisher
 	psServer           *pubsub.Server
@@ -126,6 +134,10 @@ type BinanceChain struct {
 	metrics *pub.Metrics
 
 	takeSnapshotHeight int64 // whether to take snapshot of current height, set at endblock(), reset at commit()
+
+	traceWriter *gzip.Writer // set if the store traces are compressed, flushed at commit()
+
+	chainStats chainStatsCache
 }
 
 // NewBinanceChain creates a new instance of the BinanceChain.
@@ -144,12 +156,21 @@ func NewBinanceChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseApp
 		upgradeConfig:      ServerContext.UpgradeConfig,
 		crossChainConfig:   ServerContext.CrossChainConfig,
 		abciQueryBlackList: getABCIQueryBlackList(ServerContext.QueryConfig),
+		logQueryPanics:     ServerContext.QueryConfig.LogQueryPanics,
 		publicationConfig:  ServerContext.PublicationConfig,
 		dexConfig:          ServerContext.DexConfig,
 	}
 	// set upgrade config
 	SetUpgradeConfig(app.upgradeConfig)
 	app.initRunningMode()
+	if traceStore != nil && app.baseConfig.CompressTraceStore {
+		traceWriter, err := gzip.NewWriterLevel(traceStore, app.baseConfig.TraceStoreCompressionLevel)
+		if err != nil {
+			panic(fmt.Errorf("invalid trace store compression level: %v", err))
+		}
+		app.traceWriter = traceWriter
+		traceStore = traceWriter
+	}
 	app.SetCommitMultiStoreTracer(traceStore)
 
 	// mappers
@@ -159,6 +180,8 @@ func NewBinanceChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseApp
 	app.ParamHub = param.NewKeeper(cdc, common.ParamsStoreKey, common.TParamsStoreKey)
 	app.scKeeper = sidechain.NewKeeper(common.SideChainStoreKey, app.ParamHub.Subspace(sidechain.DefaultParamspace), app.Codec)
 	app.ibcKeeper = ibc.NewKeeper(common.IbcStoreKey, app.ParamHub.Subspace(ibc.DefaultParamspace), app.RegisterCodespace(ibc.DefaultCodespace), app.scKeeper)
+	app.feeSplitKeeper = bncfees.NewSplitKeeper(app.ParamHub.Subspace(bncfees.SplitParamspace))
+	app.feeSplitKeeper.SubscribeParamChange(app.ParamHub)
 
 	app.slashKeeper = slashing.NewKeeper(
 		cdc,
@@ -203,6 +226,9 @@ func NewBinanceChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseApp
 	if app.publicationConfig.ShouldPublishAny() {
 		pub.Logger = logger.With("module", "pub")
 		pub.Cfg = app.publicationConfig
+		if err := pub.SetQtyDisplayPrecisions(app.publicationConfig.QtyDisplayPrecisions); err != nil {
+			panic(err)
+		}
 		pub.ToPublishCh = make(chan pub.BlockInfoToPublish, app.publicationConfig.PublicationChannelSize)
 		pub.ToPublishEventCh = make(chan *appsub.ToPublishEvent, app.publicationConfig.PublicationChannelSize)
 
@@ -255,7 +281,6 @@ func NewBinanceChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseApp
 		common.OracleStoreKey,
 		common.IbcStoreKey,
 	)
-	app.SetAnteHandler(tx.NewAnteHandler(app.AccountKeeper))
 	app.SetPreChecker(tx.NewTxPreChecker())
 	app.MountStoresTransient(common.TParamsStoreKey, common.TStakeStoreKey)
 
@@ -270,6 +295,9 @@ func NewBinanceChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseApp
 	app.SetAccountStoreCache(cdc, accountStore, app.baseConfig.AccountCacheSize)
 
 	tx.InitSigCache(app.baseConfig.SignatureCacheSize)
+	if ServerContext.LogConfig.AuditRejectedTxs {
+		audit.Rejections.SetWriter(newAuditLogWriter(ServerContext))
+	}
 
 	err = app.InitFromStore(common.MainStoreKey)
 	if err != nil {
@@ -338,6 +366,13 @@ func SetUpgradeConfig(upgradeConfig *config.UpgradeConfig) {
 	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP159Phase2, upgradeConfig.BEP159Phase2Height)
 	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP173, upgradeConfig.BEP173Height)
 	upgrade.Mgr.AddUpgradeHeight(upgrade.FixDoubleSignChainId, upgradeConfig.FixDoubleSignChainIdHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.OrderIDVersioning, upgradeConfig.OrderIDVersioningHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.TokenTransferMemo, upgradeConfig.TokenTransferMemoHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.OrderParamCodes, upgradeConfig.OrderParamCodesHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.TokenTransferFee, upgradeConfig.TokenTransferFeeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.OrderUnknownPairCode, upgradeConfig.OrderUnknownPairCodeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.OrderMemo, upgradeConfig.OrderMemoHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.GovernedParams, upgradeConfig.GovernedParamsHeight)
 
 	// register store keys of upgrade
 	upgrade.Mgr.RegisterStoreKeys(upgrade.BEP9, common.TimeLockStoreKey.Name())
@@ -391,6 +426,13 @@ func SetUpgradeConfig(upgradeConfig *config.UpgradeConfig) {
 	)
 
 	upgrade.Mgr.RegisterMsgTypes(upgrade.BEP82, ownership.TransferOwnershipMsg{}.Type())
+	upgrade.Mgr.RegisterMsgTypes(upgrade.TokenTransferMemo, transfermemo.SetTransferMemoRequiredMsg{}.Type())
+	upgrade.Mgr.RegisterMsgTypes(upgrade.TokenTransferFee, transferfee.SetTransferFeeMsg{}.Type())
+	upgrade.Mgr.RegisterMsgTypes(upgrade.GovernedParams,
+		dextypes.AddFeeExemptionMsg{}.Type(),
+		dextypes.RemoveFeeExemptionMsg{}.Type(),
+		dextypes.SetTokenTradingMsg{}.Type(),
+	)
 }
 
 func getABCIQueryBlackList(queryConfig *config.QueryConfig) map[string]bool {
@@ -415,6 +457,23 @@ func (app *BinanceChain) initDex() {
 		app.publicationConfig.ShouldPublishAny())
 	app.DexKeeper.SubscribeParamChange(app.ParamHub)
 	app.DexKeeper.SetBUSDSymbol(app.dexConfig.BUSDSymbol)
+	app.DexKeeper.SetLogUnknownPairOrders(app.dexConfig.LogUnknownPairOrders)
+	app.DexKeeper.SetOrderFillsCacheSize(ServerContext.QueryConfig.OrderFillsCacheSize)
+	app.DexKeeper.SetAccountTradesCacheSize(ServerContext.QueryConfig.AccountTradesCacheSize)
+	app.DexKeeper.SetRecentCancelsCacheSize(ServerContext.QueryConfig.RecentCancelsCacheSize)
+	app.DexKeeper.SetOrderBookHistoryRetention(ServerContext.QueryConfig.OrderBookHistoryRetention)
+	app.DexKeeper.SetMaxOrderBookDepth(ServerContext.QueryConfig.MaxOrderBookDepth)
+	app.DexKeeper.SetTokenMapper(app.TokenMapper)
+	app.SetAnteHandler(tx.NewAnteHandler(app.AccountKeeper, app.DexKeeper.IsFeeExempt))
+	if app.publicationConfig.PublishOrderRejections {
+		app.DexKeeper.EnableRejectionPublish()
+	}
+	if app.publicationConfig.PublishOrderAcks {
+		app.DexKeeper.EnableAckPublish()
+	}
+	if ServerContext.QueryConfig.ImbalanceTrendEnabled {
+		app.DexKeeper.EnableImbalanceTrends()
+	}
 
 	// do not proceed if we are in a unit test and `CheckState` is unset.
 	if app.CheckState == nil {
@@ -435,7 +494,25 @@ func (app *BinanceChain) initDex() {
 		stateDB,
 		app.LastBlockHeight(),
 		app.TxDecoder)
+	app.checkOrderBookIntegrity()
+}
 
+// checkOrderBookIntegrity verifies the locked coins of the accounts against the recovered open orders if configured,
+// so that a corrupted state is caught on start rather than by the failures of the later fills and cancels. The
+// mismatches are only logged for the operators to look into, the node keeps running.
+func (app *BinanceChain) checkOrderBookIntegrity() {
+	if !app.baseConfig.OrderBookIntegrityCheck {
+		return
+	}
+	mismatches := app.DexKeeper.VerifyLockedCoins(app.CheckState.Ctx)
+	if len(mismatches) == 0 {
+		app.Logger.Info("verified the locked coins of the open orders")
+		return
+	}
+	for _, m := range mismatches {
+		app.Logger.Error("locked coins mismatch the open orders", "address", m.Address,
+			"reserved", m.Reserved, "locked", m.Locked)
+	}
 }
 
 func (app *BinanceChain) initPlugins() {
@@ -450,7 +527,12 @@ func (app *BinanceChain) initPlugins() {
 	app.initParamHub()
 	app.initBridge()
 	tokens.InitPlugin(app, app.TokenMapper, app.AccountKeeper, app.CoinKeeper, app.timeLockKeeper, app.swapKeeper)
-	dex.InitPlugin(app, app.DexKeeper, app.TokenMapper, app.govKeeper)
+	tokens.SubscribeParamChange(app.ParamHub, app.TokenMapper)
+	symbolAliases, err := dex.NewSymbolAliases(app.dexConfig.QuerySymbolAlias, app.dexConfig.QuerySymbolAliases)
+	if err != nil {
+		cmn.Exit(err.Error())
+	}
+	dex.InitPlugin(app, app.DexKeeper, app.TokenMapper, app.govKeeper, symbolAliases)
 	account.InitPlugin(app, app.AccountKeeper)
 	bridge.InitPlugin(app, app.bridgeKeeper)
 	app.initParams()
@@ -458,7 +540,7 @@ func (app *BinanceChain) initPlugins() {
 	// add handlers from bnc-cosmos-sdk (others moved to plugin init funcs)
 	// we need to add handlers after all keepers initialized
 	app.Router().
-		AddRoute("bank", bank.NewHandler(app.CoinKeeper)).
+		AddRoute("bank", transferfee.NewBankHandler(app.CoinKeeper, app.TokenMapper)).
 		AddRoute("stake", stake.NewHandler(app.stakeKeeper, app.govKeeper)).
 		AddRoute("slashing", slashing.NewHandler(app.slashKeeper)).
 		AddRoute("gov", gov.NewHandler(app.govKeeper)).
@@ -473,7 +555,14 @@ func (app *BinanceChain) initPlugins() {
 	app.QueryRouter().AddRoute("sideChain", sidechain.NewQuerier(app.scKeeper))
 
 	app.RegisterQueryHandler("account", app.AccountHandler)
+	app.RegisterQueryHandler(chainStatsAbciQueryPrefix, app.StatsHandler)
 	app.RegisterQueryHandler("admin", admin.GetHandler(ServerContext.Config))
+	app.RegisterQueryHandler(paramsAbciQueryPrefix, app.ParamsHandler)
+	bncfees.Tracker.SetMaxAccounts(ServerContext.QueryConfig.FeesByAccountLimit)
+	app.RegisterQueryHandler(bncfees.AbciQueryPrefix, bncfees.CreateAbciQueryHandler(bncfees.Tracker, app.CoinKeeper))
+	txstatus.Tracker.SetLookbackBlocks(ServerContext.QueryConfig.TxStatusLookbackBlocks)
+	app.RegisterQueryHandler(txstatus.AbciQueryPrefix, txstatus.CreateAbciQueryHandler(txstatus.Tracker))
+	app.RegisterQueryHandler(pub.AbciQueryPrefix, pub.CreateAbciQueryHandler(app.publicationConfig.ShouldPublishAny()))
 
 }
 
@@ -676,12 +765,16 @@ func (app *BinanceChain) initGovHooks() {
 	scParamChangeHooks := paramHub.NewSCParamsChangeHook(app.Codec)
 	chanPermissionHooks := sidechain.NewChanPermissionSettingHook(app.Codec, &app.scKeeper)
 	delistHooks := list.NewDelistHooks(app.DexKeeper)
+	feeExemptionHooks := dex.NewFeeExemptionHooks()
+	tokenParamsChangeHooks := tokens.NewParamsChangeHooks(app.TokenMapper)
 	app.govKeeper.AddHooks(gov.ProposalTypeListTradingPair, listHooks)
 	app.govKeeper.AddHooks(gov.ProposalTypeFeeChange, feeChangeHooks)
 	app.govKeeper.AddHooks(gov.ProposalTypeCSCParamsChange, cscParamChangeHooks)
 	app.govKeeper.AddHooks(gov.ProposalTypeSCParamsChange, scParamChangeHooks)
 	app.govKeeper.AddHooks(gov.ProposalTypeDelistTradingPair, delistHooks)
 	app.govKeeper.AddHooks(gov.ProposalTypeManageChanPermission, chanPermissionHooks)
+	app.govKeeper.AddHooks(gov.ProposalTypeText, feeExemptionHooks)
+	app.govKeeper.AddHooks(gov.ProposalTypeText, tokenParamsChangeHooks)
 	bcParamChangeHooks := paramHub.NewBCParamsChangeHook(app.Codec)
 	app.govKeeper.AddHooks(gov.ProposalTypeParameterChange, bcParamChangeHooks)
 }
@@ -810,6 +903,8 @@ func (app *BinanceChain) CheckTx(req abci.RequestCheckTx) (res abci.ResponseChec
 func (app *BinanceChain) DeliverTx(req abci.RequestDeliverTx) (res abci.ResponseDeliverTx) {
 	res = app.BaseApp.DeliverTx(req)
 	txHash := cmn.HexBytes(tmhash.Sum(req.Tx)).String()
+	app.DexKeeper.CommitOrderRecords(res.IsOK())
+	bncfees.Tracker.Commit(txHash, res.IsOK())
 	if res.IsOK() {
 		// commit or panic
 		fees.Pool.CommitFee(txHash)
@@ -827,6 +922,7 @@ func (app *BinanceChain) DeliverTx(req abci.RequestDeliverTx) (res abci.Response
 	if app.publicationConfig.PublishBlock {
 		pub.Pool.AddTxRes(txHash, res)
 	}
+	txstatus.Tracker.Add(txHash, app.DeliverState.Ctx.BlockHeight(), res)
 	return res
 }
 
@@ -842,10 +938,14 @@ func (app *BinanceChain) PreDeliverTx(req abci.RequestDeliverTx) (res abci.Respo
 }
 
 func (app *BinanceChain) isBreatheBlock(height int64, lastBlockTime time.Time, blockTime time.Time) bool {
+	return isBreatheBlock(app.baseConfig.BreatheBlockInterval, height, lastBlockTime, blockTime)
+}
+
+func isBreatheBlock(breatheBlockInterval int, height int64, lastBlockTime time.Time, blockTime time.Time) bool {
 	// lastBlockTime is zero if this blockTime is for the first block (first block doesn't mean height = 1, because after
 	// state sync from breathe block, the height is breathe block + 1)
-	if app.baseConfig.BreatheBlockInterval > 0 {
-		return height%int64(app.baseConfig.BreatheBlockInterval) == 0
+	if breatheBlockInterval > 0 {
+		return height%int64(breatheBlockInterval) == 0
 	} else {
 		return !lastBlockTime.IsZero() && !utils.SameDayInUTC(lastBlockTime, blockTime)
 	}
@@ -863,6 +963,19 @@ func (app *BinanceChain) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) a
 	height := ctx.BlockHeader().Height
 	ctx = ctx.WithEventManager(sdk.NewEventManager())
 	isBreatheBlock := app.isBreatheBlock(height, lastBlockTime, blockTime)
+	// the changes passed by governance take effect before the matching
+	app.DexKeeper.UpdateScheduledHalt(ctx)
+	app.DexKeeper.UpdatePairSessions(ctx)
+	// only measured if published, so that it costs nothing otherwise
+	var blockMetrics *pub.BlockMetrics
+	if app.publicationConfig.PublishBlockMetrics && pub.IsLive {
+		blockMetrics = pub.StartBlockMetrics(app.DexKeeper.GetRoundOrdersNum())
+	}
+	convertDustFees := isBreatheBlock && sdk.IsUpgrade(upgrade.BEP159)
+	if convertDustFees {
+		// the fees accumulated for all the validators are converted by the orders matched in this block
+		app.DexKeeper.PlaceDustFeeConversions(ctx, stake.FeeForAllAccAddr)
+	}
 	var tradesToPublish []*pub.Trade
 	if sdk.IsUpgrade(upgrade.BEP19) || !isBreatheBlock {
 		if app.publicationConfig.ShouldPublishAny() && pub.IsLive {
@@ -871,6 +984,13 @@ func (app *BinanceChain) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) a
 			app.DexKeeper.MatchAndAllocateSymbols(ctx, nil, isBreatheBlock)
 		}
 	}
+	if blockMetrics != nil {
+		blockMetrics.EndMatching(len(tradesToPublish))
+	}
+	var feeConversions []pub.FeeConversion
+	if convertDustFees {
+		feeConversions = pub.CollectFeeConversionsForPublish(app.DexKeeper.FinishDustFeeConversions(ctx))
+	}
 
 	if isBreatheBlock {
 		// breathe block
@@ -886,10 +1006,22 @@ func (app *BinanceChain) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) a
 	}
 
 	app.DexKeeper.StoreTradePrices(ctx)
+	app.DexKeeper.RefreshOpenInterests(height)
+	app.DexKeeper.RefreshOrderCounts(height)
+	app.DexKeeper.RecordTWAPPrices(blockTime.Unix())
+	app.DexKeeper.RecordImbalances(height)
+	app.DexKeeper.RecordTradeCounts(height)
+	app.DexKeeper.RecordLastMatches(height)
+	app.DexKeeper.EndBookUpdatesBlock(height)
 
 	var blockFee pub.BlockFee
 	if sdk.IsUpgrade(upgrade.BEP159) {
 		blockFee = distributeFeeBEP159(ctx, app.AccountKeeper, app.ValAddrCache, app.publicationConfig.PublishBlockFee, app.stakeKeeper)
+		if isBreatheBlock {
+			// split the fees accumulated for all the validators before they are distributed by stake
+			blockFee.Splits = splitFees(ctx, app.feeSplitKeeper, app.CoinKeeper, app.TokenMapper)
+			blockFee.Conversions = feeConversions
+		}
 	} else {
 		blockFee = distributeFee(ctx, app.AccountKeeper, app.ValAddrCache, app.publicationConfig.PublishBlockFee)
 	}
@@ -919,15 +1051,21 @@ func (app *BinanceChain) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) a
 		pub.IsLive {
 		stakeUpdates := pub.CollectStakeUpdatesForPublish(completedUbd)
 		if height >= app.publicationConfig.FromHeightInclusive {
-			app.publish(tradesToPublish, &proposals, &sideProposals, &stakeUpdates, blockFee, ctx, height, blockTime.UnixNano())
+			app.publish(tradesToPublish, &proposals, &sideProposals, &stakeUpdates, blockFee, blockMetrics, ctx, height, blockTime.UnixNano(), isBreatheBlock)
 
 			appsub.SetMeta(height, blockTime, isBreatheBlock)
+			appsub.SetPairSizesUpdates(app.DexKeeper.GetPairSizesUpdates())
 			app.subscriber.Wait()
 			app.publishEvent()
 		}
 
 		// clean up intermediate cached data
 		app.DexKeeper.ClearOrderChanges()
+		app.DexKeeper.ClearOrderRejections()
+		app.DexKeeper.ClearOrderAcks()
+		app.DexKeeper.ClearPairSizesUpdates()
+		app.DexKeeper.ClearSessionEvents()
+		app.DexKeeper.ClearTokenTradingEvents()
 		app.DexKeeper.ClearRoundFee()
 
 		// clean up intermediate cached data used to be published
@@ -946,6 +1084,12 @@ func (app *BinanceChain) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) a
 
 func (app *BinanceChain) Commit() (res abci.ResponseCommit) {
 	res = app.BaseApp.Commit()
+	if app.traceWriter != nil {
+		// so that the traces of the committed block are not lost if the node crashes
+		if err := app.traceWriter.Flush(); err != nil {
+			app.Logger.Error("failed to flush the store traces", "err", err)
+		}
+	}
 	if ServerContext.Config.StateSyncReactor && app.takeSnapshotHeight > 0 {
 		app.StateSyncHelper.SnapshotHeights <- app.takeSnapshotHeight
 		app.takeSnapshotHeight = 0
@@ -992,9 +1136,19 @@ func (app *BinanceChain) ExportAppStateAndValidators() (appState json.RawMessage
 // Query performs an abci query.
 func (app *BinanceChain) Query(req abci.RequestQuery) (res abci.ResponseQuery) {
 	defer func() {
+		// the query handlers are expected to reject the malformed queries with errors, or by panicking with
+		// MalformedQueryError. Any other panic is a bug, it's always logged and answered as an internal error so
+		// that the node keeps serving the other queries.
 		if r := recover(); r != nil {
-			app.Logger.Error("internal error caused by query", "req", req, "stack", debug.Stack())
-			res = sdk.ErrInternal("internal error").QueryResult()
+			if malformed, ok := r.(types.MalformedQueryError); ok {
+				if app.logQueryPanics {
+					app.Logger.Info("malformed query", "req", req, "err", malformed)
+				}
+				res = sdk.ErrUnknownRequest(fmt.Sprintf("malformed query %s: %s", req.Path, malformed.Reason)).QueryResult()
+				return
+			}
+			app.Logger.Error("query panicked", "req", req, "err", r, "stack", string(debug.Stack()))
+			res = sdk.ErrInternal(fmt.Sprintf("failed to query %s", req.Path)).QueryResult()
 		}
 	}()
 
@@ -1044,6 +1198,67 @@ func (app *BinanceChain) AccountHandler(chainApp types.ChainApp, req abci.Reques
 		} else {
 			res = sdk.ErrInvalidAddress(addr).QueryResult()
 		}
+	} else if len(path) == 3 && path[1] == "assets" {
+		// account/assets/<address>
+		addr := path[2]
+		accAddress, err := sdk.AccAddressFromBech32(addr)
+		if err != nil {
+			res = sdk.ErrInvalidAddress(addr).QueryResult()
+			return &res
+		}
+		assets := make([]string, 0)
+		if acc := app.CheckState.AccountCache.GetAccount(accAddress); acc != nil {
+			assets = types.GetAssets(acc)
+		}
+		bz, err := Codec.MarshalBinaryLengthPrefixed(assets)
+		if err != nil {
+			res = sdk.ErrInternal(err.Error()).QueryResult()
+		} else {
+			res = abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		}
+	} else if len(path) == 3 && path[1] == "locks" {
+		// account/locks/<address>
+		addr := path[2]
+		accAddress, err := sdk.AccAddressFromBech32(addr)
+		if err != nil {
+			res = sdk.ErrInvalidAddress(addr).QueryResult()
+			return &res
+		}
+		locks := app.getAccountLocks(app.CheckState.Ctx, accAddress)
+		bz, err := Codec.MarshalBinaryLengthPrefixed(locks)
+		if err != nil {
+			res = sdk.ErrInternal(err.Error()).QueryResult()
+		} else {
+			res = abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		}
+	} else if len(path) == 3 && path[1] == "spendable" {
+		// account/spendable/<address>
+		addr := path[2]
+		accAddress, err := sdk.AccAddressFromBech32(addr)
+		if err != nil {
+			res = sdk.ErrInvalidAddress(addr).QueryResult()
+			return &res
+		}
+		spendable := types.AccountSpendable{Balances: make([]types.SpendableBalance, 0)}
+		if acc := app.CheckState.AccountCache.GetAccount(accAddress); acc != nil {
+			spendable.Found = true
+			spendable.Balances = types.GetSpendableBalances(acc)
+		}
+		bz, err := Codec.MarshalBinaryLengthPrefixed(spendable)
+		if err != nil {
+			res = sdk.ErrInternal(err.Error()).QueryResult()
+		} else {
+			res = abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		}
 	} else {
 		res = sdk.ErrUnknownRequest("invalid path").QueryResult()
 	}
@@ -1103,6 +1318,7 @@ func MakeCodec() *wire.Codec {
 	paramHub.RegisterWire(cdc)
 	dex.RegisterWire(cdc)
 	tokens.RegisterWire(cdc)
+	bncfees.RegisterWire(cdc)
 	account.RegisterWire(cdc)
 	types.RegisterWire(cdc)
 	tx.RegisterWire(cdc)
@@ -1122,18 +1338,30 @@ func (app *BinanceChain) publishEvent() {
 
 }
 
-func (app *BinanceChain) publish(tradesToPublish []*pub.Trade, proposalsToPublish *pub.Proposals, sideProposalsToPublish *pub.SideProposals, stakeUpdates *pub.StakeUpdates, blockFee pub.BlockFee, ctx sdk.Context, height, blockTime int64) {
+func (app *BinanceChain) publish(tradesToPublish []*pub.Trade, proposalsToPublish *pub.Proposals, sideProposalsToPublish *pub.SideProposals, stakeUpdates *pub.StakeUpdates, blockFee pub.BlockFee, blockMetrics *pub.BlockMetrics, ctx sdk.Context, height, blockTime int64, isBreatheBlock bool) {
 	pub.Logger.Info("start to collect publish information", "height", height)
 
 	var accountsToPublish map[string]pub.Account
 	var transferToPublish *pub.Transfers
 	var blockToPublish *pub.Block
 	var latestPriceLevels order.ChangedPriceLevelsMap
+	var bookSnapshots order.ChangedPriceLevelsMap
 
 	orderChanges := app.DexKeeper.GetAllOrderChanges()
 	orderInfoForPublish := app.DexKeeper.GetAllOrderInfosForPub()
 
+	degraded, skippedSince := pub.CheckMemoryPressure(height, app.publicationConfig.DegradeMemoryThresholdMB)
+	if degraded {
+		pub.Logger.Info("publication is degraded under memory pressure", "height", height)
+	} else if skippedSince > 0 {
+		pub.Logger.Info("full publication resumes", "height", height, "skippedSince", skippedSince)
+	}
+
 	duration := pub.Timer(app.Logger, fmt.Sprintf("collect publish information, height=%d", height), func() {
+		if degraded {
+			// only the trades and order changes are published
+			return
+		}
 		if app.publicationConfig.PublishAccountBalance {
 			txRelatedAccounts := app.Pool.TxRelatedAddrs()
 			tradeRelatedAccounts := pub.GetTradeAndOrdersRelatedAccounts(tradesToPublish, orderChanges, orderInfoForPublish)
@@ -1156,8 +1384,14 @@ func (app *BinanceChain) publish(tradesToPublish []*pub.Trade, proposalsToPublis
 		if app.publicationConfig.PublishOrderBook {
 			latestPriceLevels = app.DexKeeper.GetOrderBooks(pub.MaxOrderBookLevel)
 		}
+		if app.publicationConfig.IsOrderBookSnapshotHeight(height) {
+			bookSnapshots = app.DexKeeper.GetOrderBookSnapshots(pub.MaxOrderBookLevel, app.publicationConfig.OrderBookSnapshotDepth)
+		}
 	})
 
+	if blockMetrics != nil {
+		blockMetrics.CollectTimeMs = duration
+	}
 	if app.metrics != nil {
 		app.metrics.CollectBlockTimeMs.Set(float64(duration))
 		app.metrics.NumOrderInfoForPublish.Set(float64(len(orderInfoForPublish)))
@@ -1191,7 +1425,18 @@ func (app *BinanceChain) publish(tradesToPublish []*pub.Trade, proposalsToPublis
 		blockFee,
 		app.DexKeeper.RoundOrderFees, //only use DexKeeper RoundOrderFees
 		transferToPublish,
-		blockToPublish)
+		blockToPublish,
+		app.DexKeeper.IsMatchingPaused(ctx, height),
+		app.DexKeeper.GetScheduledHaltEvent(ctx, height),
+		app.DexKeeper.GetSessionEvents(),
+		app.DexKeeper.GetTokenTradingEvents(),
+		app.DexKeeper.GetOrderRejections(),
+		app.DexKeeper.GetOrderAcks(),
+		isBreatheBlock,
+		degraded,
+		skippedSince,
+		blockMetrics,
+		bookSnapshots)
 
 	// remove item from OrderInfoForPublish when we published removed order (cancel, iocnofill, fullyfilled, expired)
 	for o := range pub.ToRemoveOrderIdCh {
@@ -15,6 +15,8 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/fees"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/mock"
 	"github.com/cosmos/cosmos-sdk/x/stake"
 
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -26,7 +28,12 @@ import (
 	"github.com/bnb-chain/node/app/config"
 	"github.com/bnb-chain/node/app/pub"
 	appsub "github.com/bnb-chain/node/app/pub/sub"
+	bncfees "github.com/bnb-chain/node/common/fees"
 	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/tx"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/account"
+	"github.com/bnb-chain/node/plugins/account/scripts"
 	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
 	dextypes "github.com/bnb-chain/node/plugins/dex/types"
 	"github.com/bnb-chain/node/wire"
@@ -90,6 +97,7 @@ func setupAppTest(t *testing.T) (*assert.Assertions, *require.Assertions, *Binan
 		PublishOrderUpdates:    true,
 		PublishAccountBalance:  true,
 		PublishOrderBook:       true,
+		OrderBookNetDelta:      true,
 		PublishBlockFee:        true,
 		PublicationChannelSize: 0, // deliberately sync publication
 	}
@@ -219,6 +227,68 @@ func TestAppPub_MatchOrder(t *testing.T) {
 	publisher.Lock.Unlock()
 }
 
+func TestAppPub_PublicationOptOut(t *testing.T) {
+	assert, require, app, buyerAcc, sellerAcc := setupAppTest(t)
+
+	ctx := app.DeliverState.Ctx.WithBlockHeight(41).WithBlockTime(time.Unix(0, 100))
+	ctx = ctx.WithValue(baseapp.TxHashKey, "")
+	buyerAcc.SetSequence(1)
+	app.AccountKeeper.SetAccount(ctx, buyerAcc)
+	sellerAcc.SetSequence(1)
+	app.AccountKeeper.SetAccount(ctx, sellerAcc)
+
+	// the buyer opts out of the publication of its balances
+	res := account.NewHandler(app.AccountKeeper)(ctx, account.NewSetAccountFlagsMsg(buyerAcc.GetAddress(), scripts.PublicationOptOutFlag))
+	require.Equal(sdk.ABCICodeOK, res.Code, res.Log)
+
+	handler := orderPkg.NewHandler(app.DexKeeper)
+	msg := orderPkg.NewNewOrderMsg(buyerAcc.GetAddress(), orderPkg.GenerateOrderID(1, buyerAcc.GetAddress()), orderPkg.Side.BUY, "XYZ-000_BNB", 102000, 300000000)
+	res = handler(ctx, msg)
+	require.Equal(sdk.ABCICodeOK, res.Code, res.Log)
+	app.EndBlocker(ctx, abci.RequestEndBlock{Height: 41})
+
+	publisher := app.publisher.(*pub.MockMarketDataPublisher)
+	for 4 != atomic.LoadUint32(&publisher.MessagePublished) {
+		time.Sleep(1000)
+	}
+	publisher.Lock.Lock()
+	require.Len(publisher.BooksPublished, 1)
+	require.Len(publisher.AccountPublished, 1)
+	require.Len(publisher.AccountPublished[0].Accounts, 0)
+	publisher.Lock.Unlock()
+
+	msg = orderPkg.NewNewOrderMsg(sellerAcc.GetAddress(), orderPkg.GenerateOrderID(1, sellerAcc.GetAddress()), orderPkg.Side.SELL, "XYZ-000_BNB", 102000, 400000000)
+	ctx = ctx.WithBlockHeight(42).WithBlockTime(time.Unix(0, 101))
+	res = handler(ctx, msg)
+	require.Equal(sdk.ABCICodeOK, res.Code, res.Log)
+	app.EndBlocker(ctx, abci.RequestEndBlock{Height: 42})
+	for 8 != atomic.LoadUint32(&publisher.MessagePublished) {
+		time.Sleep(1000)
+	}
+
+	publisher.Lock.Lock()
+	// the trade is still published
+	require.Len(publisher.ExecutionResultsPublished, 2)
+	require.Len(publisher.ExecutionResultsPublished[1].Trades.Trades, 1)
+	assert.Equal(string(buyerAcc.GetAddress()), publisher.ExecutionResultsPublished[1].Trades.Trades[0].BAddr)
+	// but not the balances of the buyer
+	expectedAccountToPubSeller := pub.Account{string(sellerAcc.GetAddress()), "BNB:153", 1, []*pub.AssetBalance{{"BNB", 100000305847, 0, 0}, {"XYZ-000", 99600000000, 0, 100000000}}}
+	require.Len(publisher.AccountPublished, 2)
+	require.Len(publisher.AccountPublished[1].Accounts, 2) // including the validator's account
+	require.Contains(publisher.AccountPublished[1].Accounts, expectedAccountToPubSeller)
+	for _, acc := range publisher.AccountPublished[1].Accounts {
+		assert.NotEqual(string(buyerAcc.GetAddress()), acc.Owner)
+	}
+	publisher.Lock.Unlock()
+
+	// the trade is settled on chain as usual
+	buyer := app.AccountKeeper.GetAccount(ctx, buyerAcc.GetAddress()).(types.NamedAccount)
+	assert.Equal(scripts.PublicationOptOutFlag, buyer.GetFlags())
+	assert.Equal(int64(99999693847), buyer.GetCoins().AmountOf("BNB"))
+	assert.Equal(int64(100300000000), buyer.GetCoins().AmountOf("XYZ-000"))
+	assert.Equal(int64(0), buyer.GetLockedCoins().AmountOf("BNB"))
+}
+
 func TestAppPub_MatchAndCancelFee(t *testing.T) {
 	assert, require, app, buyerAcc, sellerAcc := setupAppTest(t)
 	handler := orderPkg.NewHandler(app.DexKeeper)
@@ -272,3 +342,349 @@ func TestAppPub_MatchAndCancelFee(t *testing.T) {
 	assert.Equal("BNB:108", publisher.BlockFeePublished[1].Fee)
 	publisher.Lock.Unlock()
 }
+
+func TestAppPub_FeesByAccount(t *testing.T) {
+	assert, require, app, buyerAcc, sellerAcc := setupAppTest(t)
+	bncfees.Tracker.Clear()
+	handler := orderPkg.NewHandler(app.DexKeeper)
+	ctx := app.DeliverState.Ctx.WithValue(baseapp.TxHashKey, "")
+	app.AccountKeeper.SetAccount(ctx, buyerAcc)
+	app.AccountKeeper.SetAccount(ctx, sellerAcc)
+
+	placeOrder := func(acc sdk.Account, seq int64, side int8, qty int64) {
+		msg := orderPkg.NewNewOrderMsg(acc.GetAddress(), orderPkg.GenerateOrderID(seq, acc.GetAddress()), side, "XYZ-000_BNB", 102000, qty)
+		acc = app.AccountKeeper.GetAccount(ctx, acc.GetAddress())
+		acc.SetSequence(seq)
+		app.AccountKeeper.SetAccount(ctx, acc)
+		res := handler(ctx, msg)
+		require.Equal(sdk.ABCICodeOK, res.Code, res.Log)
+	}
+
+	// the buyer is filled by two trades in two blocks
+	ctx = ctx.WithBlockHeight(41).WithBlockTime(time.Unix(0, 100))
+	placeOrder(buyerAcc, 1, orderPkg.Side.BUY, 300000000)
+	placeOrder(sellerAcc, 1, orderPkg.Side.SELL, 300000000)
+	app.EndBlocker(ctx, abci.RequestEndBlock{Height: 41})
+
+	ctx = ctx.WithBlockHeight(42).WithBlockTime(time.Unix(0, 101))
+	placeOrder(buyerAcc, 2, orderPkg.Side.BUY, 100000000)
+	placeOrder(sellerAcc, 2, orderPkg.Side.SELL, 100000000)
+	app.EndBlocker(ctx, abci.RequestEndBlock{Height: 42})
+
+	res := app.Query(abci.RequestQuery{Path: "/fees/byaccount/" + buyerAcc.GetAddress().String()})
+	require.Equal(uint32(sdk.ABCICodeOK), res.Code, res.Log)
+	var fees bncfees.AccountFees
+	require.NoError(app.Codec.UnmarshalBinaryLengthPrefixed(res.Value, &fees))
+	assert.Equal(buyerAcc.GetAddress(), fees.Address)
+	assert.Equal(sdk.Coins{sdk.NewCoin("BNB", 153+51)}, fees.Fees)
+
+	res = app.Query(abci.RequestQuery{Path: "/fees/byaccount/" + sellerAcc.GetAddress().String()})
+	require.Equal(uint32(sdk.ABCICodeOK), res.Code, res.Log)
+	require.NoError(app.Codec.UnmarshalBinaryLengthPrefixed(res.Value, &fees))
+	assert.Equal(sdk.Coins{sdk.NewCoin("BNB", 153+51)}, fees.Fees)
+
+}
+
+func TestAppPub_FeesByAccount_FailedTx(t *testing.T) {
+	assert, require, app, _, _ := setupAppTest(t)
+	bncfees.Tracker.Clear()
+	app.SetAnteHandler(tx.NewAnteHandler(app.AccountKeeper, app.DexKeeper.IsFeeExempt))
+	ctx := app.DeliverState.Ctx
+	priv, acc := testutils.NewAccountForPub(ctx, app.AccountKeeper, 100000000, 0, 0, "XYZ-000")
+	_, receiver := testutils.NewAccount(ctx, app.AccountKeeper, 0)
+
+	send := func(seq, amount int64) {
+		msg := bank.NewMsgSend(
+			[]bank.Input{bank.NewInput(acc.GetAddress(), sdk.Coins{sdk.NewCoin("XYZ-000", amount)})},
+			[]bank.Output{bank.NewOutput(receiver.GetAddress(), sdk.Coins{sdk.NewCoin("XYZ-000", amount)})},
+		)
+		stdTx := mock.GenTx([]sdk.Msg{msg}, []int64{acc.GetAccountNumber()}, []int64{seq}, priv)
+		res := app.DeliverTx(abci.RequestDeliverTx{Tx: app.Codec.MustMarshalBinaryLengthPrefixed(stdTx)})
+		require.Equal(amount <= 100000000, res.IsOK(), res.Log)
+	}
+	feesOf := func(addr sdk.AccAddress) sdk.Coins {
+		res := app.Query(abci.RequestQuery{Path: "/fees/byaccount/" + addr.String()})
+		require.Equal(uint32(sdk.ABCICodeOK), res.Code, res.Log)
+		var fees bncfees.AccountFees
+		require.NoError(app.Codec.UnmarshalBinaryLengthPrefixed(res.Value, &fees))
+		return fees.Fees
+	}
+
+	// the tx fee charged by the ante handler is not counted, as the transfer fails
+	send(0, 200000000)
+	assert.Empty(feesOf(acc.GetAddress()))
+
+	send(0, 100000)
+	assert.Equal(sdk.Coins{sdk.NewCoin("BNB", 62500)}, feesOf(acc.GetAddress()))
+}
+
+func TestAppPub_RemainingLocked(t *testing.T) {
+	assert, require, app, buyerAcc, sellerAcc := setupAppTest(t)
+	handler := orderPkg.NewHandler(app.DexKeeper)
+	ctx := app.DeliverState.Ctx.WithValue(baseapp.TxHashKey, "")
+	app.AccountKeeper.SetAccount(ctx, buyerAcc)
+	app.AccountKeeper.SetAccount(ctx, sellerAcc)
+
+	placeOrder := func(acc sdk.Account, seq int64, side int8, qty int64) string {
+		id := orderPkg.GenerateOrderID(seq, acc.GetAddress())
+		msg := orderPkg.NewNewOrderMsg(acc.GetAddress(), id, side, "XYZ-000_BNB", 102000, qty)
+		acc = app.AccountKeeper.GetAccount(ctx, acc.GetAddress())
+		acc.SetSequence(seq)
+		app.AccountKeeper.SetAccount(ctx, acc)
+		res := handler(ctx, msg)
+		require.Equal(sdk.ABCICodeOK, res.Code, res.Log)
+		return id
+	}
+	lockedOf := func(acc sdk.Account, denom string) int64 {
+		return app.AccountKeeper.GetAccount(ctx, acc.GetAddress()).(types.NamedAccount).GetLockedCoins().AmountOf(denom)
+	}
+	// the latest status of the order in the block, i.e. the fill of an order placed in the same block
+	publishedOrder := func(results *pub.ExecutionResults, id string) (order *pub.Order) {
+		for _, o := range results.Orders.Orders {
+			if o.OrderId == id {
+				order = o
+			}
+		}
+		return order
+	}
+
+	ctx = ctx.WithBlockHeight(41).WithBlockTime(time.Unix(0, 100))
+	buyId := placeOrder(buyerAcc, 1, orderPkg.Side.BUY, 300000000)
+	app.EndBlocker(ctx, abci.RequestEndBlock{Height: 41})
+
+	// the buyer is partially filled, and the seller is fully filled
+	ctx = ctx.WithBlockHeight(42).WithBlockTime(time.Unix(0, 101))
+	sellId := placeOrder(sellerAcc, 1, orderPkg.Side.SELL, 100000000)
+	app.EndBlocker(ctx, abci.RequestEndBlock{Height: 42})
+
+	publisher := app.publisher.(*pub.MockMarketDataPublisher)
+	for 8 != atomic.LoadUint32(&publisher.MessagePublished) {
+		time.Sleep(1000)
+	}
+	publisher.Lock.Lock()
+	defer publisher.Lock.Unlock()
+	require.Len(publisher.ExecutionResultsPublished, 2)
+
+	ack := publishedOrder(publisher.ExecutionResultsPublished[0], buyId)
+	require.NotNil(ack)
+	assert.Equal(orderPkg.Ack, ack.Status)
+	assert.Equal(int64(306000), ack.RemainingLocked)
+
+	partial := publishedOrder(publisher.ExecutionResultsPublished[1], buyId)
+	require.NotNil(partial)
+	assert.Equal(orderPkg.PartialFill, partial.Status)
+	assert.Equal(int64(204000), partial.RemainingLocked)
+	assert.Equal(lockedOf(buyerAcc, "BNB"), partial.RemainingLocked)
+
+	filled := publishedOrder(publisher.ExecutionResultsPublished[1], sellId)
+	require.NotNil(filled)
+	assert.Equal(orderPkg.FullyFill, filled.Status)
+	assert.Equal(int64(0), filled.RemainingLocked)
+	assert.Equal(lockedOf(sellerAcc, "XYZ-000"), filled.RemainingLocked)
+}
+
+func TestAppPub_OrderRejections(t *testing.T) {
+	assert, require, app, buyerAcc, _ := setupAppTest(t)
+	app.publicationConfig.PublishOrderRejections = true
+	app.DexKeeper.EnableRejectionPublish()
+	handler := orderPkg.NewHandler(app.DexKeeper)
+	ctx := app.DeliverState.Ctx.WithValue(baseapp.TxHashKey, "txhash").WithRunTxMode(sdk.RunTxModeDeliver).
+		WithBlockHeight(42).WithBlockTime(time.Unix(0, 100))
+
+	buyerAcc.SetSequence(1)
+	app.AccountKeeper.SetAccount(ctx, buyerAcc)
+	id := orderPkg.GenerateOrderID(1, buyerAcc.GetAddress())
+	// the price is not rounded to the tick size
+	msg := orderPkg.NewNewOrderMsg(buyerAcc.GetAddress(), id, orderPkg.Side.BUY, "XYZ-000_BNB", 102001, 3000000)
+	res := handler(ctx, msg)
+	require.False(res.IsOK())
+	app.DexKeeper.CommitOrderRecords(false)
+	// rejections in CheckTx are not collected
+	require.False(handler(ctx.WithRunTxMode(sdk.RunTxModeCheck), msg).IsOK())
+	app.DexKeeper.CommitOrderRecords(false)
+	app.EndBlocker(ctx, abci.RequestEndBlock{Height: 42})
+
+	publisher := app.publisher.(*pub.MockMarketDataPublisher)
+	for 5 != atomic.LoadUint32(&publisher.MessagePublished) {
+		time.Sleep(1000)
+	}
+	publisher.Lock.Lock()
+	defer publisher.Lock.Unlock()
+	require.Len(publisher.OrderRejectionsPublished, 1)
+	rejections := publisher.OrderRejectionsPublished[0]
+	assert.Equal(int64(42), rejections.Height)
+	require.Equal(1, rejections.NumOfMsgs)
+	rejection := rejections.Rejections[0]
+	assert.Equal(id, rejection.OrderId)
+	assert.Equal("XYZ-000_BNB", rejection.Symbol)
+	assert.Equal(buyerAcc.GetAddress().String(), rejection.Owner)
+	assert.Equal("txhash", rejection.TxHash)
+	assert.Equal(uint32(sdk.ToABCICode(dextypes.DefaultCodespace, dextypes.CodeInvalidOrderParam)), rejection.Code)
+	assert.Equal(res.Log, rejection.Reason)
+	assert.Empty(app.DexKeeper.GetOrderRejections())
+}
+
+func TestAppPub_OrderAcks(t *testing.T) {
+	assert, require, app, buyerAcc, _ := setupAppTest(t)
+	app.publicationConfig.PublishOrderAcks = true
+	app.DexKeeper.EnableAckPublish()
+	handler := orderPkg.NewHandler(app.DexKeeper)
+	ctx := app.DeliverState.Ctx.WithValue(baseapp.TxHashKey, "txhash").WithRunTxMode(sdk.RunTxModeDeliver).
+		WithBlockHeight(42).WithBlockTime(time.Unix(0, 100))
+
+	buyerAcc.SetSequence(1)
+	app.AccountKeeper.SetAccount(ctx, buyerAcc)
+	id := orderPkg.GenerateOrderID(1, buyerAcc.GetAddress())
+	msg := orderPkg.NewNewOrderMsg(buyerAcc.GetAddress(), id, orderPkg.Side.BUY, "XYZ-000_BNB", 102000, 100000000)
+	res := handler(ctx, msg)
+	require.True(res.IsOK(), res.Log)
+	app.DexKeeper.CommitOrderRecords(true)
+	// rejected orders are not acked
+	rejected := orderPkg.NewNewOrderMsg(buyerAcc.GetAddress(), id, orderPkg.Side.BUY, "XYZ-000_BNB", 102001, 100000000)
+	require.False(handler(ctx, rejected).IsOK())
+	app.DexKeeper.CommitOrderRecords(false)
+	app.EndBlocker(ctx, abci.RequestEndBlock{Height: 42})
+
+	publisher := app.publisher.(*pub.MockMarketDataPublisher)
+	for 5 != atomic.LoadUint32(&publisher.MessagePublished) {
+		time.Sleep(1000)
+	}
+	publisher.Lock.Lock()
+	defer publisher.Lock.Unlock()
+	require.Len(publisher.OrderAcksPublished, 1)
+	acks := publisher.OrderAcksPublished[0]
+	assert.Equal(int64(42), acks.Height)
+	require.Equal(1, acks.NumOfMsgs)
+	ack := acks.Acks[0]
+	assert.Equal(id, ack.OrderId)
+	assert.Equal("XYZ-000_BNB", ack.Symbol)
+	assert.Equal(buyerAcc.GetAddress().String(), ack.Owner)
+	assert.Equal(int64(102000), ack.Price)
+	assert.Equal(int64(100000000), ack.Qty)
+	assert.Equal("txhash", ack.TxHash)
+	// the accepted order is still published as an order update after the matching
+	require.Len(publisher.ExecutionResultsPublished, 1)
+	require.Len(publisher.ExecutionResultsPublished[0].Orders.Orders, 1)
+	assert.Equal(id, publisher.ExecutionResultsPublished[0].Orders.Orders[0].OrderId)
+	assert.Empty(app.DexKeeper.GetOrderAcks())
+}
+
+func TestAppPub_TradeAudits(t *testing.T) {
+	assert, require, app, buyerAcc, sellerAcc := setupAppTest(t)
+	app.publicationConfig.PublishTradeAudits = true
+	handler := orderPkg.NewHandler(app.DexKeeper)
+	ctx := app.DeliverState.Ctx.WithRunTxMode(sdk.RunTxModeDeliver)
+
+	buyerAcc.SetSequence(1)
+	app.AccountKeeper.SetAccount(ctx, buyerAcc)
+	bid := orderPkg.GenerateOrderID(1, buyerAcc.GetAddress())
+	msg := orderPkg.NewNewOrderMsg(buyerAcc.GetAddress(), bid, orderPkg.Side.BUY, "XYZ-000_BNB", 102000, 300000000)
+	ctx = ctx.WithBlockHeight(41).WithBlockTime(time.Unix(0, 100))
+	res := handler(ctx.WithValue(baseapp.TxHashKey, "buytx"), msg)
+	require.True(res.IsOK(), res.Log)
+	app.EndBlocker(ctx, abci.RequestEndBlock{Height: 41})
+
+	sellerAcc.SetSequence(1)
+	app.AccountKeeper.SetAccount(ctx, sellerAcc)
+	sid := orderPkg.GenerateOrderID(1, sellerAcc.GetAddress())
+	msg = orderPkg.NewNewOrderMsg(sellerAcc.GetAddress(), sid, orderPkg.Side.SELL, "XYZ-000_BNB", 102000, 400000000)
+	ctx = ctx.WithBlockHeight(42).WithBlockTime(time.Unix(0, 101))
+	res = handler(ctx.WithValue(baseapp.TxHashKey, "selltx"), msg)
+	require.True(res.IsOK(), res.Log)
+	app.EndBlocker(ctx, abci.RequestEndBlock{Height: 42})
+
+	publisher := app.publisher.(*pub.MockMarketDataPublisher)
+	for 10 != atomic.LoadUint32(&publisher.MessagePublished) {
+		time.Sleep(1000)
+	}
+	publisher.Lock.Lock()
+	defer publisher.Lock.Unlock()
+	require.Len(publisher.TradeAuditsPublished, 2)
+	assert.Equal(0, publisher.TradeAuditsPublished[0].NumOfMsgs)
+	audits := publisher.TradeAuditsPublished[1]
+	assert.Equal(int64(42), audits.Height)
+	require.Equal(1, audits.NumOfMsgs)
+	trade := audits.Trades[0]
+	assert.Equal("XYZ-000_BNB", trade.Symbol)
+	assert.Equal(int64(102000), trade.Price)
+	assert.Equal(int64(300000000), trade.Qty)
+	// the resting buy order is the maker
+	assert.Equal("buyer", trade.Maker)
+	assert.Equal(sid, trade.Sid)
+	assert.Equal(sellerAcc.GetAddress().String(), trade.SAddr)
+	assert.Equal("selltx", trade.STxHash)
+	assert.Equal("BNB:153", trade.SFee)
+	assert.Equal(bid, trade.Bid)
+	assert.Equal(buyerAcc.GetAddress().String(), trade.BAddr)
+	assert.Equal("buytx", trade.BTxHash)
+	assert.Equal("BNB:153", trade.BFee)
+}
+
+func TestAppPub_BlockMetrics(t *testing.T) {
+	assert, require, app, buyerAcc, sellerAcc := setupAppTest(t)
+	app.publicationConfig.PublishBlockMetrics = true
+	handler := orderPkg.NewHandler(app.DexKeeper)
+	ctx := app.DeliverState.Ctx.WithRunTxMode(sdk.RunTxModeDeliver).WithValue(baseapp.TxHashKey, "")
+
+	buyerAcc.SetSequence(1)
+	app.AccountKeeper.SetAccount(ctx, buyerAcc)
+	msg := orderPkg.NewNewOrderMsg(buyerAcc.GetAddress(), orderPkg.GenerateOrderID(1, buyerAcc.GetAddress()), orderPkg.Side.BUY, "XYZ-000_BNB", 102000, 300000000)
+	ctx = ctx.WithBlockHeight(41).WithBlockTime(time.Unix(0, 100))
+	res := handler(ctx, msg)
+	require.True(res.IsOK(), res.Log)
+	app.EndBlocker(ctx, abci.RequestEndBlock{Height: 41})
+
+	sellerAcc.SetSequence(1)
+	app.AccountKeeper.SetAccount(ctx, sellerAcc)
+	msg = orderPkg.NewNewOrderMsg(sellerAcc.GetAddress(), orderPkg.GenerateOrderID(1, sellerAcc.GetAddress()), orderPkg.Side.SELL, "XYZ-000_BNB", 102000, 400000000)
+	ctx = ctx.WithBlockHeight(42).WithBlockTime(time.Unix(0, 101))
+	res = handler(ctx, msg)
+	require.True(res.IsOK(), res.Log)
+	app.EndBlocker(ctx, abci.RequestEndBlock{Height: 42})
+
+	publisher := app.publisher.(*pub.MockMarketDataPublisher)
+	for 10 != atomic.LoadUint32(&publisher.MessagePublished) {
+		time.Sleep(1000)
+	}
+	publisher.Lock.Lock()
+	defer publisher.Lock.Unlock()
+	require.Len(publisher.BlockMetricsPublished, 2)
+	metrics := publisher.BlockMetricsPublished[0]
+	assert.Equal(int64(41), metrics.Height)
+	assert.Equal(1, metrics.NumOfOrders)
+	assert.Equal(0, metrics.NumOfTrades)
+	metrics = publisher.BlockMetricsPublished[1]
+	assert.Equal(int64(42), metrics.Height)
+	assert.Equal(int64(101), metrics.Timestamp)
+	assert.Equal(1, metrics.NumOfOrders)
+	assert.Equal(1, metrics.NumOfTrades)
+	assert.True(metrics.MatchingTimeMs >= 0)
+	assert.True(metrics.CollectTimeMs >= 0)
+}
+
+func TestAppPub_IsBreatheBlock(t *testing.T) {
+	assert, require, app, buyerAcc, _ := setupAppTest(t)
+
+	msg := orderPkg.NewNewOrderMsg(buyerAcc.GetAddress(), "1", orderPkg.Side.BUY, "XYZ-000_BNB", 102000, 3000000)
+	app.DexKeeper.AddOrder(orderPkg.OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	// in the same UTC day as the last block
+	ctx := app.DeliverState.Ctx.WithBlockHeight(42).WithBlockTime(time.Unix(0, 101))
+	app.EndBlocker(ctx, abci.RequestEndBlock{Height: 42})
+
+	publisher := app.publisher.(*pub.MockMarketDataPublisher)
+	for 4 != atomic.LoadUint32(&publisher.MessagePublished) {
+		time.Sleep(1000)
+	}
+	publisher.Lock.Lock()
+	require.Len(publisher.ExecutionResultsPublished, 1)
+	assert.False(publisher.ExecutionResultsPublished[0].IsBreatheBlock)
+	publisher.Lock.Unlock()
+
+	// the breathe blocks are decided by the interval if it's configured, by the first block of a UTC day otherwise
+	assert.True(isBreatheBlock(10, 20, time.Time{}, time.Time{}))
+	assert.False(isBreatheBlock(10, 21, time.Time{}, time.Time{}))
+	day := time.Date(2019, 1, 1, 23, 59, 59, 0, time.UTC)
+	assert.True(isBreatheBlock(0, 21, day, day.Add(time.Second)))
+	assert.False(isBreatheBlock(0, 21, day.Add(-time.Second), day))
+	assert.False(isBreatheBlock(0, 21, time.Time{}, day.Add(time.Second)))
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"sort"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/types"
+)
+
+const chainStatsAbciQueryPrefix = "stats"
+
+// globalAccountNumberKey is the key of the counter the auth module numbers the new accounts with.
+var globalAccountNumberKey = []byte("globalAccountNumber")
+
+// chainStatsCache keeps the stats of the latest height queried, so that the tokens are iterated at most once
+// a block.
+type chainStatsCache struct {
+	mtx   sync.Mutex
+	stats *types.ChainStats
+}
+
+// countAccounts reads the number of the accounts off the account number counter, as the accounts are never
+// removed, only moved to other addresses with their numbers kept. It's 0 before the genesis.
+func (app *BinanceChain) countAccounts(ctx sdk.Context) int64 {
+	bz := ctx.KVStore(common.AccountStoreKey).Get(globalAccountNumberKey)
+	if bz == nil {
+		return 0
+	}
+	var num int64
+	app.Codec.MustUnmarshalBinaryLengthPrefixed(bz, &num)
+	return num
+}
+
+func (app *BinanceChain) getChainStats(ctx sdk.Context) types.ChainStats {
+	app.chainStats.mtx.Lock()
+	defer app.chainStats.mtx.Unlock()
+	cache := &app.chainStats
+	if cache.stats == nil || cache.stats.Height != ctx.BlockHeight() {
+		stats := &types.ChainStats{
+			Height:       ctx.BlockHeight(),
+			Accounts:     app.countAccounts(ctx),
+			TradingPairs: int64(len(app.DexKeeper.PairMapper.ListAllTradingPairs(ctx))),
+			Supplies:     make([]types.AssetSupply, 0),
+		}
+		for _, isMini := range []bool{false, true} {
+			tokens := app.TokenMapper.GetTokenList(ctx, true, isMini)
+			if isMini {
+				stats.MiniTokens = int64(len(tokens))
+			} else {
+				stats.Tokens = int64(len(tokens))
+			}
+			for _, token := range tokens {
+				stats.Supplies = append(stats.Supplies, types.AssetSupply{
+					Asset:       token.GetSymbol(),
+					TotalSupply: token.GetTotalSupply().ToInt64(),
+				})
+			}
+		}
+		sort.Slice(stats.Supplies, func(i, j int) bool {
+			return stats.Supplies[i].Asset < stats.Supplies[j].Asset
+		})
+		cache.stats = stats
+	}
+	return *cache.stats
+}
+
+// StatsHandler serves the `stats` query with the ChainStats in json.
+func (app *BinanceChain) StatsHandler(chainApp types.ChainApp, req abci.RequestQuery, path []string) *abci.ResponseQuery {
+	var res abci.ResponseQuery
+	if len(path) != 1 {
+		res = sdk.ErrUnknownRequest("invalid path").QueryResult()
+		return &res
+	}
+	bz, err := Codec.MarshalJSON(app.getChainStats(app.CheckState.Ctx))
+	if err != nil {
+		res = sdk.ErrInternal(err.Error()).QueryResult()
+	} else {
+		res = abci.ResponseQuery{
+			Code:  uint32(sdk.ABCICodeOK),
+			Value: bz,
+		}
+	}
+	return &res
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/types"
+)
+
+func TestChainStats(t *testing.T) {
+	_, require, app, _, _ := setupAppTest(t)
+	ctx := app.DeliverState.Ctx.WithBlockHeight(42)
+
+	stats := app.getChainStats(ctx)
+	require.Equal(int64(42), stats.Height)
+	require.Equal(int64(2), stats.TradingPairs)
+	require.Zero(stats.MiniTokens)
+	require.Equal(int(stats.Tokens), len(stats.Supplies))
+	var nativeSupply int64
+	for _, supply := range stats.Supplies {
+		if supply.Asset == types.NativeTokenSymbol {
+			nativeSupply = supply.TotalSupply
+		}
+	}
+	require.True(nativeSupply > 0)
+	numAccounts := stats.Accounts
+	// the accounts created in the genesis
+	require.Equal(int64(2), numAccounts)
+	var iterated int64
+	app.AccountKeeper.IterateAccounts(ctx, func(_ sdk.Account) bool {
+		iterated++
+		return false
+	})
+	require.Equal(iterated, numAccounts)
+
+	token, err := types.NewToken("New Token", "NEW-000", 10000e8, nil, false)
+	require.NoError(err)
+	require.NoError(app.TokenMapper.NewToken(ctx, token))
+	testutils.NewAccount(ctx, app.AccountKeeper, 100)
+	// cached in the same height
+	require.Equal(stats, app.getChainStats(ctx))
+
+	ctx = ctx.WithBlockHeight(43)
+	stats = app.getChainStats(ctx)
+	require.Contains(stats.Supplies, types.AssetSupply{Asset: "NEW-000", TotalSupply: 10000e8})
+	require.Equal(numAccounts+1, stats.Accounts)
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"bytes"
+	"compress/gzip"
 	"math"
 	"path/filepath"
 	"text/template"
@@ -43,6 +44,13 @@ startMode = {{ .BaseConfig.StartMode }}
 orderKeeperConcurrency = {{ .BaseConfig.OrderKeeperConcurrency }}
 # Days count back for breathe block
 breatheBlockDaysCountBack = {{ .BaseConfig.BreatheBlockDaysCountBack }}
+# Whether to gzip the store traces written to the file of --trace-store, the stream is flushed at each commit
+compressTraceStore = {{ .BaseConfig.CompressTraceStore }}
+# Gzip level of the store traces, -1: default, 1: best speed ~ 9: best compression
+traceStoreCompressionLevel = {{ .BaseConfig.TraceStoreCompressionLevel }}
+# Whether to verify the locked coins of the accounts against the open orders once the order book is recovered on start,
+# the mismatches are logged. It goes through all the accounts and open orders, so it slows down the start.
+orderBookIntegrityCheck = {{ .BaseConfig.OrderBookIntegrityCheck }}
 
 [upgrade]
 # Block height of BEP6 upgrade
@@ -97,10 +105,49 @@ BEP159Phase2Height = {{ .UpgradeConfig.BEP159Phase2Height }}
 BEP173Height = {{ .UpgradeConfig.BEP173Height }}
 # Block height of FixDoubleSignChainIdHeight upgrade
 FixDoubleSignChainIdHeight = {{ .UpgradeConfig.FixDoubleSignChainIdHeight }}
+# Block height of OrderIDVersioning upgrade, since which the order ids are generated with a version prefix
+OrderIDVersioningHeight = {{ .UpgradeConfig.OrderIDVersioningHeight }}
+# Block height of TokenTransferMemo upgrade, since which the token owners can require a memo on the transfers of their tokens
+TokenTransferMemoHeight = {{ .UpgradeConfig.TokenTransferMemoHeight }}
+# Block height of OrderParamCodes upgrade, since which the orders of zero/negative prices and quantities are rejected with their own codes
+OrderParamCodesHeight = {{ .UpgradeConfig.OrderParamCodesHeight }}
+# Block height of TokenTransferFee upgrade, since which the token owners can charge a fee on the transfers of their tokens
+TokenTransferFeeHeight = {{ .UpgradeConfig.TokenTransferFeeHeight }}
+# Block height of OrderUnknownPairCode upgrade, since which the orders of unknown trading pairs are rejected with their own code
+OrderUnknownPairCodeHeight = {{ .UpgradeConfig.OrderUnknownPairCodeHeight }}
+# Block height of OrderMemo upgrade, since which the orders can carry a memo for the client tagging
+OrderMemoHeight = {{ .UpgradeConfig.OrderMemoHeight }}
+# Block height of GovernedParams upgrade, since which the dex, trading pair, token and fee split params are changed by the fee change proposals, and the fee exemptions and token trading by their own msgs
+GovernedParamsHeight = {{ .UpgradeConfig.GovernedParamsHeight }}
 
 [query]
 # ABCI query interface black list, suggested value: ["custom/gov/proposals", "custom/timelock/timelocks", "custom/atomicSwap/swapcreator", "custom/atomicSwap/swaprecipient"]
 ABCIQueryBlackList = {{ .QueryConfig.ABCIQueryBlackList }}
+# Max number of accounts whose cumulative fees are tracked in memory for the fees/byaccount query, 0 to disable.
+# The fees are counted since the node started, the least recently charged accounts are pruned first.
+feesByAccountLimit = {{ .QueryConfig.FeesByAccountLimit }}
+# Max number of recently traded orders whose fills are kept in memory for the dex/orderfills query, 0 to disable.
+orderFillsCacheSize = {{ .QueryConfig.OrderFillsCacheSize }}
+# Max number of recently traded accounts whose trades are kept in memory for the dex/accounttrades query, 0 to disable.
+accountTradesCacheSize = {{ .QueryConfig.AccountTradesCacheSize }}
+# Max number of recently cancelling accounts whose cancelled orders are kept in memory for the dex/cancelled query, 0 to disable.
+recentCancelsCacheSize = {{ .QueryConfig.RecentCancelsCacheSize }}
+# Number of recent blocks whose delivered txs are kept in memory for the tx/status query, 0 to disable.
+# The txs delivered before the lookback window, or before the node started, are reported as not found.
+txStatusLookbackBlocks = {{ .QueryConfig.TxStatusLookbackBlocks }}
+# Number of the most recent breathe block order book snapshots served by the dex/history query, 0 to disable.
+# The older order books are expected to be looked up in the archival export.
+orderBookHistoryRetention = {{ .QueryConfig.OrderBookHistoryRetention }}
+# Max number of price levels per side served by the dex/orderbook query regardless of the requested levels, 1000 by default.
+# The order books deeper than the cap are returned truncated and flagged so.
+maxOrderBookDepth = {{ .QueryConfig.MaxOrderBookDepth }}
+# Whether to log the queries rejected as malformed by the query handlers deep in their input validation. The logging
+# can be turned off on nodes exposed to untrusted query traffic to keep the logs from being flooded. The queries that
+# panic otherwise are bugs of the node, they are always logged and answered as internal errors.
+logQueryPanics = {{ .QueryConfig.LogQueryPanics }}
+# Whether to record the order book imbalances of the recent blocks served by the dex/imbalancetrend query. The
+# recording walks all the order books in every block, so it's off by default.
+imbalanceTrendEnabled = {{ .QueryConfig.ImbalanceTrendEnabled }}
 
 [addr]
 # Bech32PrefixAccAddr defines the Bech32 prefix of an account's address
@@ -133,6 +180,12 @@ accountBalanceKafka = "{{ .PublicationConfig.AccountBalanceKafka }}"
 publishOrderBook = {{ .PublicationConfig.PublishOrderBook }}
 orderBookTopic = "{{ .PublicationConfig.OrderBookTopic }}"
 orderBookKafka = "{{ .PublicationConfig.OrderBookKafka }}"
+# Whether the price levels touched in a block but ending up with the same quantity, e.g. by an order
+# placed and filled in the block, are left out of the order book changes. Set false to publish all the touched levels.
+orderBookNetDelta = {{ .PublicationConfig.OrderBookNetDelta }}
+# Precisions the quantities of the published trades and order book levels are rounded to for display, e.g. "XYZ-000_BNB:2,ABC-000_BNB:4".
+# Only the published messages are rounded, the consumers needing the exact quantities should use the queries.
+qtyDisplayPrecisions = "{{ .PublicationConfig.QtyDisplayPrecisions }}"
 
 # Whether we want publish block fee changes
 publishBlockFee = {{ .PublicationConfig.PublishBlockFee }}
@@ -184,8 +237,51 @@ publishBreatheBlock = {{ .PublicationConfig.PublishBreatheBlock }}
 breatheBlockTopic = "{{ .PublicationConfig.BreatheBlockTopic }}"
 breatheBlockKafka = "{{ .PublicationConfig.BreatheBlockKafka }}"
 
+# Whether we want publish the new orders rejected by the dex handler, together with the reasons.
+# It's off by default since the rejected orders may include spam.
+publishOrderRejections = {{ .PublicationConfig.PublishOrderRejections }}
+orderRejectionsTopic = "{{ .PublicationConfig.OrderRejectionsTopic }}"
+orderRejectionsKafka = "{{ .PublicationConfig.OrderRejectionsKafka }}"
+
+# Whether we want publish the new orders as soon as they are accepted by the dex handler, ahead of the matching.
+# The acks are not order updates, the accepted orders are still published as Ack order changes after the matching.
+publishOrderAcks = {{ .PublicationConfig.PublishOrderAcks }}
+orderAcksTopic = "{{ .PublicationConfig.OrderAcksTopic }}"
+orderAcksKafka = "{{ .PublicationConfig.OrderAcksKafka }}"
+
+# Whether we want publish the full pre-image of each matched trade for audit: both orders with their owners,
+# fees, tx hashes and the maker/taker designation. It's payload heavy and off by default.
+# The records link the addresses to their trading activities, please keep the retention of the topic in line
+# with the data retention and privacy policies, and restrict the consumers of it to the auditors.
+publishTradeAudits = {{ .PublicationConfig.PublishTradeAudits }}
+tradeAuditsTopic = "{{ .PublicationConfig.TradeAuditsTopic }}"
+tradeAuditsKafka = "{{ .PublicationConfig.TradeAuditsKafka }}"
+
+# Whether we want publish the compute metrics of each block for capacity planning: the time spent on matching,
+# the orders matched, the trades produced and the time spent on collecting the publication.
+publishBlockMetrics = {{ .PublicationConfig.PublishBlockMetrics }}
+blockMetricsTopic = "{{ .PublicationConfig.BlockMetricsTopic }}"
+blockMetricsKafka = "{{ .PublicationConfig.BlockMetricsKafka }}"
+
+# Whether we want publish the full order books every orderBookSnapshotInterval blocks, for the consumers joining
+# the order book changes mid-stream. A consumer starts from the latest snapshot of height H, then applies the order
+# book changes of the heights above H in order, where a level of quantity 0 is removed. The changes only cover the
+# levels published, so only that many top levels of the book stay up to date, even if the snapshot is deeper.
+# orderBookSnapshotDepth is the number of the price levels per side in the snapshots, 0 for the levels published in
+# the changes.
+publishOrderBookSnapshot = {{ .PublicationConfig.PublishOrderBookSnapshot }}
+orderBookSnapshotTopic = "{{ .PublicationConfig.OrderBookSnapshotTopic }}"
+orderBookSnapshotKafka = "{{ .PublicationConfig.OrderBookSnapshotKafka }}"
+orderBookSnapshotInterval = {{ .PublicationConfig.OrderBookSnapshotInterval }}
+orderBookSnapshotDepth = {{ .PublicationConfig.OrderBookSnapshotDepth }}
+
 # Global setting
 publicationChannelSize = {{ .PublicationConfig.PublicationChannelSize }}
+# Megabytes of the allocated heap above which the publication is degraded to only the trades and order changes, the
+# account balances, order book changes, transfers and blocks are not published meanwhile. The full publication resumes
+# once the heap drops below 90% of it, and the accounts message of that block has skippedSince set to the first height
+# degraded, so that the consumers resync the balances. 0 to never degrade.
+degradeMemoryThresholdMB = {{ .PublicationConfig.DegradeMemoryThresholdMB }}
 publishKafka = {{ .PublicationConfig.PublishKafka }}
 publishLocal = {{ .PublicationConfig.PublishLocal }}
 # max size in megabytes of marketdata json file before rotate
@@ -204,6 +300,14 @@ stopOnKafkaFail = {{ .PublicationConfig.StopOnKafkaFail }}
 # please modify the default value into the version of Kafka you are using
 # kafka broker version, default (and most recommended) is 2.1.0. Minimal supported version could be 0.8.2.0
 kafkaVersion = "{{ .PublicationConfig.KafkaVersion }}"
+# Max bytes of a kafka message, the larger messages of busy blocks are split into parts of at most this size.
+# The parts share the key of the message and go to the same partition, the index of the part and the number of parts
+# are in the "part" and "parts" record headers, and the consumers need to concatenate the values of the parts in order.
+# It requires kafka 0.11.0.0 at least. 0 to never split the messages.
+kafkaMaxMessageBytes = {{ .PublicationConfig.KafkaMaxMessageBytes }}
+# Encoding of the execution results (trades and order changes), books and accounts messages, "avro" or "protobuf".
+# The protobuf schemas mirror the avro ones, see app/pub/schemas/*.proto. The other messages are always in avro.
+kafkaEncoding = "{{ .PublicationConfig.KafkaEncoding }}"
 
 [log]
 
@@ -217,6 +321,12 @@ logFileRoot = "{{ .LogConfig.LogFileRoot }}"
 logFilePath = "{{ .LogConfig.LogFilePath }}"
 # Number of logs keep in memory before writing to file
 logBuffSize = {{ .LogConfig.LogBuffSize }}
+# Write the rejected txs, i.e. those rejected by the signature, sequence or fee checks and the rejected orders, cancels
+# and transfers, with the reason and the sender, to an audit log separate from the logs above. Each rejection is a line
+# of json, and those in both CheckTx and DeliverTx are written, told apart by the mode.
+auditRejectedTxs = {{ .LogConfig.AuditRejectedTxs }}
+# Audit log file path relative to log file root path
+auditLogPath = "{{ .LogConfig.AuditLogPath }}"
 
 [cross_chain]
 # IBC chain-id for current chain
@@ -229,6 +339,13 @@ bscIbcChainId = {{ .CrossChainConfig.BscIbcChainId }}
 [dex]
 # The suffixed symbol of BUSD
 BUSDSymbol = "{{ .DexConfig.BUSDSymbol }}"
+# Whether the dex queries resolve the symbol aliases below and match the symbols case-insensitively.
+# This is only a query convenience, the canonical symbols on chain remain authoritative.
+querySymbolAlias = {{ .DexConfig.QuerySymbolAlias }}
+# Aliases of the trading pair symbols in the form of "alias:CANONICAL_SYMBOL", e.g. ["xyz_bnb:XYZ-000_BNB"]
+querySymbolAliases = {{ .DexConfig.QuerySymbolAliases }}
+# Whether to log the orders of unknown trading pairs, which are usually caused by misconfigured clients
+logUnknownPairOrders = {{ .DexConfig.LogUnknownPairOrders }}
 `
 
 type BinanceChainContext struct {
@@ -301,9 +418,11 @@ type PublicationConfig struct {
 	AccountBalanceTopic   string `mapstructure:"accountBalanceTopic"`
 	AccountBalanceKafka   string `mapstructure:"accountBalanceKafka"`
 
-	PublishOrderBook bool   `mapstructure:"publishOrderBook"`
-	OrderBookTopic   string `mapstructure:"orderBookTopic"`
-	OrderBookKafka   string `mapstructure:"orderBookKafka"`
+	PublishOrderBook     bool   `mapstructure:"publishOrderBook"`
+	OrderBookTopic       string `mapstructure:"orderBookTopic"`
+	OrderBookKafka       string `mapstructure:"orderBookKafka"`
+	OrderBookNetDelta    bool   `mapstructure:"orderBookNetDelta"`
+	QtyDisplayPrecisions string `mapstructure:"qtyDisplayPrecisions"`
 
 	PublishBlockFee bool   `mapstructure:"publishBlockFee"`
 	BlockFeeTopic   string `mapstructure:"blockFeeTopic"`
@@ -345,7 +464,30 @@ type PublicationConfig struct {
 	BreatheBlockTopic   string `mapstructure:"breatheBlockTopic"`
 	BreatheBlockKafka   string `mapstructure:"breatheBlockKafka"`
 
-	PublicationChannelSize int `mapstructure:"publicationChannelSize"`
+	PublishOrderRejections bool   `mapstructure:"publishOrderRejections"`
+	OrderRejectionsTopic   string `mapstructure:"orderRejectionsTopic"`
+	OrderRejectionsKafka   string `mapstructure:"orderRejectionsKafka"`
+
+	PublishOrderAcks bool   `mapstructure:"publishOrderAcks"`
+	OrderAcksTopic   string `mapstructure:"orderAcksTopic"`
+	OrderAcksKafka   string `mapstructure:"orderAcksKafka"`
+
+	PublishTradeAudits bool   `mapstructure:"publishTradeAudits"`
+	TradeAuditsTopic   string `mapstructure:"tradeAuditsTopic"`
+	TradeAuditsKafka   string `mapstructure:"tradeAuditsKafka"`
+
+	PublishBlockMetrics bool   `mapstructure:"publishBlockMetrics"`
+	BlockMetricsTopic   string `mapstructure:"blockMetricsTopic"`
+	BlockMetricsKafka   string `mapstructure:"blockMetricsKafka"`
+
+	PublishOrderBookSnapshot  bool   `mapstructure:"publishOrderBookSnapshot"`
+	OrderBookSnapshotTopic    string `mapstructure:"orderBookSnapshotTopic"`
+	OrderBookSnapshotKafka    string `mapstructure:"orderBookSnapshotKafka"`
+	OrderBookSnapshotInterval int64  `mapstructure:"orderBookSnapshotInterval"`
+	OrderBookSnapshotDepth    int    `mapstructure:"orderBookSnapshotDepth"`
+
+	PublicationChannelSize   int   `mapstructure:"publicationChannelSize"`
+	DegradeMemoryThresholdMB int64 `mapstructure:"degradeMemoryThresholdMB"`
 
 	// DO NOT put this option in config file
 	// deliberately make it only a command line arguments
@@ -367,7 +509,9 @@ type PublicationConfig struct {
 	KafkaUserName   string `mapstructure:"kafkaUserName"`
 	KafkaPassword   string `mapstructure:"kafkaPassword"`
 
-	KafkaVersion string `mapstructure:"kafkaVersion"`
+	KafkaVersion         string `mapstructure:"kafkaVersion"`
+	KafkaMaxMessageBytes int    `mapstructure:"kafkaMaxMessageBytes"`
+	KafkaEncoding        string `mapstructure:"kafkaEncoding"`
 }
 
 func defaultPublicationConfig() *PublicationConfig {
@@ -380,9 +524,11 @@ func defaultPublicationConfig() *PublicationConfig {
 		AccountBalanceTopic:   "accounts",
 		AccountBalanceKafka:   "127.0.0.1:9092",
 
-		PublishOrderBook: false,
-		OrderBookTopic:   "orders",
-		OrderBookKafka:   "127.0.0.1:9092",
+		PublishOrderBook:     false,
+		OrderBookTopic:       "orders",
+		OrderBookKafka:       "127.0.0.1:9092",
+		OrderBookNetDelta:    true,
+		QtyDisplayPrecisions: "",
 
 		PublishBlockFee: false,
 		BlockFeeTopic:   "accounts",
@@ -424,9 +570,32 @@ func defaultPublicationConfig() *PublicationConfig {
 		BreatheBlockTopic:   "breatheBlock",
 		BreatheBlockKafka:   "127.0.0.1:9092",
 
-		PublicationChannelSize: 10000,
-		FromHeightInclusive:    1,
-		PublishKafka:           false,
+		PublishOrderRejections: false,
+		OrderRejectionsTopic:   "orderRejections",
+		OrderRejectionsKafka:   "127.0.0.1:9092",
+
+		PublishOrderAcks: false,
+		OrderAcksTopic:   "orderAcks",
+		OrderAcksKafka:   "127.0.0.1:9092",
+
+		PublishTradeAudits: false,
+		TradeAuditsTopic:   "tradeAudits",
+		TradeAuditsKafka:   "127.0.0.1:9092",
+
+		PublishBlockMetrics: false,
+		BlockMetricsTopic:   "blockMetrics",
+		BlockMetricsKafka:   "127.0.0.1:9092",
+
+		PublishOrderBookSnapshot:  false,
+		OrderBookSnapshotTopic:    "orderBookSnapshots",
+		OrderBookSnapshotKafka:    "127.0.0.1:9092",
+		OrderBookSnapshotInterval: 1000,
+		OrderBookSnapshotDepth:    0,
+
+		PublicationChannelSize:   10000,
+		DegradeMemoryThresholdMB: 0,
+		FromHeightInclusive:      1,
+		PublishKafka:             false,
 
 		PublishLocal: false,
 		LocalMaxSize: 1024,
@@ -437,7 +606,9 @@ func defaultPublicationConfig() *PublicationConfig {
 		KafkaPassword:   "",
 		StopOnKafkaFail: false,
 
-		KafkaVersion: "2.1.0",
+		KafkaVersion:         "2.1.0",
+		KafkaMaxMessageBytes: 0,
+		KafkaEncoding:        "avro",
 	}
 }
 
@@ -454,7 +625,18 @@ func (pubCfg PublicationConfig) ShouldPublishAny() bool {
 		pubCfg.PublishCrossTransfer ||
 		pubCfg.PublishMirror ||
 		pubCfg.PublishSideProposal ||
-		pubCfg.PublishBreatheBlock
+		pubCfg.PublishBreatheBlock ||
+		pubCfg.PublishOrderRejections ||
+		pubCfg.PublishOrderAcks ||
+		pubCfg.PublishTradeAudits ||
+		pubCfg.PublishBlockMetrics ||
+		pubCfg.PublishOrderBookSnapshot
+}
+
+// IsOrderBookSnapshotHeight tells whether the full order books are published at the height.
+func (pubCfg PublicationConfig) IsOrderBookSnapshotHeight(height int64) bool {
+	return pubCfg.PublishOrderBookSnapshot && pubCfg.OrderBookSnapshotInterval > 0 &&
+		height%pubCfg.OrderBookSnapshotInterval == 0
 }
 
 type CrossChainConfig struct {
@@ -478,6 +660,9 @@ type LogConfig struct {
 	LogFileRoot  string `mapstructure:"logFileRoot"`
 	LogFilePath  string `mapstructure:"logFilePath"`
 	LogBuffSize  int64  `mapstructure:"logBuffSize"`
+
+	AuditRejectedTxs bool   `mapstructure:"auditRejectedTxs"`
+	AuditLogPath     string `mapstructure:"auditLogPath"`
 }
 
 func defaultLogConfig() *LogConfig {
@@ -486,26 +671,35 @@ func defaultLogConfig() *LogConfig {
 		LogFileRoot:  "",
 		LogFilePath:  "bnc.log",
 		LogBuffSize:  10000,
+
+		AuditRejectedTxs: false,
+		AuditLogPath:     "audit.log",
 	}
 }
 
 type BaseConfig struct {
-	AccountCacheSize          int   `mapstructure:"accountCacheSize"`
-	SignatureCacheSize        int   `mapstructure:"signatureCacheSize"`
-	StartMode                 uint8 `mapstructure:"startMode"`
-	BreatheBlockInterval      int   `mapstructure:"breatheBlockInterval"`
-	OrderKeeperConcurrency    uint  `mapstructure:"orderKeeperConcurrency"`
-	BreatheBlockDaysCountBack int   `mapstructure:"breatheBlockDaysCountBack"`
+	AccountCacheSize           int   `mapstructure:"accountCacheSize"`
+	SignatureCacheSize         int   `mapstructure:"signatureCacheSize"`
+	StartMode                  uint8 `mapstructure:"startMode"`
+	BreatheBlockInterval       int   `mapstructure:"breatheBlockInterval"`
+	OrderKeeperConcurrency     uint  `mapstructure:"orderKeeperConcurrency"`
+	BreatheBlockDaysCountBack  int   `mapstructure:"breatheBlockDaysCountBack"`
+	CompressTraceStore         bool  `mapstructure:"compressTraceStore"`
+	TraceStoreCompressionLevel int   `mapstructure:"traceStoreCompressionLevel"`
+	OrderBookIntegrityCheck    bool  `mapstructure:"orderBookIntegrityCheck"`
 }
 
 func defaultBaseConfig() *BaseConfig {
 	return &BaseConfig{
-		AccountCacheSize:          30000,
-		SignatureCacheSize:        30000,
-		StartMode:                 0,
-		BreatheBlockInterval:      0,
-		OrderKeeperConcurrency:    2,
-		BreatheBlockDaysCountBack: 7,
+		AccountCacheSize:           30000,
+		SignatureCacheSize:         30000,
+		StartMode:                  0,
+		BreatheBlockInterval:       0,
+		OrderKeeperConcurrency:     2,
+		BreatheBlockDaysCountBack:  7,
+		CompressTraceStore:         false,
+		TraceStoreCompressionLevel: gzip.DefaultCompression,
+		OrderBookIntegrityCheck:    false,
 	}
 }
 
@@ -542,6 +736,13 @@ type UpgradeConfig struct {
 	BEP159Phase2Height                              int64 `mapstructure:"BEP159Phase2Height"`
 	BEP173Height                                    int64 `mapstructure:"BEP173Height"`
 	FixDoubleSignChainIdHeight                      int64 `mapstructure:"FixDoubleSignChainIdHeight"`
+	OrderIDVersioningHeight                         int64 `mapstructure:"OrderIDVersioningHeight"`
+	TokenTransferMemoHeight                         int64 `mapstructure:"TokenTransferMemoHeight"`
+	OrderParamCodesHeight                           int64 `mapstructure:"OrderParamCodesHeight"`
+	TokenTransferFeeHeight                          int64 `mapstructure:"TokenTransferFeeHeight"`
+	OrderUnknownPairCodeHeight                      int64 `mapstructure:"OrderUnknownPairCodeHeight"`
+	OrderMemoHeight                                 int64 `mapstructure:"OrderMemoHeight"`
+	GovernedParamsHeight                            int64 `mapstructure:"GovernedParamsHeight"`
 }
 
 func defaultUpgradeConfig() *UpgradeConfig {
@@ -568,6 +769,13 @@ func defaultUpgradeConfig() *UpgradeConfig {
 		BEP159Phase2Height:         math.MaxInt64,
 		BEP173Height:               math.MaxInt64,
 		FixDoubleSignChainIdHeight: math.MaxInt64,
+		OrderIDVersioningHeight:    math.MaxInt64,
+		TokenTransferMemoHeight:    math.MaxInt64,
+		OrderParamCodesHeight:      math.MaxInt64,
+		TokenTransferFeeHeight:     math.MaxInt64,
+		OrderUnknownPairCodeHeight: math.MaxInt64,
+		OrderMemoHeight:            math.MaxInt64,
+		GovernedParamsHeight:       math.MaxInt64,
 		BEP82Height:                math.MaxInt64,
 		BEP84Height:                math.MaxInt64,
 		BEP87Height:                math.MaxInt64,
@@ -577,22 +785,46 @@ func defaultUpgradeConfig() *UpgradeConfig {
 }
 
 type QueryConfig struct {
-	ABCIQueryBlackList []string `mapstructure:"ABCIQueryBlackList"`
+	ABCIQueryBlackList        []string `mapstructure:"ABCIQueryBlackList"`
+	FeesByAccountLimit        int      `mapstructure:"feesByAccountLimit"`
+	OrderFillsCacheSize       int      `mapstructure:"orderFillsCacheSize"`
+	AccountTradesCacheSize    int      `mapstructure:"accountTradesCacheSize"`
+	RecentCancelsCacheSize    int      `mapstructure:"recentCancelsCacheSize"`
+	TxStatusLookbackBlocks    int      `mapstructure:"txStatusLookbackBlocks"`
+	OrderBookHistoryRetention int      `mapstructure:"orderBookHistoryRetention"`
+	MaxOrderBookDepth         int      `mapstructure:"maxOrderBookDepth"`
+	LogQueryPanics            bool     `mapstructure:"logQueryPanics"`
+	ImbalanceTrendEnabled     bool     `mapstructure:"imbalanceTrendEnabled"`
 }
 
 func defaultQueryConfig() *QueryConfig {
 	return &QueryConfig{
-		ABCIQueryBlackList: nil,
+		ABCIQueryBlackList:        nil,
+		FeesByAccountLimit:        100000,
+		OrderFillsCacheSize:       10000,
+		AccountTradesCacheSize:    10000,
+		RecentCancelsCacheSize:    10000,
+		TxStatusLookbackBlocks:    1000,
+		OrderBookHistoryRetention: 30,
+		MaxOrderBookDepth:         1000,
+		LogQueryPanics:            true,
+		ImbalanceTrendEnabled:     false,
 	}
 }
 
 type DexConfig struct {
-	BUSDSymbol string `mapstructure:"BUSDSymbol"`
+	BUSDSymbol           string   `mapstructure:"BUSDSymbol"`
+	QuerySymbolAlias     bool     `mapstructure:"querySymbolAlias"`
+	QuerySymbolAliases   []string `mapstructure:"querySymbolAliases"`
+	LogUnknownPairOrders bool     `mapstructure:"logUnknownPairOrders"`
 }
 
 func defaultGovConfig() *DexConfig {
 	return &DexConfig{
-		BUSDSymbol: "",
+		BUSDSymbol:           "",
+		QuerySymbolAlias:     false,
+		QuerySymbolAliases:   nil,
+		LogUnknownPairOrders: false,
 	}
 }
 
@@ -7,10 +7,13 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/fees"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
 	"github.com/cosmos/cosmos-sdk/x/stake"
 
 	"github.com/bnb-chain/node/app/pub"
+	bncfees "github.com/bnb-chain/node/common/fees"
 	"github.com/bnb-chain/node/common/log"
+	"github.com/bnb-chain/node/plugins/tokens"
 )
 
 func NewValAddrCache(stakeKeeper stake.Keeper) *ValAddrCache {
@@ -135,6 +138,67 @@ func distributeFeeBEP159(ctx sdk.Context, am auth.AccountKeeper, valAddrCache *V
 	return
 }
 
+// splitFees splits the fees accumulated for all the validators per the fee split params, before they are
+// distributed to the validators in the breathe block. The shares of the destinations without address are burnt.
+// Each share is moved in a cache, a share failed to move is logged and left to the validators.
+func splitFees(ctx sdk.Context, splitKeeper bncfees.SplitKeeper, bankKeeper bank.Keeper, tokenMapper tokens.Mapper) []pub.FeeSplit {
+	params := splitKeeper.GetParams(ctx)
+	if len(params.Destinations) == 0 {
+		return nil
+	}
+	accumulated := bankKeeper.GetCoins(ctx, stake.FeeForAllAccAddr)
+	if accumulated.IsZero() {
+		return nil
+	}
+
+	shares, rest := params.Split(accumulated)
+	splits := make([]pub.FeeSplit, 0, len(shares))
+	for i, share := range shares {
+		if share.IsZero() {
+			continue
+		}
+		destination := params.Destinations[i]
+		cacheCtx, write := ctx.CacheContext()
+		if err := moveFeeShare(cacheCtx, bankKeeper, tokenMapper, destination, share); err != nil {
+			ctx.Logger().Error("FeeCalculation failed to split fees", "destination", destination.Address.String(),
+				"share", share, "err", err.Error())
+			rest = rest.Plus(share)
+			continue
+		}
+		write()
+		if destination.IsBurn() {
+			splits = append(splits, pub.FeeSplit{Fee: sdk.NewFee(share, sdk.FeeForAll).String()})
+		} else {
+			splits = append(splits, pub.FeeSplit{Address: destination.Address.String(), Fee: sdk.NewFee(share, sdk.FeeForAll).String()})
+		}
+	}
+	ctx.Logger().Info("FeeCalculation splitFees", "accumulated", accumulated, "splits", splits, "rest", rest)
+	return splits
+}
+
+// moveFeeShare sends the share of the fees to the destination, or burns it if the destination has no address.
+func moveFeeShare(ctx sdk.Context, bankKeeper bank.Keeper, tokenMapper tokens.Mapper, destination bncfees.SplitDestination, share sdk.Coins) error {
+	if !destination.IsBurn() {
+		if _, err := bankKeeper.SendCoins(ctx, stake.FeeForAllAccAddr, destination.Address, share); err != nil {
+			return err
+		}
+		return nil
+	}
+	if _, _, err := bankKeeper.SubtractCoins(ctx, stake.FeeForAllAccAddr, share); err != nil {
+		return err
+	}
+	for _, coin := range share {
+		token, err := tokenMapper.GetToken(ctx, coin.Denom)
+		if err != nil {
+			return err
+		}
+		if err := tokenMapper.UpdateTotalSupply(ctx, coin.Denom, token.GetTotalSupply().ToInt64()-coin.Amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func distributeFee(ctx sdk.Context, am auth.AccountKeeper, valAddrCache *ValAddrCache, publishBlockFee bool) (blockFee pub.BlockFee) {
 	fee := fees.Pool.BlockFees()
 	blockFee = pub.BlockFee{Height: ctx.BlockHeader().Height}
@@ -87,7 +87,7 @@ func TestNoFeeDistribution(t *testing.T) {
 
 	blockFee := distributeFee(ctx, am, valAddrCache, true)
 	fees.Pool.Clear()
-	require.Equal(t, pub.BlockFee{0, "", nil}, blockFee)
+	require.Equal(t, pub.BlockFee{0, "", nil, nil, nil}, blockFee)
 	checkBalance(t, ctx, am, valAddrCache, []int64{100, 100, 100, 100})
 }
 
@@ -97,7 +97,7 @@ func TestFeeDistribution2Proposer(t *testing.T) {
 	fees.Pool.AddAndCommitFee("DIST", sdk.NewFee(sdk.Coins{sdk.NewCoin(types.NativeTokenSymbol, 10)}, sdk.FeeForProposer))
 	blockFee := distributeFee(ctx, am, valAddrCache, true)
 	fees.Pool.Clear()
-	require.Equal(t, pub.BlockFee{0, "BNB:10", []string{string(proposerAcc.GetAddress())}}, blockFee)
+	require.Equal(t, pub.BlockFee{0, "BNB:10", []string{string(proposerAcc.GetAddress())}, nil, nil}, blockFee)
 	checkBalance(t, ctx, am, valAddrCache, []int64{110, 100, 100, 100})
 }
 
@@ -109,14 +109,14 @@ func TestFeeDistribution2AllValidators(t *testing.T) {
 	blockFee := distributeFee(ctx, am, valAddrCache, true)
 	// Notice: clean the pool after distributeFee
 	fees.Pool.Clear()
-	require.Equal(t, pub.BlockFee{0, "BNB:40", []string{string(proposerAcc.GetAddress()), string(valAcc1.GetAddress()), string(valAcc2.GetAddress()), string(valAcc3.GetAddress())}}, blockFee)
+	require.Equal(t, pub.BlockFee{0, "BNB:40", []string{string(proposerAcc.GetAddress()), string(valAcc1.GetAddress()), string(valAcc2.GetAddress()), string(valAcc3.GetAddress())}, nil, nil}, blockFee)
 	checkBalance(t, ctx, am, valAddrCache, []int64{110, 110, 110, 110})
 
 	// cannot be divided evenly
 	fees.Pool.AddAndCommitFee("DIST", sdk.NewFee(sdk.Coins{sdk.NewCoin(types.NativeTokenSymbol, 50)}, sdk.FeeForAll))
 	blockFee = distributeFee(ctx, am, valAddrCache, true)
 	fees.Pool.Clear()
-	require.Equal(t, pub.BlockFee{0, "BNB:50", []string{string(proposerAcc.GetAddress()), string(valAcc1.GetAddress()), string(valAcc2.GetAddress()), string(valAcc3.GetAddress())}}, blockFee)
+	require.Equal(t, pub.BlockFee{0, "BNB:50", []string{string(proposerAcc.GetAddress()), string(valAcc1.GetAddress()), string(valAcc2.GetAddress()), string(valAcc3.GetAddress())}, nil, nil}, blockFee)
 	checkBalance(t, ctx, am, valAddrCache, []int64{124, 122, 122, 122})
 }
 
@@ -287,6 +287,13 @@ func ApplyToBreathBlocks(t *testing.T, app *BinanceChain, ctx sdk.Context, breat
 }
 
 func TestBEP159Distribution(t *testing.T) {
+	// the upgrade heights of the test are not kept for the apps of the other tests
+	upgradeConfig, baseConfig := *ServerContext.UpgradeConfig, *ServerContext.BaseConfig
+	stateSyncReactor := ServerContext.Config.StateSyncReactor
+	defer func() {
+		*ServerContext.UpgradeConfig, *ServerContext.BaseConfig = upgradeConfig, baseConfig
+		ServerContext.Config.StateSyncReactor = stateSyncReactor
+	}()
 	app, ctx, accs := setupTestForBEP159Test()
 	// check genesis validators
 	validators := app.stakeKeeper.GetAllValidators(ctx)
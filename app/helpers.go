@@ -87,6 +87,24 @@ func newLogger(ctx *config.BinanceChainContext) log.Logger {
 	}
 }
 
+// newAuditLogWriter opens the audit log of the rejected txs for appending, it's relative to the log file root
+// like the log file.
+func newAuditLogWriter(ctx *config.BinanceChainContext) *os.File {
+	logFileRoot := ctx.LogConfig.LogFileRoot
+	if logFileRoot == "" {
+		logFileRoot = ctx.Config.RootDir
+	}
+	auditLogPath := path.Join(logFileRoot, ctx.LogConfig.AuditLogPath)
+	if err := cmn.EnsureDir(path.Dir(auditLogPath), 0755); err != nil {
+		panic(fmt.Sprintf("create audit log dir failed, err=%s", err.Error()))
+	}
+	file, err := os.OpenFile(auditLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		panic(fmt.Sprintf("open audit log failed, err=%s", err.Error()))
+	}
+	return file
+}
+
 // PersistentPreRunEFn returns a PersistentPreRunE function for cobra
 // that initailizes the passed in context with a properly configured
 // logger and config object
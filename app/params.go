@@ -0,0 +1,62 @@
+package app
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramTypes "github.com/cosmos/cosmos-sdk/x/paramHub/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	bncfees "github.com/bnb-chain/node/common/fees"
+	"github.com/bnb-chain/node/common/types"
+	dextypes "github.com/bnb-chain/node/plugins/dex/types"
+	tokenStore "github.com/bnb-chain/node/plugins/tokens/store"
+)
+
+const paramsAbciQueryPrefix = "params"
+
+// ChainParams are the parameters of the chain in effect at the latest committed block, which originate from the
+// genesis or are changed by governance since then. It's returned by the `params` query in amino json, e.g.
+//
+//	{
+//	  "height": "100",
+//	  "fees": [{"type": "params/FixedFeeParams", "value": {"msg_type": "submit_proposal", ...}}, ...],
+//	  "dex": {"matching_paused": false, "max_orders_per_account_per_block": "0", ...},
+//	  "tokens": {"max_symbol_length": "8", "symbol_charset": "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789", ...},
+//	  "fee_split": {"destinations": [{"address": "bnb1...", "ratio": "1000"}]}
+//	}
+//
+// The fields are only appended, so the existing ones stay stable.
+type ChainParams struct {
+	Height   int64                  `json:"height"`
+	Fees     []paramTypes.FeeParam  `json:"fees"`      // see the `param/fees` query for the details
+	Dex      dextypes.DexParams     `json:"dex"`       // see DexParams
+	Tokens   tokenStore.TokenParams `json:"tokens"`    // see TokenParams
+	FeeSplit bncfees.SplitParams    `json:"fee_split"` // see SplitParams
+}
+
+// ParamsHandler serves the `params` query from the committed state, rather than the check state which may
+// be ahead of it.
+func (app *BinanceChain) ParamsHandler(chainApp types.ChainApp, req abci.RequestQuery, path []string) *abci.ResponseQuery {
+	if len(path) != 1 {
+		res := sdk.ErrUnknownRequest("params query takes no argument").QueryResult()
+		return &res
+	}
+	height := app.LastBlockHeight()
+	ctx := sdk.NewContext(app.GetCommitMultiStore().CacheMultiStore(), abci.Header{Height: height},
+		sdk.RunTxModeCheck, app.Logger)
+	params := ChainParams{
+		Height:   height,
+		Fees:     app.ParamHub.GetFeeParams(ctx),
+		Dex:      app.DexKeeper.GetParams(ctx),
+		Tokens:   app.TokenMapper.GetParams(ctx),
+		FeeSplit: app.feeSplitKeeper.GetParams(ctx),
+	}
+	bz, err := app.Codec.MarshalJSON(params)
+	if err != nil {
+		res := sdk.ErrInternal(err.Error()).QueryResult()
+		return &res
+	}
+	return &abci.ResponseQuery{
+		Code:  uint32(sdk.ABCICodeOK),
+		Value: bz,
+	}
+}
@@ -0,0 +1,51 @@
+package pub
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+)
+
+const AbciQueryPrefix = "pub"
+
+// CreateAbciQueryHandler creates the handler of the publication queries, enabled tells whether any publication
+// is configured on this node.
+func CreateAbciQueryHandler(enabled bool) types.AbciQueryHandler {
+	return func(app types.ChainApp, req abci.RequestQuery, path []string) (res *abci.ResponseQuery) {
+		// expects at least two query path segments.
+		if path[0] != AbciQueryPrefix || len(path) < 2 {
+			return nil
+		}
+		switch path[1] {
+		case "status": // args: ["pub", "status"]
+			if !enabled {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "publication is disabled on this node",
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(GetStatus())
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		default:
+			return &abci.ResponseQuery{
+				Code: uint32(sdk.ABCICodeOK),
+				Info: fmt.Sprintf(
+					"Unknown `%s` query path: %v",
+					AbciQueryPrefix, path),
+			}
+		}
+	}
+}
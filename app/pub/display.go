@@ -0,0 +1,60 @@
+package pub
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const qtyDecimals = 8
+
+// symbol -> number of the decimals of the published quantities of the pair
+var qtyDisplayPrecisions map[string]int
+
+// SetQtyDisplayPrecisions sets the precisions the quantities of the trades and order book levels are rounded to
+// in the published messages, in the format of `<symbol>:<decimals>,...`, e.g. `XYZ-000_BNB:2`. It's for display only,
+// the consumers needing the exact quantities should use the raw values from the queries instead.
+func SetQtyDisplayPrecisions(spec string) error {
+	precisions := make(map[string]int)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid qty display precision %q, expected <symbol>:<decimals>", item)
+		}
+		decimals, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || decimals < 0 || decimals > qtyDecimals {
+			return fmt.Errorf("invalid qty display precision %q, decimals should be in [0, %d]", item, qtyDecimals)
+		}
+		precisions[strings.ToUpper(strings.TrimSpace(parts[0]))] = decimals
+	}
+	qtyDisplayPrecisions = precisions
+	return nil
+}
+
+// displayQty rounds the quantity half up to the display precision of the pair. A positive quantity is never
+// rounded to 0, which would read as a removed price level, but to the smallest displayed unit instead.
+func displayQty(symbol string, qty int64) int64 {
+	decimals, ok := qtyDisplayPrecisions[symbol]
+	if !ok || qty <= 0 {
+		return qty
+	}
+	unit := int64(math.Pow10(qtyDecimals - decimals))
+	if unit == 1 {
+		return qty
+	}
+	var rounded int64
+	if qty > math.MaxInt64-unit/2 {
+		rounded = qty / unit * unit
+	} else {
+		rounded = (qty + unit/2) / unit * unit
+	}
+	if rounded == 0 {
+		return unit
+	}
+	return rounded
+}
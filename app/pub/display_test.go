@@ -0,0 +1,29 @@
+package pub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplayQty(t *testing.T) {
+	require.Error(t, SetQtyDisplayPrecisions("XYZ-000_BNB"))
+	require.Error(t, SetQtyDisplayPrecisions("XYZ-000_BNB:9"))
+	require.NoError(t, SetQtyDisplayPrecisions("XYZ-000_BNB:2, ABC-000_BNB:8"))
+	defer SetQtyDisplayPrecisions("")
+
+	require.Equal(t, int64(123000000), displayQty("XYZ-000_BNB", 123456789))
+	require.Equal(t, int64(124000000), displayQty("XYZ-000_BNB", 123500000))
+	require.Equal(t, int64(1000000), displayQty("XYZ-000_BNB", 1))
+	require.Equal(t, int64(0), displayQty("XYZ-000_BNB", 0))
+	require.Equal(t, int64(123456789), displayQty("ABC-000_BNB", 123456789))
+	require.Equal(t, int64(123456789), displayQty("ZCB-000_BNB", 123456789))
+
+	trade := Trade{Symbol: "XYZ-000_BNB", Qty: 123456789}
+	require.Equal(t, int64(123000000), trade.toNativeMap()["qty"])
+	require.Equal(t, int64(123456789), trade.Qty)
+	delta := OrderBookDelta{"XYZ-000_BNB", []PriceLevel{{1e8, 123456789}}, []PriceLevel{{2e8, 1}}}
+	native := delta.ToNativeMap()
+	require.Equal(t, int64(123000000), native["buys"].([]map[string]interface{})[0]["lastQty"])
+	require.Equal(t, int64(1000000), native["sells"].([]map[string]interface{})[0]["lastQty"])
+}
@@ -18,11 +18,15 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/stake"
 
 	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/account/scripts"
 	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
+	"github.com/bnb-chain/node/plugins/dex/utils"
 	"github.com/bnb-chain/node/plugins/tokens/burn"
 	"github.com/bnb-chain/node/plugins/tokens/freeze"
 	"github.com/bnb-chain/node/plugins/tokens/issue"
 	"github.com/bnb-chain/node/plugins/tokens/seturi"
+	"github.com/bnb-chain/node/plugins/tokens/transferfee"
+	"github.com/bnb-chain/node/plugins/tokens/transfermemo"
 )
 
 func GetTradeAndOrdersRelatedAccounts(tradesToPublish []*Trade, orderChanges orderPkg.OrderChanges, orderInfosForPublish orderPkg.OrderInfoForPublish) []string {
@@ -137,6 +141,10 @@ func GetBlockPublished(pool *sdk.Pool, header abci.Header, blockHash []byte) *Bl
 			txAsset = msg.Symbol
 		case seturi.SetURIMsg:
 			txAsset = msg.Symbol
+		case transfermemo.SetTransferMemoRequiredMsg:
+			txAsset = msg.Symbol
+		case transferfee.SetTransferFeeMsg:
+			txAsset = msg.Symbol
 		}
 		transactionsToPublish = append(transactionsToPublish, Transaction{
 			TxHash:    txhash,
@@ -223,6 +231,10 @@ func GetAccountBalances(mapper auth.AccountKeeper, ctx sdk.Context, accSlices ..
 			if _, ok := res[addrBytesStr]; !ok {
 				addr := sdk.AccAddress([]byte(addrBytesStr))
 				if acc, ok := mapper.GetAccount(ctx, addr).(types.NamedAccount); ok {
+					if scripts.IsPublicationOptedOut(acc.GetFlags()) {
+						// only the publication is skipped, the account is still settled on chain
+						continue
+					}
 					assetsMap := make(map[string]*AssetBalance)
 					// TODO(#66): set the length to be the total coins this account owned
 					assets := make([]*AssetBalance, 0, 10)
@@ -311,7 +323,8 @@ func extractTradesToPublish(dexKeeper *orderPkg.DexKeeper, tradeHeight int64) (t
 	tradesToPublish = make([]*Trade, 0, 32)
 	tradeIdx := 0
 
-	for symbol := range dexKeeper.GetEngines() {
+	// the trades are numbered in the same order as the ones of the dex queries
+	for _, symbol := range dexKeeper.GetMatchedSymbols(tradeHeight) {
 		matchEngTrades, _ := dexKeeper.GetLastTrades(tradeHeight, symbol)
 		for _, trade := range matchEngTrades {
 			var ssinglefee string
@@ -326,7 +339,7 @@ func extractTradesToPublish(dexKeeper *orderPkg.DexKeeper, tradeHeight int64) (t
 			}
 
 			t := &Trade{
-				Id:         fmt.Sprintf("%d-%d", tradeHeight, tradeIdx),
+				Id:         orderPkg.TradeId(tradeHeight, tradeIdx),
 				Symbol:     symbol,
 				Sid:        trade.Sid,
 				Bid:        trade.Bid,
@@ -335,6 +348,8 @@ func extractTradesToPublish(dexKeeper *orderPkg.DexKeeper, tradeHeight int64) (t
 				SSingleFee: ssinglefee,
 				BSingleFee: bsinglefee,
 				TickType:   int(trade.TickType),
+				SFeeExempt: orderPkg.IsExemptTradeFee(trade.SellerFee),
+				BFeeExempt: orderPkg.IsExemptTradeFee(trade.BuyerFee),
 			}
 			tradeIdx += 1
 			tradesToPublish = append(tradesToPublish, t)
@@ -374,6 +389,12 @@ func DelistTradingPairForPublish(ctx sdk.Context, dexKeeper *orderPkg.DexKeeper,
 				Fee:     tran.Fee.String(),
 				Symbol:  tran.Symbol,
 			}
+		} else if tran.IsDelisted() {
+			expireHolderCh <- orderPkg.ExpireHolder{
+				OrderId: tran.Oid,
+				Reason:  orderPkg.Delisted,
+				Symbol:  tran.Symbol,
+			}
 		}
 	}
 	dexKeeper.DelistTradingPair(ctx, symbol, collectorForExpires)
@@ -381,6 +402,25 @@ func DelistTradingPairForPublish(ctx sdk.Context, dexKeeper *orderPkg.DexKeeper,
 	wg.Wait()
 }
 
+func ExpireDisabledTokenOrdersForPublish(ctx sdk.Context, dexKeeper *orderPkg.DexKeeper) {
+	expireHolderCh := make(chan orderPkg.ExpireHolder, TransferCollectionChannelSize)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go updateExpireFeeForPublish(dexKeeper, &wg, expireHolderCh)
+	var collectorForExpires = func(tran orderPkg.Transfer) {
+		if tran.IsTokenDisabled() {
+			expireHolderCh <- orderPkg.ExpireHolder{
+				OrderId: tran.Oid,
+				Reason:  orderPkg.TokenDisabled,
+				Symbol:  tran.Symbol,
+			}
+		}
+	}
+	dexKeeper.ExpireDisabledTokenOrders(ctx, collectorForExpires)
+	close(expireHolderCh)
+	wg.Wait()
+}
+
 func CollectProposalsForPublish(passed, failed []gov.SimpleProposal) (Proposals, SideProposals) {
 	ps := make([]*Proposal, 0)
 	sidePs := make([]*SideProposal, 0)
@@ -401,6 +441,18 @@ func CollectProposalsForPublish(passed, failed []gov.SimpleProposal) (Proposals,
 	return Proposals{len(ps), ps}, SideProposals{NumOfMsgs: len(sidePs), Proposals: sidePs}
 }
 
+// CollectFeeConversionsForPublish collects the dust fee conversions of the breathe block.
+func CollectFeeConversionsForPublish(conversions []orderPkg.DustFeeConversion) []FeeConversion {
+	if len(conversions) == 0 {
+		return nil
+	}
+	res := make([]FeeConversion, len(conversions))
+	for i, c := range conversions {
+		res[i] = FeeConversion{c.Symbol, c.OrderId, c.Quantity, c.Sold, c.Received}
+	}
+	return res
+}
+
 func CollectStakeUpdatesForPublish(unbondingDelegations []stake.UnbondingDelegation) StakeUpdates {
 	length := len(unbondingDelegations)
 	completedUnbondingDelegations := make([]*CompletedUnbondingDelegation, 0, length)
@@ -423,10 +475,13 @@ func updateExpireFeeForPublish(
 	}
 }
 
-// collect all changed books according to published order status
+// collect all changed books according to published order status.
+// With netDelta, the levels touched in the block but with the quantity unchanged at the end of it are left out,
+// e.g. the level of an order placed and fully filled in the block. Otherwise all the touched levels are kept.
 func filterChangedOrderBooksByOrders(
 	ordersToPublish []*Order,
-	latestPriceLevels orderPkg.ChangedPriceLevelsMap) orderPkg.ChangedPriceLevelsMap {
+	latestPriceLevels orderPkg.ChangedPriceLevelsMap,
+	netDelta bool) orderPkg.ChangedPriceLevelsMap {
 	var res = make(orderPkg.ChangedPriceLevelsMap)
 	// map from symbol -> price -> qty diff in this block
 	var buyQtyDiff = make(map[string]map[int64]int64)
@@ -464,6 +519,10 @@ func filterChangedOrderBooksByOrders(
 		}
 	}
 
+	if !netDelta {
+		return res
+	}
+
 	// filter touched but qty actually not changed price levels
 	for symbol, priceToQty := range buyQtyDiff {
 		for price, qty := range priceToQty {
@@ -517,6 +576,8 @@ func tradeToOrder(t *Trade, o *orderPkg.OrderInfo, timestamp int64, feeHolder or
 		orderPkg.NEW,
 		o.TxHash,
 		"",
+		remainingLocked(o, status),
+		o.Memo,
 	}
 	if o.Side == orderPkg.Side.BUY {
 		res.SingleFee = t.BSingleFee
@@ -532,6 +593,18 @@ func tradeToOrder(t *Trade, o *orderPkg.OrderInfo, timestamp int64, feeHolder or
 	return res
 }
 
+// remainingLocked is the amount still locked by the order in the account, in the quote asset for buy orders and
+// the base asset for sell orders. Nothing is locked once the order is closed, or if it failed to be placed.
+func remainingLocked(o *orderPkg.OrderInfo, status orderPkg.ChangeType) int64 {
+	if !status.IsOpen() || status == orderPkg.FailedBlocking {
+		return 0
+	}
+	if o.Side == orderPkg.Side.BUY {
+		return utils.CalBigNotionalInt64(o.Price, o.Quantity) - utils.CalBigNotionalInt64(o.Price, o.CumQty)
+	}
+	return o.Quantity - o.CumQty
+}
+
 // we collect OrderPart here to make matcheng module independent
 func collectOrdersToPublish(
 	trades []*Trade,
@@ -621,6 +694,7 @@ func collectOrders(orderChanges orderPkg.OrderChanges, orderInfos orderPkg.Order
 				0, 0, orderInfo.CumQty, "",
 				orderInfo.CreatedTimestamp, timestamp, orderInfo.TimeInForce,
 				orderPkg.NEW, orderInfo.TxHash, o.SingleFee,
+				remainingLocked(orderInfo, o.Tpe), orderInfo.Memo,
 			}
 
 			if o.Tpe.IsOpen() {
@@ -694,3 +768,43 @@ func transferOutputsToPublish(outputs []bank.Output) []Output {
 	}
 	return transOutputs
 }
+
+// CollectReplayedBlockForPublish collects the market data of a block replayed by DexKeeper.ReplayBlockForPublish.
+// Only the trades, order updates and order books are reconstructed, see the limitations of the replay.
+func CollectReplayedBlockForPublish(dexKeeper *orderPkg.DexKeeper, ctx sdk.Context, height, timestamp int64,
+	isBreatheBlock bool) BlockInfoToPublish {
+	var latestPriceLevels orderPkg.ChangedPriceLevelsMap
+	if Cfg.PublishOrderBook {
+		latestPriceLevels = dexKeeper.GetOrderBooks(MaxOrderBookLevel)
+	}
+	var bookSnapshots orderPkg.ChangedPriceLevelsMap
+	if Cfg.IsOrderBookSnapshotHeight(height) {
+		bookSnapshots = dexKeeper.GetOrderBookSnapshots(MaxOrderBookLevel, Cfg.OrderBookSnapshotDepth)
+	}
+	return NewBlockInfoToPublish(
+		height,
+		timestamp,
+		extractTradesToPublish(dexKeeper, height),
+		&Proposals{},
+		&SideProposals{},
+		&StakeUpdates{},
+		dexKeeper.GetAllOrderChanges(),
+		dexKeeper.GetAllOrderInfosForPub(),
+		nil,
+		latestPriceLevels,
+		BlockFee{},
+		dexKeeper.RoundOrderFees,
+		nil,
+		nil,
+		dexKeeper.IsMatchingPaused(ctx, height),
+		dexKeeper.GetScheduledHaltEvent(ctx, height),
+		nil,
+		nil,
+		nil,
+		nil,
+		isBreatheBlock,
+		false,
+		0,
+		nil,
+		bookSnapshots)
+}
@@ -1,6 +1,7 @@
 package pub
 
 import (
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -114,7 +115,7 @@ func TestKeeper_AddOrder(t *testing.T) {
 func TestKeeper_IOCExpireWithFee(t *testing.T) {
 	assert, require := setupKeeperTest(t)
 
-	msg := orderPkg.NewOrderMsg{buyer, "1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 102000, 3000000, orderPkg.TimeInForce.IOC}
+	msg := orderPkg.NewOrderMsg{buyer, "1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 102000, 3000000, orderPkg.TimeInForce.IOC, ""}
 	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "08E19B16880CF70D59DDD996E3D75C66CD0405DE", 0}, false)
 
 	require.Len(keeper.GetOrderChanges(orderPkg.PairType.BEP2), 1)
@@ -142,7 +143,7 @@ func TestKeeper_IOCExpireWithFee(t *testing.T) {
 func TestKeeper_ExpireWithFee(t *testing.T) {
 	assert, require := setupKeeperTest(t)
 
-	msg := orderPkg.NewOrderMsg{buyer, "1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 102000, 3000000, orderPkg.TimeInForce.GTE}
+	msg := orderPkg.NewOrderMsg{buyer, "1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 102000, 3000000, orderPkg.TimeInForce.GTE, ""}
 	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "08E19B16880CF70D59DDD996E3D75C66CD0405DE", 0}, false)
 
 	require.Len(keeper.GetOrderChanges(orderPkg.PairType.BEP2), 1)
@@ -167,7 +168,7 @@ func TestKeeper_ExpireWithFee(t *testing.T) {
 func TestKeeper_DelistWithFee(t *testing.T) {
 	assert, require := setupKeeperTest(t)
 
-	msg := orderPkg.NewOrderMsg{buyer, "1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 102000, 3000000, orderPkg.TimeInForce.GTE}
+	msg := orderPkg.NewOrderMsg{buyer, "1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 102000, 3000000, orderPkg.TimeInForce.GTE, ""}
 	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "08E19B16880CF70D59DDD996E3D75C66CD0405DE", 0}, false)
 
 	require.Len(keeper.GetOrderChanges(orderPkg.PairType.BEP2), 1)
@@ -192,9 +193,9 @@ func TestKeeper_DelistWithFee(t *testing.T) {
 func Test_IOCPartialExpire(t *testing.T) {
 	assert, require := setupKeeperTest(t)
 
-	msg := orderPkg.NewOrderMsg{buyer, "b-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 100000000, 300000000, orderPkg.TimeInForce.IOC}
+	msg := orderPkg.NewOrderMsg{buyer, "b-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 100000000, 300000000, orderPkg.TimeInForce.IOC, ""}
 	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "", 0}, false)
-	msg2 := orderPkg.NewOrderMsg{seller, "s-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 100000000, orderPkg.TimeInForce.GTE}
+	msg2 := orderPkg.NewOrderMsg{seller, "s-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 100000000, orderPkg.TimeInForce.GTE, ""}
 	keeper.AddOrder(orderPkg.OrderInfo{msg2, 42, 100, 42, 100, 0, "", 0}, false)
 
 	require.Len(keeper.GetOrderChanges(orderPkg.PairType.BEP2), 2)
@@ -232,9 +233,9 @@ func Test_IOCPartialExpire(t *testing.T) {
 func Test_GTEPartialExpire(t *testing.T) {
 	assert, require := setupKeeperTest(t)
 
-	msg := orderPkg.NewOrderMsg{buyer, "b-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 100000000, 100000000, orderPkg.TimeInForce.GTE}
+	msg := orderPkg.NewOrderMsg{buyer, "b-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 100000000, 100000000, orderPkg.TimeInForce.GTE, ""}
 	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "", 0}, false)
-	msg2 := orderPkg.NewOrderMsg{seller, "s-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 300000000, orderPkg.TimeInForce.GTE}
+	msg2 := orderPkg.NewOrderMsg{seller, "s-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 300000000, orderPkg.TimeInForce.GTE, ""}
 	keeper.AddOrder(orderPkg.OrderInfo{msg2, 42, 100, 42, 100, 0, "", 0}, false)
 
 	require.Len(keeper.GetOrderChanges(orderPkg.PairType.BEP2), 2)
@@ -274,14 +275,35 @@ func Test_GTEPartialExpire(t *testing.T) {
 	assert.Equal(orderPkg.Expired, orderChange2.Tpe)
 }
 
+func Test_TradeAddresses(t *testing.T) {
+	assert, require := setupKeeperTest(t)
+
+	msg := orderPkg.NewOrderMsg{buyer, "b-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 100000000, 100000000, orderPkg.TimeInForce.GTE, ""}
+	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "", 0}, false)
+	msg2 := orderPkg.NewOrderMsg{seller, "s-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 300000000, orderPkg.TimeInForce.GTE, ""}
+	keeper.AddOrder(orderPkg.OrderInfo{msg2, 42, 100, 42, 100, 0, "", 0}, false)
+
+	trades := MatchAndAllocateAllForPublish(keeper, ctx, false)
+	require.Len(trades, 1)
+	assert.Equal("b-1", trades[0].Bid)
+	assert.Equal("s-1", trades[0].Sid)
+	// the addresses are resolved along with the orders of the trades, which are published ahead of the trades
+	collectOrdersToPublish(trades, keeper.GetAllOrderChanges(), keeper.GetAllOrderInfosForPub(), orderPkg.FeeHolder{}, 100)
+	assert.Equal(string(buyer.Bytes()), trades[0].BAddr)
+	assert.Equal(string(seller.Bytes()), trades[0].SAddr)
+	native := trades[0].toNativeMap()
+	assert.Equal(buyer.String(), native["baddr"])
+	assert.Equal(seller.String(), native["saddr"])
+}
+
 func Test_OneBuyVsTwoSell(t *testing.T) {
 	assert, require := setupKeeperTest(t)
 
-	msg := orderPkg.NewOrderMsg{buyer, "b-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 100000000, 300000000, orderPkg.TimeInForce.GTE}
+	msg := orderPkg.NewOrderMsg{buyer, "b-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.BUY, 100000000, 300000000, orderPkg.TimeInForce.GTE, ""}
 	keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "", 0}, false)
-	msg2 := orderPkg.NewOrderMsg{seller, "s-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 100000000, orderPkg.TimeInForce.GTE}
+	msg2 := orderPkg.NewOrderMsg{seller, "s-1", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 100000000, orderPkg.TimeInForce.GTE, ""}
 	keeper.AddOrder(orderPkg.OrderInfo{msg2, 42, 100, 42, 100, 0, "", 0}, false)
-	msg3 := orderPkg.NewOrderMsg{seller, "s-2", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 200000000, orderPkg.TimeInForce.GTE}
+	msg3 := orderPkg.NewOrderMsg{seller, "s-2", "XYZ-000_BNB", orderPkg.OrderType.LIMIT, orderPkg.Side.SELL, 100000000, 200000000, orderPkg.TimeInForce.GTE, ""}
 	keeper.AddOrder(orderPkg.OrderInfo{msg3, 42, 100, 42, 100, 0, "", 0}, false)
 
 	require.Len(keeper.GetOrderChanges(orderPkg.PairType.BEP2), 3)
@@ -325,3 +347,62 @@ func prepareExpire(height int64) time.Time {
 	keeper.MarkBreatheBlock(ctx, height, breathTime)
 	return breathTime.AddDate(0, 0, 3)
 }
+
+func Test_OrderBookNetDelta(t *testing.T) {
+	symbol := "XYZ-000_BNB"
+	latest := orderPkg.ChangedPriceLevelsMap{
+		symbol: {Buys: map[int64]int64{100: 6, 99: 3}, Sells: map[int64]int64{}},
+	}
+	orders := []*Order{
+		// placed at 100, the level is partially filled by another order
+		{Symbol: symbol, Status: orderPkg.Ack, OrderId: "1", Side: orderPkg.Side.BUY, Price: 100, Qty: 2},
+		{Symbol: symbol, Status: orderPkg.PartialFill, OrderId: "0", Side: orderPkg.Side.BUY, Price: 100, Qty: 5, LastExecutedQty: 1, CumQty: 1},
+		// placed and fully filled at 99
+		{Symbol: symbol, Status: orderPkg.Ack, OrderId: "2", Side: orderPkg.Side.BUY, Price: 99, Qty: 4},
+		{Symbol: symbol, Status: orderPkg.FullyFill, OrderId: "2", Side: orderPkg.Side.BUY, Price: 99, Qty: 4, LastExecutedQty: 4, CumQty: 4},
+	}
+
+	net := filterChangedOrderBooksByOrders(orders, latest, true)
+	require.Len(t, net, 1)
+	require.Equal(t, map[int64]int64{100: 6}, net[symbol].Buys)
+	require.Len(t, net[symbol].Sells, 0)
+
+	verbose := filterChangedOrderBooksByOrders(orders, latest, false)
+	require.Len(t, verbose, 1)
+	require.Equal(t, map[int64]int64{100: 6, 99: 3}, verbose[symbol].Buys)
+}
+
+func Test_OrderBookSnapshots(t *testing.T) {
+	assert, require := setupKeeperTest(t)
+
+	for i, price := range []int64{100000, 102000, 101000} {
+		msg := orderPkg.NewNewOrderMsg(buyer, fmt.Sprintf("b-%d", i), orderPkg.Side.BUY, "XYZ-000_BNB", price, int64(i+1)*1000000)
+		keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "", 0}, false)
+	}
+	for i, price := range []int64{105000, 103000, 104000} {
+		msg := orderPkg.NewNewOrderMsg(seller, fmt.Sprintf("s-%d", i), orderPkg.Side.SELL, "XYZ-000_BNB", price, int64(i+1)*1000000)
+		keeper.AddOrder(orderPkg.OrderInfo{msg, 42, 100, 42, 100, 0, "", 0}, false)
+	}
+
+	// the snapshot depth is deeper than the published levels
+	publisher := NewMockMarketDataPublisher()
+	publishOrderBookSnapshots(publisher, 42, 100, keeper.GetOrderBookSnapshots(1, 2))
+	require.Len(publisher.BookSnapshotsPublished, 1)
+	snapshots := publisher.BookSnapshotsPublished[0]
+	assert.Equal(1, snapshots.NumOfMsgs)
+	// the best levels first
+	assert.Equal([]OrderBookDelta{{
+		"XYZ-000_BNB",
+		[]PriceLevel{{102000, 2000000}, {101000, 3000000}},
+		[]PriceLevel{{103000, 2000000}, {104000, 3000000}},
+	}}, snapshots.Books)
+
+	// the published levels are deeper than the snapshot depth
+	publishOrderBookSnapshots(publisher, 43, 100, keeper.GetOrderBookSnapshots(MaxOrderBookLevel, 2))
+	require.Len(publisher.BookSnapshotsPublished, 2)
+	assert.Equal([]OrderBookDelta{{
+		"XYZ-000_BNB",
+		[]PriceLevel{{102000, 2000000}, {101000, 3000000}, {100000, 1000000}},
+		[]PriceLevel{{103000, 2000000}, {104000, 3000000}, {105000, 1000000}},
+	}}, publisher.BookSnapshotsPublished[1].Books)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
@@ -27,6 +28,11 @@ const (
 	mirrorTpe
 	sideProposalType
 	breatheBlockTpe
+	orderRejectionsTpe
+	orderAcksTpe
+	tradeAuditsTpe
+	blockMetricsTpe
+	bookSnapshotsTpe
 )
 
 var (
@@ -64,6 +70,16 @@ func (this msgType) String() string {
 		return "SideProposal"
 	case breatheBlockTpe:
 		return "BreatheBlock"
+	case orderRejectionsTpe:
+		return "OrderRejections"
+	case orderAcksTpe:
+		return "OrderAcks"
+	case tradeAuditsTpe:
+		return "TradeAudits"
+	case blockMetricsTpe:
+		return "BlockMetrics"
+	case bookSnapshotsTpe:
+		return "BookSnapshots"
 	default:
 		return "Unknown"
 	}
@@ -74,10 +90,10 @@ func (this msgType) String() string {
 // figure out which version of writer schema to use.
 // This allows consumers be deployed independently (in advance) with publisher
 var latestSchemaVersions = map[msgType]int{
-	accountsTpe:        1,
+	accountsTpe:        2,
 	booksTpe:           0,
-	executionResultTpe: 1,
-	blockFeeTpe:        0,
+	executionResultTpe: 9,
+	blockFeeTpe:        2,
 	transferTpe:        1,
 	blockTpe:           0,
 	stakingTpe:         0,
@@ -86,7 +102,12 @@ var latestSchemaVersions = map[msgType]int{
 	crossTransferTpe:   0,
 	mirrorTpe:          0,
 	sideProposalType:   0,
-	breatheBlockTpe:    0,
+	breatheBlockTpe:    1,
+	orderRejectionsTpe: 0,
+	orderAcksTpe:       0,
+	tradeAuditsTpe:     0,
+	blockMetricsTpe:    0,
+	bookSnapshotsTpe:   0,
 }
 
 type AvroOrJsonMsg interface {
@@ -119,13 +140,19 @@ type EssMsg interface {
 }
 
 type ExecutionResults struct {
-	Height       int64
-	Timestamp    int64 // milli seconds since Epoch
-	NumOfMsgs    int   // number of individual messages we published, consumer can verify messages they received against this field to make sure they does not miss messages
-	Trades       trades
-	Orders       Orders
-	Proposals    Proposals
-	StakeUpdates StakeUpdates
+	Height         int64
+	Timestamp      int64 // milli seconds since Epoch
+	NumOfMsgs      int   // number of individual messages we published, consumer can verify messages they received against this field to make sure they does not miss messages
+	Trades         trades
+	Orders         Orders
+	Proposals      Proposals
+	StakeUpdates   StakeUpdates
+	MatchingPaused bool            // whether the matching is paused by governance or the halt schedule in this block
+	IsBreatheBlock bool            // whether the block is a breathe block, in which the stale orders are expired
+	HaltEvent      string          // HaltStarted or HaltEnded if the halt schedule takes effect in this block
+	SessionEvents  []*SessionEvent // trading sessions of the pairs opened or closed in this block
+	// pairs whose base or quote token is disabled or enabled from trading in this block
+	TokenTradingEvents []*TokenTradingEvent
 }
 
 func (msg *ExecutionResults) String() string {
@@ -137,6 +164,19 @@ func (msg *ExecutionResults) ToNativeMap() map[string]interface{} {
 	native["height"] = msg.Height
 	native["timestamp"] = msg.Timestamp
 	native["numOfMsgs"] = msg.NumOfMsgs
+	native["matchingPaused"] = msg.MatchingPaused
+	native["isBreatheBlock"] = msg.IsBreatheBlock
+	native["haltEvent"] = msg.HaltEvent
+	sessionEvents := make([]map[string]interface{}, len(msg.SessionEvents))
+	for idx, event := range msg.SessionEvents {
+		sessionEvents[idx] = event.toNativeMap()
+	}
+	native["sessionEvents"] = sessionEvents
+	tokenTradingEvents := make([]map[string]interface{}, len(msg.TokenTradingEvents))
+	for idx, event := range msg.TokenTradingEvents {
+		tokenTradingEvents[idx] = event.toNativeMap()
+	}
+	native["tokenTradingEvents"] = tokenTradingEvents
 	if msg.Trades.NumOfMsgs > 0 {
 		native["trades"] = map[string]interface{}{"org.binance.dex.model.avro.Trades": msg.Trades.ToNativeMap()}
 	}
@@ -175,9 +215,51 @@ func (msg *ExecutionResults) EmptyCopy() AvroOrJsonMsg {
 		Orders{len(nonExpiredOrders), nonExpiredOrders},
 		msg.Proposals,
 		msg.StakeUpdates,
+		msg.MatchingPaused,
+		msg.IsBreatheBlock,
+		msg.HaltEvent,
+		msg.SessionEvents,
+		msg.TokenTradingEvents,
 	}
 }
 
+// SessionEvent is the opening or closing of the trading session of a pair, see TradingPair.SessionOpen.
+type SessionEvent struct {
+	Symbol string
+	Event  string // SessionOpened or SessionClosed
+}
+
+func (msg *SessionEvent) String() string {
+	return fmt.Sprintf("SessionEvent: %v", msg.toNativeMap())
+}
+
+func (msg *SessionEvent) toNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["symbol"] = msg.Symbol
+	native["event"] = msg.Event
+	return native
+}
+
+// TokenTradingEvent is the trading of the base or quote token of a pair disabled or enabled by governance, see
+// SetTokenTradingMsg.
+type TokenTradingEvent struct {
+	Symbol   string
+	Token    string
+	Disabled bool
+}
+
+func (msg *TokenTradingEvent) String() string {
+	return fmt.Sprintf("TokenTradingEvent: %v", msg.toNativeMap())
+}
+
+func (msg *TokenTradingEvent) toNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["symbol"] = msg.Symbol
+	native["token"] = msg.Token
+	native["disabled"] = msg.Disabled
+	return native
+}
+
 // deliberated not implemented Ess
 type trades struct {
 	NumOfMsgs int
@@ -215,6 +297,8 @@ type Trade struct {
 	SSingleFee string // seller's fee for this trade - ADDED Galileo
 	BSingleFee string // buyer's fee for this trade - ADDED Galileo
 	TickType   int    // ADDED Galileo
+	SFeeExempt bool   // the seller is fee exempt, so its fee is waived
+	BFeeExempt bool   // the buyer is fee exempt, so its fee is waived
 }
 
 func (msg *Trade) MarshalJSON() ([]byte, error) {
@@ -239,7 +323,7 @@ func (msg *Trade) toNativeMap() map[string]interface{} {
 	native["id"] = msg.Id
 	native["symbol"] = msg.Symbol
 	native["price"] = msg.Price
-	native["qty"] = msg.Qty
+	native["qty"] = displayQty(msg.Symbol, msg.Qty)
 	native["sid"] = msg.Sid
 	native["bid"] = msg.Bid
 	native["sfee"] = msg.Sfee
@@ -251,6 +335,8 @@ func (msg *Trade) toNativeMap() map[string]interface{} {
 	native["ssinglefee"] = msg.SSingleFee
 	native["bsinglefee"] = msg.BSingleFee
 	native["tickType"] = msg.TickType
+	native["sfeeExempt"] = msg.SFeeExempt
+	native["bfeeExempt"] = msg.BFeeExempt
 	return native
 }
 
@@ -306,6 +392,8 @@ type Order struct {
 	CurrentExecutionType orderPkg.ExecutionType
 	TxHash               string
 	SingleFee            string // fee for this order update - ADDED Galileo
+	RemainingLocked      int64  // amount still locked by the order, in the quote asset for buy orders and the base asset for sell orders
+	Memo                 string // memo of the order for the client tagging
 }
 
 func (msg *Order) String() string {
@@ -318,7 +406,7 @@ func (msg *Order) effectQtyToOrderBook() int64 {
 		return msg.Qty
 	case orderPkg.FullyFill, orderPkg.PartialFill:
 		return -msg.LastExecutedQty
-	case orderPkg.Expired, orderPkg.IocExpire, orderPkg.IocNoFill, orderPkg.Canceled, orderPkg.FailedMatching:
+	case orderPkg.Expired, orderPkg.IocExpire, orderPkg.IocNoFill, orderPkg.Canceled, orderPkg.FailedMatching, orderPkg.Delisted, orderPkg.TokenDisabled:
 		return msg.CumQty - msg.Qty // deliberated be negative value
 	case orderPkg.FailedBlocking:
 		return 0
@@ -349,6 +437,8 @@ func (msg *Order) toNativeMap() map[string]interface{} {
 	native["currentExecutionType"] = msg.CurrentExecutionType.String()
 	native["txHash"] = msg.TxHash
 	native["singlefee"] = msg.SingleFee
+	native["remainingLocked"] = msg.RemainingLocked
+	native["memo"] = msg.Memo
 	return native
 }
 
@@ -492,6 +582,12 @@ func (msg *PriceLevel) ToNativeMap() map[string]interface{} {
 	return native
 }
 
+func (msg *PriceLevel) toDisplayNativeMap(symbol string) map[string]interface{} {
+	native := msg.ToNativeMap()
+	native["lastQty"] = displayQty(symbol, msg.LastQty)
+	return native
+}
+
 type OrderBookDelta struct {
 	Symbol string
 	Buys   []PriceLevel
@@ -507,12 +603,12 @@ func (msg *OrderBookDelta) ToNativeMap() map[string]interface{} {
 	native["symbol"] = msg.Symbol
 	bs := make([]map[string]interface{}, len(msg.Buys))
 	for idx, buy := range msg.Buys {
-		bs[idx] = buy.ToNativeMap()
+		bs[idx] = buy.toDisplayNativeMap(msg.Symbol)
 	}
 	native["buys"] = bs
 	ss := make([]map[string]interface{}, len(msg.Sells))
 	for idx, sell := range msg.Sells {
-		ss[idx] = sell.ToNativeMap()
+		ss[idx] = sell.toDisplayNativeMap(msg.Symbol)
 	}
 	native["sells"] = ss
 	return native
@@ -545,6 +641,32 @@ func (msg *Books) ToNativeMap() map[string]interface{} {
 	return native
 }
 
+// BookSnapshots are the full order books of all the pairs at Height, published every
+// PublicationConfig.OrderBookSnapshotInterval blocks, for the consumers to start applying the Books from.
+type BookSnapshots struct {
+	Height    int64
+	Timestamp int64
+	NumOfMsgs int
+	Books     []OrderBookDelta
+}
+
+func (msg *BookSnapshots) String() string {
+	return fmt.Sprintf("BookSnapshots at height: %d, numOfMsgs: %d", msg.Height, msg.NumOfMsgs)
+}
+
+func (msg *BookSnapshots) ToNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["height"] = msg.Height
+	native["timestamp"] = msg.Timestamp
+	native["numOfMsgs"] = msg.NumOfMsgs
+	bs := make([]map[string]interface{}, len(msg.Books))
+	for idx, book := range msg.Books {
+		bs[idx] = book.ToNativeMap()
+	}
+	native["books"] = bs
+	return native
+}
+
 type AssetBalance struct {
 	Asset  string
 	Free   int64
@@ -601,10 +723,15 @@ func (msg *Account) ToNativeMap() map[string]interface{} {
 	return native
 }
 
+// Accounts are the balances of the accounts changed in the block. SkippedSince is the first height whose balances
+// were not published as the publication was degraded under memory pressure, and it's only set in the message of
+// the block the full publication resumes at. The consumers seeing it should resync the balances of all the accounts
+// they track, e.g. by the account queries, as the changes of the blocks [SkippedSince, Height) are missing.
 type Accounts struct {
-	Height    int64
-	NumOfMsgs int
-	Accounts  []Account
+	Height       int64
+	NumOfMsgs    int
+	Accounts     []Account
+	SkippedSince int64
 }
 
 func (msg *Accounts) String() string {
@@ -622,6 +749,7 @@ func (msg *Accounts) ToNativeMap() map[string]interface{} {
 		}
 		native["accounts"] = as
 	}
+	native["skippedSince"] = msg.SkippedSince
 	return native
 }
 
@@ -639,14 +767,17 @@ func (msg *Accounts) EmptyCopy() AvroOrJsonMsg {
 		msg.Height,
 		0,
 		[]Account{},
+		msg.SkippedSince,
 	}
 }
 
 // deliberated not implemented Ess
 type BlockFee struct {
-	Height     int64
-	Fee        string
-	Validators []string // slice of string wrappers of bytes representation of sdk.AccAddress
+	Height      int64
+	Fee         string
+	Validators  []string        // slice of string wrappers of bytes representation of sdk.AccAddress
+	Splits      []FeeSplit      // the split of the fees accumulated for all the validators, only in breathe blocks
+	Conversions []FeeConversion // the dust fees converted into the native token, only in breathe blocks
 }
 
 func (msg BlockFee) MarshalJSON() ([]byte, error) {
@@ -665,7 +796,7 @@ func (msg BlockFee) MarshalJSON() ([]byte, error) {
 }
 
 func (msg BlockFee) String() string {
-	return fmt.Sprintf("Blockfee at height: %d, fee: %s, validators: %v", msg.Height, msg.Fee, msg.Validators)
+	return fmt.Sprintf("Blockfee at height: %d, fee: %s, validators: %v, splits: %v, conversions: %v", msg.Height, msg.Fee, msg.Validators, msg.Splits, msg.Conversions)
 }
 
 func (msg BlockFee) ToNativeMap() map[string]interface{} {
@@ -677,6 +808,57 @@ func (msg BlockFee) ToNativeMap() map[string]interface{} {
 		validators[idx] = sdk.AccAddress(addr).String()
 	}
 	native["validators"] = validators
+	splits := make([]map[string]interface{}, len(msg.Splits))
+	for idx, split := range msg.Splits {
+		splits[idx] = split.ToNativeMap()
+	}
+	native["splits"] = splits
+	conversions := make([]map[string]interface{}, len(msg.Conversions))
+	for idx, conversion := range msg.Conversions {
+		conversions[idx] = conversion.ToNativeMap()
+	}
+	native["conversions"] = conversions
+	return native
+}
+
+// FeeSplit is the share of the fees sent to a destination of the fee split params, Address is empty if it's burnt
+type FeeSplit struct {
+	Address string
+	Fee     string
+}
+
+func (msg FeeSplit) String() string {
+	return fmt.Sprintf("FeeSplit: address: %s, fee: %s", msg.Address, msg.Fee)
+}
+
+func (msg FeeSplit) ToNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["address"] = msg.Address
+	native["fee"] = msg.Fee
+	return native
+}
+
+// FeeConversion is the conversion of an asset of the fees into the native token by the order of the fee account
+type FeeConversion struct {
+	Symbol   string
+	OrderId  string
+	Quantity int64 // quantity put on sale
+	Sold     int64 // quantity filled
+	Received int64 // native token received
+}
+
+func (msg FeeConversion) String() string {
+	return fmt.Sprintf("FeeConversion: symbol: %s, orderId: %s, quantity: %d, sold: %d, received: %d",
+		msg.Symbol, msg.OrderId, msg.Quantity, msg.Sold, msg.Received)
+}
+
+func (msg FeeConversion) ToNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["symbol"] = msg.Symbol
+	native["orderId"] = msg.OrderId
+	native["quantity"] = msg.Quantity
+	native["sold"] = msg.Sold
+	native["received"] = msg.Received
 	return native
 }
 
@@ -1150,16 +1332,22 @@ func (msg *AllocatedAmt) toNativeMap() map[string]interface{} {
 type BreatheBlockMsg struct {
 	Height    int64
 	Timestamp int64
+	Pairs     []PairSizes // pairs whose tick size or lot size is changed in the breathe block
 }
 
 func (msg *BreatheBlockMsg) String() string {
-	return fmt.Sprintf("BreatheBlockMsg at height: %d", msg.Height)
+	return fmt.Sprintf("BreatheBlockMsg at height: %d, pairs: %v", msg.Height, msg.Pairs)
 }
 
 func (msg *BreatheBlockMsg) ToNativeMap() map[string]interface{} {
 	var native = make(map[string]interface{})
 	native["height"] = msg.Height
 	native["timestamp"] = msg.Timestamp
+	pairs := make([]map[string]interface{}, len(msg.Pairs))
+	for idx, pair := range msg.Pairs {
+		pairs[idx] = pair.toNativeMap()
+	}
+	native["pairs"] = pairs
 	return native
 }
 
@@ -1173,5 +1361,248 @@ func (msg *BreatheBlockMsg) EmptyCopy() AvroOrJsonMsg {
 	return &BreatheBlockMsg{
 		msg.Height,
 		msg.Timestamp,
+		[]PairSizes{},
 	}
 }
+
+// PairSizes are the tick size and lot size of a trading pair, which the new orders of the pair must be rounded to
+type PairSizes struct {
+	Symbol   string
+	TickSize int64
+	LotSize  int64
+}
+
+func (msg PairSizes) String() string {
+	return fmt.Sprintf("PairSizes: %v", msg.toNativeMap())
+}
+
+func (msg PairSizes) toNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["symbol"] = msg.Symbol
+	native["tickSize"] = msg.TickSize
+	native["lotSize"] = msg.LotSize
+	return native
+}
+
+// OrderRejections are the new orders rejected by the dex handler in a block.
+// deliberated not implemented Ess
+type OrderRejections struct {
+	Height     int64
+	Timestamp  int64
+	NumOfMsgs  int
+	Rejections []*OrderRejection
+}
+
+func (msg *OrderRejections) String() string {
+	return fmt.Sprintf("OrderRejections at height: %d, numOfMsgs: %d", msg.Height, msg.NumOfMsgs)
+}
+
+func (msg *OrderRejections) ToNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["height"] = msg.Height
+	native["timestamp"] = msg.Timestamp
+	native["numOfMsgs"] = msg.NumOfMsgs
+	rejections := make([]map[string]interface{}, len(msg.Rejections))
+	for idx, r := range msg.Rejections {
+		rejections[idx] = r.toNativeMap()
+	}
+	native["rejections"] = rejections
+	return native
+}
+
+type OrderRejection struct {
+	OrderId     string
+	Symbol      string
+	Owner       string
+	Side        int8
+	Price       int64
+	Qty         int64
+	TimeInForce int8
+	TxHash      string
+	Code        uint32 // abci code of the rejection, which carries the codespace
+	Reason      string
+}
+
+func (msg *OrderRejection) String() string {
+	return fmt.Sprintf("OrderRejection: %s, code: %d, reason: %s", msg.OrderId, msg.Code, msg.Reason)
+}
+
+func (msg *OrderRejection) toNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["orderId"] = msg.OrderId
+	native["symbol"] = msg.Symbol
+	native["owner"] = msg.Owner
+	native["side"] = int(msg.Side)
+	native["price"] = msg.Price
+	native["qty"] = msg.Qty
+	native["timeInForce"] = int(msg.TimeInForce)
+	native["txHash"] = msg.TxHash
+	native["code"] = int64(msg.Code)
+	native["reason"] = msg.Reason
+	return native
+}
+
+// OrderAcks are the new orders accepted by the dex handler in a block, published before the matching results.
+// They are NOT order updates: each accepted order is still published as an `Ack` order change in the
+// ExecutionResults of the block, so the consumers counting the orders should only count one of them.
+type OrderAcks struct {
+	Height    int64
+	Timestamp int64
+	NumOfMsgs int
+	Acks      []*OrderAck
+}
+
+func (msg *OrderAcks) String() string {
+	return fmt.Sprintf("OrderAcks at height: %d, numOfMsgs: %d", msg.Height, msg.NumOfMsgs)
+}
+
+func (msg *OrderAcks) ToNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["height"] = msg.Height
+	native["timestamp"] = msg.Timestamp
+	native["numOfMsgs"] = msg.NumOfMsgs
+	acks := make([]map[string]interface{}, len(msg.Acks))
+	for idx, a := range msg.Acks {
+		acks[idx] = a.toNativeMap()
+	}
+	native["acks"] = acks
+	return native
+}
+
+type OrderAck struct {
+	OrderId     string
+	Symbol      string
+	Owner       string
+	Side        int8
+	Price       int64
+	Qty         int64
+	TimeInForce int8
+	TxHash      string
+}
+
+func (msg *OrderAck) String() string {
+	return fmt.Sprintf("OrderAck: %s, symbol: %s, txHash: %s", msg.OrderId, msg.Symbol, msg.TxHash)
+}
+
+func (msg *OrderAck) toNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["orderId"] = msg.OrderId
+	native["symbol"] = msg.Symbol
+	native["owner"] = msg.Owner
+	native["side"] = int(msg.Side)
+	native["price"] = msg.Price
+	native["qty"] = msg.Qty
+	native["timeInForce"] = int(msg.TimeInForce)
+	native["txHash"] = msg.TxHash
+	return native
+}
+
+// TradeAudits are the trades matched in a block together with the full context of their orders, i.e. the
+// pre-image of each trade for audit. Unlike the Trades in the ExecutionResults, the owners and the originating
+// tx hashes are always resolved, so the records can be verified on their own.
+type TradeAudits struct {
+	Height    int64
+	Timestamp int64
+	NumOfMsgs int
+	Trades    []*TradeAudit
+}
+
+func (msg *TradeAudits) String() string {
+	return fmt.Sprintf("TradeAudits at height: %d, numOfMsgs: %d", msg.Height, msg.NumOfMsgs)
+}
+
+func (msg *TradeAudits) ToNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["height"] = msg.Height
+	native["timestamp"] = msg.Timestamp
+	native["numOfMsgs"] = msg.NumOfMsgs
+	ts := make([]map[string]interface{}, len(msg.Trades))
+	for idx, t := range msg.Trades {
+		ts[idx] = t.toNativeMap()
+	}
+	native["trades"] = ts
+	return native
+}
+
+type TradeAudit struct {
+	Id       string
+	Symbol   string
+	Price    int64
+	Qty      int64
+	TickType int
+	Maker    string // "buyer", "seller" or empty if both orders are takers of the block
+	Sid      string
+	SAddr    string // bech32 address of the seller
+	SFee     string // seller's fee for this trade
+	STxHash  string
+	SSrc     int64
+	Bid      string
+	BAddr    string // bech32 address of the buyer
+	BFee     string // buyer's fee for this trade
+	BTxHash  string
+	BSrc     int64
+}
+
+func (msg *TradeAudit) String() string {
+	return fmt.Sprintf("TradeAudit: %s, symbol: %s, sid: %s, bid: %s", msg.Id, msg.Symbol, msg.Sid, msg.Bid)
+}
+
+func (msg *TradeAudit) toNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["id"] = msg.Id
+	native["symbol"] = msg.Symbol
+	native["price"] = msg.Price
+	native["qty"] = msg.Qty
+	native["tickType"] = msg.TickType
+	native["maker"] = msg.Maker
+	native["sid"] = msg.Sid
+	native["saddr"] = msg.SAddr
+	native["sfee"] = msg.SFee
+	native["stxHash"] = msg.STxHash
+	native["ssrc"] = msg.SSrc
+	native["bid"] = msg.Bid
+	native["baddr"] = msg.BAddr
+	native["bfee"] = msg.BFee
+	native["btxHash"] = msg.BTxHash
+	native["bsrc"] = msg.BSrc
+	return native
+}
+
+// BlockMetrics are the compute costs of the matching and the publication of a block, for capacity planning.
+type BlockMetrics struct {
+	Height         int64
+	Timestamp      int64
+	MatchingTimeMs int64 // time spent on matching the orders and allocating the trades
+	NumOfOrders    int   // orders accrued for the matching, including the ones of the pairs not matched in the block
+	NumOfTrades    int   // trades produced by the matching
+	CollectTimeMs  int64 // time spent on collecting the messages to publish in the EndBlocker
+
+	matchingStart time.Time
+}
+
+// StartBlockMetrics starts measuring the matching of the block with the number of the orders accrued.
+func StartBlockMetrics(numOfOrders int) *BlockMetrics {
+	return &BlockMetrics{NumOfOrders: numOfOrders, matchingStart: time.Now()}
+}
+
+// EndMatching stops measuring the matching of the block with the number of the trades produced.
+func (msg *BlockMetrics) EndMatching(numOfTrades int) {
+	msg.MatchingTimeMs = time.Since(msg.matchingStart).Nanoseconds() / int64(time.Millisecond)
+	msg.NumOfTrades = numOfTrades
+}
+
+func (msg *BlockMetrics) String() string {
+	return fmt.Sprintf("BlockMetrics at height: %d, matchingTimeMs: %d, numOfOrders: %d, numOfTrades: %d, collectTimeMs: %d",
+		msg.Height, msg.MatchingTimeMs, msg.NumOfOrders, msg.NumOfTrades, msg.CollectTimeMs)
+}
+
+func (msg *BlockMetrics) ToNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["height"] = msg.Height
+	native["timestamp"] = msg.Timestamp
+	native["matchingTimeMs"] = msg.MatchingTimeMs
+	native["numOfOrders"] = msg.NumOfOrders
+	native["numOfTrades"] = msg.NumOfTrades
+	native["collectTimeMs"] = msg.CollectTimeMs
+	return native
+}
@@ -0,0 +1,434 @@
+package pub
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	KafkaEncodingAvro     = "avro"
+	KafkaEncodingProtobuf = "protobuf"
+)
+
+// protobufMsgTypes are the messages published in protobuf when Cfg.KafkaEncoding is protobuf, the others are
+// always published in avro. Their protobuf schemas are in pub/schemas/*.proto.
+var protobufMsgTypes = map[msgType]string{
+	executionResultTpe: executionResultSchema,
+	booksTpe:           booksSchema,
+	accountsTpe:        accountSchema,
+}
+
+const (
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+	protoWireFixed32 = 5
+)
+
+// protoCodec encodes the native maps of the publication messages in protobuf (proto3). The protobuf schema
+// mirrors the avro schema of the message, so that the two encodings never drift:
+//   - a record is a message, whose fields are numbered by their positions in the record starting from 1. The
+//     numbers are stable as the fields of the avro schemas are only appended for the backward compatibility;
+//   - long, int, boolean and string are int64, int32, bool and string;
+//   - an array is a repeated field, packed if its items are numbers;
+//   - a ["null", T] union is an optional field of T, i.e. a message field is absent if it's null.
+//
+// The other avro types, e.g. maps and enums, are not supported.
+type protoCodec struct {
+	root *protoType
+}
+
+type protoType struct {
+	// one of the avro primitives supported, or record, array and union
+	kind string
+	// full name of the record, or the name of the non-null type of the union in the native maps
+	name   string
+	fields []protoField
+	// items of the array, or the non-null type of the union
+	elem *protoType
+}
+
+type protoField struct {
+	name   string
+	number uint64
+	tpe    *protoType
+}
+
+func newProtoCodec(avroSchema string) (*protoCodec, error) {
+	var schema interface{}
+	if err := json.Unmarshal([]byte(avroSchema), &schema); err != nil {
+		return nil, err
+	}
+	root, err := parseProtoType(schema, "", make(map[string]*protoType))
+	if err != nil {
+		return nil, err
+	}
+	if root.kind != "record" {
+		return nil, fmt.Errorf("top level of the schema should be a record, got %s", root.kind)
+	}
+	return &protoCodec{root: root}, nil
+}
+
+func parseProtoType(schema interface{}, namespace string, named map[string]*protoType) (*protoType, error) {
+	switch s := schema.(type) {
+	case string:
+		switch s {
+		case "long", "int", "boolean", "string":
+			return &protoType{kind: s}, nil
+		}
+		name := s
+		if !strings.Contains(name, ".") && namespace != "" {
+			name = namespace + "." + name
+		}
+		if t, ok := named[name]; ok {
+			return t, nil
+		}
+		return nil, fmt.Errorf("unsupported avro type %s", s)
+	case []interface{}:
+		if len(s) != 2 || s[0] != "null" {
+			return nil, errors.New("only the unions of null and another type are supported")
+		}
+		elem, err := parseProtoType(s[1], namespace, named)
+		if err != nil {
+			return nil, err
+		}
+		if elem.kind == "array" {
+			return nil, errors.New("union of array is not supported")
+		}
+		name := elem.kind
+		if elem.kind == "record" {
+			name = elem.name
+		}
+		return &protoType{kind: "union", name: name, elem: elem}, nil
+	case map[string]interface{}:
+		switch s["type"] {
+		case "record":
+			return parseProtoRecord(s, namespace, named)
+		case "array":
+			items, err := parseProtoType(s["items"], namespace, named)
+			if err != nil {
+				return nil, err
+			}
+			if items.kind == "array" || items.kind == "union" {
+				return nil, fmt.Errorf("array of %s is not supported", items.kind)
+			}
+			return &protoType{kind: "array", elem: items}, nil
+		default:
+			return parseProtoType(s["type"], namespace, named)
+		}
+	}
+	return nil, fmt.Errorf("unsupported avro schema %v", schema)
+}
+
+func parseProtoRecord(s map[string]interface{}, namespace string, named map[string]*protoType) (*protoType, error) {
+	name, _ := s["name"].(string)
+	if ns, ok := s["namespace"].(string); ok {
+		namespace = ns
+	}
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		namespace = name[:idx]
+	} else if namespace != "" {
+		name = namespace + "." + name
+	}
+	t := &protoType{kind: "record", name: name}
+	named[name] = t
+
+	fields, _ := s["fields"].([]interface{})
+	for idx, f := range fields {
+		field, _ := f.(map[string]interface{})
+		fieldName, _ := field["name"].(string)
+		fieldType, err := parseProtoType(field["type"], namespace, named)
+		if err != nil {
+			return nil, fmt.Errorf("field %s of %s: %v", fieldName, name, err)
+		}
+		t.fields = append(t.fields, protoField{name: fieldName, number: uint64(idx + 1), tpe: fieldType})
+	}
+	return t, nil
+}
+
+func (t *protoType) isNumber() bool {
+	return t.kind == "long" || t.kind == "int" || t.kind == "boolean"
+}
+
+// Marshal encodes the native map of a message, which is the same as the one encoded in avro.
+func (c *protoCodec) Marshal(native map[string]interface{}) ([]byte, error) {
+	return appendProtoMessage(nil, c.root, native)
+}
+
+func appendProtoMessage(buf []byte, t *protoType, native map[string]interface{}) ([]byte, error) {
+	var err error
+	for _, f := range t.fields {
+		v, ok := native[f.name]
+		if !ok || v == nil {
+			continue
+		}
+		if buf, err = appendProtoField(buf, f.number, f.tpe, v, false); err != nil {
+			return nil, fmt.Errorf("field %s of %s: %v", f.name, t.name, err)
+		}
+	}
+	return buf, nil
+}
+
+// appendProtoField appends the field, the zero values are skipped as in proto3 unless it's an item of an array.
+func appendProtoField(buf []byte, number uint64, t *protoType, v interface{}, isItem bool) ([]byte, error) {
+	switch t.kind {
+	case "long", "int", "boolean":
+		n, err := protoNumber(v)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 && !isItem {
+			return buf, nil
+		}
+		buf = appendProtoVarint(buf, number<<3|protoWireVarint)
+		return appendProtoVarint(buf, n), nil
+	case "string":
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expect string, got %T", v)
+		}
+		if s == "" && !isItem {
+			return buf, nil
+		}
+		return appendProtoBytes(buf, number, []byte(s)), nil
+	case "record":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expect map, got %T", v)
+		}
+		bz, err := appendProtoMessage(nil, t, m)
+		if err != nil {
+			return nil, err
+		}
+		return appendProtoBytes(buf, number, bz), nil
+	case "union":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expect union of %s, got %T", t.name, v)
+		}
+		elem, ok := m[t.name]
+		if !ok {
+			return nil, fmt.Errorf("expect union of %s", t.name)
+		}
+		return appendProtoField(buf, number, t.elem, elem, true)
+	case "array":
+		items := reflect.ValueOf(v)
+		if items.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("expect slice, got %T", v)
+		}
+		if items.Len() == 0 {
+			return buf, nil
+		}
+		if t.elem.isNumber() {
+			var packed []byte
+			for i := 0; i < items.Len(); i++ {
+				n, err := protoNumber(items.Index(i).Interface())
+				if err != nil {
+					return nil, err
+				}
+				packed = appendProtoVarint(packed, n)
+			}
+			return appendProtoBytes(buf, number, packed), nil
+		}
+		var err error
+		for i := 0; i < items.Len(); i++ {
+			if buf, err = appendProtoField(buf, number, t.elem, items.Index(i).Interface(), true); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t.kind)
+}
+
+// protoNumber returns the varint of an integer or bool, the negative integers are sign extended to 64 bits.
+func protoNumber(v interface{}) (uint64, error) {
+	if b, ok := v.(bool); ok {
+		if b {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint(), nil
+	}
+	return 0, fmt.Errorf("expect integer or bool, got %T", v)
+}
+
+func appendProtoVarint(buf []byte, n uint64) []byte {
+	var varint [binary.MaxVarintLen64]byte
+	l := binary.PutUvarint(varint[:], n)
+	return append(buf, varint[:l]...)
+}
+
+func appendProtoBytes(buf []byte, number uint64, bz []byte) []byte {
+	buf = appendProtoVarint(buf, number<<3|protoWireBytes)
+	buf = appendProtoVarint(buf, uint64(len(bz)))
+	return append(buf, bz...)
+}
+
+// Unmarshal decodes a message into the native map in the same form as it's decoded from avro, i.e. long, int
+// and boolean are int64, int32 and bool, arrays are []interface{}, records are map[string]interface{}, and
+// the non-null unions are map[string]interface{}{<type name>: <value>}. The absent fields are the zero values.
+func (c *protoCodec) Unmarshal(bz []byte) (map[string]interface{}, error) {
+	return decodeProtoMessage(c.root, bz)
+}
+
+func decodeProtoMessage(t *protoType, bz []byte) (map[string]interface{}, error) {
+	native := make(map[string]interface{}, len(t.fields))
+	for _, f := range t.fields {
+		zero, err := protoZero(f.tpe)
+		if err != nil {
+			return nil, err
+		}
+		native[f.name] = zero
+	}
+
+	for len(bz) > 0 {
+		key, n := binary.Uvarint(bz)
+		if n <= 0 {
+			return nil, errors.New("invalid field key")
+		}
+		bz = bz[n:]
+		number, wireType := key>>3, key&7
+		if number == 0 || number > uint64(len(t.fields)) {
+			// unknown field of a newer schema
+			l, err := protoFieldLen(bz, wireType)
+			if err != nil {
+				return nil, err
+			}
+			bz = bz[l:]
+			continue
+		}
+		f := t.fields[number-1]
+		l, err := protoFieldLen(bz, wireType)
+		if err != nil {
+			return nil, fmt.Errorf("field %s of %s: %v", f.name, t.name, err)
+		}
+		value := bz[:l]
+		bz = bz[l:]
+		if wireType == protoWireBytes {
+			_, n := binary.Uvarint(value)
+			value = value[n:]
+		}
+		if native[f.name], err = decodeProtoField(f.tpe, wireType, value, native[f.name]); err != nil {
+			return nil, fmt.Errorf("field %s of %s: %v", f.name, t.name, err)
+		}
+	}
+	return native, nil
+}
+
+func decodeProtoField(t *protoType, wireType uint64, value []byte, current interface{}) (interface{}, error) {
+	switch t.kind {
+	case "long", "int", "boolean":
+		if wireType != protoWireVarint {
+			return nil, fmt.Errorf("unexpected wire type %d of %s", wireType, t.kind)
+		}
+		n, _ := binary.Uvarint(value)
+		return protoNativeNumber(t, n), nil
+	case "string":
+		if wireType != protoWireBytes {
+			return nil, fmt.Errorf("unexpected wire type %d of string", wireType)
+		}
+		return string(value), nil
+	case "record":
+		if wireType != protoWireBytes {
+			return nil, fmt.Errorf("unexpected wire type %d of %s", wireType, t.name)
+		}
+		return decodeProtoMessage(t, value)
+	case "union":
+		var elem interface{}
+		if m, ok := current.(map[string]interface{}); ok {
+			elem = m[t.name]
+		}
+		elem, err := decodeProtoField(t.elem, wireType, value, elem)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{t.name: elem}, nil
+	case "array":
+		items, _ := current.([]interface{})
+		if t.elem.isNumber() && wireType == protoWireBytes {
+			for len(value) > 0 {
+				n, l := binary.Uvarint(value)
+				if l <= 0 {
+					return nil, errors.New("invalid packed varint")
+				}
+				items = append(items, protoNativeNumber(t.elem, n))
+				value = value[l:]
+			}
+			return items, nil
+		}
+		item, err := decodeProtoField(t.elem, wireType, value, nil)
+		if err != nil {
+			return nil, err
+		}
+		return append(items, item), nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t.kind)
+}
+
+func protoNativeNumber(t *protoType, n uint64) interface{} {
+	switch t.kind {
+	case "int":
+		return int32(n)
+	case "boolean":
+		return n != 0
+	default:
+		return int64(n)
+	}
+}
+
+func protoZero(t *protoType) (interface{}, error) {
+	switch t.kind {
+	case "long":
+		return int64(0), nil
+	case "int":
+		return int32(0), nil
+	case "boolean":
+		return false, nil
+	case "string":
+		return "", nil
+	case "record":
+		return decodeProtoMessage(t, nil)
+	case "array":
+		return []interface{}{}, nil
+	case "union":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t.kind)
+}
+
+// protoFieldLen returns the length of the field value in bz including the length prefix if any.
+func protoFieldLen(bz []byte, wireType uint64) (int, error) {
+	switch wireType {
+	case protoWireVarint:
+		if _, n := binary.Uvarint(bz); n > 0 {
+			return n, nil
+		}
+	case protoWireFixed64:
+		if len(bz) >= 8 {
+			return 8, nil
+		}
+	case protoWireFixed32:
+		if len(bz) >= 4 {
+			return 4, nil
+		}
+	case protoWireBytes:
+		l, n := binary.Uvarint(bz)
+		if n > 0 && l <= uint64(len(bz)-n) {
+			return n + int(l), nil
+		}
+	default:
+		return 0, fmt.Errorf("unsupported wire type %d", wireType)
+	}
+	return 0, errors.New("truncated field")
+}
@@ -0,0 +1,151 @@
+package pub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
+)
+
+func TestProtoCodec_RoundTrip(t *testing.T) {
+	valAddr := sdk.ValAddress([]byte("validator"))
+	delAddr := sdk.AccAddress([]byte("delegator"))
+	executionResults := &ExecutionResults{
+		Height:    42,
+		Timestamp: 100,
+		NumOfMsgs: 6,
+		Trades: trades{
+			NumOfMsgs: 1,
+			Trades: []*Trade{{
+				Id: "42-0", Symbol: "NNB_BNB", Price: 100, Qty: 100,
+				Sid: "s-1", Bid: "b-1", TickType: 1,
+				Sfee: "BNB:8;ETH:1", Bfee: "BNB:10;BTC:1", SSingleFee: "BNB:8;ETH:1", BSingleFee: "BNB:10;BTC:1",
+				SAddr: "s", BAddr: "b", SSrc: 0, BSrc: 1}},
+		},
+		Orders: Orders{
+			NumOfMsgs: 2,
+			Orders: []*Order{
+				{"NNB_BNB", orderPkg.FullyFill, "b-1", "42-0", "b", orderPkg.Side.BUY, orderPkg.OrderType.LIMIT, 100, 100, 100, 100, 100, "BNB:10;BTC:1", 100, 100, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "BNB:10;BTC:1", 0, ""},
+				{"NNB_BNB", orderPkg.PartialFill, "s-1", "42-0", "s", orderPkg.Side.SELL, orderPkg.OrderType.LIMIT, 100, 200, 100, 100, 100, "BNB:8;ETH:1", 99, 99, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "BNB:8;ETH:1", 100, "client-1"},
+			},
+		},
+		StakeUpdates: StakeUpdates{
+			NumOfMsgs: 1,
+			CompletedUnbondingDelegations: []*CompletedUnbondingDelegation{
+				{Validator: valAddr, Delegator: delAddr, Amount: Coin{"BNB", 1e8}},
+			},
+		},
+		IsBreatheBlock:     true,
+		HaltEvent:          orderPkg.HaltStarted,
+		SessionEvents:      []*SessionEvent{{"NNB_BNB", orderPkg.SessionClosed}},
+		TokenTradingEvents: []*TokenTradingEvent{{"NNB_BNB", "NNB", true}},
+	}
+	books := &Books{42, 100, 1, []OrderBookDelta{
+		{"NNB_BNB", []PriceLevel{{100, 100}, {99, 0}}, []PriceLevel{{101, 100}}},
+	}}
+	accounts := &Accounts{42, 1, []Account{
+		{"b-1", "BNB:1000;BTC:10", 7, []*AssetBalance{{Asset: "BNB", Free: 100}, {Asset: "BTC", Locked: 10}}},
+	}, 40}
+
+	for _, tc := range []struct {
+		tpe msgType
+		msg AvroOrJsonMsg
+	}{
+		{executionResultTpe, executionResults},
+		{booksTpe, books},
+		{accountsTpe, accounts},
+	} {
+		codec, err := newProtoCodec(protobufMsgTypes[tc.tpe])
+		require.NoError(t, err)
+		bz, err := codec.Marshal(tc.msg.ToNativeMap())
+		require.NoError(t, err)
+		decoded, err := codec.Unmarshal(bz)
+		require.NoError(t, err)
+		reencoded, err := codec.Marshal(decoded)
+		require.NoError(t, err)
+		require.Equal(t, bz, reencoded, tc.tpe.String())
+
+		// the fields of the newer schemas are skipped
+		decodedWithUnknown, err := codec.Unmarshal(append(append([]byte{}, bz...), 0xa0, 0x06, 0x01))
+		require.NoError(t, err)
+		require.Equal(t, decoded, decodedWithUnknown, tc.tpe.String())
+	}
+
+	codec, err := newProtoCodec(executionResultSchema)
+	require.NoError(t, err)
+	bz, err := codec.Marshal(executionResults.ToNativeMap())
+	require.NoError(t, err)
+	decoded, err := codec.Unmarshal(bz)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), decoded["height"])
+	require.Equal(t, int32(6), decoded["numOfMsgs"])
+	require.Equal(t, true, decoded["isBreatheBlock"])
+	require.Equal(t, false, decoded["matchingPaused"])
+	require.Equal(t, orderPkg.HaltStarted, decoded["haltEvent"])
+	require.Equal(t, []interface{}{map[string]interface{}{"symbol": "NNB_BNB", "event": orderPkg.SessionClosed}}, decoded["sessionEvents"])
+	require.Equal(t, []interface{}{map[string]interface{}{"symbol": "NNB_BNB", "token": "NNB", "disabled": true}}, decoded["tokenTradingEvents"])
+	require.Nil(t, decoded["proposals"])
+	tradesMsg := decoded["trades"].(map[string]interface{})["org.binance.dex.model.avro.Trades"].(map[string]interface{})
+	require.Len(t, tradesMsg["trades"], 1)
+	trade := tradesMsg["trades"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, "NNB_BNB", trade["symbol"])
+	require.Equal(t, int64(100), trade["price"])
+	require.Equal(t, int64(0), trade["ssrc"])
+	require.Equal(t, int64(1), trade["bsrc"])
+	require.Equal(t, sdk.AccAddress("s").String(), trade["saddr"])
+	orders := decoded["orders"].(map[string]interface{})["org.binance.dex.model.avro.Orders"].(map[string]interface{})
+	order := orders["orders"].([]interface{})[1].(map[string]interface{})
+	require.Equal(t, "s-1", order["orderId"])
+	require.Equal(t, orderPkg.PartialFill.String(), order["status"])
+	require.Equal(t, int32(orderPkg.Side.SELL), order["side"])
+	require.Equal(t, int64(100), order["remainingLocked"])
+	require.Equal(t, "client-1", order["memo"])
+	stakeUpdates := decoded["stakeUpdates"].(map[string]interface{})["org.binance.dex.model.avro.StakeUpdates"].(map[string]interface{})
+	unbonding := stakeUpdates["completedUnbondingDelegations"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, map[string]interface{}{"denom": "BNB", "amount": int64(1e8)}, unbonding["amount"])
+}
+
+func TestProtoCodec_WireFormat(t *testing.T) {
+	codec, err := newProtoCodec(booksSchema)
+	require.NoError(t, err)
+	bz, err := codec.Marshal((&Books{2, 0, 1, []OrderBookDelta{{"A", []PriceLevel{{1, 3}}, nil}}}).ToNativeMap())
+	require.NoError(t, err)
+	// height=2, numOfMsgs=1, books=[{symbol="A", buys=[{price=1, lastQty=3}]}], the zero timestamp is omitted
+	require.Equal(t, []byte{0x08, 0x02, 0x18, 0x01, 0x22, 0x09, 0x0a, 0x01, 'A', 0x12, 0x04, 0x08, 0x01, 0x10, 0x03}, bz)
+
+	decoded, err := codec.Unmarshal(bz)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), decoded["timestamp"])
+	book := decoded["books"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, []interface{}{}, book["sells"])
+	require.Equal(t, []interface{}{map[string]interface{}{"price": int64(1), "lastQty": int64(3)}}, book["buys"])
+
+	_, err = codec.Unmarshal([]byte{0x22, 0x09, 0x0a})
+	require.Error(t, err)
+	// maps are not supported
+	_, err = newProtoCodec(stakingSchema)
+	require.Error(t, err)
+}
+
+func TestPublisher_MarshalProtobuf(t *testing.T) {
+	Cfg.KafkaEncoding = KafkaEncodingProtobuf
+	defer func() {
+		Cfg.KafkaEncoding = ""
+	}()
+	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
+	msg := &Accounts{42, 1, []Account{{"b-1", "BNB:1000", 1, []*AssetBalance{{Asset: "BNB", Free: 100}}}}, 0}
+	bz, err := publisher.marshal(msg, accountsTpe)
+	require.NoError(t, err)
+	decoded, err := publisher.protoCodecs[accountsTpe].Unmarshal(bz)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), decoded["height"])
+
+	// the other messages are still in avro
+	_, ok := publisher.protoCodecs[blockFeeTpe]
+	require.False(t, ok)
+	_, err = publisher.marshal(BlockFee{Height: 42, Fee: "BNB:1000"}, blockFeeTpe)
+	require.NoError(t, err)
+}
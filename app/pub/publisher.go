@@ -2,6 +2,7 @@ package pub
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/bnb-chain/node/app/config"
 	"github.com/bnb-chain/node/app/pub/sub"
+	"github.com/bnb-chain/node/plugins/dex/matcheng"
 	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
 )
 
@@ -18,7 +20,7 @@ const (
 	// do we need better way to make main thread less possibility to block
 	TransferCollectionChannelSize = 4000
 	ToRemoveOrderIdChannelSize    = 1000
-	MaxOrderBookLevel             = 100
+	MaxOrderBookLevel             = orderPkg.DefaultPublicationDepth
 )
 
 type OrderSymbolId struct {
@@ -423,9 +425,18 @@ func PublishEvent(
 		}
 
 		if cfg.PublishBreatheBlock && toPublish.IsBreatheBlock {
+			pairs := make([]PairSizes, 0, len(toPublish.PairSizesUpdates))
+			for _, pair := range toPublish.PairSizesUpdates {
+				pairs = append(pairs, PairSizes{
+					Symbol:   pair.GetSymbol(),
+					TickSize: pair.TickSize.ToInt64(),
+					LotSize:  pair.LotSize.ToInt64(),
+				})
+			}
 			breatheBlockMsg := BreatheBlockMsg{
 				Height:    toPublish.Height,
 				Timestamp: toPublish.Timestamp.UnixNano(),
+				Pairs:     pairs,
 			}
 			publisher.publish(&breatheBlockMsg, breatheBlockTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
 		}
@@ -446,6 +457,13 @@ func Publish(
 		}
 
 		publishTotalTime := Timer(Logger, fmt.Sprintf("publish market data, height=%d", marketData.height), func() {
+			// the acks go out ahead of the matching results of the block, they are not order updates
+			if cfg.PublishOrderAcks {
+				Timer(Logger, "publish order acks", func() {
+					publishOrderAcks(publisher, marketData.height, marketData.timestamp, marketData.orderAcks)
+				})
+			}
+
 			// Implementation note: publication order are important here,
 			// DEX query service team relies on the fact that we publish orders before trades so that
 			// they can assign buyer/seller address into trade before persist into DB
@@ -459,6 +477,12 @@ func Publish(
 				marketData.orderInfos,
 				marketData.feeHolder,
 				marketData.timestamp)
+
+			// the owners and tx hashes of the filled orders have to be resolved before they are removed below
+			var tradeAudits []*TradeAudit
+			if cfg.PublishTradeAudits {
+				tradeAudits = collectTradeAudits(marketData.tradesToPublish, marketData.orderInfos)
+			}
 			addClosedOrder(closedToPublish, ToRemoveOrderIdCh)
 
 			// ToRemoveOrderIdCh would be only used in production code
@@ -478,7 +502,12 @@ func Publish(
 						ordersToPublish,
 						marketData.tradesToPublish,
 						marketData.proposalsToPublish,
-						marketData.stakeUpdates)
+						marketData.stakeUpdates,
+						marketData.matchingPaused,
+						marketData.haltEvent,
+						marketData.sessionEvents,
+						marketData.tokenTradingEvents,
+						marketData.isBreatheBlock)
 				})
 
 				if metrics != nil {
@@ -488,9 +517,19 @@ func Publish(
 				}
 			}
 
-			if cfg.PublishAccountBalance {
+			if cfg.PublishTradeAudits {
+				Timer(Logger, "publish trade audits", func() {
+					publishTradeAudits(publisher, marketData.height, marketData.timestamp, tradeAudits)
+				})
+			}
+
+			if cfg.PublishBlockMetrics {
+				publishBlockMetrics(publisher, marketData.height, marketData.timestamp, marketData.blockMetrics)
+			}
+
+			if cfg.PublishAccountBalance && !marketData.degraded {
 				duration := Timer(Logger, "publish all changed accounts", func() {
-					publishAccount(publisher, marketData.height, marketData.timestamp, marketData.accounts, feeToPublish)
+					publishAccount(publisher, marketData.height, marketData.timestamp, marketData.accounts, feeToPublish, marketData.skippedSince)
 				})
 
 				if metrics != nil {
@@ -499,10 +538,10 @@ func Publish(
 				}
 			}
 
-			if cfg.PublishOrderBook {
+			if cfg.PublishOrderBook && !marketData.degraded {
 				var changedPrices = make(orderPkg.ChangedPriceLevelsMap)
 				duration := Timer(Logger, "prepare order books to publish", func() {
-					changedPrices = filterChangedOrderBooksByOrders(ordersToPublish, marketData.latestPricesLevels)
+					changedPrices = filterChangedOrderBooksByOrders(ordersToPublish, marketData.latestPricesLevels, cfg.OrderBookNetDelta)
 				})
 				if metrics != nil {
 					numOfChangedPrices := 0
@@ -523,6 +562,13 @@ func Publish(
 				}
 			}
 
+			// published even if degraded, as it's what the consumers resync the order books from
+			if cfg.PublishOrderBookSnapshot && marketData.bookSnapshots != nil {
+				Timer(Logger, "publish order book snapshots", func() {
+					publishOrderBookSnapshots(publisher, marketData.height, marketData.timestamp, marketData.bookSnapshots)
+				})
+			}
+
 			if cfg.PublishBlockFee {
 				duration := Timer(Logger, "publish blockfee", func() {
 					publishBlockFee(publisher, marketData.height, marketData.timestamp, marketData.blockFee)
@@ -533,7 +579,7 @@ func Publish(
 				}
 			}
 
-			if cfg.PublishTransfer {
+			if cfg.PublishTransfer && !marketData.degraded {
 				duration := Timer(Logger, "publish transfers", func() {
 					publishTransfers(publisher, marketData.height, marketData.timestamp, marketData.transfers)
 				})
@@ -543,7 +589,7 @@ func Publish(
 				}
 			}
 
-			if cfg.PublishBlock {
+			if cfg.PublishBlock && !marketData.degraded {
 				duration := Timer(Logger, "publish block", func() {
 					publishBlock(publisher, marketData.height, marketData.timestamp, marketData.block)
 				})
@@ -552,6 +598,12 @@ func Publish(
 				}
 			}
 
+			if cfg.PublishOrderRejections {
+				Timer(Logger, "publish order rejections", func() {
+					publishOrderRejections(publisher, marketData.height, marketData.timestamp, marketData.orderRejections)
+				})
+			}
+
 			if cfg.PublishSideProposal {
 				duration := Timer(Logger, "publish side chain proposal", func() {
 					publishSideProposals(publisher, marketData.height, marketData.timestamp, marketData.sideProposals)
@@ -561,6 +613,7 @@ func Publish(
 				}
 			}
 
+			state.setPublished(marketData.height)
 			if metrics != nil {
 				metrics.PublicationHeight.Set(float64(marketData.height))
 				blockInterval := time.Since(lastPublishedTime)
@@ -602,12 +655,19 @@ func Stop(publisher MarketDataPublisher) {
 	publisher.Stop()
 }
 
-func publishExecutionResult(publisher MarketDataPublisher, height int64, timestamp int64, os []*Order, tradesToPublish []*Trade, proposalsToPublish *Proposals, stakeUpdates *StakeUpdates) {
+func publishExecutionResult(publisher MarketDataPublisher, height int64, timestamp int64, os []*Order, tradesToPublish []*Trade, proposalsToPublish *Proposals, stakeUpdates *StakeUpdates, matchingPaused bool, haltEvent string, sessionEvents []orderPkg.SessionEvent, tokenTradingEvents []orderPkg.TokenTradingEvent, isBreatheBlock bool) {
 	numOfOrders := len(os)
 	numOfTrades := len(tradesToPublish)
 	numOfProposals := proposalsToPublish.NumOfMsgs
 	numOfStakeUpdatedAccounts := stakeUpdates.NumOfMsgs
-	executionResultsMsg := ExecutionResults{Height: height, Timestamp: timestamp, NumOfMsgs: numOfTrades + numOfOrders + numOfProposals + numOfStakeUpdatedAccounts}
+	executionResultsMsg := ExecutionResults{Height: height, Timestamp: timestamp, NumOfMsgs: numOfTrades + numOfOrders + numOfProposals + numOfStakeUpdatedAccounts, MatchingPaused: matchingPaused, HaltEvent: haltEvent, IsBreatheBlock: isBreatheBlock}
+	for _, event := range sessionEvents {
+		executionResultsMsg.SessionEvents = append(executionResultsMsg.SessionEvents, &SessionEvent{event.Symbol, event.Event})
+	}
+	for _, event := range tokenTradingEvents {
+		executionResultsMsg.TokenTradingEvents = append(executionResultsMsg.TokenTradingEvents,
+			&TokenTradingEvent{event.Symbol, event.Token, event.Disabled})
+	}
 	if numOfOrders > 0 {
 		executionResultsMsg.Orders = Orders{numOfOrders, os}
 	}
@@ -624,7 +684,7 @@ func publishExecutionResult(publisher MarketDataPublisher, height int64, timesta
 	publisher.publish(&executionResultsMsg, executionResultTpe, height, timestamp)
 }
 
-func publishAccount(publisher MarketDataPublisher, height int64, timestamp int64, accountsToPublish map[string]Account, feeToPublish map[string]string) {
+func publishAccount(publisher MarketDataPublisher, height int64, timestamp int64, accountsToPublish map[string]Account, feeToPublish map[string]string, skippedSince int64) {
 	numOfMsgs := len(accountsToPublish)
 
 	idx := 0
@@ -636,7 +696,7 @@ func publishAccount(publisher MarketDataPublisher, height int64, timestamp int64
 		accs[idx] = acc
 		idx++
 	}
-	accountsMsg := Accounts{height, numOfMsgs, accs}
+	accountsMsg := Accounts{height, numOfMsgs, accs, skippedSince}
 
 	publisher.publish(&accountsMsg, accountsTpe, height, timestamp)
 }
@@ -664,6 +724,28 @@ func publishOrderBookDelta(publisher MarketDataPublisher, height int64, timestam
 	publisher.publish(&books, booksTpe, height, timestamp)
 }
 
+func publishOrderBookSnapshots(publisher MarketDataPublisher, height int64, timestamp int64, orderBooks orderPkg.ChangedPriceLevelsMap) {
+	books := make([]OrderBookDelta, 0, len(orderBooks))
+	for pair, pls := range orderBooks {
+		buys := make([]PriceLevel, 0, len(pls.Buys))
+		for price, qty := range pls.Buys {
+			buys = append(buys, PriceLevel{price, qty})
+		}
+		sells := make([]PriceLevel, 0, len(pls.Sells))
+		for price, qty := range pls.Sells {
+			sells = append(sells, PriceLevel{price, qty})
+		}
+		// sorted from the best prices, as the consumers build the books from them
+		sort.Slice(buys, func(i, j int) bool { return buys[i].Price > buys[j].Price })
+		sort.Slice(sells, func(i, j int) bool { return sells[i].Price < sells[j].Price })
+		books = append(books, OrderBookDelta{pair, buys, sells})
+	}
+	sort.Slice(books, func(i, j int) bool { return books[i].Symbol < books[j].Symbol })
+
+	snapshots := BookSnapshots{height, timestamp, len(books), books}
+	publisher.publish(&snapshots, bookSnapshotsTpe, height, timestamp)
+}
+
 func publishBlockFee(publisher MarketDataPublisher, height, timestamp int64, blockFee BlockFee) {
 	publisher.publish(blockFee, blockFeeTpe, height, timestamp)
 }
@@ -674,6 +756,109 @@ func publishTransfers(publisher MarketDataPublisher, height, timestamp int64, tr
 	}
 }
 
+func publishOrderRejections(publisher MarketDataPublisher, height, timestamp int64, rejections []orderPkg.OrderRejection) {
+	msg := OrderRejections{
+		Height:     height,
+		Timestamp:  timestamp,
+		NumOfMsgs:  len(rejections),
+		Rejections: make([]*OrderRejection, len(rejections)),
+	}
+	for i, r := range rejections {
+		msg.Rejections[i] = &OrderRejection{
+			OrderId:     r.OrderId,
+			Symbol:      r.Symbol,
+			Owner:       r.Sender.String(),
+			Side:        r.Side,
+			Price:       r.Price,
+			Qty:         r.Quantity,
+			TimeInForce: r.TimeInForce,
+			TxHash:      r.TxHash,
+			Code:        uint32(r.Code),
+			Reason:      r.Reason,
+		}
+	}
+	publisher.publish(&msg, orderRejectionsTpe, height, timestamp)
+}
+
+func publishOrderAcks(publisher MarketDataPublisher, height, timestamp int64, acks []orderPkg.OrderAck) {
+	msg := OrderAcks{
+		Height:    height,
+		Timestamp: timestamp,
+		NumOfMsgs: len(acks),
+		Acks:      make([]*OrderAck, len(acks)),
+	}
+	for i, a := range acks {
+		msg.Acks[i] = &OrderAck{
+			OrderId:     a.OrderId,
+			Symbol:      a.Symbol,
+			Owner:       a.Sender.String(),
+			Side:        a.Side,
+			Price:       a.Price,
+			Qty:         a.Quantity,
+			TimeInForce: a.TimeInForce,
+			TxHash:      a.TxHash,
+		}
+	}
+	publisher.publish(&msg, orderAcksTpe, height, timestamp)
+}
+
+func collectTradeAudits(trades []*Trade, orderInfos orderPkg.OrderInfoForPublish) []*TradeAudit {
+	audits := make([]*TradeAudit, len(trades))
+	for i, t := range trades {
+		audit := &TradeAudit{
+			Id:       t.Id,
+			Symbol:   t.Symbol,
+			Price:    t.Price,
+			Qty:      t.Qty,
+			TickType: t.TickType,
+			Sid:      t.Sid,
+			SFee:     t.SSingleFee,
+			Bid:      t.Bid,
+			BFee:     t.BSingleFee,
+		}
+		switch t.TickType {
+		case matcheng.SellTaker:
+			audit.Maker = "buyer"
+		case matcheng.BuyTaker:
+			audit.Maker = "seller"
+		}
+		if o, ok := orderInfos[t.Sid]; ok {
+			audit.SAddr = o.Sender.String()
+			audit.STxHash = o.TxHash
+			audit.SSrc = o.TxSource
+		} else {
+			Logger.Error("failed to resolve order information from orderInfos", "orderId", t.Sid)
+		}
+		if o, ok := orderInfos[t.Bid]; ok {
+			audit.BAddr = o.Sender.String()
+			audit.BTxHash = o.TxHash
+			audit.BSrc = o.TxSource
+		} else {
+			Logger.Error("failed to resolve order information from orderInfos", "orderId", t.Bid)
+		}
+		audits[i] = audit
+	}
+	return audits
+}
+
+func publishTradeAudits(publisher MarketDataPublisher, height, timestamp int64, audits []*TradeAudit) {
+	msg := TradeAudits{
+		Height:    height,
+		Timestamp: timestamp,
+		NumOfMsgs: len(audits),
+		Trades:    audits,
+	}
+	publisher.publish(&msg, tradeAuditsTpe, height, timestamp)
+}
+
+func publishBlockMetrics(publisher MarketDataPublisher, height, timestamp int64, blockMetrics *BlockMetrics) {
+	if blockMetrics != nil {
+		blockMetrics.Height = height
+		blockMetrics.Timestamp = timestamp
+		publisher.publish(blockMetrics, blockMetricsTpe, height, timestamp)
+	}
+}
+
 func publishSideProposals(publisher MarketDataPublisher, height, timestamp int64, sideProposals *SideProposals) {
 	if sideProposals != nil {
 		sideProposals.Height = height
@@ -37,6 +37,13 @@ type KafkaMarketDataPublisher struct {
 	mirrorCodec           *goavro.Codec
 	sideProposalCodec     *goavro.Codec
 	breatheBlockCodec     *goavro.Codec
+	orderRejectionsCodec  *goavro.Codec
+	orderAcksCodec        *goavro.Codec
+	tradeAuditsCodec      *goavro.Codec
+	blockMetricsCodec     *goavro.Codec
+	bookSnapshotsCodec    *goavro.Codec
+	// the codecs of the messages published in protobuf, if Cfg.KafkaEncoding is protobuf
+	protoCodecs map[msgType]*protoCodec
 
 	failFast         bool
 	essentialLogPath string                         // the path (default to db dir) we write essential file to make up data on kafka error
@@ -58,6 +65,9 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 	if !supported {
 		return nil, fmt.Errorf("kafka version in app.toml is not supported. Please choose a version within: %s", sarama.SupportedVersions)
 	}
+	if Cfg.KafkaMaxMessageBytes > 0 && !version.IsAtLeast(sarama.V0_11_0_0) {
+		return nil, fmt.Errorf("splitting the kafka messages requires the record headers of kafka 0.11.0.0 at least")
+	}
 
 	config = sarama.NewConfig()
 	config.Version = version
@@ -65,7 +75,9 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 		return
 	}
 
-	config.Producer.Partitioner = sarama.NewRandomPartitioner
+	// the keys are unique per message, so the messages are spread over the partitions as randomly as before,
+	// while the parts of a split message, which share the key, go to the same partition in order
+	config.Producer.Partitioner = sarama.NewHashPartitioner
 	config.Producer.MaxMessageBytes = 100 * 1024 * 1024 // TODO(#66): 100M, make this configurable
 	config.Producer.RequiredAcks = sarama.WaitForAll
 	config.Producer.Return.Successes = true
@@ -214,6 +226,56 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 			return
 		}
 	}
+	if Cfg.PublishOrderRejections {
+		if _, ok := publisher.producers[Cfg.OrderRejectionsTopic]; !ok {
+			publisher.producers[Cfg.OrderRejectionsTopic], err =
+				publisher.connectWithRetry(strings.Split(Cfg.OrderRejectionsKafka, KafkaBrokerSep), config)
+		}
+		if err != nil {
+			Logger.Error("failed to create order rejections producer", "err", err)
+			return
+		}
+	}
+	if Cfg.PublishOrderAcks {
+		if _, ok := publisher.producers[Cfg.OrderAcksTopic]; !ok {
+			publisher.producers[Cfg.OrderAcksTopic], err =
+				publisher.connectWithRetry(strings.Split(Cfg.OrderAcksKafka, KafkaBrokerSep), config)
+		}
+		if err != nil {
+			Logger.Error("failed to create order acks producer", "err", err)
+			return
+		}
+	}
+	if Cfg.PublishTradeAudits {
+		if _, ok := publisher.producers[Cfg.TradeAuditsTopic]; !ok {
+			publisher.producers[Cfg.TradeAuditsTopic], err =
+				publisher.connectWithRetry(strings.Split(Cfg.TradeAuditsKafka, KafkaBrokerSep), config)
+		}
+		if err != nil {
+			Logger.Error("failed to create trade audits producer", "err", err)
+			return
+		}
+	}
+	if Cfg.PublishBlockMetrics {
+		if _, ok := publisher.producers[Cfg.BlockMetricsTopic]; !ok {
+			publisher.producers[Cfg.BlockMetricsTopic], err =
+				publisher.connectWithRetry(strings.Split(Cfg.BlockMetricsKafka, KafkaBrokerSep), config)
+		}
+		if err != nil {
+			Logger.Error("failed to create block metrics producer", "err", err)
+			return
+		}
+	}
+	if Cfg.PublishOrderBookSnapshot {
+		if _, ok := publisher.producers[Cfg.OrderBookSnapshotTopic]; !ok {
+			publisher.producers[Cfg.OrderBookSnapshotTopic], err =
+				publisher.connectWithRetry(strings.Split(Cfg.OrderBookSnapshotKafka, KafkaBrokerSep), config)
+		}
+		if err != nil {
+			Logger.Error("failed to create order book snapshot producer", "err", err)
+			return
+		}
+	}
 	return
 }
 
@@ -231,27 +293,84 @@ func (publisher *KafkaMarketDataPublisher) prepareMessage(
 	msg := &sarama.ProducerMessage{
 		Topic:     topic,
 		Partition: -1,
-		Key:       sarama.StringEncoder(fmt.Sprintf("%s_%d_%s_%d", msgId, timeStamp, msgTpe.String(), latestSchemaVersions[msgTpe])),
+		Key:       sarama.StringEncoder(messageKey(msgId, timeStamp, msgTpe)),
 		Value:     sarama.ByteEncoder(message),
 	}
 
 	return msg
 }
 
+func messageKey(msgId string, timeStamp int64, msgTpe msgType) string {
+	return fmt.Sprintf("%s_%d_%s_%d", msgId, timeStamp, msgTpe.String(), latestSchemaVersions[msgTpe])
+}
+
+// the record headers of the parts of a split message
+const (
+	partHeader  = "part"  // the index of the part
+	partsHeader = "parts" // the number of the parts
+)
+
+// prepareMessages splits the message into parts of at most Cfg.KafkaMaxMessageBytes if it's larger, so that a busy
+// block doesn't exceed the message size limit of kafka. The parts share the key of the message, so they go to the
+// same partition in order, and carry the index of the part and the number of parts in the record headers. The
+// consumers reassemble the message by concatenating the values of the parts in the order of the index. The message
+// is kept as a whole and without the headers if it fits.
+func (publisher *KafkaMarketDataPublisher) prepareMessages(
+	topic string,
+	msgId string,
+	timeStamp int64,
+	msgTpe msgType,
+	message []byte) []*sarama.ProducerMessage {
+	parts := splitPayload(message, Cfg.KafkaMaxMessageBytes)
+	if len(parts) == 1 {
+		return []*sarama.ProducerMessage{publisher.prepareMessage(topic, msgId, timeStamp, msgTpe, message)}
+	}
+	msgs := make([]*sarama.ProducerMessage, len(parts))
+	for idx, part := range parts {
+		msgs[idx] = publisher.prepareMessage(topic, msgId, timeStamp, msgTpe, part)
+		msgs[idx].Headers = []sarama.RecordHeader{
+			{Key: []byte(partHeader), Value: []byte(strconv.Itoa(idx))},
+			{Key: []byte(partsHeader), Value: []byte(strconv.Itoa(len(parts)))},
+		}
+	}
+	return msgs
+}
+
+// splitPayload splits the payload into parts of at most maxSize bytes, it's not split if maxSize is not positive.
+func splitPayload(payload []byte, maxSize int) [][]byte {
+	if maxSize <= 0 || len(payload) <= maxSize {
+		return [][]byte{payload}
+	}
+	parts := make([][]byte, 0, (len(payload)+maxSize-1)/maxSize)
+	for start := 0; start < len(payload); start += maxSize {
+		end := start + maxSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+		parts = append(parts, payload[start:end])
+	}
+	return parts
+}
+
 func (publisher *KafkaMarketDataPublisher) publish(avroMessage AvroOrJsonMsg, tpe msgType, height, timestamp int64) {
 	topic := publisher.resolveTopic(tpe)
 
 	if msg, err := publisher.marshal(avroMessage, tpe); err == nil {
-		kafkaMsg := publisher.prepareMessage(topic, strconv.FormatInt(height, 10), timestamp, tpe, msg)
-		if partition, offset, err := publisher.publishWithRetry(kafkaMsg, topic); err == nil {
-			Logger.Info("published", "topic", topic, "msg", avroMessage.String(), "offset", offset, "partition", partition)
-		} else {
-			Logger.Error("failed to publish, tring to log essential message", "topic", topic, "msg", avroMessage.String(), "err", err)
-			if essMsg, ok := avroMessage.(EssMsg); ok {
-				publisher.publishEssentialMsg(essMsg, topic, tpe, height, timestamp)
-			}
-			if publisher.failFast {
-				panic(fmt.Sprintf("publish kafka message failed %v", err))
+		kafkaMsgs := publisher.prepareMessages(topic, strconv.FormatInt(height, 10), timestamp, tpe, msg)
+		for idx, kafkaMsg := range kafkaMsgs {
+			if partition, offset, err := publisher.publishWithRetry(kafkaMsg, topic); err == nil {
+				Logger.Info("published", "topic", topic, "msg", avroMessage.String(), "part", idx, "parts", len(kafkaMsgs),
+					"offset", offset, "partition", partition)
+			} else {
+				Logger.Error("failed to publish, tring to log essential message", "topic", topic, "msg", avroMessage.String(),
+					"part", idx, "parts", len(kafkaMsgs), "err", err)
+				if essMsg, ok := avroMessage.(EssMsg); ok {
+					publisher.publishEssentialMsg(essMsg, topic, tpe, height, timestamp)
+				}
+				if publisher.failFast {
+					panic(fmt.Sprintf("publish kafka message failed %v", err))
+				}
+				break
 			}
 		}
 	} else {
@@ -311,6 +430,16 @@ func (publisher KafkaMarketDataPublisher) resolveTopic(tpe msgType) (topic strin
 		topic = Cfg.SideProposalTopic
 	case breatheBlockTpe:
 		topic = Cfg.BreatheBlockTopic
+	case orderRejectionsTpe:
+		topic = Cfg.OrderRejectionsTopic
+	case orderAcksTpe:
+		topic = Cfg.OrderAcksTopic
+	case tradeAuditsTpe:
+		topic = Cfg.TradeAuditsTopic
+	case blockMetricsTpe:
+		topic = Cfg.BlockMetricsTopic
+	case bookSnapshotsTpe:
+		topic = Cfg.OrderBookSnapshotTopic
 	}
 	return
 }
@@ -356,8 +485,10 @@ func (publisher *KafkaMarketDataPublisher) publishWithRetry(
 			backOffInSeconds <<= 1
 			backOffTime := backOffInSeconds * time.Second
 			Logger.Error("encountered retryable error, retrying...", "after", backOffTime, "err", err)
+			state.setRetrying(backOffTime, err)
 			time.Sleep(backOffTime)
 		} else {
+			state.clearRetrying()
 			return
 		}
 	}
@@ -375,6 +506,13 @@ func (publisher *KafkaMarketDataPublisher) isRetryableErr(err error) bool {
 
 func (publisher *KafkaMarketDataPublisher) marshal(msg AvroOrJsonMsg, tpe msgType) ([]byte, error) {
 	native := msg.ToNativeMap()
+	if protoCodec, ok := publisher.protoCodecs[tpe]; ok {
+		bb, err := protoCodec.Marshal(native)
+		if err != nil {
+			Logger.Error("failed to serialize message in protobuf", "msg", msg, "err", err)
+		}
+		return bb, err
+	}
 	var codec *goavro.Codec
 	switch tpe {
 	case accountsTpe:
@@ -403,6 +541,16 @@ func (publisher *KafkaMarketDataPublisher) marshal(msg AvroOrJsonMsg, tpe msgTyp
 		codec = publisher.sideProposalCodec
 	case breatheBlockTpe:
 		codec = publisher.breatheBlockCodec
+	case orderRejectionsTpe:
+		codec = publisher.orderRejectionsCodec
+	case orderAcksTpe:
+		codec = publisher.orderAcksCodec
+	case tradeAuditsTpe:
+		codec = publisher.tradeAuditsCodec
+	case blockMetricsTpe:
+		codec = publisher.blockMetricsCodec
+	case bookSnapshotsTpe:
+		codec = publisher.bookSnapshotsCodec
 	default:
 		return nil, fmt.Errorf("doesn't support marshal kafka msg tpe: %s", tpe.String())
 	}
@@ -440,6 +588,28 @@ func (publisher *KafkaMarketDataPublisher) initAvroCodecs() (err error) {
 		return err
 	} else if publisher.breatheBlockCodec, err = goavro.NewCodec(breatheBlockSchema); err != nil {
 		return err
+	} else if publisher.orderRejectionsCodec, err = goavro.NewCodec(orderRejectionsSchema); err != nil {
+		return err
+	} else if publisher.orderAcksCodec, err = goavro.NewCodec(orderAcksSchema); err != nil {
+		return err
+	} else if publisher.tradeAuditsCodec, err = goavro.NewCodec(tradeAuditsSchema); err != nil {
+		return err
+	} else if publisher.blockMetricsCodec, err = goavro.NewCodec(blockMetricsSchema); err != nil {
+		return err
+	} else if publisher.bookSnapshotsCodec, err = goavro.NewCodec(bookSnapshotsSchema); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (publisher *KafkaMarketDataPublisher) initProtoCodecs() error {
+	publisher.protoCodecs = make(map[msgType]*protoCodec, len(protobufMsgTypes))
+	for tpe, schema := range protobufMsgTypes {
+		codec, err := newProtoCodec(schema)
+		if err != nil {
+			return fmt.Errorf("failed to create protobuf codec of %s: %v", tpe.String(), err)
+		}
+		publisher.protoCodecs[tpe] = codec
 	}
 	return nil
 }
@@ -458,6 +628,16 @@ func NewKafkaMarketDataPublisher(
 		Logger.Error("failed to initialize avro codec", "err", err)
 		panic(err)
 	}
+	switch Cfg.KafkaEncoding {
+	case "", KafkaEncodingAvro:
+	case KafkaEncodingProtobuf:
+		if err := publisher.initProtoCodecs(); err != nil {
+			Logger.Error("failed to initialize protobuf codec", "err", err)
+			panic(err)
+		}
+	default:
+		panic(fmt.Sprintf("unsupported kafka encoding %s", Cfg.KafkaEncoding))
+	}
 
 	if saramaCfg, err := publisher.newProducers(); err != nil {
 		logger.Error("failed to create new kafka producer", "err", err)
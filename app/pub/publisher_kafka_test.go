@@ -0,0 +1,61 @@
+package pub
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareMessages_SplitOversizedBlock(t *testing.T) {
+	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
+	defer func() { Cfg.KafkaMaxMessageBytes = 0 }()
+
+	books := make([]OrderBookDelta, 0, 100)
+	for i := 0; i < 100; i++ {
+		levels := make([]PriceLevel, 0, 50)
+		for j := 0; j < 50; j++ {
+			levels = append(levels, PriceLevel{int64(j+1) * 1e8, int64(i+1) * 1e8})
+		}
+		books = append(books, OrderBookDelta{fmt.Sprintf("XYZ-%03d_BNB", i), levels, levels})
+	}
+	msg := Books{42, 100, len(books), books}
+	payload, err := publisher.marshal(&msg, booksTpe)
+	require.NoError(t, err)
+
+	// kept as a whole if it fits
+	Cfg.KafkaMaxMessageBytes = 0
+	require.Len(t, publisher.prepareMessages("books", "42", 100, booksTpe, payload), 1)
+	Cfg.KafkaMaxMessageBytes = len(payload)
+	kafkaMsgs := publisher.prepareMessages("books", "42", 100, booksTpe, payload)
+	require.Len(t, kafkaMsgs, 1)
+	key, _ := kafkaMsgs[0].Key.Encode()
+	require.Equal(t, messageKey("42", 100, booksTpe), string(key))
+	require.Empty(t, kafkaMsgs[0].Headers)
+
+	Cfg.KafkaMaxMessageBytes = 10 * 1024
+	kafkaMsgs = publisher.prepareMessages("books", "42", 100, booksTpe, payload)
+	parts := (len(payload) + Cfg.KafkaMaxMessageBytes - 1) / Cfg.KafkaMaxMessageBytes
+	require.True(t, parts > 1)
+	require.Len(t, kafkaMsgs, parts)
+
+	var reassembled bytes.Buffer
+	for idx, kafkaMsg := range kafkaMsgs {
+		key, _ := kafkaMsg.Key.Encode()
+		require.Equal(t, messageKey("42", 100, booksTpe), string(key))
+		require.Equal(t, []sarama.RecordHeader{
+			{Key: []byte(partHeader), Value: []byte(strconv.Itoa(idx))},
+			{Key: []byte(partsHeader), Value: []byte(strconv.Itoa(parts))},
+		}, kafkaMsg.Headers)
+		value, _ := kafkaMsg.Value.Encode()
+		require.True(t, len(value) <= Cfg.KafkaMaxMessageBytes)
+		reassembled.Write(value)
+	}
+	require.Equal(t, payload, reassembled.Bytes())
+	native, _, err := publisher.booksCodec.NativeFromBinary(reassembled.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, int64(42), native.(map[string]interface{})["height"])
+}
@@ -13,6 +13,11 @@ type MockMarketDataPublisher struct {
 	BlockFeePublished         []BlockFee
 	TransferPublished         []Transfers
 	BlockPublished            []*Block
+	OrderRejectionsPublished  []*OrderRejections
+	OrderAcksPublished        []*OrderAcks
+	TradeAuditsPublished      []*TradeAudits
+	BlockMetricsPublished     []*BlockMetrics
+	BookSnapshotsPublished    []*BookSnapshots
 
 	Lock             *sync.Mutex // as mock publisher is only used in testing, its no harm to have this granularity Lock
 	MessagePublished uint32      // atomic integer used to determine the published messages
@@ -35,6 +40,16 @@ func (publisher *MockMarketDataPublisher) publish(msg AvroOrJsonMsg, tpe msgType
 		publisher.TransferPublished = append(publisher.TransferPublished, msg.(Transfers))
 	case blockTpe:
 		publisher.BlockPublished = append(publisher.BlockPublished, msg.(*Block))
+	case orderRejectionsTpe:
+		publisher.OrderRejectionsPublished = append(publisher.OrderRejectionsPublished, msg.(*OrderRejections))
+	case orderAcksTpe:
+		publisher.OrderAcksPublished = append(publisher.OrderAcksPublished, msg.(*OrderAcks))
+	case tradeAuditsTpe:
+		publisher.TradeAuditsPublished = append(publisher.TradeAuditsPublished, msg.(*TradeAudits))
+	case blockMetricsTpe:
+		publisher.BlockMetricsPublished = append(publisher.BlockMetricsPublished, msg.(*BlockMetrics))
+	case bookSnapshotsTpe:
+		publisher.BookSnapshotsPublished = append(publisher.BookSnapshotsPublished, msg.(*BookSnapshots))
 	default:
 		panic(fmt.Errorf("does not support type %s", tpe.String()))
 	}
@@ -59,6 +74,11 @@ func NewMockMarketDataPublisher() (publisher *MockMarketDataPublisher) {
 		make([]BlockFee, 0),
 		make([]Transfers, 0),
 		make([]*Block, 0),
+		make([]*OrderRejections, 0),
+		make([]*OrderAcks, 0),
+		make([]*TradeAudits, 0),
+		make([]*BlockMetrics, 0),
+		make([]*BookSnapshots, 0),
 		&sync.Mutex{},
 		0,
 	}
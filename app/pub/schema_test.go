@@ -10,6 +10,7 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/bnb-chain/node/app/config"
 	"github.com/bnb-chain/node/common/log"
@@ -40,9 +41,9 @@ func TestExecutionResultsMarshaling(t *testing.T) {
 	orders := Orders{
 		NumOfMsgs: 3,
 		Orders: []*Order{
-			{"NNB_BNB", orderPkg.Ack, "b-1", "", "b", orderPkg.Side.BUY, orderPkg.OrderType.LIMIT, 100, 100, 0, 0, 0, "", 100, 100, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", ""},
-			{"NNB_BNB", orderPkg.FullyFill, "b-1", "42-0", "b", orderPkg.Side.BUY, orderPkg.OrderType.LIMIT, 100, 100, 100, 100, 100, "BNB:10;BTC:1", 100, 100, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "BNB:10;BTC:1"},
-			{"NNB_BNB", orderPkg.FullyFill, "s-1", "42-0", "s", orderPkg.Side.SELL, orderPkg.OrderType.LIMIT, 100, 100, 100, 100, 100, "BNB:8;ETH:1", 99, 99, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "BNB:8;ETH:1"},
+			{"NNB_BNB", orderPkg.Ack, "b-1", "", "b", orderPkg.Side.BUY, orderPkg.OrderType.LIMIT, 100, 100, 0, 0, 0, "", 100, 100, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "", 0, ""},
+			{"NNB_BNB", orderPkg.FullyFill, "b-1", "42-0", "b", orderPkg.Side.BUY, orderPkg.OrderType.LIMIT, 100, 100, 100, 100, 100, "BNB:10;BTC:1", 100, 100, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "BNB:10;BTC:1", 0, ""},
+			{"NNB_BNB", orderPkg.FullyFill, "s-1", "42-0", "s", orderPkg.Side.SELL, orderPkg.OrderType.LIMIT, 100, 100, 100, 100, 100, "BNB:8;ETH:1", 99, 99, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "BNB:8;ETH:1", 0, ""},
 		},
 	}
 	proposals := Proposals{
@@ -91,10 +92,36 @@ func TestBooksMarshaling(t *testing.T) {
 	}
 }
 
+func TestBookSnapshotsMarshaling(t *testing.T) {
+	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
+	book := OrderBookDelta{"NNB_BNB", []PriceLevel{{100, 100}, {99, 200}}, []PriceLevel{{101, 100}}}
+	msg := BookSnapshots{42, 100, 1, []OrderBookDelta{book}}
+	_, err := publisher.marshal(&msg, bookSnapshotsTpe)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPublishOrderBookSnapshots(t *testing.T) {
+	publisher := NewMockMarketDataPublisher()
+	publishOrderBookSnapshots(publisher, 42, 100, orderPkg.ChangedPriceLevelsMap{
+		"XYZ-000_BNB": {Buys: map[int64]int64{99: 1, 100: 2}, Sells: map[int64]int64{102: 3, 101: 4}},
+		"ABC-000_BNB": {Buys: map[int64]int64{}, Sells: map[int64]int64{}},
+	})
+	require.Len(t, publisher.BookSnapshotsPublished, 1)
+	snapshots := publisher.BookSnapshotsPublished[0]
+	require.Equal(t, int64(42), snapshots.Height)
+	require.Equal(t, 2, snapshots.NumOfMsgs)
+	require.Equal(t, []OrderBookDelta{
+		{"ABC-000_BNB", []PriceLevel{}, []PriceLevel{}},
+		{"XYZ-000_BNB", []PriceLevel{{100, 2}, {99, 1}}, []PriceLevel{{101, 4}, {102, 3}}},
+	}, snapshots.Books)
+}
+
 func TestAccountsMarshaling(t *testing.T) {
 	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
 	accs := []Account{{"b-1", "BNB:1000;BTC:10", 0, []*AssetBalance{{Asset: "BNB", Free: 100}}}}
-	msg := Accounts{42, 2, accs}
+	msg := Accounts{42, 2, accs, 0}
 	_, err := publisher.marshal(&msg, accountsTpe)
 	if err != nil {
 		t.Fatal(err)
@@ -103,7 +130,7 @@ func TestAccountsMarshaling(t *testing.T) {
 
 func TestBlockFeeMarshaling(t *testing.T) {
 	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
-	msg := BlockFee{1, "BNB:1000;BTC:10", []string{"bnc1", "bnc2", "bnc3"}}
+	msg := BlockFee{1, "BNB:1000;BTC:10", []string{"bnc1", "bnc2", "bnc3"}, []FeeSplit{{"bnc4", "BNB:100"}, {"", "BNB:50"}}, []FeeConversion{{"XYZ-000_BNB", "order-1", 1e8, 5e7, 1e6}}}
 	_, err := publisher.marshal(&msg, blockFeeTpe)
 	if err != nil {
 		t.Fatal(err)
@@ -181,6 +208,38 @@ func TestSideProposalMarsha(t *testing.T) {
 	}
 }
 
+func TestOrderRejectionsMarshaling(t *testing.T) {
+	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
+	msg := OrderRejections{
+		Height:    10,
+		Timestamp: time.Now().Unix(),
+		NumOfMsgs: 1,
+		Rejections: []*OrderRejection{
+			{OrderId: "b-1", Symbol: "NNB_BNB", Owner: "b", Side: 1, Price: 100, Qty: 100, TimeInForce: 1, TxHash: "xxxx", Code: 393617, Reason: "price(101) is not rounded to tickSize(100)"},
+		},
+	}
+	_, err := publisher.marshal(&msg, orderRejectionsTpe)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOrderAcksMarshaling(t *testing.T) {
+	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
+	msg := OrderAcks{
+		Height:    10,
+		Timestamp: time.Now().Unix(),
+		NumOfMsgs: 1,
+		Acks: []*OrderAck{
+			{OrderId: "b-1", Symbol: "NNB_BNB", Owner: "b", Side: 1, Price: 100, Qty: 100, TimeInForce: 1, TxHash: "xxxx"},
+		},
+	}
+	_, err := publisher.marshal(&msg, orderAcksTpe)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestStakingMarshaling(t *testing.T) {
 	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
 	valAddr, _ := sdk.ValAddressFromBech32("bva1e2y8w2rz957lahwy0y5h3w53sm8d78qexkn3rh")
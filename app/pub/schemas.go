@@ -4,6 +4,7 @@ package pub
 // consumers should aware of changes in this file
 // update app/pub/msgs.go `latestSchemaVersions`
 // put old version into pub/schemas with version suffixed to filename for tracking historical version
+// update the protobuf schemas in pub/schemas/*.proto of the messages in `protobufMsgTypes`
 
 // Backward compatibility:
 // 1. publisher add field, consumer should initialize two decoder with two publisher schema, choose which decoder should be used by `lastestSchemaVersion` component in kafka message Key
@@ -47,7 +48,9 @@ const (
                                         { "name": "bsrc", "type": "long" },
                                         { "name": "ssinglefee", "type": "string" },
                                         { "name": "bsinglefee", "type": "string" },
-                                        { "name": "tickType", "type": "int" }
+                                        { "name": "tickType", "type": "int" },
+                                        { "name": "sfeeExempt", "type": "boolean", "default": false },
+                                        { "name": "bfeeExempt", "type": "boolean", "default": false }
                                     ]
                                 }
                             }
@@ -86,7 +89,9 @@ const (
                                     { "name": "timeInForce", "type": "int" },
                                     { "name": "currentExecutionType", "type": "string" },
                                     { "name": "txHash", "type": "string" },
-                                    { "name": "singlefee", "type": "string" }
+                                    { "name": "singlefee", "type": "string" },
+                                    { "name": "remainingLocked", "type": "long", "default": 0 },
+                                    { "name": "memo", "type": "string", "default": "" }
                                 ]
                             }
                            }
@@ -146,7 +151,37 @@ const (
                            }
                         }
                     ]
-                }], "default": null }
+                }], "default": null },
+                { "name": "matchingPaused", "type": "boolean", "default": false },
+                { "name": "isBreatheBlock", "type": "boolean", "default": false },
+                { "name": "haltEvent", "type": "string", "default": "" },
+                { "name": "sessionEvents", "type": {
+                    "type": "array",
+                    "items":
+                    {
+                        "type": "record",
+                        "name": "SessionEvent",
+                        "namespace": "org.binance.dex.model.avro",
+                        "fields": [
+                            { "name": "symbol", "type": "string" },
+                            { "name": "event", "type": "string" }
+                        ]
+                    }
+                }, "default": [] },
+                { "name": "tokenTradingEvents", "type": {
+                    "type": "array",
+                    "items":
+                    {
+                        "type": "record",
+                        "name": "TokenTradingEvent",
+                        "namespace": "org.binance.dex.model.avro",
+                        "fields": [
+                            { "name": "symbol", "type": "string" },
+                            { "name": "token", "type": "string" },
+                            { "name": "disabled", "type": "boolean" }
+                        ]
+                    }
+                }, "default": [] }
             ]
         }
     `
@@ -230,7 +265,8 @@ const (
                             ]
                         }
                    }, "default": []
-                }
+                },
+                { "name": "skippedSince", "type": "long", "default": 0 }
             ]
         }
     `
@@ -243,7 +279,32 @@ const (
             "fields": [
                 { "name": "height", "type": "long"},
                 { "name": "fee", "type": "string"},
-                { "name": "validators", "type": { "type": "array", "items": "string" }}
+                { "name": "validators", "type": { "type": "array", "items": "string" }},
+                { "name": "splits", "type": { "type": "array", "items":
+                    {
+                        "type": "record",
+                        "name": "FeeSplit",
+                        "namespace": "com.company",
+                        "fields": [
+                            { "name": "address", "type": "string" },
+                            { "name": "fee", "type": "string" }
+                        ]
+                    }
+                }, "default": [] },
+                { "name": "conversions", "type": { "type": "array", "items":
+                    {
+                        "type": "record",
+                        "name": "FeeConversion",
+                        "namespace": "com.company",
+                        "fields": [
+                            { "name": "symbol", "type": "string" },
+                            { "name": "orderId", "type": "string" },
+                            { "name": "quantity", "type": "long" },
+                            { "name": "sold", "type": "long" },
+                            { "name": "received", "type": "long" }
+                        ]
+                    }
+                }, "default": [] }
             ]
         }
     `
@@ -963,7 +1024,178 @@ const (
 			"namespace": "org.binance.dex.model.avro",
 			"fields": [
 				{"name": "height", "type": "long"},
-				{"name": "timestamp", "type": "long"}
+				{"name": "timestamp", "type": "long"},
+				{"name": "pairs", "type": {"type": "array", "items":
+					{
+						"type": "record",
+						"name": "PairSizes",
+						"namespace": "org.binance.dex.model.avro",
+						"fields": [
+							{"name": "symbol", "type": "string"},
+							{"name": "tickSize", "type": "long"},
+							{"name": "lotSize", "type": "long"}
+						]
+					}
+				}, "default": []}
+			]
+		}
+	`
+
+	orderRejectionsSchema = `
+		{
+			"type": "record",
+			"name": "OrderRejections",
+			"namespace": "org.binance.dex.model.avro",
+			"fields": [
+				{"name": "height", "type": "long"},
+				{"name": "timestamp", "type": "long"},
+				{"name": "numOfMsgs", "type": "int"},
+				{"name": "rejections", "type": {
+					"type": "array",
+					"items": {
+						"type": "record",
+						"name": "OrderRejection",
+						"namespace": "org.binance.dex.model.avro",
+						"fields": [
+							{"name": "orderId", "type": "string"},
+							{"name": "symbol", "type": "string"},
+							{"name": "owner", "type": "string"},
+							{"name": "side", "type": "int"},
+							{"name": "price", "type": "long"},
+							{"name": "qty", "type": "long"},
+							{"name": "timeInForce", "type": "int"},
+							{"name": "txHash", "type": "string"},
+							{"name": "code", "type": "long"},
+							{"name": "reason", "type": "string"}
+						]
+					}
+				}}
+			]
+		}
+	`
+
+	orderAcksSchema = `
+		{
+			"type": "record",
+			"name": "OrderAcks",
+			"namespace": "org.binance.dex.model.avro",
+			"fields": [
+				{"name": "height", "type": "long"},
+				{"name": "timestamp", "type": "long"},
+				{"name": "numOfMsgs", "type": "int"},
+				{"name": "acks", "type": {
+					"type": "array",
+					"items": {
+						"type": "record",
+						"name": "OrderAck",
+						"namespace": "org.binance.dex.model.avro",
+						"fields": [
+							{"name": "orderId", "type": "string"},
+							{"name": "symbol", "type": "string"},
+							{"name": "owner", "type": "string"},
+							{"name": "side", "type": "int"},
+							{"name": "price", "type": "long"},
+							{"name": "qty", "type": "long"},
+							{"name": "timeInForce", "type": "int"},
+							{"name": "txHash", "type": "string"}
+						]
+					}
+				}}
+			]
+		}
+	`
+
+	tradeAuditsSchema = `
+		{
+			"type": "record",
+			"name": "TradeAudits",
+			"namespace": "org.binance.dex.model.avro",
+			"fields": [
+				{"name": "height", "type": "long"},
+				{"name": "timestamp", "type": "long"},
+				{"name": "numOfMsgs", "type": "int"},
+				{"name": "trades", "type": {
+					"type": "array",
+					"items": {
+						"type": "record",
+						"name": "TradeAudit",
+						"namespace": "org.binance.dex.model.avro",
+						"fields": [
+							{"name": "id", "type": "string"},
+							{"name": "symbol", "type": "string"},
+							{"name": "price", "type": "long"},
+							{"name": "qty", "type": "long"},
+							{"name": "tickType", "type": "int"},
+							{"name": "maker", "type": "string"},
+							{"name": "sid", "type": "string"},
+							{"name": "saddr", "type": "string"},
+							{"name": "sfee", "type": "string"},
+							{"name": "stxHash", "type": "string"},
+							{"name": "ssrc", "type": "long"},
+							{"name": "bid", "type": "string"},
+							{"name": "baddr", "type": "string"},
+							{"name": "bfee", "type": "string"},
+							{"name": "btxHash", "type": "string"},
+							{"name": "bsrc", "type": "long"}
+						]
+					}
+				}}
+			]
+		}
+	`
+
+	blockMetricsSchema = `
+		{
+			"type": "record",
+			"name": "BlockMetrics",
+			"namespace": "org.binance.dex.model.avro",
+			"fields": [
+				{"name": "height", "type": "long"},
+				{"name": "timestamp", "type": "long"},
+				{"name": "matchingTimeMs", "type": "long"},
+				{"name": "numOfOrders", "type": "int"},
+				{"name": "numOfTrades", "type": "int"},
+				{"name": "collectTimeMs", "type": "long"}
+			]
+		}
+	`
+
+	bookSnapshotsSchema = `
+		{
+			"type": "record",
+			"name": "BookSnapshots",
+			"namespace": "com.company",
+			"fields": [
+				{"name": "height", "type": "long"},
+				{"name": "timestamp", "type": "long"},
+				{"name": "numOfMsgs", "type": "int"},
+				{"name": "books", "type": {
+					"type": "array",
+					"items": {
+						"type": "record",
+						"name": "OrderBookDelta",
+						"namespace": "com.company",
+						"fields": [
+							{"name": "symbol", "type": "string"},
+							{"name": "buys", "type": {
+								"type": "array",
+								"items": {
+									"type": "record",
+									"name": "PriceLevel",
+									"namespace": "com.company",
+									"fields": [
+										{"name": "price", "type": "long"},
+										{"name": "lastQty", "type": "long"}
+									]
+								}
+							}},
+							{"name": "sells", "type": {
+								"type": "array",
+								"items": "com.company.PriceLevel"
+							}}
+						]
+					}
+				}, "default": []}
 			]
 		}
 	`
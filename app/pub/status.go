@@ -0,0 +1,109 @@
+package pub
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Status is the state of the publication on this node, for query usage.
+type Status struct {
+	IsLive              bool   `json:"is_live"`
+	LastPublishedHeight int64  `json:"last_published_height"` // 0 if no block is published since the node started
+	QueueSize           int    `json:"queue_size"`            // number of the blocks waiting to be published
+	QueueCapacity       int    `json:"queue_capacity"`
+	Retrying            bool   `json:"retrying"`              // whether a kafka message is being retried
+	RetryBackoffSeconds int64  `json:"retry_backoff_seconds"` // the backoff before the next retry
+	LastRetryError      string `json:"last_retry_error"`
+	Degraded            bool   `json:"degraded"`       // whether the publication is degraded under memory pressure
+	DegradedSince       int64  `json:"degraded_since"` // the height since which it's degraded, 0 if it's not
+}
+
+// publicationState keeps the progress of the publication updated by the publishing goroutines.
+type publicationState struct {
+	mtx                 sync.Mutex
+	lastPublishedHeight int64
+	retrying            bool
+	retryBackoff        time.Duration
+	lastRetryError      string
+	degradedSince       int64 // 0 if the publication is not degraded
+}
+
+var state = &publicationState{}
+
+func (s *publicationState) setPublished(height int64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.lastPublishedHeight = height
+}
+
+func (s *publicationState) setRetrying(backoff time.Duration, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.retrying = true
+	s.retryBackoff = backoff
+	s.lastRetryError = err.Error()
+}
+
+func (s *publicationState) clearRetrying() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.retrying = false
+	s.retryBackoff = 0
+}
+
+// resumeFullPublicationRatio is the share of the memory threshold the heap has to drop below to resume the full
+// publication, so that it doesn't flap around the threshold.
+const resumeFullPublicationRatio = 0.9
+
+// CheckMemoryPressure decides whether the publication of the block at the height is degraded, by the bytes of
+// the allocated heap against the threshold in MB, 0 disables it. The degraded publication only publishes the
+// trades and order changes, and the account balances, order book changes, transfers and blocks are not collected.
+// Once the heap drops below resumeFullPublicationRatio of the threshold, the full publication resumes and
+// skippedSince is the first height degraded, see Accounts.SkippedSince. It's 0 in all the other blocks.
+func CheckMemoryPressure(height int64, thresholdMB int64) (degraded bool, skippedSince int64) {
+	if thresholdMB <= 0 {
+		return false, 0
+	}
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return state.updateMemoryPressure(height, stats.HeapAlloc, uint64(thresholdMB)<<20)
+}
+
+func (s *publicationState) updateMemoryPressure(height int64, heapBytes, thresholdBytes uint64) (degraded bool, skippedSince int64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.degradedSince == 0 {
+		if heapBytes < thresholdBytes {
+			return false, 0
+		}
+		s.degradedSince = height
+		return true, 0
+	}
+	if float64(heapBytes) >= float64(thresholdBytes)*resumeFullPublicationRatio {
+		return true, 0
+	}
+	skippedSince = s.degradedSince
+	s.degradedSince = 0
+	return false, skippedSince
+}
+
+// GetStatus returns the current state of the publication.
+func GetStatus() Status {
+	state.mtx.Lock()
+	status := Status{
+		IsLive:              IsLive,
+		LastPublishedHeight: state.lastPublishedHeight,
+		Retrying:            state.retrying,
+		RetryBackoffSeconds: int64(state.retryBackoff / time.Second),
+		LastRetryError:      state.lastRetryError,
+		Degraded:            state.degradedSince > 0,
+		DegradedSince:       state.degradedSince,
+	}
+	state.mtx.Unlock()
+	if ToPublishCh != nil {
+		status.QueueSize = len(ToPublishCh)
+		status.QueueCapacity = cap(ToPublishCh)
+	}
+	return status
+}
@@ -0,0 +1,38 @@
+package pub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicationState_MemoryPressure(t *testing.T) {
+	s := &publicationState{}
+	const threshold = 100
+
+	degraded, skippedSince := s.updateMemoryPressure(10, 99, threshold)
+	require.False(t, degraded)
+	require.Zero(t, skippedSince)
+
+	degraded, skippedSince = s.updateMemoryPressure(11, 100, threshold)
+	require.True(t, degraded)
+	require.Zero(t, skippedSince)
+	// stays degraded until the heap drops below 90% of the threshold
+	degraded, _ = s.updateMemoryPressure(12, 95, threshold)
+	require.True(t, degraded)
+	degraded, _ = s.updateMemoryPressure(13, 90, threshold)
+	require.True(t, degraded)
+
+	// the marker is only set in the block the full publication resumes at
+	degraded, skippedSince = s.updateMemoryPressure(14, 89, threshold)
+	require.False(t, degraded)
+	require.Equal(t, int64(11), skippedSince)
+	degraded, skippedSince = s.updateMemoryPressure(15, 89, threshold)
+	require.False(t, degraded)
+	require.Zero(t, skippedSince)
+
+	// disabled
+	degraded, skippedSince = CheckMemoryPressure(16, 0)
+	require.False(t, degraded)
+	require.Zero(t, skippedSince)
+}
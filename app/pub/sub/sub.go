@@ -5,6 +5,7 @@ import (
 
 	"github.com/bnb-chain/node/app/config"
 	"github.com/bnb-chain/node/plugins/bridge"
+	dextypes "github.com/bnb-chain/node/plugins/dex/types"
 
 	"github.com/cosmos/cosmos-sdk/pubsub"
 )
@@ -68,6 +69,8 @@ type ToPublishEvent struct {
 	Timestamp      time.Time
 	IsBreatheBlock bool
 	EventData      *EventStore
+	// trading pairs whose tick size or lot size is changed in the breathe block
+	PairSizesUpdates []dextypes.TradingPair
 }
 
 type EventStore struct {
@@ -100,6 +103,10 @@ func SetMeta(height int64, timestamp time.Time, isBreatheBlock bool) {
 	toPublish.IsBreatheBlock = isBreatheBlock
 }
 
+func SetPairSizesUpdates(pairs []dextypes.TradingPair) {
+	toPublish.PairSizesUpdates = pairs
+}
+
 func commit(cfg *config.PublicationConfig) {
 	if cfg.PublishStaking {
 		commitStake()
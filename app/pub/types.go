@@ -20,6 +20,17 @@ type BlockInfoToPublish struct {
 	feeHolder          orderPkg.FeeHolder
 	transfers          *Transfers
 	block              *Block
+	matchingPaused     bool
+	haltEvent          string
+	sessionEvents      []orderPkg.SessionEvent
+	tokenTradingEvents []orderPkg.TokenTradingEvent
+	orderRejections    []orderPkg.OrderRejection
+	orderAcks          []orderPkg.OrderAck
+	isBreatheBlock     bool
+	degraded           bool                           // only the trades and order changes are published, see CheckMemoryPressure
+	skippedSince       int64                          // see Accounts.SkippedSince
+	blockMetrics       *BlockMetrics                  // nil if not published
+	bookSnapshots      orderPkg.ChangedPriceLevelsMap // full order books, nil if not a snapshot height
 }
 
 func NewBlockInfoToPublish(
@@ -34,7 +45,9 @@ func NewBlockInfoToPublish(
 	accounts map[string]Account,
 	latestPriceLevels orderPkg.ChangedPriceLevelsMap,
 	blockFee BlockFee,
-	feeHolder orderPkg.FeeHolder, transfers *Transfers, block *Block) BlockInfoToPublish {
+	feeHolder orderPkg.FeeHolder, transfers *Transfers, block *Block, matchingPaused bool, haltEvent string,
+	sessionEvents []orderPkg.SessionEvent, tokenTradingEvents []orderPkg.TokenTradingEvent, orderRejections []orderPkg.OrderRejection, orderAcks []orderPkg.OrderAck, isBreatheBlock bool,
+	degraded bool, skippedSince int64, blockMetrics *BlockMetrics, bookSnapshots orderPkg.ChangedPriceLevelsMap) BlockInfoToPublish {
 	return BlockInfoToPublish{
 		height,
 		timestamp,
@@ -50,5 +63,16 @@ func NewBlockInfoToPublish(
 		feeHolder,
 		transfers,
 		block,
+		matchingPaused,
+		haltEvent,
+		sessionEvents,
+		tokenTradingEvents,
+		orderRejections,
+		orderAcks,
+		isBreatheBlock,
+		degraded,
+		skippedSince,
+		blockMetrics,
+		bookSnapshots,
 	}
 }
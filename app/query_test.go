@@ -0,0 +1,99 @@
+package app
+
+import (
+	"testing"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+
+	bncfees "github.com/bnb-chain/node/common/fees"
+	"github.com/bnb-chain/node/common/types"
+	dextypes "github.com/bnb-chain/node/plugins/dex/types"
+)
+
+func TestQuery_MalformedRequests(t *testing.T) {
+	_, require, app, _, _ := setupAppTest(t)
+	// the pairs are listed in the committed state, so that the ranges of the pairs query are checked
+	for _, symbol := range []string{"XYZ-000", "ZCB-000"} {
+		require.NoError(app.DexKeeper.PairMapper.AddTradingPair(app.CheckState.Ctx, dextypes.NewTradingPair(symbol, "BNB", 102000)))
+	}
+
+	for _, req := range []abci.RequestQuery{
+		{Path: "/account/bnb1invalid"},
+		{Path: "/account/assets/bnb1invalid"},
+		{Path: "/account/locks/bnb1invalid"},
+		{Path: "/account/spendable/bnb1invalid"},
+		{Path: "/dex/pairs/x/y"},
+		{Path: "/dex/pairs/0/-1"},
+		{Path: "/dex/orderbook"},
+		{Path: "/dex/orderbook/XYZ-000_BNB/-1"},
+		{Path: "/dex/orderbook/XYZ-000_BNB/10/0/-1"},
+		{Path: "/dex/openorders/XYZ-000_BNB/bnb1invalid"},
+		{Path: "/dex/expiring/x/0/10"},
+		{Path: "/dex/accountrisk/bnb1invalid"},
+		{Path: "/dex/history/yesterday/XYZ-000_BNB"},
+		{Path: "/dex/orderfills/1/-1/10"},
+		{Path: "/dex/accounttrades/bnb1invalid/0/10"},
+		{Path: "/dex/cancelled/bnb1invalid/0/10"},
+		{Path: "/dex/toptraders/XYZ-000_BNB/x/y"},
+		{Path: "/dex/activesymbols/x"},
+		{Path: "/dex/quotes"},
+		{Path: "/dex/twap/XYZ-000_BNB/-1"},
+		{Path: "/dex/imbalancetrend/XYZ-000_BNB/0"},
+		{Path: "/dex/traderate/1001"},
+		{Path: "/dex/slippage/XYZ-000_BNB/UP/1"},
+		{Path: "/dex/slippage/XYZ-000_BNB/BUY/1/hex"},
+		{Path: "/dex/sizedist/XYZ-000_BNB/10,5"},
+		{Path: "/tokens/info"},
+		{Path: "/tokens/list/x/y"},
+		{Path: "/tokens/list/0/-1"},
+		{Path: "/fees/byaccount/bnb1invalid"},
+		{Path: "/tx/status/not-hex"},
+		{Path: "/custom/gov/proposal", Data: []byte("{")},
+		{Path: "/custom/stake/validator", Data: []byte("{")},
+		{Path: "/custom/timelock/timelocks", Data: []byte("{")},
+		{Path: "/custom/atomicSwap/swapcreator", Data: []byte{0xff, 0x00}},
+	} {
+		res := app.Query(req)
+		require.False(sdk.ABCICodeType(res.Code).IsOK(), req.Path)
+		// rejected by the handlers rather than panicking
+		require.NotContains(res.Log, "malformed query", req.Path)
+	}
+
+	app.RegisterQueryHandler("malformed", func(types.ChainApp, abci.RequestQuery, []string) *abci.ResponseQuery {
+		panic(types.MalformedQueryError{Reason: "invalid param"})
+	})
+	res := app.Query(abci.RequestQuery{Path: "/malformed/x"})
+	require.Equal(sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnknownRequest), sdk.ABCICodeType(res.Code))
+	require.Contains(res.Log, "malformed query /malformed/x: invalid param")
+
+	// the other panics are bugs rather than malformed queries, they are answered as internal errors
+	app.RegisterQueryHandler("panicking", func(types.ChainApp, abci.RequestQuery, []string) *abci.ResponseQuery {
+		var path []string
+		_ = path[1]
+		return nil
+	})
+	res = app.Query(abci.RequestQuery{Path: "/panicking/x"})
+	require.Equal(sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeInternal), sdk.ABCICodeType(res.Code))
+	require.Contains(res.Log, "failed to query /panicking/x")
+	require.NotContains(res.Log, "malformed query")
+}
+
+func TestQuery_FeesPool(t *testing.T) {
+	assert, require, app, _, _ := setupAppTest(t)
+	ctx := app.CheckState.Ctx
+	_, _, err := app.CoinKeeper.AddCoins(ctx, stake.FeeCollectorAddr, sdk.Coins{sdk.NewCoin("BNB", 100), sdk.NewCoin("XYZ-000", 10)})
+	require.NoError(err)
+	_, _, err = app.CoinKeeper.AddCoins(ctx, stake.FeeForAllAccAddr, sdk.Coins{sdk.NewCoin("ABC-000", 5), sdk.NewCoin("BNB", 1000)})
+	require.NoError(err)
+
+	res := app.Query(abci.RequestQuery{Path: "/fees/pool"})
+	require.Equal(uint32(sdk.ABCICodeOK), res.Code, res.Log)
+	var pool bncfees.Pool
+	require.NoError(app.Codec.UnmarshalBinaryLengthPrefixed(res.Value, &pool))
+	assert.Equal(sdk.Coins{sdk.NewCoin("BNB", 100), sdk.NewCoin("XYZ-000", 10)}, pool.Collected)
+	assert.Equal(sdk.Coins{sdk.NewCoin("ABC-000", 5), sdk.NewCoin("BNB", 1000)}, pool.Accumulated)
+	assert.Equal(sdk.Coins{sdk.NewCoin("ABC-000", 5), sdk.NewCoin("BNB", 1100), sdk.NewCoin("XYZ-000", 10)}, pool.Total)
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	tmstore "github.com/tendermint/tendermint/store"
+
+	"github.com/bnb-chain/node/app/config"
+	"github.com/bnb-chain/node/app/pub"
+	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/dex"
+	"github.com/bnb-chain/node/wire"
+)
+
+const replayDir = "replay"
+
+// ReplayPublication re-publishes the market data of the blocks in [fromHeight, toHeight] to Kafka, e.g. after
+// an outage of the consumers. The data is published to the topics with the suffix, so that it's never mixed
+// up with the live data. It must run on a stopped node, as the databases of the node are opened.
+//
+// The order book is rebuilt from the snapshot of the last breathe block before fromHeight and the dex txs in the
+// stored blocks since then, against the trading pairs and dex params of the latest state. So the reconstruction
+// is limited to what can be derived from the dex txs:
+//   - only the trades, order updates and order books are published, the accounts, fees, transfers, blocks,
+//     proposals and staking updates are not, as the historical state of the accounts is not kept;
+//   - the expiries of the IOC orders are not published, and the expiries of the breathe blocks are not replayed,
+//     so the order books drift from the live ones after a breathe block in the range;
+//   - the pairs delisted since the breathe block are not replayed.
+func ReplayPublication(logger log.Logger, baseConfig *config.BaseConfig, publicationConfig *config.PublicationConfig,
+	dbDir string, fromHeight, toHeight int64, topicSuffix string) error {
+	if fromHeight <= 0 || toHeight < fromHeight {
+		return fmt.Errorf("invalid height range [%d, %d]", fromHeight, toHeight)
+	}
+	if topicSuffix == "" {
+		return fmt.Errorf("topic suffix is required, so that the live topics are not published twice")
+	}
+
+	blockDB := baseapp.LoadBlockDB()
+	defer blockDB.Close()
+	blockStore := tmstore.NewBlockStore(blockDB)
+	if toHeight > blockStore.Height() {
+		return fmt.Errorf("block %d is not stored yet, the latest one is %d", toHeight, blockStore.Height())
+	}
+	stateDB := baseapp.LoadStateDB()
+	defer stateDB.Close()
+	appDB := baseapp.LoadDB("application")
+	defer appDB.Close()
+
+	cms := store.NewCommitMultiStore(appDB)
+	for _, name := range common.NonTransientStoreKeyNames {
+		cms.MountStoreWithDB(common.StoreKeyNameMap[name], sdk.StoreTypeIAVL, nil)
+	}
+	cms.MountStoreWithDB(common.TParamsStoreKey, sdk.StoreTypeTransient, nil)
+	cms.MountStoreWithDB(common.TStakeStoreKey, sdk.StoreTypeTransient, nil)
+	if err := cms.LoadLatestVersion(); err != nil {
+		return err
+	}
+	ctx := sdk.NewContext(cms.CacheMultiStore(), abci.Header{}, sdk.RunTxModeCheck, logger)
+
+	cdc := Codec
+	accountKeeper := auth.NewAccountKeeper(cdc, common.AccountStoreKey, types.ProtoAppAccount)
+	pairMapper := dex.NewTradingPairMapper(cdc, common.PairStoreKey)
+	dexKeeper := dex.NewDexKeeper(common.DexStoreKey, accountKeeper, pairMapper,
+		sdk.NewCodespacer().RegisterNext(dex.DefaultCodespace), baseConfig.OrderKeeperConcurrency, cdc, true)
+	txDecoder := wire.ComposeTxDecoders(cdc, defaultTxDecoder)
+	// the order books at the end of the block before the range
+	dexKeeper.Init(ctx, baseConfig.BreatheBlockInterval, baseConfig.BreatheBlockDaysCountBack,
+		blockStore, stateDB, fromHeight-1, txDecoder)
+	dexKeeper.ClearOrderChanges()
+
+	pub.Logger = logger.With("module", "pub")
+	pub.Cfg = replayPublicationConfig(publicationConfig, topicSuffix)
+	if err := pub.SetQtyDisplayPrecisions(pub.Cfg.QtyDisplayPrecisions); err != nil {
+		return err
+	}
+	pub.ToPublishCh = make(chan pub.BlockInfoToPublish, pub.Cfg.PublicationChannelSize)
+	publisher := pub.NewKafkaMarketDataPublisher(pub.Logger, filepath.Join(dbDir, replayDir), true)
+	defer publisher.Stop()
+	done := make(chan struct{})
+	go func() {
+		pub.Publish(publisher, nil, pub.Logger, pub.Cfg, pub.ToPublishCh)
+		close(done)
+	}()
+
+	var lastBlockTime time.Time
+	if meta := blockStore.LoadBlockMeta(fromHeight - 1); meta != nil {
+		lastBlockTime = meta.Header.Time
+	}
+	for height := fromHeight; height <= toHeight; height++ {
+		block := blockStore.LoadBlock(height)
+		if block == nil {
+			close(pub.ToPublishCh)
+			<-done
+			return fmt.Errorf("block %d is not found", height)
+		}
+		dexKeeper.ReplayBlockForPublish(ctx, block, stateDB, txDecoder)
+		breathe := isBreatheBlock(baseConfig.BreatheBlockInterval, height, lastBlockTime, block.Time)
+		lastBlockTime = block.Time
+
+		pub.ToRemoveOrderIdCh = make(chan pub.OrderSymbolId, pub.ToRemoveOrderIdChannelSize)
+		pub.ToPublishCh <- pub.CollectReplayedBlockForPublish(dexKeeper, ctx, height, block.Time.UnixNano(), breathe)
+		for o := range pub.ToRemoveOrderIdCh {
+			dexKeeper.RemoveOrderInfosForPub(o.Symbol, o.Id)
+		}
+		dexKeeper.ClearOrderChanges()
+		logger.Info("replayed publication", "height", height)
+	}
+	close(pub.ToPublishCh)
+	<-done
+	return nil
+}
+
+// replayPublicationConfig keeps only the publications that can be reconstructed, with the topics suffixed
+func replayPublicationConfig(cfg *config.PublicationConfig, topicSuffix string) *config.PublicationConfig {
+	replayCfg := *cfg
+	replayCfg.OrderUpdatesTopic += topicSuffix
+	replayCfg.OrderBookTopic += topicSuffix
+	replayCfg.OrderBookSnapshotTopic += topicSuffix
+
+	replayCfg.PublishAccountBalance = false
+	replayCfg.PublishBlockFee = false
+	replayCfg.PublishTransfer = false
+	replayCfg.PublishBlock = false
+	replayCfg.PublishDistributeReward = false
+	replayCfg.PublishStaking = false
+	replayCfg.PublishSlashing = false
+	replayCfg.PublishCrossTransfer = false
+	replayCfg.PublishMirror = false
+	replayCfg.PublishSideProposal = false
+	replayCfg.PublishBreatheBlock = false
+	replayCfg.PublishOrderRejections = false
+	replayCfg.PublishOrderAcks = false
+	replayCfg.PublishKafka = true
+	replayCfg.PublishLocal = false
+	return &replayCfg
+}
@@ -38,7 +38,7 @@ func runTestSuccess(t *testing.T, symbol string, upgrade bool) {
 
 	openOrders := issueMustSuccessQuery(pair, buyer, assert)
 	require.Len(openOrders, 1)
-	expected := store.OpenOrder{"b-1", pair, utils.Fixed8(102000), utils.Fixed8(3000000), utils.Fixed8(0), int64(100), int64(0), int64(100), int64(0)}
+	expected := store.OpenOrder{"b-1", pair, utils.Fixed8(102000), utils.Fixed8(3000000), utils.Fixed8(0), int64(100), int64(0), int64(100), int64(0), ""}
 	assert.Equal(expected, openOrders[0])
 
 	msg = orderPkg.NewNewOrderMsg(seller, "s-1", orderPkg.Side.SELL, pair, 102000, 1000000)
@@ -46,7 +46,7 @@ func runTestSuccess(t *testing.T, symbol string, upgrade bool) {
 
 	openOrders = issueMustSuccessQuery(pair, seller, assert)
 	require.Len(openOrders, 1)
-	expected = store.OpenOrder{"s-1", pair, 102000, 1000000, 0, 101, 1, 101, 1}
+	expected = store.OpenOrder{"s-1", pair, 102000, 1000000, 0, 101, 1, 101, 1, ""}
 	assert.Equal(expected, openOrders[0])
 
 	ctx = ctx.WithBlockHeader(abci.Header{Height: 101, Time: time.Unix(1, 0)})
@@ -55,7 +55,7 @@ func runTestSuccess(t *testing.T, symbol string, upgrade bool) {
 
 	openOrders = issueMustSuccessQuery(pair, buyer, assert)
 	require.Len(openOrders, 1)
-	expected = store.OpenOrder{"b-1", pair, 102000, 3000000, 1000000, 100, 0, 101, 1000000000}
+	expected = store.OpenOrder{"b-1", pair, 102000, 3000000, 1000000, 100, 0, 101, 1000000000, ""}
 	assert.Equal(expected, openOrders[0])
 
 	openOrders = issueMustSuccessQuery(pair, seller, assert)
@@ -67,7 +67,7 @@ func runTestSuccess(t *testing.T, symbol string, upgrade bool) {
 	openOrders = issueMustSuccessQuery(pair, buyer, assert)
 	require.Len(openOrders, 2)
 	require.Contains(openOrders, expected)
-	expected = store.OpenOrder{"b-2", pair, 104000, 6000000, 0, 102, 2, 102, 2}
+	expected = store.OpenOrder{"b-2", pair, 104000, 6000000, 0, 102, 2, 102, 2, ""}
 	require.Contains(openOrders, expected)
 }
 
@@ -0,0 +1,60 @@
+package init
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/libs/cli"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cosmos/cosmos-sdk/server"
+
+	"github.com/bnb-chain/node/app"
+	configPkg "github.com/bnb-chain/node/app/config"
+)
+
+const (
+	flagFromHeight  = "from-height"
+	flagToHeight    = "to-height"
+	flagTopicSuffix = "topic-suffix"
+)
+
+func ReplayPublicationCmd(ctx *server.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay-publication",
+		Short: "Re-publish the trades, order updates and order books of a past block range to Kafka",
+		Long: `Re-publish the trades, order updates and order books of a past block range to Kafka, to the topics
+with the suffix. It rebuilds the order books from the stored blocks, so the node must be stopped.
+The accounts, fees and the other market data are not reconstructed.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout))
+
+			config := ctx.Config
+			config.SetRoot(viper.GetString(cli.HomeFlag))
+			appCtx := configPkg.NewDefaultContext()
+			err := appCtx.ParseAppConfigInPlace()
+			if err != nil {
+				return err
+			}
+			app.SetUpgradeConfig(appCtx.BinanceChainConfig.UpgradeConfig)
+
+			return app.ReplayPublication(logger,
+				appCtx.BinanceChainConfig.BaseConfig,
+				appCtx.BinanceChainConfig.PublicationConfig,
+				config.DBDir(),
+				viper.GetInt64(flagFromHeight),
+				viper.GetInt64(flagToHeight),
+				viper.GetString(flagTopicSuffix))
+		},
+	}
+
+	cmd.Flags().Int64(flagFromHeight, 0, "first height (inclusive) to re-publish")
+	cmd.Flags().Int64(flagToHeight, 0, "last height (inclusive) to re-publish")
+	cmd.Flags().String(flagTopicSuffix, "_replay", "suffix of the topics to re-publish to, so that the live topics are not published twice")
+	_ = cmd.MarkFlagRequired(flagFromHeight)
+	_ = cmd.MarkFlagRequired(flagToHeight)
+
+	return cmd
+}
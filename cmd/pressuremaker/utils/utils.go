@@ -159,7 +159,18 @@ func (mg MessageGenerator) Publish(height, timePub int64, tradesToPublish []*pub
 		pub.BlockFee{},
 		nil,
 		transfers,
-		block)
+		block,
+		false,
+		"",
+		nil,
+		nil,
+		nil,
+		nil,
+		false,
+		false,
+		0,
+		nil,
+		nil)
 }
 
 func makeOrderInfo(sender sdk.AccAddress, side int8, height, price, qty, cumQty, timePub int64) orderPkg.OrderInfo {
@@ -200,5 +211,7 @@ func makeTradeToPub(id, sid, bid, saddr, baddr string, price, qty int64) *pub.Tr
 		"",
 		"",
 		1,
+		false,
+		false,
 	}
 }
@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Rejections is the audit log of the rejected txs, written by the ante handler and the handlers of the orders and
+// transfers. It's disabled until a writer is set, see SetWriter.
+var Rejections = NewRejectionLog(nil)
+
+// the modes of the txs a rejection is recorded in
+const (
+	ModeCheck   = "check"
+	ModeDeliver = "deliver"
+)
+
+// Rejection is a tx rejected by the ante handler, or a msg of it rejected by its handler, as a line of json in the
+// audit log. MsgType is the route and the type of the msg, e.g. dex/orderNew, and is empty if the tx has no msg.
+type Rejection struct {
+	Height  int64  `json:"height"`
+	Mode    string `json:"mode"`
+	TxHash  string `json:"txHash"`
+	Sender  string `json:"sender"`
+	MsgType string `json:"msgType"`
+	Code    uint32 `json:"code"`
+	Reason  string `json:"reason"`
+}
+
+type RejectionLog struct {
+	mtx     sync.Mutex
+	writer  io.Writer
+	encoder *json.Encoder
+}
+
+func NewRejectionLog(writer io.Writer) *RejectionLog {
+	l := &RejectionLog{}
+	l.SetWriter(writer)
+	return l
+}
+
+// SetWriter sets the sink of the audit log, the log is disabled if the writer is nil.
+func (l *RejectionLog) SetWriter(writer io.Writer) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.writer = writer
+	l.encoder = nil
+	if writer != nil {
+		l.encoder = json.NewEncoder(writer)
+	}
+}
+
+func (l *RejectionLog) Enabled() bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.writer != nil
+}
+
+// Record writes the rejection of the msg sent by the sender if the log is enabled. Only the rejections in CheckTx
+// and DeliverTx are recorded, the rechecks and simulations are left out. The msg can be nil if the tx is rejected
+// before its msgs are known.
+func (l *RejectionLog) Record(ctx sdk.Context, sender sdk.AccAddress, msg sdk.Msg, res sdk.Result) {
+	var mode string
+	switch {
+	case ctx.IsCheckTx():
+		mode = ModeCheck
+	case ctx.IsDeliverTx():
+		mode = ModeDeliver
+	default:
+		return
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.writer == nil {
+		return
+	}
+	rejection := Rejection{
+		Height: ctx.BlockHeight(),
+		Mode:   mode,
+		Code:   uint32(res.Code),
+		Reason: res.Log,
+	}
+	rejection.TxHash, _ = ctx.Value(baseapp.TxHashKey).(string)
+	if len(sender) > 0 {
+		rejection.Sender = sender.String()
+	}
+	if msg != nil {
+		rejection.MsgType = msg.Route() + "/" + msg.Type()
+	}
+	// the audit log never fails the tx, a write error is left to the writer
+	_ = l.encoder.Encode(rejection)
+}
@@ -0,0 +1,63 @@
+package audit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/common/audit"
+	"github.com/bnb-chain/node/common/testutils"
+)
+
+func TestRejectionLog_Record(t *testing.T) {
+	ms, _, _ := testutils.SetupMultiStoreForUnitTest()
+	ctx := sdk.NewContext(ms, abci.Header{Height: 42}, sdk.RunTxModeDeliver, log.NewNopLogger()).
+		WithValue(baseapp.TxHashKey, "TXHASH")
+	_, addr := testutils.PrivAndAddr()
+	msg := sdk.NewTestMsg(addr)
+	res := sdk.ErrInsufficientCoins("not enough").Result()
+
+	// disabled without a writer
+	rejections := audit.NewRejectionLog(nil)
+	require.False(t, rejections.Enabled())
+	rejections.Record(ctx, addr, msg, res)
+
+	var buf bytes.Buffer
+	rejections.SetWriter(&buf)
+	require.True(t, rejections.Enabled())
+	rejections.Record(ctx, addr, msg, res)
+	rejections.Record(ctx.WithRunTxMode(sdk.RunTxModeCheck), nil, nil, res)
+	// the rechecks and simulations are left out
+	rejections.Record(ctx.WithRunTxMode(sdk.RunTxModeReCheck), addr, msg, res)
+	rejections.Record(ctx.WithRunTxMode(sdk.RunTxModeSimulate), addr, msg, res)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var rejection audit.Rejection
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &rejection))
+	require.Equal(t, audit.Rejection{
+		Height:  42,
+		Mode:    audit.ModeDeliver,
+		TxHash:  "TXHASH",
+		Sender:  addr.String(),
+		MsgType: msg.Route() + "/" + msg.Type(),
+		Code:    uint32(res.Code),
+		Reason:  res.Log,
+	}, rejection)
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &rejection))
+	require.Equal(t, audit.ModeCheck, rejection.Mode)
+	require.Empty(t, rejection.Sender)
+	require.Empty(t, rejection.MsgType)
+
+	rejections.SetWriter(nil)
+	require.False(t, rejections.Enabled())
+}
@@ -0,0 +1,94 @@
+package fees
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+
+	"github.com/bnb-chain/node/common/types"
+)
+
+const AbciQueryPrefix = "fees"
+
+// Pool is the fees collected but not distributed yet, served by the fees/pool query. The coins are sorted by denom.
+type Pool struct {
+	Collected   sdk.Coins // fees of the last block, distributed to the proposer and for all the validators next block
+	Accumulated sdk.Coins // fees accumulated for all the validators, distributed in the next breathe block
+	Total       sdk.Coins
+}
+
+func CreateAbciQueryHandler(tracker *AccountFeeTracker, bankKeeper bank.Keeper) types.AbciQueryHandler {
+	return func(app types.ChainApp, req abci.RequestQuery, path []string) (res *abci.ResponseQuery) {
+		// expects at least two query path segments.
+		if path[0] != AbciQueryPrefix || len(path) < 2 {
+			return nil
+		}
+		switch path[1] {
+		case "byaccount": // args: ["fees", "byaccount", <bech32Str>]
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log: fmt.Sprintf(
+						"%s %s query requires an address path arg",
+						AbciQueryPrefix, path[1]),
+				}
+			}
+			if !tracker.Enabled() {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "fees tracking by account is disabled on this node",
+				}
+			}
+			addr, err := sdk.AccAddressFromBech32(path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "address is not valid",
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(tracker.Get(addr))
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "pool": // args: ["fees", "pool"]
+			// the fees are only held undistributed since BEP159, they are distributed in the block otherwise
+			ctx := app.GetContextForCheckState()
+			collected := bankKeeper.GetCoins(ctx, stake.FeeCollectorAddr)
+			accumulated := bankKeeper.GetCoins(ctx, stake.FeeForAllAccAddr)
+			pool := Pool{
+				Collected:   collected,
+				Accumulated: accumulated,
+				Total:       collected.Plus(accumulated),
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(pool)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		default:
+			return &abci.ResponseQuery{
+				Code: uint32(sdk.ABCICodeOK),
+				Info: fmt.Sprintf(
+					"Unknown `%s` query path: %v",
+					AbciQueryPrefix, path),
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package fees
+
+import (
+	"fmt"
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramhub "github.com/cosmos/cosmos-sdk/x/paramHub/keeper"
+	paramTypes "github.com/cosmos/cosmos-sdk/x/paramHub/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
+
+	bnclog "github.com/bnb-chain/node/common/log"
+	"github.com/bnb-chain/node/common/upgrade"
+)
+
+const (
+	// SplitParamspace is the params subspace of the fee split params
+	SplitParamspace = "feesplit"
+
+	// SplitRatioBase is the denominator of the ratios of the fee split destinations, i.e. the ratios are in bps
+	SplitRatioBase = 10000
+	// MaxSplitDestinations is the max number of the fee split destinations
+	MaxSplitDestinations = 10
+
+	// SplitParamsType is the param type of the SplitParams in the fee change proposals.
+	SplitParamsType = "fee_split_params"
+)
+
+var (
+	keySplitParams           = []byte("SplitParams")
+	splitParamsTypeTableKeys = []interface{}{
+		keySplitParams, SplitParams{},
+	}
+)
+
+// SplitParams are the fee split parameters under governance. They decide how the fees accumulated for all
+// the validators are split in the breathe blocks: each destination gets its ratio of the fees, and the rest
+// stays with the validators. Since the GovernedParams upgrade, they are carried as a whole by a fee change proposal
+// of the param hub, e.g.
+//
+//	{"fee_params":[{"type":"fees/SplitParams","value":{"destinations":[{"address":"bnb1...","ratio":"1000"}]}}],...}
+//
+// and replace the current ones at the end of the next breathe block, before the fees are split. No destinations
+// stop splitting the fees.
+type SplitParams struct {
+	Destinations []SplitDestination `json:"destinations"`
+}
+
+// SplitDestination is where a ratio of the fees goes, the fees are burnt if the address is empty.
+type SplitDestination struct {
+	Address sdk.AccAddress `json:"address"`
+	// Ratio is in 1/SplitRatioBase of the fees
+	Ratio int64 `json:"ratio"`
+}
+
+func (d SplitDestination) IsBurn() bool {
+	return len(d.Address) == 0
+}
+
+var _ paramTypes.FeeParam = (*SplitParams)(nil)
+
+// GetParamType implements the FeeParam of the param hub.
+func (p *SplitParams) GetParamType() string {
+	return SplitParamsType
+}
+
+// Check implements the FeeParam of the param hub, it's called when the fee change proposal is submitted and again
+// when it's applied.
+func (p *SplitParams) Check() error {
+	if !sdk.IsUpgrade(upgrade.GovernedParams) {
+		return fmt.Errorf("fee split params can not be changed before the %s upgrade", upgrade.GovernedParams)
+	}
+	return p.validate()
+}
+
+func (p SplitParams) validate() error {
+	if len(p.Destinations) > MaxSplitDestinations {
+		return fmt.Errorf("at most %d fee split destinations are allowed, got %d", MaxSplitDestinations, len(p.Destinations))
+	}
+	var total int64
+	for _, d := range p.Destinations {
+		if d.Ratio <= 0 || d.Ratio > SplitRatioBase {
+			return fmt.Errorf("ratio of fee split destination should be in (0, %d], got %d", SplitRatioBase, d.Ratio)
+		}
+		total += d.Ratio
+		if total > SplitRatioBase {
+			return fmt.Errorf("sum of the fee split ratios should not be larger than %d", SplitRatioBase)
+		}
+	}
+	return nil
+}
+
+// Split returns the share of each destination of the fees, and the rest left to the validators. The shares
+// are rounded down, so that the shares and the rest always add up to the fees exactly.
+func (p SplitParams) Split(fees sdk.Coins) (shares []sdk.Coins, rest sdk.Coins) {
+	shares = make([]sdk.Coins, len(p.Destinations))
+	for _, fee := range fees {
+		left := fee.Amount
+		for i, d := range p.Destinations {
+			var amount big.Int
+			amount.Mul(big.NewInt(fee.Amount), big.NewInt(d.Ratio))
+			amount.Quo(&amount, big.NewInt(SplitRatioBase))
+			if amount.Int64() == 0 {
+				continue
+			}
+			shares[i] = append(shares[i], sdk.NewCoin(fee.Denom, amount.Int64()))
+			left -= amount.Int64()
+		}
+		if left != 0 {
+			rest = append(rest, sdk.NewCoin(fee.Denom, left))
+		}
+	}
+	return shares, rest
+}
+
+// SplitKeeper keeps the fee split params in the params store.
+type SplitKeeper struct {
+	space params.Subspace
+}
+
+func NewSplitKeeper(space params.Subspace) SplitKeeper {
+	return SplitKeeper{
+		space: space.WithTypeTable(params.NewTypeTable(splitParamsTypeTableKeys...)),
+	}
+}
+
+// GetParams returns the fee split params, no fees are split before they are set by governance.
+func (k SplitKeeper) GetParams(ctx sdk.Context) SplitParams {
+	var p SplitParams
+	k.space.GetIfExists(ctx, keySplitParams, &p)
+	return p
+}
+
+func (k SplitKeeper) SetParams(ctx sdk.Context, p SplitParams) {
+	k.space.Set(ctx, keySplitParams, p)
+}
+
+// SubscribeParamChange puts the fee split params of the passed fee change proposals in effect, at the end of the
+// breathe blocks.
+func (k SplitKeeper) SubscribeParamChange(hub *paramhub.Keeper) {
+	logger := bnclog.With("module", "fees")
+	hub.SubscribeParamChange(
+		func(ctx sdk.Context, iChange interface{}) {
+			changes, ok := iChange.([]paramTypes.FeeParam)
+			if !ok {
+				return
+			}
+			for _, p := range changes {
+				if params, ok := p.(*SplitParams); ok {
+					logger.Info("apply fee split params change", "params", *params)
+					k.SetParams(ctx, *params)
+				}
+			}
+		},
+		nil, nil, nil)
+}
@@ -0,0 +1,79 @@
+package fees
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/upgrade"
+)
+
+func TestSplitParams_Split(t *testing.T) {
+	treasury := sdk.AccAddress([]byte("treasury"))
+	pool := sdk.AccAddress([]byte("pool"))
+	params := SplitParams{Destinations: []SplitDestination{
+		{Address: treasury, Ratio: 3333},
+		{Ratio: 1667},
+		{Address: pool, Ratio: 4999},
+	}}
+	require.NoError(t, params.validate())
+
+	for _, fees := range []sdk.Coins{
+		{sdk.NewCoin("BNB", 1)},
+		{sdk.NewCoin("BNB", 3)},
+		{sdk.NewCoin("BNB", 10007), sdk.NewCoin("XYZ-000", 99999)},
+		{sdk.NewCoin("BNB", math.MaxInt64)},
+	} {
+		shares, rest := params.Split(fees)
+		require.Len(t, shares, 3)
+		total := rest
+		for _, share := range shares {
+			total = total.Plus(share)
+		}
+		require.Equal(t, fees, total, "the split of %v should sum to it", fees)
+	}
+
+	shares, rest := params.Split(sdk.Coins{sdk.NewCoin("BNB", 10000)})
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 3333)}, shares[0])
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 1667)}, shares[1])
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 4999)}, shares[2])
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 1)}, rest)
+
+	// too small to split
+	shares, rest = params.Split(sdk.Coins{sdk.NewCoin("BNB", 2)})
+	require.Equal(t, []sdk.Coins{nil, nil, nil}, shares)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 2)}, rest)
+
+	// nothing is left to the validators if the ratios add up to the base
+	params = SplitParams{Destinations: []SplitDestination{{Address: treasury, Ratio: 7000}, {Ratio: 3000}}}
+	shares, rest = params.Split(sdk.Coins{sdk.NewCoin("BNB", 12345)})
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 8641)}, shares[0])
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 3703)}, shares[1])
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 1)}, rest)
+}
+
+func TestSplitParams_Check(t *testing.T) {
+	params := SplitParams{Destinations: []SplitDestination{{Ratio: 500}, {Ratio: 1000}}}
+	require.Error(t, params.Check())
+	upgrade.Mgr.AddUpgradeHeight(upgrade.GovernedParams, -1)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+	}()
+	require.NoError(t, params.Check())
+	require.True(t, params.Destinations[0].IsBurn())
+	// no destinations stop splitting the fees
+	require.NoError(t, (&SplitParams{}).Check())
+
+	for _, invalid := range [][]SplitDestination{
+		{{Ratio: 6000}, {Ratio: 4001}},
+		{{Ratio: 0}},
+		{{Ratio: -1}, {Ratio: 10001}},
+		make([]SplitDestination, MaxSplitDestinations+1),
+	} {
+		params = SplitParams{Destinations: invalid}
+		require.Error(t, params.Check())
+	}
+}
@@ -0,0 +1,137 @@
+package fees
+
+import (
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const DefaultMaxTrackedAccounts = 100000
+
+// Tracker accumulates the fees paid by each account, i.e. both the tx fees and the trade fees.
+// It's kept in memory by the node only and is not part of the consensus state, so the fees are
+// counted since the node started, and the least recently charged accounts are pruned once there
+// are more than the max tracked accounts. The fees charged in a tx are staged until the tx is delivered,
+// and are only counted if it succeeds.
+var Tracker = NewAccountFeeTracker(DefaultMaxTrackedAccounts)
+
+// AccountFees is the cumulative fees paid by an account, per asset.
+type AccountFees struct {
+	Address sdk.AccAddress `json:"address"`
+	Fees    sdk.Coins      `json:"fees"`
+}
+
+type AccountFeeTracker struct {
+	mtx    sync.Mutex
+	cache  *lru.Cache                      // string of addr bytes -> sdk.Coins
+	staged map[string]map[string]sdk.Coins // TxHash -> string of addr bytes -> sdk.Coins
+}
+
+func NewAccountFeeTracker(maxAccounts int) *AccountFeeTracker {
+	tracker := &AccountFeeTracker{staged: map[string]map[string]sdk.Coins{}}
+	tracker.SetMaxAccounts(maxAccounts)
+	return tracker
+}
+
+// SetMaxAccounts resets the tracker with the new max number of tracked accounts,
+// tracking is disabled if maxAccounts is not positive.
+func (t *AccountFeeTracker) SetMaxAccounts(maxAccounts int) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if maxAccounts <= 0 {
+		t.cache = nil
+		return
+	}
+	cache, err := lru.New(maxAccounts)
+	if err != nil {
+		panic(err)
+	}
+	t.cache = cache
+}
+
+func (t *AccountFeeTracker) Enabled() bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.cache != nil
+}
+
+// Add stages the fee paid by the account in the tx, it's accumulated once the tx is committed.
+func (t *AccountFeeTracker) Add(txHash string, addr sdk.AccAddress, fee sdk.Coins) {
+	if fee.IsZero() {
+		return
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.cache == nil {
+		return
+	}
+	fees, ok := t.staged[txHash]
+	if !ok {
+		fees = map[string]sdk.Coins{}
+		t.staged[txHash] = fees
+	}
+	key := string(addr.Bytes())
+	fees[key] = fees[key].Plus(append(sdk.Coins{}, fee...).Sort())
+}
+
+// AddAndCommit accumulates the fee paid by the account out of any tx, e.g. the trade fees.
+func (t *AccountFeeTracker) AddAndCommit(addr sdk.AccAddress, fee sdk.Coins) {
+	if fee.IsZero() {
+		return
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.cache == nil {
+		return
+	}
+	t.accumulate(string(addr.Bytes()), append(sdk.Coins{}, fee...).Sort())
+}
+
+// Commit accumulates the fees staged in the tx if it succeeded, they're dropped otherwise.
+func (t *AccountFeeTracker) Commit(txHash string, success bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	fees, ok := t.staged[txHash]
+	if !ok {
+		return
+	}
+	delete(t.staged, txHash)
+	if !success || t.cache == nil {
+		return
+	}
+	for key, fee := range fees {
+		t.accumulate(key, fee)
+	}
+}
+
+func (t *AccountFeeTracker) accumulate(key string, fee sdk.Coins) {
+	if existing, ok := t.cache.Get(key); ok {
+		fee = existing.(sdk.Coins).Plus(fee)
+	}
+	t.cache.Add(key, fee)
+}
+
+// Get returns the cumulative fees paid by the account, per asset.
+func (t *AccountFeeTracker) Get(addr sdk.AccAddress) AccountFees {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	fees := AccountFees{Address: addr, Fees: sdk.Coins{}}
+	if t.cache == nil {
+		return fees
+	}
+	if existing, ok := t.cache.Peek(string(addr.Bytes())); ok {
+		fees.Fees = existing.(sdk.Coins)
+	}
+	return fees
+}
+
+func (t *AccountFeeTracker) Clear() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.cache != nil {
+		t.cache.Purge()
+	}
+	t.staged = map[string]map[string]sdk.Coins{}
+}
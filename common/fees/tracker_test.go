@@ -0,0 +1,33 @@
+package fees
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestAccountFeeTracker_Commit(t *testing.T) {
+	tracker := NewAccountFeeTracker(10)
+	alice := sdk.AccAddress([]byte("alice"))
+	bob := sdk.AccAddress([]byte("bob"))
+
+	tracker.Add("AA01", alice, sdk.Coins{sdk.NewCoin("BNB", 10)})
+	tracker.Add("AA01", alice, sdk.Coins{sdk.NewCoin("XYZ-000", 5)})
+	tracker.Add("AA02", bob, sdk.Coins{sdk.NewCoin("BNB", 20)})
+	// the fees are not counted until the txs are committed
+	require.Equal(t, sdk.Coins{}, tracker.Get(alice).Fees)
+	require.Equal(t, sdk.Coins{}, tracker.Get(bob).Fees)
+
+	tracker.Commit("AA01", true)
+	tracker.Commit("AA02", false)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 10), sdk.NewCoin("XYZ-000", 5)}, tracker.Get(alice).Fees)
+	// the fees of the failed tx are dropped
+	require.Equal(t, sdk.Coins{}, tracker.Get(bob).Fees)
+	tracker.Commit("AA02", true)
+	require.Equal(t, sdk.Coins{}, tracker.Get(bob).Fees)
+
+	tracker.AddAndCommit(bob, sdk.Coins{sdk.NewCoin("BNB", 3)})
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 3)}, tracker.Get(bob).Fees)
+}
@@ -0,0 +1,9 @@
+package fees
+
+import (
+	"github.com/bnb-chain/node/wire"
+)
+
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(&SplitParams{}, "fees/SplitParams", nil)
+}
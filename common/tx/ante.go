@@ -16,6 +16,8 @@ import (
 	"github.com/tendermint/tendermint/crypto/tmhash"
 	"github.com/tendermint/tendermint/libs/common"
 
+	"github.com/bnb-chain/node/common/audit"
+	bncfees "github.com/bnb-chain/node/common/fees"
 	"github.com/bnb-chain/node/common/log"
 )
 
@@ -59,6 +61,9 @@ func InitSigCache(size int) {
 	sigCache = newSigLRUCache(size)
 }
 
+// FeeExemption tells whether the account pays no tx fees, i.e. it's one of the fee exempt accounts under governance.
+type FeeExemption func(ctx sdk.Context, addr sdk.AccAddress) bool
+
 // this function is not implemented in AnteHandler in BaseApp.
 func NewTxPreChecker() sdk.PreChecker {
 	return func(ctx sdk.Context, txBytes []byte, tx sdk.Tx) (res sdk.Result) {
@@ -115,14 +120,20 @@ func NewTxPreChecker() sdk.PreChecker {
 // and increments sequence numbers, checks signatures & account numbers,
 // and deducts fees from the first signer.
 // NOTE: Receiving the `NewOrder` dependency here avoids an import cycle.
+// The isFeeExempt may be nil, then no account is exempt from the tx fees.
 // nolint: gocyclo
 //
 // panic thrown in this function will be caught in RunTx
-func NewAnteHandler(am auth.AccountKeeper) sdk.AnteHandler {
+func NewAnteHandler(am auth.AccountKeeper, isFeeExempt FeeExemption) sdk.AnteHandler {
 	return func(
 		ctx sdk.Context, tx sdk.Tx, mode sdk.RunTxMode,
 	) (newCtx sdk.Context, res sdk.Result, abort bool) {
 		newCtx = ctx
+		defer func() {
+			if !res.IsOK() && audit.Rejections.Enabled() {
+				recordRejection(ctx, tx, res)
+			}
+		}()
 		// This AnteHandler requires Txs to be StdTxs
 		stdTx, ok := tx.(auth.StdTx)
 		if !ok {
@@ -185,7 +196,7 @@ func NewAnteHandler(am auth.AccountKeeper) sdk.AnteHandler {
 
 		// for blockHeight == 0, we do not collect fees since we have some StdTx(s) in InitChain.
 		if newCtx.BlockHeight() != 0 {
-			res = calcAndCollectFees(newCtx, am, signerAccs[0], msgs[0], txHash)
+			res = calcAndCollectFees(newCtx, am, isFeeExempt, signerAccs[0], msgs[0], txHash)
 			if !res.IsOK() {
 				return newCtx, res, true
 			}
@@ -199,6 +210,19 @@ func NewAnteHandler(am auth.AccountKeeper) sdk.AnteHandler {
 	}
 }
 
+// recordRejection records the tx rejected by the ante handler in the audit log, with its first msg and signer.
+func recordRejection(ctx sdk.Context, tx sdk.Tx, res sdk.Result) {
+	var sender sdk.AccAddress
+	var msg sdk.Msg
+	if msgs := tx.GetMsgs(); len(msgs) > 0 && msgs[0] != nil {
+		msg = msgs[0]
+		if signers := msg.GetSigners(); len(signers) > 0 {
+			sender = signers[0]
+		}
+	}
+	audit.Rejections.Record(ctx, sender, msg, res)
+}
+
 // Validate the transaction based on things that don't depend on the context
 func validateBasic(tx auth.StdTx) (err sdk.Error) {
 	// Assert that there are signatures.
@@ -318,7 +342,8 @@ func processSig(txHash string,
 	return
 }
 
-func calcAndCollectFees(ctx sdk.Context, am auth.AccountKeeper, acc sdk.Account, msg sdk.Msg, txHash string) sdk.Result {
+func calcAndCollectFees(ctx sdk.Context, am auth.AccountKeeper, isFeeExempt FeeExemption, acc sdk.Account, msg sdk.Msg,
+	txHash string) sdk.Result {
 	// first sig pays the fees
 	// Can this function be moved outside of the loop?
 
@@ -327,6 +352,9 @@ func calcAndCollectFees(ctx sdk.Context, am auth.AccountKeeper, acc sdk.Account,
 		ctx.Logger().Error("calculate fees error", "err", err.Error())
 		return sdk.ErrInternal("calculate fees error").Result()
 	}
+	if isFeeExempt != nil && isFeeExempt(ctx, acc.GetAddress()) {
+		fee = sdk.NewFee(sdk.Coins{}, sdk.FeeFree)
+	}
 
 	if fee.Type != sdk.FeeFree && !fee.Tokens.IsZero() {
 		fee.Tokens.Sort()
@@ -339,6 +367,9 @@ func calcAndCollectFees(ctx sdk.Context, am auth.AccountKeeper, acc sdk.Account,
 	if ctx.IsDeliverTx() {
 		// add fee to pool, even it's free
 		sdkfees.Pool.AddFee(txHash, fee)
+		if fee.Type != sdk.FeeFree {
+			bncfees.Tracker.Add(txHash, acc.GetAddress(), fee.Tokens)
+		}
 	}
 	return sdk.Result{}
 }
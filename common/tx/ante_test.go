@@ -1,6 +1,8 @@
 package tx_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -19,6 +21,7 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/bnb-chain/node/app"
+	"github.com/bnb-chain/node/common/audit"
 	"github.com/bnb-chain/node/common/testutils"
 	"github.com/bnb-chain/node/common/tx"
 	"github.com/bnb-chain/node/common/types"
@@ -115,7 +118,7 @@ func TestAnteHandlerSigErrors(t *testing.T) {
 	cdc := wire.NewCodec()
 	auth.RegisterBaseAccount(cdc)
 	mapper := auth.NewAccountKeeper(cdc, capKey, auth.ProtoBaseAccount)
-	anteHandler := tx.NewAnteHandler(mapper)
+	anteHandler := tx.NewAnteHandler(mapper, nil)
 	accountCache := getAccountCache(cdc, ms, capKey)
 
 	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid"}, sdk.RunTxModeDeliver, log.NewNopLogger()).WithAccountCache(accountCache)
@@ -167,7 +170,7 @@ func TestAnteHandlerAccountNumbers(t *testing.T) {
 	cdc := wire.NewCodec()
 	auth.RegisterBaseAccount(cdc)
 	mapper := auth.NewAccountKeeper(cdc, capKey, auth.ProtoBaseAccount)
-	anteHandler := tx.NewAnteHandler(mapper)
+	anteHandler := tx.NewAnteHandler(mapper, nil)
 	accountCache := getAccountCache(cdc, ms, capKey)
 	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid", Height: 1}, sdk.RunTxModeDeliver, log.NewNopLogger()).WithAccountCache(accountCache)
 
@@ -225,7 +228,7 @@ func TestAnteHandlerSequences(t *testing.T) {
 	cdc := wire.NewCodec()
 	auth.RegisterBaseAccount(cdc)
 	mapper := auth.NewAccountKeeper(cdc, capKey, auth.ProtoBaseAccount)
-	anteHandler := tx.NewAnteHandler(mapper)
+	anteHandler := tx.NewAnteHandler(mapper, nil)
 	accountCache := getAccountCache(cdc, ms, capKey)
 
 	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid", Height: 1}, sdk.RunTxModeDeliver, log.NewNopLogger()).WithAccountCache(accountCache)
@@ -302,7 +305,7 @@ func TestAnteHandlerMultiSigner(t *testing.T) {
 	cdc := wire.NewCodec()
 	auth.RegisterBaseAccount(cdc)
 	mapper := auth.NewAccountKeeper(cdc, capKey, auth.ProtoBaseAccount)
-	anteHandler := tx.NewAnteHandler(mapper)
+	anteHandler := tx.NewAnteHandler(mapper, nil)
 	accountCache := getAccountCache(cdc, ms, capKey)
 
 	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid", Height: 1}, sdk.RunTxModeDeliver, log.NewNopLogger()).WithAccountCache(accountCache)
@@ -353,7 +356,7 @@ func TestAnteHandlerBadSignBytes(t *testing.T) {
 	cdc := wire.NewCodec()
 	auth.RegisterBaseAccount(cdc)
 	mapper := auth.NewAccountKeeper(cdc, capKey, auth.ProtoBaseAccount)
-	anteHandler := tx.NewAnteHandler(mapper)
+	anteHandler := tx.NewAnteHandler(mapper, nil)
 	accountCache := getAccountCache(cdc, ms, capKey)
 
 	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid", Height: 1}, sdk.RunTxModeDeliver, log.NewNopLogger()).WithAccountCache(accountCache)
@@ -426,7 +429,7 @@ func TestAnteHandlerSetPubKey(t *testing.T) {
 	cdc := wire.NewCodec()
 	auth.RegisterBaseAccount(cdc)
 	mapper := auth.NewAccountKeeper(cdc, capKey, auth.ProtoBaseAccount)
-	anteHandler := tx.NewAnteHandler(mapper)
+	anteHandler := tx.NewAnteHandler(mapper, nil)
 	accountCache := getAccountCache(cdc, ms, capKey)
 	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid", Height: 1}, sdk.RunTxModeDeliver, log.NewNopLogger()).WithAccountCache(accountCache)
 
@@ -473,12 +476,39 @@ func TestAnteHandlerSetPubKey(t *testing.T) {
 	require.Nil(t, acc2.GetPubKey())
 }
 
+func TestAnteHandlerRecordsRejections(t *testing.T) {
+	mapper, ctx, anteHandler := setup()
+	var buf bytes.Buffer
+	audit.Rejections.SetWriter(&buf)
+	defer audit.Rejections.SetWriter(nil)
+
+	priv1, addr1 := testutils.PrivAndAddr()
+	acc1 := mapper.NewAccountWithAddress(ctx, addr1)
+	acc1.SetCoins(newCoins())
+	mapper.SetAccount(ctx, acc1)
+	msg := newTestMsg(addr1)
+	txn := newTestTx(ctx, []sdk.Msg{msg}, []crypto.PrivKey{priv1}, []int64{0}, []int64{0})
+	checkValidTx(t, anteHandler, ctx, txn, sdk.RunTxModeDeliver)
+	require.Zero(t, buf.Len())
+
+	// the replayed tx is rejected and recorded
+	checkInvalidTx(t, anteHandler, ctx, txn, sdk.CodeInvalidSequence, sdk.RunTxModeDeliver)
+	var rejection audit.Rejection
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rejection))
+	require.Equal(t, audit.ModeDeliver, rejection.Mode)
+	require.Equal(t, int64(1), rejection.Height)
+	require.Equal(t, addr1.String(), rejection.Sender)
+	require.Equal(t, msg.Route()+"/"+msg.Type(), rejection.MsgType)
+	require.Equal(t, uint32(sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeInvalidSequence)), rejection.Code)
+	require.NotEmpty(t, rejection.Reason)
+}
+
 func setup() (mapper auth.AccountKeeper, ctx sdk.Context, anteHandler sdk.AnteHandler) {
 	ms, capKey, _ := testutils.SetupMultiStoreForUnitTest()
 	cdc := wire.NewCodec()
 	auth.RegisterBaseAccount(cdc)
 	mapper = auth.NewAccountKeeper(cdc, capKey, auth.ProtoBaseAccount)
-	anteHandler = tx.NewAnteHandler(mapper)
+	anteHandler = tx.NewAnteHandler(mapper, nil)
 	accountCache := getAccountCache(cdc, ms, capKey)
 
 	ctx = sdk.NewContext(ms, abci.Header{ChainID: "mychainid", Height: 1}, sdk.RunTxModeDeliver, log.NewNopLogger()).WithAccountCache(accountCache)
@@ -546,6 +576,27 @@ func TestAnteHandlerOneTxFee(t *testing.T) {
 	checkFee(t, sdk.NewFee(sdk.Coins{sdk.NewCoin(types.NativeTokenSymbol, 10)}, sdk.FeeForAll))
 }
 
+func TestAnteHandlerFeeExemption(t *testing.T) {
+	var exempt sdk.AccAddress
+	isFeeExempt := func(_ sdk.Context, addr sdk.AccAddress) bool {
+		return addr.Equals(exempt)
+	}
+
+	am, ctx, _ := setup()
+	priv1, acc1 := testutils.NewAccount(ctx, am, 100)
+	exempt = acc1.GetAddress()
+	ctx = runAnteHandlerWithMultiTxFees(ctx, tx.NewAnteHandler(am, isFeeExempt), priv1, acc1.GetAddress(), sdkfees.FixedFeeCalculator(10, sdk.FeeForAll))
+	checkBalance(t, am, ctx, acc1.GetAddress(), sdk.Coins{sdk.NewCoin(types.NativeTokenSymbol, 100)})
+	checkFee(t, sdk.Fee{})
+
+	// the txs are signed with the first account number, so the other account is set up in a store of its own
+	am, ctx, _ = setup()
+	priv2, acc2 := testutils.NewAccount(ctx, am, 100)
+	ctx = runAnteHandlerWithMultiTxFees(ctx, tx.NewAnteHandler(am, isFeeExempt), priv2, acc2.GetAddress(), sdkfees.FixedFeeCalculator(10, sdk.FeeForAll))
+	checkBalance(t, am, ctx, acc2.GetAddress(), sdk.Coins{sdk.NewCoin(types.NativeTokenSymbol, 90)})
+	checkFee(t, sdk.NewFee(sdk.Coins{sdk.NewCoin(types.NativeTokenSymbol, 10)}, sdk.FeeForAll))
+}
+
 func TestAnteHandlerMultiTxFees(t *testing.T) {
 	// two txs, 1. FeeFree 2. FeeProposer
 	am, ctx, anteHandler := setup()
@@ -645,7 +696,7 @@ func Test_NewTxPreCheckerSignature(t *testing.T) {
 	cdc.RegisterConcrete(sdk.TestMsg{}, "antetest/TestMsg", nil)
 	mapper := auth.NewAccountKeeper(cdc, capKey, auth.ProtoBaseAccount)
 	accountCache := getAccountCache(cdc, ms, capKey)
-	anteHandler := tx.NewAnteHandler(mapper)
+	anteHandler := tx.NewAnteHandler(mapper, nil)
 
 	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid", Height: 1}, sdk.RunTxModeDeliver, log.NewNopLogger()).WithAccountCache(accountCache)
 
@@ -0,0 +1,64 @@
+package txstatus
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+)
+
+const AbciQueryPrefix = "tx"
+
+func CreateAbciQueryHandler(tracker *RecentTxTracker) types.AbciQueryHandler {
+	return func(app types.ChainApp, req abci.RequestQuery, path []string) (res *abci.ResponseQuery) {
+		// expects at least two query path segments.
+		if path[0] != AbciQueryPrefix || len(path) < 2 {
+			return nil
+		}
+		switch path[1] {
+		case "status": // args: ["tx", "status", <hex tx hash>]
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log: fmt.Sprintf(
+						"%s %s query requires a tx hash path arg",
+						AbciQueryPrefix, path[1]),
+				}
+			}
+			if !tracker.Enabled() {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "tx status tracking is disabled on this node",
+				}
+			}
+			if _, err := hex.DecodeString(path[2]); err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "tx hash is not valid",
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(tracker.Get(path[2]))
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		default:
+			return &abci.ResponseQuery{
+				Code: uint32(sdk.ABCICodeOK),
+				Info: fmt.Sprintf(
+					"Unknown `%s` query path: %v",
+					AbciQueryPrefix, path),
+			}
+		}
+	}
+}
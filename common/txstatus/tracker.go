@@ -0,0 +1,116 @@
+package txstatus
+
+import (
+	"strings"
+	"sync"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+const DefaultLookbackBlocks = 1000
+
+// Tracker keeps the results of the txs delivered in the recent blocks, i.e. the lookback window, for the
+// tx/status query. It's kept in memory by the node only, so the txs delivered before the node started are not known.
+var Tracker = NewRecentTxTracker(DefaultLookbackBlocks)
+
+// TxStatus is the inclusion status of a tx. Found is false if the tx is unknown to the node,
+// i.e. it is still pending, it was delivered before the lookback window, or it never existed.
+type TxStatus struct {
+	Hash   string `json:"hash"`
+	Found  bool   `json:"found"`
+	Height int64  `json:"height"`
+	Ok     bool   `json:"ok"`
+	Code   uint32 `json:"code"`
+	Log    string `json:"log"`
+}
+
+type blockTxs struct {
+	height int64
+	hashes []string
+}
+
+type RecentTxTracker struct {
+	mtx            sync.Mutex
+	lookbackBlocks int64
+	blocks         []blockTxs // in the order of heights
+	statuses       map[string]TxStatus
+}
+
+func NewRecentTxTracker(lookbackBlocks int) *RecentTxTracker {
+	tracker := &RecentTxTracker{}
+	tracker.SetLookbackBlocks(lookbackBlocks)
+	return tracker
+}
+
+// SetLookbackBlocks resets the tracker with the new lookback window,
+// tracking is disabled if lookbackBlocks is not positive.
+func (t *RecentTxTracker) SetLookbackBlocks(lookbackBlocks int) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if lookbackBlocks < 0 {
+		lookbackBlocks = 0
+	}
+	t.lookbackBlocks = int64(lookbackBlocks)
+	t.blocks = nil
+	t.statuses = make(map[string]TxStatus)
+}
+
+func (t *RecentTxTracker) Enabled() bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.lookbackBlocks > 0
+}
+
+// Add records the result of a tx delivered at the height, and drops the txs delivered before the lookback window.
+func (t *RecentTxTracker) Add(hash string, height int64, res abci.ResponseDeliverTx) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.lookbackBlocks <= 0 {
+		return
+	}
+	hash = strings.ToUpper(hash)
+	if n := len(t.blocks); n == 0 || t.blocks[n-1].height != height {
+		t.blocks = append(t.blocks, blockTxs{height: height})
+	}
+	last := &t.blocks[len(t.blocks)-1]
+	last.hashes = append(last.hashes, hash)
+	t.statuses[hash] = TxStatus{
+		Hash:   hash,
+		Found:  true,
+		Height: height,
+		Ok:     res.IsOK(),
+		Code:   res.Code,
+		Log:    res.Log,
+	}
+	t.prune(height)
+}
+
+func (t *RecentTxTracker) prune(height int64) {
+	pruned := 0
+	for _, block := range t.blocks {
+		if block.height > height-t.lookbackBlocks {
+			break
+		}
+		for _, hash := range block.hashes {
+			// the same tx may be delivered again later, e.g. replayed after a failure
+			if status, ok := t.statuses[hash]; ok && status.Height == block.height {
+				delete(t.statuses, hash)
+			}
+		}
+		pruned++
+	}
+	if pruned > 0 {
+		t.blocks = append(t.blocks[:0], t.blocks[pruned:]...)
+	}
+}
+
+// Get returns the status of the tx, Found is false if the tx is not delivered within the lookback window.
+func (t *RecentTxTracker) Get(hash string) TxStatus {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	hash = strings.ToUpper(hash)
+	if status, ok := t.statuses[hash]; ok {
+		return status
+	}
+	return TxStatus{Hash: hash}
+}
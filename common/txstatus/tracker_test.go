@@ -0,0 +1,32 @@
+package txstatus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestRecentTxTracker(t *testing.T) {
+	tracker := NewRecentTxTracker(2)
+	tracker.Add("aa01", 10, abci.ResponseDeliverTx{})
+	tracker.Add("AA02", 10, abci.ResponseDeliverTx{Code: 65540, Log: "insufficient fee"})
+	tracker.Add("AA03", 11, abci.ResponseDeliverTx{})
+
+	require.Equal(t, TxStatus{Hash: "AA01", Found: true, Height: 10, Ok: true}, tracker.Get("AA01"))
+	require.Equal(t, TxStatus{Hash: "AA02", Found: true, Height: 10, Ok: false, Code: 65540, Log: "insufficient fee"}, tracker.Get("aa02"))
+	require.Equal(t, TxStatus{Hash: "AA04"}, tracker.Get("aa04"))
+
+	// the txs of height 10 are out of the lookback window
+	tracker.Add("AA04", 12, abci.ResponseDeliverTx{})
+	require.False(t, tracker.Get("AA01").Found)
+	require.False(t, tracker.Get("AA02").Found)
+	require.True(t, tracker.Get("AA03").Found)
+	require.Equal(t, int64(12), tracker.Get("AA04").Height)
+
+	tracker.SetLookbackBlocks(0)
+	require.False(t, tracker.Enabled())
+	tracker.Add("AA05", 13, abci.ResponseDeliverTx{})
+	require.False(t, tracker.Get("AA05").Found)
+}
@@ -6,3 +6,14 @@ import (
 
 // AbciQueryHandler represents an abci query handler, registered by a plugin's InitPlugin.
 type AbciQueryHandler func(app ChainApp, req abci.RequestQuery, path []string) (res *abci.ResponseQuery)
+
+// MalformedQueryError is panicked with by the query handlers, or the helpers they call, to reject the input of a
+// query that can't be validated up front, it's answered as an unknown request. Any other panic of a query is taken
+// as a bug of the node and answered as an internal error.
+type MalformedQueryError struct {
+	Reason string
+}
+
+func (e MalformedQueryError) Error() string {
+	return e.Reason
+}
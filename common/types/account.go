@@ -1,6 +1,8 @@
 package types
 
 import (
+	"sort"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 
@@ -79,6 +81,63 @@ func (acc *AppAccount) Clone() sdk.Account {
 	return clonedAcc
 }
 
+// GetAssets returns the sorted denoms that the account holds any free, frozen or locked balance of
+func GetAssets(acc sdk.Account) []string {
+	denoms := make(map[string]bool)
+	collect := func(coins sdk.Coins) {
+		for _, coin := range coins {
+			if coin.IsPositive() {
+				denoms[coin.Denom] = true
+			}
+		}
+	}
+	collect(acc.GetCoins())
+	if namedAcc, ok := acc.(NamedAccount); ok {
+		collect(namedAcc.GetFrozenCoins())
+		collect(namedAcc.GetLockedCoins())
+	}
+	assets := make([]string, 0, len(denoms))
+	for denom := range denoms {
+		assets = append(assets, denom)
+	}
+	sort.Strings(assets)
+	return assets
+}
+
+// SpendableBalance is the balance of an asset of an account. Spendable is the free balance that can be
+// transferred or used for new orders, it doesn't include the Frozen and Locked balances.
+type SpendableBalance struct {
+	Asset     string `json:"asset"`
+	Spendable int64  `json:"spendable"`
+	Frozen    int64  `json:"frozen"`
+	Locked    int64  `json:"locked"`
+}
+
+// AccountSpendable is the spendable balances of an account, Found is false if the account doesn't exist
+type AccountSpendable struct {
+	Found    bool               `json:"found"`
+	Balances []SpendableBalance `json:"balances"`
+}
+
+// GetSpendableBalances returns the spendable, frozen and locked balances of each asset that the account holds,
+// sorted by the asset
+func GetSpendableBalances(acc sdk.Account) []SpendableBalance {
+	assets := GetAssets(acc)
+	balances := make([]SpendableBalance, 0, len(assets))
+	for _, asset := range assets {
+		balance := SpendableBalance{
+			Asset:     asset,
+			Spendable: acc.GetCoins().AmountOf(asset),
+		}
+		if namedAcc, ok := acc.(NamedAccount); ok {
+			balance.Frozen = namedAcc.GetFrozenCoins().AmountOf(asset)
+			balance.Locked = namedAcc.GetLockedCoins().AmountOf(asset)
+		}
+		balances = append(balances, balance)
+	}
+	return balances
+}
+
 // Get the AccountDecoder function for the custom AppAccount
 func GetAccountDecoder(cdc *wire.Codec) auth.AccountDecoder {
 	return func(accBytes []byte) (res sdk.Account, err error) {
@@ -0,0 +1,25 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// the reasons of the account locks
+const (
+	LockReasonOrder    = "order"    // reserved by an open order, i.e. the locked coins of the account
+	LockReasonFrozen   = "frozen"   // frozen by the owner, i.e. the frozen coins of the account
+	LockReasonTimeLock = "timelock" // locked by a time lock until the lock time
+	LockReasonHTLC     = "htlc"     // sent out by an open atomic swap until it's claimed or refunded
+)
+
+// AccountLock is a part of the coins of an account not spendable for a reason. Ref refers to what holds the
+// coins, i.e. the order id, the time lock id or the swap id, and is empty for the frozen coins. UnlockTime is
+// in unix seconds and UnlockHeight is the height since which the swap can be refunded, they are 0 if the
+// lock isn't released at a certain time or height.
+type AccountLock struct {
+	Reason       string    `json:"reason"`
+	Ref          string    `json:"ref"`
+	Amount       sdk.Coins `json:"amount"`
+	UnlockTime   int64     `json:"unlock_time"`
+	UnlockHeight int64     `json:"unlock_height"`
+}
@@ -0,0 +1,41 @@
+package types_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/common/types"
+)
+
+func TestGetAssets(t *testing.T) {
+	acc := &types.AppAccount{}
+	require.Equal(t, []string{}, types.GetAssets(acc))
+
+	acc.SetCoins(sdk.Coins{sdk.NewCoin("BNB", 100), sdk.NewCoin("XYZ-000", 0)})
+	acc.SetFrozenCoins(sdk.Coins{sdk.NewCoin("ABC-000", 10)})
+	acc.SetLockedCoins(sdk.Coins{sdk.NewCoin("BNB", 10), sdk.NewCoin("XYZ-000", 5)})
+	require.Equal(t, []string{"ABC-000", "BNB", "XYZ-000"}, types.GetAssets(acc))
+
+	baseAcc := &auth.BaseAccount{Coins: sdk.Coins{sdk.NewCoin("BNB", 100)}}
+	require.Equal(t, []string{"BNB"}, types.GetAssets(baseAcc))
+}
+
+func TestGetSpendableBalances(t *testing.T) {
+	acc := &types.AppAccount{}
+	require.Equal(t, []types.SpendableBalance{}, types.GetSpendableBalances(acc))
+
+	acc.SetCoins(sdk.Coins{sdk.NewCoin("BNB", 100), sdk.NewCoin("XYZ-000", 0)})
+	acc.SetFrozenCoins(sdk.Coins{sdk.NewCoin("ABC-000", 10)})
+	acc.SetLockedCoins(sdk.Coins{sdk.NewCoin("BNB", 10), sdk.NewCoin("XYZ-000", 5)})
+	require.Equal(t, []types.SpendableBalance{
+		{Asset: "ABC-000", Frozen: 10},
+		{Asset: "BNB", Spendable: 100, Locked: 10},
+		{Asset: "XYZ-000", Locked: 5},
+	}, types.GetSpendableBalances(acc))
+
+	baseAcc := &auth.BaseAccount{Coins: sdk.Coins{sdk.NewCoin("BNB", 100)}}
+	require.Equal(t, []types.SpendableBalance{{Asset: "BNB", Spendable: 100}}, types.GetSpendableBalances(baseAcc))
+}
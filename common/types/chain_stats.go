@@ -0,0 +1,16 @@
+package types
+
+// ChainStats are the chain wide statistics for the explorers, as of Height, i.e. the latest committed block.
+type ChainStats struct {
+	Height       int64         `json:"height"`
+	Accounts     int64         `json:"accounts"`
+	Tokens       int64         `json:"tokens"`
+	MiniTokens   int64         `json:"mini_tokens"`
+	TradingPairs int64         `json:"trading_pairs"`
+	Supplies     []AssetSupply `json:"supplies"` // of all the tokens including the mini tokens, sorted by the asset
+}
+
+type AssetSupply struct {
+	Asset       string `json:"asset"`
+	TotalSupply int64  `json:"total_supply"`
+}
@@ -46,6 +46,14 @@ const (
 	BEP159Phase2 = sdk.BEP159Phase2
 	BEP173       = sdk.BEP173 // https://github.com/bnb-chain/BEPs/pull/173 Text Proposal
         FixDoubleSignChainId = sdk.FixDoubleSignChainId
+
+	OrderIDVersioning    = "OrderIDVersioning"    // order ids are generated with a version prefix
+	TokenTransferMemo    = "TokenTransferMemo"    // token owners can require a memo on the transfers of their tokens
+	OrderParamCodes      = "OrderParamCodes"      // zero/negative prices and quantities of orders are rejected with their own codes
+	TokenTransferFee     = "TokenTransferFee"     // token owners can charge a fee on the transfers of their tokens
+	OrderUnknownPairCode = "OrderUnknownPairCode" // orders of the unknown trading pairs are rejected with their own code
+	OrderMemo            = "OrderMemo"            // orders can carry a memo for the client tagging
+	GovernedParams       = "GovernedParams"       // the dex, trading pair, token and fee split params are changed by the fee change proposals, the fee exemptions and token trading by their own msgs
 )
 
 func UpgradeBEP10(before func(), after func()) {
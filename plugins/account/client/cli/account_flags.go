@@ -72,67 +72,32 @@ func setAccountFlagsCmd(cdc *wire.Codec) *cobra.Command {
 }
 
 func enableMemoCheckFlagCmd(cdc *wire.Codec) *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "enable-memo-checker",
-		Short: "enable memo checker",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			cliCtx, txBldr := client.PrepareCtx(cdc)
-			from, err := cliCtx.GetFromAddress()
-			if err != nil {
-				return err
-			}
+	return updateAccountFlagCmd(cdc, "enable-memo-checker", "enable memo checker",
+		scripts.TransferMemoCheckerFlag, setFlagBits)
+}
 
-			var flags uint64
-			if viper.GetBool(clientFlags.FlagOffline) {
-				flagsHexStr := viper.GetString(accountFlags)
-				if len(flagsHexStr) == 0 {
-					return fmt.Errorf("on offline mode, you must specify current account flags")
-				}
-				if !strings.HasPrefix(flagsHexStr, "0x") {
-					return fmt.Errorf("flags must be hex string and start with 0x")
-				}
+func disableMemoCheckFlagCmd(cdc *wire.Codec) *cobra.Command {
+	return updateAccountFlagCmd(cdc, "disable-memo-checker", "disable memo checker",
+		scripts.TransferMemoCheckerFlag, unsetFlagBits)
+}
 
-				flagsHexStr = flagsHexStr[2:]
-				flags, err = strconv.ParseUint(flagsHexStr, 16, 64)
-				if err != nil {
-					return err
-				}
-			} else {
-				if err := cliCtx.EnsureAccountExistsFromAddr(from); err != nil {
-					return err
-				}
-				acc, err := cliCtx.GetAccount(from)
-				if err != nil {
-					return err
-				}
-				appAccount, ok := acc.(types.NamedAccount)
-				if !ok {
-					return fmt.Errorf("unexpected account type")
-				}
-				flags = appAccount.GetFlags()
-			}
-			flags, err = setFlagBits(flags, scripts.TransferMemoCheckerFlag)
-			if err != nil {
-				return err
-			}
-			// build message
-			msg := account.NewSetAccountFlagsMsg(from, flags)
-			err = msg.ValidateBasic()
-			if err != nil {
-				return err
-			}
+func enablePublicationOptOutFlagCmd(cdc *wire.Codec) *cobra.Command {
+	return updateAccountFlagCmd(cdc, "enable-publication-opt-out",
+		"stop the publication of the account balances, the on-chain state and the queries are not affected",
+		scripts.PublicationOptOutFlag, setFlagBits)
+}
 
-			return client.SendOrPrintTx(cliCtx, txBldr, msg)
-		},
-	}
-	cmd.Flags().String(accountFlags, "", "account flags, hex encoding string with prefix 0x")
-	return cmd
+func disablePublicationOptOutFlagCmd(cdc *wire.Codec) *cobra.Command {
+	return updateAccountFlagCmd(cdc, "disable-publication-opt-out", "resume the publication of the account balances",
+		scripts.PublicationOptOutFlag, unsetFlagBits)
 }
 
-func disableMemoCheckFlagCmd(cdc *wire.Codec) *cobra.Command {
+// updateAccountFlagCmd builds a command that sets or unsets the targetFlag on the current account flags
+func updateAccountFlagCmd(cdc *wire.Codec, use, short string, targetFlag uint64,
+	update func(flags uint64, targetFlag uint64) (uint64, error)) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "disable-memo-checker",
-		Short: "disable memo checker",
+		Use:   use,
+		Short: short,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cliCtx, txBldr := client.PrepareCtx(cdc)
 			from, err := cliCtx.GetFromAddress()
@@ -169,7 +134,7 @@ func disableMemoCheckFlagCmd(cdc *wire.Codec) *cobra.Command {
 				}
 				flags = appAccount.GetFlags()
 			}
-			flags, err = unsetFlagBits(flags, scripts.TransferMemoCheckerFlag)
+			flags, err = update(flags, targetFlag)
 			if err != nil {
 				return err
 			}
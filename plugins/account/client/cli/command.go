@@ -19,6 +19,8 @@ func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
 		client.PostCommands(
 			setAccountFlagsCmd(cdc),
 			enableMemoCheckFlagCmd(cdc),
-			disableMemoCheckFlagCmd(cdc))...)
+			disableMemoCheckFlagCmd(cdc),
+			enablePublicationOptOutFlagCmd(cdc),
+			disablePublicationOptOutFlagCmd(cdc))...)
 	cmd.AddCommand(scriptsCmd)
 }
@@ -0,0 +1,132 @@
+package account
+
+import (
+	"bytes"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/bnb-chain/node/common/log"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
+)
+
+// AddressConverter derives the new address of an account from its current one,
+// the account is kept as it is if ok is false or the address is not changed.
+type AddressConverter func(addr sdk.AccAddress) (converted sdk.AccAddress, ok bool)
+
+// AddressMigration is the move of an account from one address to another.
+type AddressMigration struct {
+	From sdk.AccAddress
+	To   sdk.AccAddress
+}
+
+// MigrateAccountAddresses moves the accounts to the addresses derived by convert, the coins, sequence,
+// account number, public key and the other fields of the accounts are preserved. Nothing is changed in
+// the dry run, the moves are only reported. All the moves are checked before any account is touched,
+// so either all the accounts are migrated or none is if there is a conflict.
+//
+// The accounts are keyed by the raw address bytes, so a change of the bech32 prefix alone needs no
+// migration. The accounts are iterated from the committed store, so it's expected to run in the begin
+// blocker of an upgrade, see RegisterAddressMigrationUpgrade. The accounts at the old addresses are
+// deleted from the store of accountKey directly, see removeAccount. Only the accounts are migrated, the addresses
+// kept by the other modules, e.g. the token owners, are out of its scope.
+func MigrateAccountAddresses(ctx sdk.Context, am auth.AccountKeeper, accountKey sdk.StoreKey, convert AddressConverter,
+	dryRun bool) ([]AddressMigration, error) {
+	migrations := make([]AddressMigration, 0)
+	sources := make(map[string]bool)
+	targets := make(map[string]bool)
+	var err error
+	am.IterateAccounts(ctx, func(acc sdk.Account) bool {
+		from := acc.GetAddress()
+		to, ok := convert(from)
+		if !ok || bytes.Equal(from, to) {
+			return false
+		}
+		if len(to) != sdk.AddrLen {
+			err = fmt.Errorf("account %s is converted to an address of illegal length %d", from, len(to))
+			return true
+		}
+		if targets[string(to)] {
+			err = fmt.Errorf("more than one account is converted to the address %s", to)
+			return true
+		}
+		targets[string(to)] = true
+		sources[string(from)] = true
+		migrations = append(migrations, AddressMigration{From: from, To: to})
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range migrations {
+		// the target may be occupied by an account that is moved away as well
+		if !sources[string(m.To)] && am.GetAccount(ctx, m.To) != nil {
+			return nil, fmt.Errorf("account %s can not be moved to %s, which is already taken", m.From, m.To)
+		}
+	}
+	if dryRun {
+		return migrations, nil
+	}
+
+	moved := make([]sdk.Account, 0, len(migrations))
+	for _, m := range migrations {
+		acc := am.GetAccount(ctx, m.From)
+		migrated, err := withAddress(acc, m.To)
+		if err != nil {
+			return nil, err
+		}
+		moved = append(moved, migrated)
+	}
+	for _, m := range migrations {
+		// the old address is taken over by another moved account
+		if !targets[string(m.From)] {
+			removeAccount(ctx, accountKey, m.From)
+		}
+	}
+	// set after all the removals, in case an account is moved to the old address of another one
+	for _, acc := range moved {
+		am.SetAccount(ctx, acc)
+	}
+	return migrations, nil
+}
+
+// removeAccount deletes the account at addr. The account caches of the sdk can not delete an account,
+// the deletion panics once the cache is written, so the account is deleted from the store and only hidden
+// in the account cache of the ctx. The account store cache of the app may still hold the account, so it
+// has to be cleared after the deletion is committed.
+func removeAccount(ctx sdk.Context, accountKey sdk.StoreKey, addr sdk.AccAddress) {
+	ctx.KVStore(accountKey).Delete(auth.AddressStoreKey(addr))
+	ctx.AccountCache().SetAccount(addr, nil)
+}
+
+func withAddress(acc sdk.Account, addr sdk.AccAddress) (sdk.Account, error) {
+	switch acc := acc.(type) {
+	case *types.AppAccount:
+		migrated := acc.Clone().(*types.AppAccount)
+		migrated.BaseAccount.Address = addr
+		return migrated, nil
+	case *auth.BaseAccount:
+		migrated := acc.Clone().(*auth.BaseAccount)
+		migrated.Address = addr
+		return migrated, nil
+	default:
+		return nil, fmt.Errorf("account %s of type %T can not be migrated", acc.GetAddress(), acc)
+	}
+}
+
+// RegisterAddressMigrationUpgrade migrates the accounts with convert once at the height of the upgrade.
+// The upgrade halts the chain if the migration fails, as the accounts are then not in the expected format.
+// The app has to clear its account store cache once the block of the upgrade is committed, as the cache
+// may still hold the accounts at the old addresses.
+func RegisterAddressMigrationUpgrade(upgradeName string, am auth.AccountKeeper, accountKey sdk.StoreKey,
+	convert AddressConverter) {
+	upgrade.Mgr.RegisterBeginBlocker(upgradeName, func(ctx sdk.Context) {
+		migrations, err := MigrateAccountAddresses(ctx, am, accountKey, convert, false)
+		if err != nil {
+			panic(fmt.Sprintf("failed to migrate the account addresses in upgrade %s: %v", upgradeName, err))
+		}
+		log.With("module", "account").Info("migrated the account addresses", "upgrade", upgradeName, "accounts", len(migrations))
+	})
+}
@@ -0,0 +1,124 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/wire"
+)
+
+// setupMigration caches the accounts over the store like the app, commit writes the account cache
+// and renews the caches, as the app clears its account store cache after the migration is committed.
+func setupMigration() (ctx sdk.Context, accountKeeper auth.AccountKeeper, accountKey sdk.StoreKey, commit func(sdk.Context) sdk.Context) {
+	ms, _, capKey2 := testutils.SetupMultiStoreForUnitTest()
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+	cdc.RegisterConcrete(&types.AppAccount{}, "bnbchain/Account", nil)
+	accountKeeper = auth.NewAccountKeeper(cdc, capKey2, auth.ProtoBaseAccount)
+	newAccountCache := func() sdk.AccountCache {
+		return auth.NewAccountCache(auth.NewAccountStoreCache(cdc, ms.GetKVStore(capKey2), 10))
+	}
+	ctx = sdk.NewContext(ms, abci.Header{ChainID: "mychainid", Height: 1}, sdk.RunTxModeDeliver, log.NewNopLogger()).
+		WithAccountCache(newAccountCache())
+	commit = func(ctx sdk.Context) sdk.Context {
+		ctx.AccountCache().Write()
+		return ctx.WithAccountCache(newAccountCache())
+	}
+	return ctx, accountKeeper, capKey2, commit
+}
+
+// flipAddress is its own inverse, so it's used to migrate the accounts forth and back
+func flipAddress(addr sdk.AccAddress) (sdk.AccAddress, bool) {
+	converted := make(sdk.AccAddress, len(addr))
+	copy(converted, addr)
+	converted[0] ^= 0xff
+	return converted, true
+}
+
+func TestMigrateAccountAddresses(t *testing.T) {
+	ctx, accountKeeper, accountKey, commit := setupMigration()
+	_, acc1 := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	_, acc2 := testutils.NewNamedAccount(ctx, accountKeeper, 200e8)
+	require.NoError(t, acc1.SetSequence(3))
+	accountKeeper.SetAccount(ctx, acc1)
+	// the accounts are iterated from the store
+	ctx = commit(ctx)
+	origins := []sdk.Account{acc1, acc2}
+
+	// dry run
+	migrations, err := MigrateAccountAddresses(ctx, accountKeeper, accountKey, flipAddress, true)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	for _, acc := range origins {
+		require.NotNil(t, accountKeeper.GetAccount(ctx, acc.GetAddress()))
+		flipped, _ := flipAddress(acc.GetAddress())
+		require.Nil(t, accountKeeper.GetAccount(ctx, flipped))
+	}
+
+	// migrate
+	migrations, err = MigrateAccountAddresses(ctx, accountKeeper, accountKey, flipAddress, false)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	// the old addresses are gone before the commit as well
+	for _, acc := range origins {
+		require.Nil(t, accountKeeper.GetAccount(ctx, acc.GetAddress()))
+	}
+	ctx = commit(ctx)
+	for _, acc := range origins {
+		require.Nil(t, accountKeeper.GetAccount(ctx, acc.GetAddress()))
+		flipped, _ := flipAddress(acc.GetAddress())
+		migrated := accountKeeper.GetAccount(ctx, flipped)
+		require.NotNil(t, migrated)
+		require.Equal(t, acc.GetCoins(), migrated.GetCoins())
+		require.Equal(t, acc.GetSequence(), migrated.GetSequence())
+		require.Equal(t, acc.GetAccountNumber(), migrated.GetAccountNumber())
+	}
+
+	// migrate back
+	migrations, err = MigrateAccountAddresses(ctx, accountKeeper, accountKey, flipAddress, false)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	ctx = commit(ctx)
+	for _, acc := range origins {
+		restored := accountKeeper.GetAccount(ctx, acc.GetAddress())
+		require.NotNil(t, restored)
+		require.Equal(t, acc.GetCoins(), restored.GetCoins())
+		require.Equal(t, acc.GetSequence(), restored.GetSequence())
+		require.Equal(t, acc.GetAccountNumber(), restored.GetAccountNumber())
+		flipped, _ := flipAddress(acc.GetAddress())
+		require.Nil(t, accountKeeper.GetAccount(ctx, flipped))
+	}
+}
+
+func TestMigrateAccountAddresses_Conflict(t *testing.T) {
+	ctx, accountKeeper, accountKey, commit := setupMigration()
+	_, acc1 := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	_, acc2 := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	_, acc3 := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	ctx = commit(ctx)
+
+	// two accounts moved to the same address
+	toSame := func(addr sdk.AccAddress) (sdk.AccAddress, bool) {
+		return acc3.GetAddress(), !addr.Equals(acc3.GetAddress())
+	}
+	_, err := MigrateAccountAddresses(ctx, accountKeeper, accountKey, toSame, false)
+	require.Error(t, err)
+
+	// moved to an address that is taken
+	toTaken := func(addr sdk.AccAddress) (sdk.AccAddress, bool) {
+		return acc2.GetAddress(), addr.Equals(acc1.GetAddress())
+	}
+	_, err = MigrateAccountAddresses(ctx, accountKeeper, accountKey, toTaken, false)
+	require.Error(t, err)
+	require.NotNil(t, accountKeeper.GetAccount(ctx, acc1.GetAddress()))
+	require.NotNil(t, accountKeeper.GetAccount(ctx, acc2.GetAddress()))
+}
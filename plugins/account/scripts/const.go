@@ -2,4 +2,17 @@ package scripts
 
 const (
 	TransferMemoCheckerFlag uint64 = 0x0000000000000001 // BEP12
+
+	// PublicationOptOutFlag asks the nodes not to publish the balances of the account to the
+	// market data publication (the Accounts topic). It only suppresses the off-chain publication:
+	// the on-chain state of the account is unchanged, its balances can still be queried from any
+	// node, its orders, trades and transfers are still executed and published as usual (so the
+	// changes of its balances can still be inferred from them), and the flag itself is part of
+	// the public account state. It is not a privacy guarantee, only a way to reduce the exposure.
+	PublicationOptOutFlag uint64 = 0x0000000000000002
 )
+
+// IsPublicationOptedOut returns whether the account flags contain the PublicationOptOutFlag.
+func IsPublicationOptedOut(flags uint64) bool {
+	return flags&PublicationOptOutFlag != 0
+}
@@ -3,12 +3,15 @@ package dex
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	app "github.com/bnb-chain/node/common/types"
+	cmnutils "github.com/bnb-chain/node/common/utils"
 	"github.com/bnb-chain/node/plugins/dex/order"
 	"github.com/bnb-chain/node/plugins/dex/store"
 	"github.com/bnb-chain/node/plugins/dex/types"
@@ -17,8 +20,10 @@ import (
 
 const MaxDepthLevels = 1000    // matches UI requirement
 const DefaultDepthLevels = 100 // matches UI requirement
+const MaxQueryLimit = 1000     // upper bound of the page size of the paginated queries
+const MaxQuoteSymbols = 100    // upper bound of the number of pairs in a dex/quotes query
 
-func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQueryHandler {
+func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string, symbolAliases *SymbolAliases) app.AbciQueryHandler {
 	queryPrefix := abciQueryPrefix
 	return func(app app.ChainApp, req abci.RequestQuery, path []string) (res *abci.ResponseQuery) {
 		// expects at least two query path segments.
@@ -26,7 +31,7 @@ func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQ
 			return nil
 		}
 		switch path[1] {
-		case "pairs": // args: ["dex" or "dex-mini", "pairs", <offset>, <limit>]
+		case "pairs": // args: ["dex" or "dex-mini", "pairs", <offset>, <limit>], with the effective publication depths
 			if len(path) < 4 {
 				return &abci.ResponseQuery{
 					Code: uint32(sdk.CodeUnknownRequest),
@@ -37,6 +42,10 @@ func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQ
 			}
 			ctx := app.GetContextForCheckState()
 			pairs := listPairs(keeper, ctx, queryPrefix)
+			for i := range pairs {
+				pairs[i].PublicationDepth = int64(keeper.GetPublicationDepth(pairs[i].GetSymbol(), order.DefaultPublicationDepth))
+				pairs[i].GTCTTLDays = int64(keeper.GetGTCTTLDays(pairs[i].GetSymbol()))
+			}
 			var offset, limit, end int
 			var err error
 			if len(pairs) == 0 {
@@ -81,7 +90,7 @@ func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQ
 				Code:  uint32(sdk.ABCICodeOK),
 				Value: bz,
 			}
-		case "orderbook": // args: ["dex", "orderbook"]
+		case "orderbook": // args: ["dex", "orderbook", <pair>, <levels>, <bucket size>, <notional depth>(optional)], levels bound the walk to the notional depth
 			if queryPrefix == DexMiniAbciQueryPrefix {
 				return &abci.ResponseQuery{
 					Code: uint32(sdk.ABCICodeOK),
@@ -96,10 +105,17 @@ func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQ
 					Log:  "OrderBook query requires the pair symbol and levels",
 				}
 			}
-			pair := path[2]
-			height := app.GetContextForCheckState().BlockHeight()
+			ctx := app.GetContextForCheckState()
+			pair, err := symbolAliases.Resolve(ctx, keeper, path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  err.Error(),
+				}
+			}
+			height := ctx.BlockHeight()
 			levelLimit := DefaultDepthLevels
-			if len(path) == 4 {
+			if len(path) >= 4 {
 				if l, err := strconv.Atoi(path[3]); err != nil {
 					return &abci.ResponseQuery{
 						Code: uint32(sdk.CodeUnknownRequest),
@@ -114,11 +130,55 @@ func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQ
 					levelLimit = l
 				}
 			}
-			levels, pendingMatch := keeper.GetOrderBookLevels(pair, levelLimit)
+			// the raw price levels by default, or aggregated into the buckets of a multiple of the tick size
+			var bucketSize int64
+			if len(path) >= 5 {
+				if bucketSize, err = strconv.ParseInt(path[4], 10, 64); err != nil || bucketSize < 0 {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeUnknownRequest),
+						Log:  fmt.Sprintf("OrderBook query requires valid bucket size parameter: %s", path[4]),
+					}
+				}
+				if bucketSize > 0 {
+					baseAsset, quoteAsset := utils.TradingPair2AssetsSafe(pair)
+					tradingPair, err := keeper.PairMapper.GetTradingPair(ctx, baseAsset, quoteAsset)
+					if err != nil {
+						return &abci.ResponseQuery{
+							Code: uint32(sdk.CodeUnknownRequest),
+							Log:  err.Error(),
+						}
+					}
+					if tickSize := tradingPair.TickSize.ToInt64(); bucketSize%tickSize != 0 {
+						return &abci.ResponseQuery{
+							Code: uint32(sdk.CodeUnknownRequest),
+							Log:  fmt.Sprintf("OrderBook query requires the bucket size to be a multiple of the tick size %d", tickSize),
+						}
+					}
+				}
+			}
+			// all the levels up to the level limit by default, or those until the cumulative quote notional of each
+			// side reaches the depth
+			var notionalDepth int64
+			if len(path) >= 6 {
+				if notionalDepth, err = strconv.ParseInt(path[5], 10, 64); err != nil || notionalDepth < 0 {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeUnknownRequest),
+						Log:  fmt.Sprintf("OrderBook query requires valid notional depth parameter: %s", path[5]),
+					}
+				}
+			}
+			levels, pendingMatch, truncated := keeper.GetAggregatedOrderBookLevels(pair, levelLimit, bucketSize)
 			book := store.OrderBook{
-				Height:       height,
-				Levels:       levels,
-				PendingMatch: pendingMatch,
+				Height:        height,
+				Levels:        levels,
+				PendingMatch:  pendingMatch,
+				Truncated:     truncated,
+				BucketSize:    cmnutils.Fixed8(bucketSize),
+				NotionalDepth: cmnutils.Fixed8(notionalDepth),
+			}
+			if notionalDepth > 0 {
+				buyNotional, sellNotional := order.LimitOrderBookLevelsByNotional(levels, notionalDepth)
+				book.BuyNotional, book.SellNotional = cmnutils.Fixed8(buyNotional), cmnutils.Fixed8(sellNotional)
 			}
 			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(book)
 			if err != nil {
@@ -148,7 +208,14 @@ func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQ
 			}
 
 			// verify pair is legal
-			pair := path[2]
+			ctx := app.GetContextForCheckState()
+			pair, err := symbolAliases.Resolve(ctx, keeper, path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  err.Error(),
+				}
+			}
 			baseAsset, quoteAsset, err := utils.TradingPair2Assets(pair)
 			if err != nil {
 				return &abci.ResponseQuery{
@@ -156,7 +223,6 @@ func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQ
 					Log:  "pair is not valid",
 				}
 			}
-			ctx := app.GetContextForCheckState()
 			if !keeper.PairMapper.Exists(ctx, baseAsset, quoteAsset) {
 				return &abci.ResponseQuery{
 					Code: uint32(sdk.CodeInternal),
@@ -184,30 +250,895 @@ func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQ
 				Code:  uint32(sdk.ABCICodeOK),
 				Value: bz,
 			}
-		default:
+		case "expiring": // args: ["dex", "expiring", <blocks>, <offset>, <limit>, <bech32Str>(optional)]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			if len(path) < 5 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "Expiring query requires the number of blocks, offset and limit",
+				}
+			}
+			blocks, err := strconv.ParseInt(path[2], 10, 64)
+			if err != nil || blocks < 0 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "Expiring query requires valid non-negative number of blocks",
+				}
+			}
+			offset, limit, errRes := parsePagination(path[3], path[4])
+			if errRes != nil {
+				return errRes
+			}
+			var owner sdk.AccAddress
+			if len(path) > 5 {
+				owner, err = sdk.AccAddressFromBech32(path[5])
+				if err != nil {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeInternal),
+						Log:  "address is not valid",
+					}
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			expiring := keeper.GetExpiringOrders(ctx, blocks, owner)
+			start, end := pageRange(len(expiring), offset, limit)
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(expiring[start:end])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
 			return &abci.ResponseQuery{
-				Code: uint32(sdk.ABCICodeOK),
-				Info: fmt.Sprintf(
-					"Unknown `%s` query path: %v",
-					queryPrefix, path),
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
 			}
-		}
-	}
-}
-
-func listPairs(keeper *DexKeeper, ctx sdk.Context, abciPrefix string) []types.TradingPair {
-	pairs := keeper.PairMapper.ListAllTradingPairs(ctx)
-	rs := make([]types.TradingPair, 0, len(pairs))
-	for _, pair := range pairs {
-		if keeper.GetPairType(pair.GetSymbol()) == order.PairType.MINI {
-			if abciPrefix == DexMiniAbciQueryPrefix {
-				rs = append(rs, pair)
+		case "accountrisk": // args: ["dex", "accountrisk", <bech32Str>]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
 			}
-		} else {
-			if abciPrefix == DexAbciQueryPrefix {
-				rs = append(rs, pair)
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "AccountRisk query requires the address",
+				}
 			}
-		}
-	}
-	return rs
+			addr, err := sdk.AccAddressFromBech32(path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "address is not valid",
+				}
+			}
+			risk := keeper.GetAccountRisk(addr, order.DefaultAccountRiskMaxOrders)
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(risk)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "ordercount": // args: ["dex", "ordercount"]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			count, ok := keeper.GetOrderCounts()
+			if !ok {
+				count = store.OrderCount{Total: keeper.GetTotalOrders()}
+			}
+			count.Max = keeper.GetParams(ctx).MaxTotalOrders
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(count)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "engineconfig": // args: ["dex", "engineconfig"]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(keeper.GetEngineConfig(ctx))
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "feerules": // args: ["dex" or "dex-mini", "feerules"]
+			ctx := app.GetContextForCheckState()
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(keeper.GetFeeRules(ctx))
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "history": // args: ["dex", "history", <date>, <pair>], date in the format of yyyy-mm-dd
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			if len(path) < 4 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "History query requires the date and the pair",
+				}
+			}
+			if !keeper.OrderBookHistoryEnabled() {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "order book history is disabled on this node",
+				}
+			}
+			date, err := time.Parse(store.HistoricalOrderBookDateFormat, path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  fmt.Sprintf("unable to parse date, expected format is %s", store.HistoricalOrderBookDateFormat),
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			pair, err := symbolAliases.Resolve(ctx, keeper, path[3])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  err.Error(),
+				}
+			}
+			book, err := keeper.GetHistoricalOrderBook(ctx, date, pair)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  err.Error(),
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(book)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "orderfills": // args: ["dex", "orderfills", <orderId>, <offset>, <limit>]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			if len(path) < 5 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "OrderFills query requires the order id, offset and limit",
+				}
+			}
+			offset, limit, errRes := parsePagination(path[3], path[4])
+			if errRes != nil {
+				return errRes
+			}
+			if !keeper.OrderFillsEnabled() {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "fills of orders are not kept on this node",
+				}
+			}
+			fills, ok := keeper.GetOrderFills(path[2])
+			if !ok {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log: fmt.Sprintf("no recent fills of order %s are found, "+
+						"the fills of older orders are available from the published trades", path[2]),
+				}
+			}
+			start, end := pageRange(len(fills), offset, limit)
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(fills[start:end])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "accounttrades": // args: ["dex", "accounttrades", <bech32Str>, <offset>, <limit>]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			if len(path) < 5 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "AccountTrades query requires the address, offset and limit",
+				}
+			}
+			addr, err := sdk.AccAddressFromBech32(path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "address is not valid",
+				}
+			}
+			offset, limit, errRes := parsePagination(path[3], path[4])
+			if errRes != nil {
+				return errRes
+			}
+			if !keeper.AccountTradesEnabled() {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "trades of accounts are not kept on this node",
+				}
+			}
+			trades := keeper.GetAccountTrades(addr)
+			start, end := pageRange(len(trades), offset, limit)
+			page := make([]store.AccountTrade, 0, end-start)
+			page = append(page, trades[start:end]...)
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(page)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "cancelled": // args: ["dex", "cancelled", <bech32Str>, <offset>, <limit>]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			if len(path) < 5 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "Cancelled query requires the address, offset and limit",
+				}
+			}
+			addr, err := sdk.AccAddressFromBech32(path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "address is not valid",
+				}
+			}
+			offset, limit, errRes := parsePagination(path[3], path[4])
+			if errRes != nil {
+				return errRes
+			}
+			if !keeper.RecentCancelsEnabled() {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "cancelled orders of accounts are not kept on this node",
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			cancels := keeper.GetRecentCancels(addr, ctx.BlockHeight())
+			start, end := pageRange(len(cancels), offset, limit)
+			page := make([]store.CancelledOrder, 0, end-start)
+			page = append(page, cancels[start:end]...)
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(page)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "toptraders": // args: ["dex", "toptraders", <symbol>, <n>, <days>]
+			if len(path) < 5 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "TopTraders query requires the symbol, the number of traders and the window in days",
+				}
+			}
+			n, err := strconv.Atoi(path[3])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "unable to parse the number of traders",
+				}
+			}
+			days, err := strconv.Atoi(path[4])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "unable to parse the window in days",
+				}
+			}
+			traders, err := keeper.GetTopTraders(path[2], n, days)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  err.Error(),
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(traders)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "activesymbols": // args: ["dex" or "dex-mini", "activesymbols", <days>]
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "ActiveSymbols query requires the window in days",
+				}
+			}
+			days, err := strconv.Atoi(path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "unable to parse the window in days",
+				}
+			}
+			pairType := order.PairType.BEP2
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				pairType = order.PairType.MINI
+			}
+			symbols, err := keeper.GetActiveSymbols(pairType, days)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  err.Error(),
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(symbols)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "updaterate": // args: ["dex" or "dex-mini", "updaterate"]
+			pairType := order.PairType.BEP2
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				pairType = order.PairType.MINI
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(keeper.GetBookUpdateRates(pairType))
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "spread": // args: ["dex" or "dex-mini", "spread", <pair>(optional)], all the pairs if no pair is given
+			ctx := app.GetContextForCheckState()
+			var spreads []store.Spread
+			if len(path) > 2 {
+				pair, err := symbolAliases.Resolve(ctx, keeper, path[2])
+				if err != nil {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeUnknownRequest),
+						Log:  err.Error(),
+					}
+				}
+				spreads = []store.Spread{keeper.GetSpread(pair)}
+			} else {
+				pairs := listPairs(keeper, ctx, queryPrefix)
+				spreads = make([]store.Spread, 0, len(pairs))
+				for _, pair := range pairs {
+					spreads = append(spreads, keeper.GetSpread(pair.GetSymbol()))
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(spreads)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "lastmatch": // args: ["dex" or "dex-mini", "lastmatch", <pair>(optional)], all the pairs if no pair is given
+			ctx := app.GetContextForCheckState()
+			var lastMatches []store.LastMatch
+			if len(path) > 2 {
+				pair, err := symbolAliases.Resolve(ctx, keeper, path[2])
+				if err != nil {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeUnknownRequest),
+						Log:  err.Error(),
+					}
+				}
+				lastMatches = []store.LastMatch{keeper.GetLastMatch(pair)}
+			} else {
+				pairs := listPairs(keeper, ctx, queryPrefix)
+				lastMatches = make([]store.LastMatch, 0, len(pairs))
+				for _, pair := range pairs {
+					lastMatches = append(lastMatches, keeper.GetLastMatch(pair.GetSymbol()))
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(lastMatches)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "quotes": // args: ["dex" or "dex-mini", "quotes", <pair>, <pair>, ...], the unknown pairs are marked so
+			symbols := path[2:]
+			if len(symbols) == 0 || len(symbols) > MaxQuoteSymbols {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  fmt.Sprintf("Quotes query requires 1 to %d pairs", MaxQuoteSymbols),
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			quotes := make([]store.Quote, 0, len(symbols))
+			for _, symbol := range symbols {
+				pair, err := symbolAliases.Resolve(ctx, keeper, symbol)
+				if err != nil {
+					quotes = append(quotes, store.Quote{Symbol: symbol, Unknown: true})
+					continue
+				}
+				quotes = append(quotes, keeper.GetQuote(pair))
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(quotes)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "twap": // args: ["dex" or "dex-mini", "twap", <pair>, <windowSeconds>]
+			if len(path) < 4 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "TWAP query requires the pair and the window in seconds",
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			pair, err := symbolAliases.Resolve(ctx, keeper, path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  err.Error(),
+				}
+			}
+			window, err := strconv.ParseInt(path[3], 10, 64)
+			if err != nil || window <= 0 || window > order.MaxTWAPWindowSeconds {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  fmt.Sprintf("TWAP query requires valid window in seconds (>0 && <=%d)", order.MaxTWAPWindowSeconds),
+				}
+			}
+			twap := keeper.GetTWAP(pair, ctx.BlockHeader().Time.Unix(), window)
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(twap)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "traderate": // args: ["dex" or "dex-mini", "traderate", <blocks>, <pair>(optional)], all the pairs if no pair is given
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "Trade rate query requires the number of blocks",
+				}
+			}
+			blocks, err := strconv.Atoi(path[2])
+			if err != nil || blocks <= 0 || blocks > order.MaxTradeRateBlocks {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  fmt.Sprintf("Trade rate query requires valid number of blocks (>0 && <=%d)", order.MaxTradeRateBlocks),
+				}
+			}
+			var pair string
+			if len(path) > 3 {
+				ctx := app.GetContextForCheckState()
+				if pair, err = symbolAliases.Resolve(ctx, keeper, path[3]); err != nil {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeUnknownRequest),
+						Log:  err.Error(),
+					}
+				}
+			}
+			pairType := order.PairType.BEP2
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				pairType = order.PairType.MINI
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(keeper.GetTradeRate(pairType, pair, blocks))
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "imbalancetrend": // args: ["dex" or "dex-mini", "imbalancetrend", <pair>, <blocks>?]
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "Imbalance trend query requires the pair",
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			pair, err := symbolAliases.Resolve(ctx, keeper, path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  err.Error(),
+				}
+			}
+			blocks := order.MaxImbalanceTrendBlocks
+			if len(path) > 3 {
+				blocks, err = strconv.Atoi(path[3])
+				if err != nil || blocks <= 0 || blocks > order.MaxImbalanceTrendBlocks {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeUnknownRequest),
+						Log:  fmt.Sprintf("Imbalance trend query requires valid number of blocks (>0 && <=%d)", order.MaxImbalanceTrendBlocks),
+					}
+				}
+			}
+			if !keeper.ImbalanceTrendsEnabled() {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "Imbalance trends are not recorded by the node, see imbalanceTrendEnabled of the query config",
+				}
+			}
+			trend := keeper.GetImbalanceTrend(pair, blocks)
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(trend)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "slippage": // args: ["dex" or "dex-mini", "slippage", <pair>, <side>, <quantity>, <format>(optional)], side as BUY or SELL, format as raw (default) or decimal
+			if len(path) < 5 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "Slippage query requires the pair, the side and the quantity",
+				}
+			}
+			decimal := false
+			if len(path) > 5 {
+				switch path[5] {
+				case "raw":
+				case "decimal":
+					decimal = true
+				default:
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeUnknownRequest),
+						Log:  "format must be raw or decimal",
+					}
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			pair, err := symbolAliases.Resolve(ctx, keeper, path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  err.Error(),
+				}
+			}
+			side, err := order.SideStringToSideCode(path[3])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  err.Error(),
+				}
+			}
+			quantity, err := strconv.ParseInt(path[4], 10, 64)
+			if err != nil || quantity <= 0 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "unable to parse the quantity",
+				}
+			}
+			estimate := keeper.EstimateSlippage(pair, side, quantity)
+			var bz []byte
+			if decimal {
+				bz, err = app.GetCodec().MarshalBinaryLengthPrefixed(estimate.ToDecimal())
+			} else {
+				bz, err = app.GetCodec().MarshalBinaryLengthPrefixed(estimate)
+			}
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "pendingmatch": // args: ["dex" or "dex-mini", "pendingmatch", <pair>]
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "PendingMatch query requires the pair symbol",
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			pair, err := symbolAliases.Resolve(ctx, keeper, path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  err.Error(),
+				}
+			}
+			// as the matching of the next block would produce
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(keeper.SimulateMatch(pair, ctx.BlockHeight()+1))
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "sizedist": // args: ["dex" or "dex-mini", "sizedist", <pair>, <boundaries>(optional)], boundaries as comma separated ascending quantities
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "SizeDist query requires the pair symbol",
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			pair, err := symbolAliases.Resolve(ctx, keeper, path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  err.Error(),
+				}
+			}
+			boundaries := order.DefaultOrderSizeBoundaries
+			if len(path) > 3 {
+				if boundaries, err = parseOrderSizeBoundaries(path[3]); err != nil {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeUnknownRequest),
+						Log:  err.Error(),
+					}
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(keeper.GetOrderSizeDistribution(pair, boundaries))
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "openinterest": // args: ["dex" or "dex-mini", "openinterest", <pair>(optional)], all the pairs if no pair is given
+			ctx := app.GetContextForCheckState()
+			var symbols []string
+			if len(path) > 2 {
+				pair, err := symbolAliases.Resolve(ctx, keeper, path[2])
+				if err != nil {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeUnknownRequest),
+						Log:  err.Error(),
+					}
+				}
+				symbols = []string{pair}
+			} else {
+				pairs := listPairs(keeper, ctx, queryPrefix)
+				symbols = make([]string, 0, len(pairs))
+				for _, pair := range pairs {
+					symbols = append(symbols, pair.GetSymbol())
+				}
+			}
+			openInterests := make([]store.OpenInterest, 0, len(symbols))
+			for _, symbol := range symbols {
+				// the pairs listed in the current block are not refreshed yet
+				if openInterest, ok := keeper.GetOpenInterest(symbol); ok {
+					openInterests = append(openInterests, openInterest)
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(openInterests)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "matchbacklog": // args: ["dex", "matchbacklog"]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(keeper.GetMatchBacklog())
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		default:
+			return &abci.ResponseQuery{
+				Code: uint32(sdk.ABCICodeOK),
+				Info: fmt.Sprintf(
+					"Unknown `%s` query path: %v",
+					queryPrefix, path),
+			}
+		}
+	}
+}
+
+// parsePagination parses the offset and limit of a paginated query, the limit is capped by MaxQueryLimit.
+func parsePagination(offsetStr, limitStr string) (offset, limit int, errRes *abci.ResponseQuery) {
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		return 0, 0, &abci.ResponseQuery{
+			Code: uint32(sdk.CodeInternal),
+			Log:  "unable to parse offset",
+		}
+	}
+	limit, err = strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		return 0, 0, &abci.ResponseQuery{
+			Code: uint32(sdk.CodeInternal),
+			Log:  "unable to parse limit",
+		}
+	}
+	if limit > MaxQueryLimit {
+		limit = MaxQueryLimit
+	}
+	return offset, limit, nil
+}
+
+// pageRange returns the bounds of the page [offset, offset+limit) of a list with `total` items,
+// the page is empty if the offset is out of range.
+func pageRange(total, offset, limit int) (start, end int) {
+	if offset > total {
+		offset = total
+	}
+	end = offset + limit
+	if end > total {
+		end = total
+	}
+	return offset, end
+}
+
+func listPairs(keeper *DexKeeper, ctx sdk.Context, abciPrefix string) []types.TradingPair {
+	pairs := keeper.PairMapper.ListAllTradingPairs(ctx)
+	rs := make([]types.TradingPair, 0, len(pairs))
+	for _, pair := range pairs {
+		if keeper.GetPairType(pair.GetSymbol()) == order.PairType.MINI {
+			if abciPrefix == DexMiniAbciQueryPrefix {
+				rs = append(rs, pair)
+			}
+		} else {
+			if abciPrefix == DexAbciQueryPrefix {
+				rs = append(rs, pair)
+			}
+		}
+	}
+	return rs
+}
+
+// parseOrderSizeBoundaries parses the comma separated bucket boundaries of the dex/sizedist query, they have to be
+// positive and ascending.
+func parseOrderSizeBoundaries(arg string) ([]int64, error) {
+	parts := strings.Split(arg, ",")
+	if len(parts) > order.MaxOrderSizeBoundaries {
+		return nil, fmt.Errorf("SizeDist query takes at most %d boundaries", order.MaxOrderSizeBoundaries)
+	}
+	boundaries := make([]int64, len(parts))
+	for i, part := range parts {
+		boundary, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || boundary <= 0 || (i > 0 && boundary <= boundaries[i-1]) {
+			return nil, fmt.Errorf("SizeDist query requires positive and ascending boundaries, got %s", arg)
+		}
+		boundaries[i] = boundary
+	}
+	return boundaries, nil
 }
@@ -0,0 +1,111 @@
+package dex
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// SymbolAliases resolves the human-readable symbols used by some integrations to the canonical
+// trading pair symbols in the dex queries. It's only a query convenience: the canonical symbols
+// on chain remain authoritative and are never changed by the resolution.
+type SymbolAliases struct {
+	enabled bool
+	aliases map[string][]string // upper-cased alias -> canonical symbols
+
+	mtx   sync.Mutex
+	pairs *pairSymbols // symbols of the listed pairs, see listedSymbols
+}
+
+// pairSymbols indexes the symbols of the listed pairs by their upper-cased forms, so the
+// queries don't go through all the pairs in the store. It's rebuilt once a pair is listed or
+// delisted, which changes the pairs version of the keeper. The pairs are changed while a block
+// is executed but the queries only see them once it's committed, so the index built right after
+// a change is only reused at the same height, until it's settled by a rebuild at a later height.
+type pairSymbols struct {
+	version int64
+	height  int64
+	settled bool
+	symbols map[string][]string // upper-cased symbol -> canonical symbols
+}
+
+// NewSymbolAliases creates the alias resolution layer from the configured aliases,
+// each of which is in the form of "alias:CANONICAL_SYMBOL".
+func NewSymbolAliases(enabled bool, aliases []string) (*SymbolAliases, error) {
+	sa := &SymbolAliases{
+		enabled: enabled,
+		aliases: make(map[string][]string, len(aliases)),
+	}
+	for _, entry := range aliases {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return nil, fmt.Errorf("invalid symbol alias %q, expected the form of alias:CANONICAL_SYMBOL", entry)
+		}
+		alias := strings.ToUpper(parts[0])
+		sa.aliases[alias] = appendIfMissing(sa.aliases[alias], parts[1])
+	}
+	return sa, nil
+}
+
+// Resolve returns the canonical symbol of the given one. The symbol is returned as is if aliasing
+// is disabled or there is nothing to resolve, an error is returned if it's ambiguous.
+func (sa *SymbolAliases) Resolve(ctx sdk.Context, keeper *DexKeeper, symbol string) (string, error) {
+	if sa == nil || !sa.enabled {
+		return symbol, nil
+	}
+
+	upper := strings.ToUpper(symbol)
+	candidates := make([]string, 0, 1)
+	for _, canonical := range sa.aliases[upper] {
+		candidates = appendIfMissing(candidates, canonical)
+	}
+	for _, canonical := range sa.listedSymbols(ctx, keeper)[upper] {
+		if canonical == symbol {
+			return canonical, nil
+		}
+		candidates = appendIfMissing(candidates, canonical)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return symbol, nil
+	case 1:
+		return candidates[0], nil
+	default:
+		return "", fmt.Errorf("symbol alias %s is ambiguous, it could be any of %v", symbol, candidates)
+	}
+}
+
+func (sa *SymbolAliases) listedSymbols(ctx sdk.Context, keeper *DexKeeper) map[string][]string {
+	version := keeper.PairsVersion()
+	sa.mtx.Lock()
+	defer sa.mtx.Unlock()
+	if ps := sa.pairs; ps != nil && ps.version == version && (ps.settled || ps.height == ctx.BlockHeight()) {
+		return ps.symbols
+	}
+
+	symbols := make(map[string][]string)
+	for _, pair := range keeper.PairMapper.ListAllTradingPairs(ctx) {
+		canonical := pair.GetSymbol()
+		upper := strings.ToUpper(canonical)
+		symbols[upper] = appendIfMissing(symbols[upper], canonical)
+	}
+	sa.pairs = &pairSymbols{
+		version: version,
+		height:  ctx.BlockHeight(),
+		settled: sa.pairs != nil && sa.pairs.version == version,
+		symbols: symbols,
+	}
+	return symbols
+}
+
+func appendIfMissing(symbols []string, symbol string) []string {
+	for _, s := range symbols {
+		if s == symbol {
+			return symbols
+		}
+	}
+	return append(symbols, symbol)
+}
@@ -0,0 +1,101 @@
+package dex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkstore "github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/types"
+	dextypes "github.com/bnb-chain/node/plugins/dex/types"
+	"github.com/bnb-chain/node/wire"
+)
+
+func setupAliases(t *testing.T, aliases ...string) (sdk.Context, *DexKeeper, *SymbolAliases) {
+	cdc := wire.NewCodec()
+	types.RegisterWire(cdc)
+	ms := sdkstore.NewCommitMultiStore(db.NewMemDB())
+	ms.MountStoreWithDB(common.AccountStoreKey, sdk.StoreTypeIAVL, nil)
+	ms.MountStoreWithDB(common.DexStoreKey, sdk.StoreTypeIAVL, nil)
+	ms.MountStoreWithDB(common.PairStoreKey, sdk.StoreTypeIAVL, nil)
+	require.NoError(t, ms.LoadLatestVersion())
+	ctx := sdk.NewContext(ms.CacheMultiStore(), abci.Header{Height: 10}, sdk.RunTxModeDeliver, log.NewNopLogger())
+
+	accKeeper := auth.NewAccountKeeper(cdc, common.AccountStoreKey, types.ProtoAppAccount)
+	pairMapper := NewTradingPairMapper(cdc, common.PairStoreKey)
+	keeper := NewDexKeeper(common.DexStoreKey, accKeeper, pairMapper, sdk.NewCodespacer().RegisterNext(DefaultCodespace), 2, cdc, false)
+
+	symbolAliases, err := NewSymbolAliases(true, aliases)
+	require.NoError(t, err)
+	return ctx, keeper, symbolAliases
+}
+
+func TestSymbolAliases_Resolve(t *testing.T) {
+	ctx, keeper, symbolAliases := setupAliases(t, "xyz_bnb:XYZ-000_BNB", "abc_bnb:ABC-000_BNB", "abc_bnb:ABC-001_BNB")
+	pair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+
+	for symbol, expected := range map[string]string{
+		"XYZ-000_BNB": "XYZ-000_BNB",
+		"xyz-000_bnb": "XYZ-000_BNB",
+		"XYZ_BNB":     "XYZ-000_BNB",
+		"ZZZ-000_BNB": "ZZZ-000_BNB",
+	} {
+		resolved, err := symbolAliases.Resolve(ctx, keeper, symbol)
+		require.NoError(t, err)
+		require.Equal(t, expected, resolved, symbol)
+	}
+	_, err := symbolAliases.Resolve(ctx, keeper, "abc_bnb")
+	require.Error(t, err)
+
+	// the aliases are not resolved once disabled
+	disabled, err := NewSymbolAliases(false, []string{"xyz_bnb:XYZ-000_BNB"})
+	require.NoError(t, err)
+	resolved, err := disabled.Resolve(ctx, keeper, "xyz_bnb")
+	require.NoError(t, err)
+	require.Equal(t, "xyz_bnb", resolved)
+}
+
+func TestSymbolAliases_Resolve_Cached(t *testing.T) {
+	ctx, keeper, symbolAliases := setupAliases(t)
+	xyzPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, xyzPair))
+	keeper.AddEngine(xyzPair)
+	resolve := func(ctx sdk.Context, symbol string) string {
+		resolved, err := symbolAliases.Resolve(ctx, keeper, symbol)
+		require.NoError(t, err)
+		return resolved
+	}
+	require.Equal(t, "XYZ-000_BNB", resolve(ctx, "xyz-000_bnb"))
+
+	// the pairs changed in the store only are not seen until the index built after the last change is settled at a
+	// later height
+	abcPair := dextypes.NewTradingPair("ABC-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, abcPair))
+	require.Equal(t, "abc-000_bnb", resolve(ctx, "abc-000_bnb"))
+	ctx = ctx.WithBlockHeight(11)
+	require.Equal(t, "ABC-000_BNB", resolve(ctx, "abc-000_bnb"))
+	defPair := dextypes.NewTradingPair("DEF-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, defPair))
+	ctx = ctx.WithBlockHeight(12)
+	require.Equal(t, "def-000_bnb", resolve(ctx, "def-000_bnb"))
+
+	// listed
+	keeper.AddEngine(defPair)
+	require.Equal(t, "DEF-000_BNB", resolve(ctx, "def-000_bnb"))
+
+	// delisted
+	keeper.DelistTradingPair(ctx, "XYZ-000_BNB", nil)
+	ctx = ctx.WithBlockHeight(13)
+	require.Equal(t, "xyz-000_bnb", resolve(ctx, "xyz-000_bnb"))
+	require.Equal(t, "DEF-000_BNB", resolve(ctx, "def-000_bnb"))
+}
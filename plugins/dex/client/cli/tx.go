@@ -29,6 +29,7 @@ const (
 	flagQty         = "qty"
 	flagSide        = "side"
 	flagTimeInForce = "tif"
+	flagOrderMemo   = "order-memo"
 )
 
 func newOrderCmd(cdc *wire.Codec) *cobra.Command {
@@ -90,6 +91,7 @@ func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 			}
 
 			msg.TimeInForce = tif
+			msg.Memo = viper.GetString(flagOrderMemo)
 
 			err = client.SendOrPrintTx(cliCtx, txBldr, msg)
 			if err != nil {
@@ -105,6 +107,7 @@ func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 	cmd.Flags().StringP(flagPrice, "p", "", "price for the order")
 	cmd.Flags().StringP(flagQty, "q", "", "quantity for the order")
 	cmd.Flags().StringP(flagTimeInForce, "t", "gte", "TimeInForce for the order (gte or ioc)")
+	cmd.Flags().String(flagOrderMemo, "", "optional memo of the order for the client tagging, other than the memo of the tx")
 	return cmd
 }
 
@@ -0,0 +1,67 @@
+package exemption
+
+import (
+	"fmt"
+	"reflect"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+
+	"github.com/bnb-chain/node/plugins/dex/order"
+	"github.com/bnb-chain/node/plugins/dex/types"
+)
+
+// NewHandler initialises the handler of the fee exemption msgs
+func NewHandler(keeper *order.DexKeeper, govKeeper gov.Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case types.AddFeeExemptionMsg:
+			return handleFeeExemption(ctx, keeper, govKeeper, msg.ProposalId, msg.Account, true)
+		case types.RemoveFeeExemptionMsg:
+			return handleFeeExemption(ctx, keeper, govKeeper, msg.ProposalId, msg.Account, false)
+		default:
+			errMsg := fmt.Sprintf("Unrecognized fee exemption msg type: %v", reflect.TypeOf(msg).Name())
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func checkFeeExemptionProposal(ctx sdk.Context, govKeeper gov.Keeper, proposalId int64, account sdk.AccAddress,
+	exempt bool) error {
+	proposal := govKeeper.GetProposal(ctx, proposalId)
+	if proposal == nil {
+		return fmt.Errorf("proposal %d does not exist", proposalId)
+	}
+	if proposal.GetProposalType() != gov.ProposalTypeText {
+		return fmt.Errorf("proposal type(%s) should be %s", proposal.GetProposalType(), gov.ProposalTypeText)
+	}
+	if proposal.GetStatus() != gov.StatusPassed {
+		return fmt.Errorf("proposal status(%s) should be Passed", proposal.GetStatus())
+	}
+	change, ok, err := types.GetFeeExemptionsChange(proposal.GetDescription())
+	if !ok {
+		return fmt.Errorf("proposal %d is not a fee exemptions change", proposalId)
+	}
+	if err != nil {
+		return err
+	}
+	if !change.Lists(account, exempt) {
+		action := "add"
+		if !exempt {
+			action = "remove"
+		}
+		return fmt.Errorf("proposal %d does not %s the fee exemption of %s", proposalId, action, account.String())
+	}
+	return nil
+}
+
+func handleFeeExemption(ctx sdk.Context, keeper *order.DexKeeper, govKeeper gov.Keeper, proposalId int64,
+	account sdk.AccAddress, exempt bool) sdk.Result {
+	if err := checkFeeExemptionProposal(ctx, govKeeper, proposalId, account, exempt); err != nil {
+		return types.ErrInvalidProposal(err.Error()).Result()
+	}
+	if err := keeper.SetFeeExempt(ctx, account, exempt, proposalId); err != nil {
+		return types.ErrInvalidProposal(err.Error()).Result()
+	}
+	return sdk.Result{}
+}
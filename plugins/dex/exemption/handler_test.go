@@ -0,0 +1,140 @@
+package exemption
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdkStore "github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/dex/order"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	dexTypes "github.com/bnb-chain/node/plugins/dex/types"
+)
+
+func MakeCodec() *codec.Codec {
+	var cdc = codec.New()
+
+	bank.RegisterCodec(cdc)
+	sdk.RegisterCodec(cdc)
+	types.RegisterWire(cdc)
+	gov.RegisterCodec(cdc)
+
+	return cdc
+}
+
+func MakeKeepers(cdc *codec.Codec) (ms sdkStore.CommitMultiStore, dexKeeper *order.DexKeeper, govKeeper gov.Keeper) {
+	accKey := sdk.NewKVStoreKey("acc")
+	pairKey := sdk.NewKVStoreKey("pair")
+	paramKey := sdk.NewKVStoreKey("param")
+	paramTKey := sdk.NewTransientStoreKey("t_param")
+	stakeKey := sdk.NewKVStoreKey("stake")
+	stakeRewardKey := sdk.NewKVStoreKey("stake_reward")
+	stakeTKey := sdk.NewTransientStoreKey("t_stake")
+	govKey := sdk.NewKVStoreKey("gov")
+
+	memDB := db.NewMemDB()
+	ms = sdkStore.NewCommitMultiStore(memDB)
+	ms.MountStoreWithDB(accKey, sdk.StoreTypeIAVL, memDB)
+	ms.MountStoreWithDB(pairKey, sdk.StoreTypeIAVL, memDB)
+	ms.MountStoreWithDB(common.DexStoreKey, sdk.StoreTypeIAVL, memDB)
+	ms.MountStoreWithDB(paramKey, sdk.StoreTypeIAVL, memDB)
+	ms.MountStoreWithDB(stakeKey, sdk.StoreTypeIAVL, memDB)
+	ms.MountStoreWithDB(stakeRewardKey, sdk.StoreTypeIAVL, memDB)
+	ms.MountStoreWithDB(govKey, sdk.StoreTypeIAVL, memDB)
+	ms.LoadLatestVersion()
+
+	accKeeper := auth.NewAccountKeeper(cdc, accKey, types.ProtoAppAccount)
+	codespacer := sdk.NewCodespacer()
+	pairMapper := store.NewTradingPairMapper(cdc, pairKey)
+	dexKeeper = order.NewDexKeeper(common.DexStoreKey, accKeeper, pairMapper, codespacer.RegisterNext(dexTypes.DefaultCodespace), 2, cdc, false)
+
+	paramsKeeper := params.NewKeeper(cdc, paramKey, paramTKey)
+	bankKeeper := bank.NewBaseKeeper(accKeeper)
+	stakeKeeper := stake.NewKeeper(
+		cdc,
+		stakeKey, stakeRewardKey, stakeTKey,
+		bankKeeper, nil, paramsKeeper.Subspace(stake.DefaultParamspace),
+		stake.DefaultCodespace,
+		sdk.ChainID(0),
+		"",
+	)
+	govKeeper = gov.NewKeeper(cdc, govKey,
+		paramsKeeper, paramsKeeper.Subspace(gov.DefaultParamSpace),
+		bankKeeper,
+		stakeKeeper,
+		gov.DefaultCodespace,
+		new(sdk.Pool))
+
+	return ms, dexKeeper, govKeeper
+}
+
+func setProposal(ctx sdk.Context, govKeeper gov.Keeper, proposalId int64, change dexTypes.FeeExemptionsChange,
+	status gov.ProposalStatus) {
+	bz, _ := json.Marshal(map[string]dexTypes.FeeExemptionsChange{"fee_exemptions": change})
+	govKeeper.SetProposal(ctx, &gov.TextProposal{
+		ProposalID:   proposalId,
+		Title:        "fee exemptions",
+		Description:  string(bz),
+		ProposalType: gov.ProposalTypeText,
+		Status:       status,
+		TallyResult:  gov.EmptyTallyResult(),
+		TotalDeposit: sdk.Coins{},
+		SubmitTime:   time.Now(),
+	})
+}
+
+func TestFeeExemptionHandler(t *testing.T) {
+	cdc := MakeCodec()
+	ms, dexKeeper, govKeeper := MakeKeepers(cdc)
+	ctx := sdk.NewContext(ms, abci.Header{}, sdk.RunTxModeDeliver, log.NewNopLogger())
+	handler := NewHandler(dexKeeper, govKeeper)
+	from := sdk.AccAddress([]byte("from________________"))
+	account := sdk.AccAddress([]byte("account_____________"))
+	other := sdk.AccAddress([]byte("other_______________"))
+
+	result := handler(ctx, dexTypes.NewAddFeeExemptionMsg(from, 1, account))
+	require.Contains(t, result.Log, "proposal 1 does not exist")
+
+	change := dexTypes.FeeExemptionsChange{Add: []string{account.String()}, Remove: []string{other.String()}}
+	setProposal(ctx, govKeeper, 1, change, gov.StatusVotingPeriod)
+	result = handler(ctx, dexTypes.NewAddFeeExemptionMsg(from, 1, account))
+	require.Contains(t, result.Log, "proposal status(VotingPeriod) should be Passed")
+
+	setProposal(ctx, govKeeper, 1, change, gov.StatusPassed)
+	result = handler(ctx, dexTypes.NewAddFeeExemptionMsg(from, 1, other))
+	require.Contains(t, result.Log, "does not add the fee exemption")
+	result = handler(ctx, dexTypes.NewRemoveFeeExemptionMsg(from, 1, account))
+	require.Contains(t, result.Log, "does not remove the fee exemption")
+	require.False(t, dexKeeper.IsFeeExempt(ctx, account))
+
+	result = handler(ctx, dexTypes.NewAddFeeExemptionMsg(from, 1, account))
+	require.True(t, result.IsOK(), result.Log)
+	require.True(t, dexKeeper.IsFeeExempt(ctx, account))
+	require.False(t, dexKeeper.IsFeeExempt(ctx, other))
+	result = handler(ctx, dexTypes.NewRemoveFeeExemptionMsg(from, 1, other))
+	require.True(t, result.IsOK(), result.Log)
+	require.False(t, dexKeeper.IsFeeExempt(ctx, other))
+
+	// the exemption is removed by a later proposal, which the earlier one can't revert
+	setProposal(ctx, govKeeper, 2, dexTypes.FeeExemptionsChange{Remove: []string{account.String()}}, gov.StatusPassed)
+	result = handler(ctx, dexTypes.NewRemoveFeeExemptionMsg(from, 2, account))
+	require.True(t, result.IsOK(), result.Log)
+	require.False(t, dexKeeper.IsFeeExempt(ctx, account))
+	result = handler(ctx, dexTypes.NewAddFeeExemptionMsg(from, 1, account))
+	require.Contains(t, result.Log, "has been changed by proposal 2")
+	require.False(t, dexKeeper.IsFeeExempt(ctx, account))
+}
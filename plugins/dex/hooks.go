@@ -0,0 +1,42 @@
+package dex
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/dex/types"
+)
+
+// FeeExemptionHooks validates the fee exemptions changes carried by text proposals since the GovernedParams
+// upgrade, which are applied by the fee exemption msgs once passed. Plain text proposals are left untouched. The dex
+// params and the trading pair params are changed by the fee change proposals instead, see DexParams and
+// PairParamsChange.
+type FeeExemptionHooks struct{}
+
+func NewFeeExemptionHooks() FeeExemptionHooks {
+	return FeeExemptionHooks{}
+}
+
+var _ gov.GovHooks = FeeExemptionHooks{}
+
+func (hooks FeeExemptionHooks) OnProposalSubmitted(ctx sdk.Context, proposal gov.Proposal) error {
+	if proposal.GetProposalType() != gov.ProposalTypeText {
+		panic(fmt.Sprintf("received wrong type of proposal %x", proposal.GetProposalType()))
+	}
+	if !sdk.IsUpgrade(upgrade.GovernedParams) {
+		return nil
+	}
+
+	return hooks.onFeeExemptionsChangeSubmitted(ctx, proposal)
+}
+
+func (hooks FeeExemptionHooks) onFeeExemptionsChangeSubmitted(ctx sdk.Context, proposal gov.Proposal) error {
+	_, ok, err := types.GetFeeExemptionsChange(proposal.GetDescription())
+	if !ok {
+		return nil
+	}
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	param "github.com/cosmos/cosmos-sdk/x/paramHub/types"
@@ -15,6 +16,8 @@ import (
 	"github.com/bnb-chain/node/common/upgrade"
 	cmnUtils "github.com/bnb-chain/node/common/utils"
 	"github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	dexTypes "github.com/bnb-chain/node/plugins/dex/types"
 	"github.com/bnb-chain/node/plugins/dex/utils"
 	"github.com/bnb-chain/node/wire"
 )
@@ -36,6 +39,9 @@ const (
 	FeeRateNativeField   = "FeeRateNative"
 	IOCExpireFee         = "IOCExpireFee"
 	IOCExpireFeeNative   = "IOCExpireFeeNative"
+	// MinTradeFeeFieldPrefix is followed by the asset in the name of the fee field of the min trade fee of the
+	// asset, e.g. "MinTradeFee:BNB"
+	MinTradeFeeFieldPrefix = "MinTradeFee:"
 )
 
 var (
@@ -69,13 +75,19 @@ func (m *FeeManager) GetConfig() FeeConfig {
 	return m.FeeConfig
 }
 
-func (m *FeeManager) CalcTradesFee(balances sdk.Coins, tradeTransfers TradeTransfers, engines map[string]*matcheng.MatchEng) sdk.Fee {
+// CalcTradesFee charges the trades of an account in the given allocation ordering, see dexTypes.AllocationOrderingMatch etc.
+func (m *FeeManager) CalcTradesFee(balances sdk.Coins, tradeTransfers TradeTransfers, engines map[string]*matcheng.MatchEng, ordering string) sdk.Fee {
 	var fees sdk.Fee
 	if tradeTransfers == nil {
 		return fees
 	}
-	tradeTransfers.Sort()
+	if ordering == dexTypes.AllocationOrderingOrderId {
+		tradeTransfers.SortByOrderId()
+	} else {
+		tradeTransfers.Sort()
+	}
 	for _, tran := range tradeTransfers {
+		tran.feeRoundUp = ordering == dexTypes.AllocationOrderingAlternateRounding && tran.alternateFeeRoundsUp()
 		fee := m.calcTradeFeeFromTransfer(balances, tran, engines)
 		tran.Fee = fee
 		if tran.IsBuyer() {
@@ -116,27 +128,40 @@ func (m *FeeManager) calcTradeFeeFromTransfer(balances sdk.Coins, tran *Transfer
 		// 1. the fee is paid by native token
 		// 2. the balance is enough to pay the fee.
 		// 3. never have int64 overflow
-		return dexFeeWrap(sdk.NewCoin(types.NativeTokenSymbol, nativeFee))
+		return dexFeeWrap(m.applyMinTradeFee(balances, sdk.NewCoin(types.NativeTokenSymbol, nativeFee)))
 	}
 
 	if isOverflow || nativeFee == 0 || nativeFee > balances.AmountOf(types.NativeTokenSymbol) {
 		// 1. if the fee is too low and round to 0, we charge by inAsset
 		// 2. no enough NativeToken, use the received tokens as fee
-		feeToken = sdk.NewCoin(tran.inAsset, m.TradeFee(big.NewInt(tran.in), FeeByTradeToken).Int64())
+		feeToken = sdk.NewCoin(tran.inAsset, m.tradeFee(big.NewInt(tran.in), FeeByTradeToken, tran.feeRoundUp).Int64())
 		m.logger.Debug("No enough native token to pay trade fee", "feeToken", feeToken)
 	} else {
 		// have sufficient native token to pay the fees
 		feeToken = sdk.NewCoin(types.NativeTokenSymbol, nativeFee)
 	}
-	return dexFeeWrap(feeToken)
+	return dexFeeWrap(m.applyMinTradeFee(balances, feeToken))
+}
+
+// applyMinTradeFee raises the trade fee to the min trade fee of the charged asset if any, so that the tiny trades
+// are not charged nothing due to the truncation. The raised fee is capped by the balance of the asset.
+func (m *FeeManager) applyMinTradeFee(balances sdk.Coins, fee sdk.Coin) sdk.Coin {
+	minFee := m.FeeConfig.GetMinTradeFee(fee.Denom)
+	if fee.Amount >= minFee {
+		return fee
+	}
+	if balance := balances.AmountOf(fee.Denom); balance < minFee {
+		minFee = cmnUtils.MaxInt(balance, fee.Amount)
+	}
+	return sdk.NewCoin(fee.Denom, minFee)
 }
 
 func (m *FeeManager) calcNativeFee(tran *Transfer, engines map[string]*matcheng.MatchEng) (fee int64, isOverflow bool) {
 	var nativeFee *big.Int
 	if tran.IsNativeIn() {
-		nativeFee = m.TradeFee(big.NewInt(tran.in), FeeByNativeToken)
+		nativeFee = m.tradeFee(big.NewInt(tran.in), FeeByNativeToken, tran.feeRoundUp)
 	} else if tran.IsNativeOut() {
-		nativeFee = m.TradeFee(big.NewInt(tran.out), FeeByNativeToken)
+		nativeFee = m.tradeFee(big.NewInt(tran.out), FeeByNativeToken, tran.feeRoundUp)
 	} else {
 		// pair pattern: ABC_XYZ/XYZ_ABC, inAsset: ABC
 		// must exist ABC/BNB. or ABC/BUSD after upgrade
@@ -159,7 +184,7 @@ func (m *FeeManager) calcNativeFee(tran *Transfer, engines map[string]*matcheng.
 				}
 			}
 		}
-		nativeFee = m.TradeFee(notional, FeeByNativeToken)
+		nativeFee = m.tradeFee(notional, FeeByNativeToken, tran.feeRoundUp)
 	}
 	if nativeFee.IsInt64() {
 		return nativeFee.Int64(), false
@@ -180,6 +205,36 @@ func (m *FeeManager) calcNotional(asset string, qty int64, quoteAsset string, en
 	return notional, true
 }
 
+// projectNativeTradeFee returns the worst-case fee in the native token of the trade of the transfer, i.e. the fee
+// charged by calcTradeFeeFromTransfer rounded up and raised to the min trade fee. It's nil if the in asset has no
+// price against the native token, either directly or through BUSD.
+func (m *FeeManager) projectNativeTradeFee(tran *Transfer, engines map[string]*matcheng.MatchEng) *big.Int {
+	var notional *big.Int
+	if tran.IsNativeIn() {
+		notional = big.NewInt(tran.in)
+	} else if tran.IsNativeOut() {
+		notional = big.NewInt(tran.out)
+	} else {
+		var pairExist bool
+		notional, pairExist = m.calcNotional(tran.inAsset, tran.in, types.NativeTokenSymbol, engines)
+		if !pairExist && sdk.IsUpgrade(upgrade.BEP70) && len(BUSDSymbol) > 0 {
+			qty := tran.out
+			if tran.inAsset == BUSDSymbol {
+				qty = tran.in
+			}
+			notional, pairExist = m.calcNotional(BUSDSymbol, qty, types.NativeTokenSymbol, engines)
+		}
+		if !pairExist {
+			return nil
+		}
+	}
+	fee := m.tradeFee(notional, FeeByNativeToken, true)
+	if minFee := big.NewInt(m.FeeConfig.GetMinTradeFee(types.NativeTokenSymbol)); fee.Cmp(minFee) < 0 {
+		fee = minFee
+	}
+	return fee
+}
+
 // DEPRECATED
 // Note1: the result of `CalcTradeFeeDeprecated` depends on the balances of the acc,
 // so the right way of allocation is:
@@ -309,6 +364,11 @@ func (m *FeeManager) CancelFees() (int64, int64) {
 }
 
 func (m *FeeManager) TradeFee(amount *big.Int, feeType FeeType) *big.Int {
+	return m.tradeFee(amount, feeType, false)
+}
+
+// tradeFee calculates the trade fee, which is truncated unless roundUp is set.
+func (m *FeeManager) tradeFee(amount *big.Int, feeType FeeType, roundUp bool) *big.Int {
 	var feeRate int64
 	if feeType == FeeByNativeToken {
 		feeRate = m.FeeConfig.FeeRateNative
@@ -318,7 +378,11 @@ func (m *FeeManager) TradeFee(amount *big.Int, feeType FeeType) *big.Int {
 
 	// TODO: (Perf) find a more efficient way to replace the big.Int solution.
 	var fee big.Int
-	return fee.Div(fee.Mul(amount, big.NewInt(feeRate)), FeeRateMultiplier)
+	fee.Mul(amount, big.NewInt(feeRate))
+	if roundUp && fee.Sign() > 0 {
+		fee.Add(&fee, new(big.Int).Sub(FeeRateMultiplier, big.NewInt(1)))
+	}
+	return fee.Div(&fee, FeeRateMultiplier)
 }
 
 func (m *FeeManager) ExpireFee(feeType FeeType) int64 {
@@ -360,6 +424,18 @@ type FeeConfig struct {
 	CancelFeeNative    int64 `json:"cancel_fee_native"`
 	FeeRate            int64 `json:"fee_rate"`
 	FeeRateNative      int64 `json:"fee_rate_native"`
+	// MinTradeFees are the min fees of a trade in the charged assets, applied after the fee rates
+	MinTradeFees []store.MinTradeFee `json:"min_trade_fees"`
+}
+
+// GetMinTradeFee returns the min fee of a trade charged in the asset, 0 if there is none.
+func (config FeeConfig) GetMinTradeFee(asset string) int64 {
+	for _, minFee := range config.MinTradeFees {
+		if minFee.Asset == asset {
+			return minFee.Fee
+		}
+	}
+	return 0
 }
 
 func NewFeeConfig() FeeConfig {
@@ -412,6 +488,11 @@ func ParamToFeeConfig(feeParams []param.FeeParam) *FeeConfig {
 					config.IOCExpireFee = d.FeeValue
 				case IOCExpireFeeNative:
 					config.IOCExpireFeeNative = d.FeeValue
+				default:
+					if strings.HasPrefix(d.FeeName, MinTradeFeeFieldPrefix) {
+						asset := strings.TrimPrefix(d.FeeName, MinTradeFeeFieldPrefix)
+						config.MinTradeFees = append(config.MinTradeFees, store.MinTradeFee{Asset: asset, Fee: d.FeeValue})
+					}
 				}
 			}
 			return &config
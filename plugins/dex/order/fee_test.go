@@ -1,15 +1,20 @@
 package order
 
 import (
+	"fmt"
+	"math/big"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	param "github.com/cosmos/cosmos-sdk/x/paramHub/types"
 
 	"github.com/bnb-chain/node/common/testutils"
 	"github.com/bnb-chain/node/common/types"
 	"github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
 	dextype "github.com/bnb-chain/node/plugins/dex/types"
 )
 
@@ -145,7 +150,7 @@ func TestFeeManager_CalcTradesFee(t *testing.T) {
 		{"XYZ-111", 100e8},
 		{"ZYX-000M", 100e8},
 	})
-	fees := keeper.FeeManager.CalcTradesFee(acc.GetCoins(), tradeTransfers, keeper.engines)
+	fees := keeper.FeeManager.CalcTradesFee(acc.GetCoins(), tradeTransfers, keeper.engines, dextype.AllocationOrderingMatch)
 	require.Equal(t, "ABC-000:8000;BNB:15251305;BTC:100000;XYZ-111:2000;ZYX-000M:20000", fees.String())
 	require.Equal(t, "BNB:250000", tradeTransfers[0].Fee.String())
 	require.Equal(t, "BNB:15000000", tradeTransfers[1].Fee.String())
@@ -168,6 +173,129 @@ func TestFeeManager_CalcTradesFee(t *testing.T) {
 	}, acc.GetCoins())
 }
 
+func TestFeeManager_MinTradeFee(t *testing.T) {
+	setChainVersion()
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	config := ParamToFeeConfig([]param.FeeParam{&param.DexFeeParam{DexFeeFields: []param.DexFeeField{
+		{FeeName: FeeRateField, FeeValue: 1000},
+		{FeeName: FeeRateNativeField, FeeValue: 500},
+		{FeeName: ExpireFeeField, FeeValue: 1e5},
+		{FeeName: ExpireFeeNativeField, FeeValue: 2e4},
+		{FeeName: IOCExpireFee, FeeValue: 5e4},
+		{FeeName: IOCExpireFeeNative, FeeValue: 1e4},
+		{FeeName: CancelFeeField, FeeValue: 1e5},
+		{FeeName: CancelFeeNativeField, FeeValue: 2e4},
+		{FeeName: MinTradeFeeFieldPrefix + "BNB", FeeValue: 10},
+		{FeeName: MinTradeFeeFieldPrefix + "ABC-000", FeeValue: 3},
+	}}})
+	require.Equal(t, []store.MinTradeFee{{"BNB", 10}, {"ABC-000", 3}}, config.MinTradeFees)
+	require.NoError(t, keeper.FeeManager.UpdateConfig(*config))
+	keeper.AddEngine(dextype.NewTradingPair("ABC-000", "BNB", 1e7))
+	_, acc := testutils.NewAccount(ctx, am, 100)
+
+	// the fee in BNB rounds to 0
+	tran := Transfer{Oid: "s-1", inAsset: "BNB", in: 100, outAsset: "ABC-000", out: 1000, Trade: &matcheng.Trade{Sid: "s-1", Bid: "b-1"}}
+	fee := keeper.FeeManager.CalcTradesFee(acc.GetCoins(), TradeTransfers{&tran}, keeper.engines, dextype.AllocationOrderingMatch)
+	require.Equal(t, "BNB:10", fee.String())
+	// the published fee is the raised one
+	require.Equal(t, "BNB:10", tran.Trade.SellerFee.String())
+	// capped by the balance
+	fee = keeper.FeeManager.calcTradeFeeFromTransfer(sdk.Coins{{"BNB", 4}}, &tran, keeper.engines)
+	require.Equal(t, sdk.Coins{{"BNB", 4}}, fee.Tokens)
+	// the fee in ABC-000 is 1 by the rate
+	tran = Transfer{inAsset: "ABC-000", in: 1000, outAsset: "BNB", out: 100}
+	fee = keeper.FeeManager.calcTradeFeeFromTransfer(sdk.Coins{{"ABC-000", 1000}}, &tran, keeper.engines)
+	require.Equal(t, sdk.Coins{{"ABC-000", 3}}, fee.Tokens)
+	// above the min fee
+	tran = Transfer{inAsset: "ABC-000", in: 1e6, outAsset: "BNB", out: 1e5}
+	fee = keeper.FeeManager.calcTradeFeeFromTransfer(sdk.Coins{{"ABC-000", 1e6}}, &tran, keeper.engines)
+	require.Equal(t, sdk.Coins{{"ABC-000", 1000}}, fee.Tokens)
+	require.Equal(t, config.MinTradeFees, keeper.GetFeeRules(ctx).MinTradeFees)
+}
+
+func TestFeeManager_CalcTradesFee_SortByOrderId(t *testing.T) {
+	setChainVersion()
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	keeper.AddEngine(dextype.NewTradingPair("ABC-000", "BNB", 1e7))
+
+	tradeTransfers := TradeTransfers{
+		{inAsset: "ABC-000", outAsset: "BNB", Oid: "3", in: 1e5, out: 2e6, Trade: &matcheng.Trade{}},
+		{inAsset: "BNB", outAsset: "ABC-000", Oid: "2", in: 1e6, out: 1e7, Trade: &matcheng.Trade{}},
+		{inAsset: "ABC-000", outAsset: "BNB", Oid: "1", in: 2e5, out: 2e6, Trade: &matcheng.Trade{}},
+	}
+	_, acc := testutils.NewAccount(ctx, am, 0)
+	_ = acc.SetCoins(sdk.Coins{{"ABC-000", 100e8}, {"BNB", 1500}})
+	// only the first of the ABC-000 trades is charged by BNB, which is "3" in the match ordering
+	fees := keeper.FeeManager.CalcTradesFee(acc.GetCoins(), tradeTransfers, keeper.engines, dextype.AllocationOrderingOrderId)
+	require.Equal(t, "2", tradeTransfers[0].Oid)
+	require.Equal(t, "1", tradeTransfers[1].Oid)
+	require.Equal(t, "3", tradeTransfers[2].Oid)
+	require.Equal(t, "BNB:500", tradeTransfers[0].Fee.String())
+	require.Equal(t, "BNB:1000", tradeTransfers[1].Fee.String())
+	require.Equal(t, "ABC-000:100", tradeTransfers[2].Fee.String())
+	require.Equal(t, "ABC-000:100;BNB:1500", fees.String())
+}
+
+// Over many trades, the truncation of the fees is always in favor of the traders in the match ordering,
+// while the alternate rounding leaves neither side of the trades systematically biased.
+func TestFeeManager_CalcTradesFee_AlternateRounding(t *testing.T) {
+	setChainVersion()
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	keeper.AddEngine(dextype.NewTradingPair("ABC-000", "BNB", 1e7))
+	_, acc := testutils.NewAccount(ctx, am, 0)
+	_ = acc.SetCoins(sdk.Coins{{"ABC-000", 1e16}, {"BNB", 1e16}})
+
+	const numTrades = 10000
+	rnd := rand.New(rand.NewSource(42))
+	trades := make([]*matcheng.Trade, numTrades)
+	for i := range trades {
+		qty := rnd.Int63n(1e9) + 1e5
+		trades[i] = &matcheng.Trade{
+			Bid: fmt.Sprintf("B-%d", i), Sid: fmt.Sprintf("S-%d", i),
+			LastPx: 1e7, LastQty: qty, BuyCumQty: qty, SellCumQty: qty,
+		}
+	}
+
+	// the deviations from the exact fees of the buyers and the sellers, in FeeRateMultiplier units
+	deviations := func(ordering string) (buyers, sellers int64, buyersRoundUp int) {
+		for _, trade := range trades {
+			notional := trade.LastQty / 10
+			seller := &Transfer{Oid: trade.Sid, inAsset: "BNB", in: notional, outAsset: "ABC-000", out: trade.LastQty, Trade: trade}
+			buyer := &Transfer{Oid: trade.Bid, inAsset: "ABC-000", in: trade.LastQty, outAsset: "BNB", out: notional, Trade: trade}
+			exact := notional * keeper.FeeManager.FeeConfig.FeeRateNative
+			for _, tran := range []*Transfer{seller, buyer} {
+				fee := keeper.FeeManager.CalcTradesFee(acc.GetCoins(), TradeTransfers{tran}, keeper.engines, ordering)
+				deviation := fee.Tokens.AmountOf("BNB")*FeeRateMultiplier.Int64() - exact
+				if tran.IsBuyer() {
+					buyers += deviation
+					if deviation > 0 {
+						buyersRoundUp++
+					}
+				} else {
+					sellers += deviation
+				}
+			}
+		}
+		return
+	}
+
+	multiplier := FeeRateMultiplier.Int64()
+	buyers, sellers, _ := deviations(dextype.AllocationOrderingMatch)
+	// truncated by half a unit on average
+	require.True(t, buyers < -numTrades/4*multiplier, buyers)
+	require.True(t, sellers < -numTrades/4*multiplier, sellers)
+
+	buyers, sellers, buyersRoundUp := deviations(dextype.AllocationOrderingAlternateRounding)
+	require.True(t, buyers > -numTrades/20*multiplier && buyers < numTrades/20*multiplier, buyers)
+	require.True(t, sellers > -numTrades/20*multiplier && sellers < numTrades/20*multiplier, sellers)
+	require.InDelta(t, numTrades/2, buyersRoundUp, numTrades/20)
+}
+
 func TestFeeManager_CalcExpiresFee(t *testing.T) {
 	setChainVersion()
 	defer resetChainVersion()
@@ -493,3 +621,32 @@ func TestFeeManager_CalcFixedFee_SupportBUSD(t *testing.T) {
 	fee = keeper.FeeManager.CalcFixedFee(acc.GetCoins(), eventFullyExpire, "XYZ-999", keeper.engines)
 	require.Equal(t, sdk.Coins{sdk.NewCoin("XYZ-999", 1e2)}, fee.Tokens)
 }
+
+func TestKeeper_GetFeeRules(t *testing.T) {
+	ctx, _, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	params := keeper.GetParams(ctx)
+	params.MakerRebateRate = 2500
+	params.DelistFeeFree = true
+	keeper.setParams(ctx, params)
+
+	rules := keeper.GetFeeRules(ctx)
+	require.Equal(t, types.NativeTokenSymbol, rules.NativeAsset)
+	require.Equal(t, int64(1000), rules.FeeRate)
+	require.Equal(t, int64(500), rules.FeeRateNative)
+	require.Equal(t, int64(1e4), rules.IOCExpireFeeNative)
+	require.Equal(t, int64(1e5), rules.CancelFee)
+	require.Equal(t, dextype.AllocationOrderingMatch, rules.AllocationOrdering)
+	require.Equal(t, int64(2500), rules.MakerRebateRate)
+	require.True(t, rules.DelistFeeFree)
+	require.Equal(t, dextype.InsufficientFeePolicyChargeReceived, rules.InsufficientFeePolicy)
+
+	// the clients get the same trade fee out of the rules
+	notional := int64(123456789)
+	var pow int64 = 1
+	for i := int64(0); i < rules.FeeRateDecimals; i++ {
+		pow *= 10
+	}
+	require.Equal(t, notional*rules.FeeRateNative/pow, keeper.FeeManager.TradeFee(big.NewInt(notional), FeeByNativeToken).Int64())
+	require.Equal(t, notional*rules.FeeRate/pow, keeper.FeeManager.TradeFee(big.NewInt(notional), FeeByTradeToken).Int64())
+}
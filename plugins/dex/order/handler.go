@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strings"
 
@@ -11,6 +12,8 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/fees"
 
+	"github.com/bnb-chain/node/common/audit"
+	bncfees "github.com/bnb-chain/node/common/fees"
 	common "github.com/bnb-chain/node/common/types"
 	"github.com/bnb-chain/node/common/upgrade"
 	me "github.com/bnb-chain/node/plugins/dex/matcheng"
@@ -27,12 +30,25 @@ func NewHandler(dexKeeper *DexKeeper) sdk.Handler {
 	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
 		switch msg := msg.(type) {
 		case NewOrderMsg:
+			var res sdk.Result
 			if sdk.IsUpgrade(upgrade.BEP151) {
-				return sdk.ErrMsgNotSupported("NewOrderMsg disabled in BEP-151").Result()
+				res = sdk.ErrMsgNotSupported("NewOrderMsg disabled in BEP-151").Result()
+			} else {
+				res = handleNewOrder(ctx, dexKeeper, msg)
 			}
-			return handleNewOrder(ctx, dexKeeper, msg)
+			if res.IsOK() {
+				dexKeeper.recordOrderAck(ctx, msg, res)
+			} else {
+				dexKeeper.recordOrderRejection(ctx, msg, res)
+				audit.Rejections.Record(ctx, msg.Sender, msg, res)
+			}
+			return res
 		case CancelOrderMsg:
-			return handleCancelOrder(ctx, dexKeeper, msg)
+			res := handleCancelOrder(ctx, dexKeeper, msg)
+			if !res.IsOK() {
+				audit.Rejections.Record(ctx, msg.Sender, msg, res)
+			}
+			return res
 		default:
 			errMsg := fmt.Sprintf("Unrecognized dex msg type: %v", reflect.TypeOf(msg).Name())
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -43,7 +59,11 @@ func NewHandler(dexKeeper *DexKeeper) sdk.Handler {
 func validateQtyAndLockBalance(ctx sdk.Context, keeper *DexKeeper, acc common.NamedAccount, msg NewOrderMsg) error {
 	symbol := strings.ToUpper(msg.Symbol)
 	baseAssetSymbol, quoteAssetSymbol := utils.TradingPair2AssetsSafe(symbol)
-	notional := utils.CalBigNotionalInt64(msg.Price, msg.Quantity)
+	// the notional is checked by validateOrder, but not on rechecks
+	notional, ok := utils.CalNotionalInt64Checked(msg.Price, msg.Quantity)
+	if !ok {
+		return types.ErrNotionalOverflow(msg.Price, msg.Quantity)
+	}
 
 	// note: the check sequence is well designed.
 	freeBalance := acc.GetCoins()
@@ -95,21 +115,147 @@ func validateQtyAndLockBalance(ctx sdk.Context, keeper *DexKeeper, acc common.Na
 		toLockCoins = sdk.Coins{{Denom: baseAssetSymbol, Amount: msg.Quantity}}
 	}
 
+	if err := checkFeeAffordable(ctx, keeper, acc, msg, freeBalance.Minus(toLockCoins)); err != nil {
+		return err
+	}
+
 	_ = acc.SetCoins(freeBalance.Minus(toLockCoins))
 	acc.SetLockedCoins(acc.GetLockedCoins().Plus(toLockCoins))
 	keeper.am.SetAccount(ctx, acc)
 	return nil
 }
 
+// validateMinBalance rejects the orders of the accounts without enough free native token, to reduce the spam from
+// empty accounts. Cancellations are not restricted.
+func validateMinBalance(ctx sdk.Context, keeper *DexKeeper, acc common.NamedAccount) error {
+	minBalance := keeper.GetParams(ctx).MinBalanceToPlaceOrder
+	if minBalance <= 0 {
+		return nil
+	}
+	if balance := acc.GetCoins().AmountOf(common.NativeTokenSymbol); balance < minBalance {
+		return fmt.Errorf("the free balance of %s is %d, at least %d is required to place orders",
+			common.NativeTokenSymbol, balance, minBalance)
+	}
+	return nil
+}
+
+// checkFeeAffordable rejects the order under the InsufficientFeePolicyReject policy if the free balance of the native
+// token left after locking it can't cover the worst-case trade fee of filling the whole order at its price. Under
+// the default policy the fee is charged from the received asset instead, see calcTradeFeeFromTransfer.
+func checkFeeAffordable(ctx sdk.Context, keeper *DexKeeper, acc common.NamedAccount, msg NewOrderMsg, freeBalance sdk.Coins) error {
+	if keeper.GetParams(ctx).InsufficientFeePolicy != types.InsufficientFeePolicyReject ||
+		keeper.IsFeeExempt(ctx, acc.GetAddress()) {
+		return nil
+	}
+	baseAsset, quoteAsset := utils.TradingPair2AssetsSafe(strings.ToUpper(msg.Symbol))
+	notional := utils.CalBigNotionalInt64(msg.Price, msg.Quantity)
+	tran := Transfer{inAsset: baseAsset, in: msg.Quantity, outAsset: quoteAsset, out: notional}
+	if msg.Side == Side.SELL {
+		tran = Transfer{inAsset: quoteAsset, in: notional, outAsset: baseAsset, out: msg.Quantity}
+	}
+	if tran.IsNativeIn() {
+		return nil
+	}
+	fee := keeper.FeeManager.projectNativeTradeFee(&tran, keeper.engines)
+	if fee == nil {
+		return nil
+	}
+	if balance := freeBalance.AmountOf(common.NativeTokenSymbol); fee.Cmp(big.NewInt(balance)) > 0 {
+		return fmt.Errorf("the free balance of %s is %d after locking the order, not enough for the trade fee %s",
+			common.NativeTokenSymbol, balance, fee.String())
+	}
+	return nil
+}
+
+// checkCancelCooldown rejects the cancellation of an order placed less than CancelCooldownBlocks blocks ago.
+func checkCancelCooldown(ctx sdk.Context, keeper *DexKeeper, ord OrderInfo) error {
+	cooldown := keeper.GetParams(ctx).CancelCooldownBlocks
+	if cooldown <= 0 {
+		return nil
+	}
+	if cancellableFrom := ord.CreatedHeight + cooldown; ctx.BlockHeight() < cancellableFrom {
+		return fmt.Errorf("order [%v] was placed at height %d, it can not be cancelled until height %d",
+			ord.Id, ord.CreatedHeight, cancellableFrom)
+	}
+	return nil
+}
+
+// checkMinOrderLifetime rejects the cancellation of an order resting in the order book for less than
+// MinOrderLifetimeBlocks blocks. The order rests since the block it's placed in once it has gone through a matching
+// of the pair, which is the same block unless the matching of the pair is deferred.
+func checkMinOrderLifetime(ctx sdk.Context, keeper *DexKeeper, ord OrderInfo) error {
+	lifetime := keeper.GetParams(ctx).MinOrderLifetimeBlocks
+	if lifetime <= 0 {
+		return nil
+	}
+	// the last match heights are only kept since BEP19
+	if eng, ok := keeper.engines[ord.Symbol]; ok && sdk.IsUpgrade(upgrade.BEP19) && eng.LastMatchHeight < ord.CreatedHeight {
+		return fmt.Errorf("order [%v] is not matched into the order book yet, it can not be cancelled until it has rested for %d blocks",
+			ord.Id, lifetime)
+	}
+	if rested := ctx.BlockHeight() - ord.CreatedHeight; rested < lifetime {
+		return fmt.Errorf("order [%v] has rested in the order book for %d blocks, it can not be cancelled until height %d",
+			ord.Id, rested, ord.CreatedHeight+lifetime)
+	}
+	return nil
+}
+
+// checkTradingPairExists rejects the orders of the trading pairs not listed with their own code, rather than the
+// generic invalid order param one, so that the misconfigured clients can be told apart.
+func checkTradingPairExists(ctx sdk.Context, keeper *DexKeeper, msg NewOrderMsg) sdk.Error {
+	baseAsset, quoteAsset, err := utils.TradingPair2Assets(msg.Symbol)
+	if err == nil && keeper.PairMapper.Exists(ctx, baseAsset, quoteAsset) {
+		return nil
+	}
+	if keeper.logUnknownPairOrders && !ctx.IsReCheckTx() {
+		keeper.logger.Info("order of unknown trading pair", "symbol", msg.Symbol, "sender", msg.Sender, "id", msg.Id,
+			"deliverTx", ctx.IsDeliverTx())
+	}
+	return types.ErrUnknownTradingPair(msg.Symbol)
+}
+
 func handleNewOrder(
 	ctx sdk.Context, dexKeeper *DexKeeper, msg NewOrderMsg,
 ) sdk.Result {
+	// ValidateBasic is not run by every caller, so check again before touching any state
+	if err := validateOrderPriceAndQty(msg.Price, msg.Quantity); err != nil {
+		return err.Result()
+	}
+	if sdk.IsUpgrade(upgrade.OrderUnknownPairCode) {
+		if err := checkTradingPairExists(ctx, dexKeeper, msg); err != nil {
+			return err.Result()
+		}
+	}
 	if _, ok := dexKeeper.OrderExists(msg.Symbol, msg.Id); ok {
 		errString := fmt.Sprintf("Duplicated order [%v] on symbol [%v]", msg.Id, msg.Symbol)
 		return sdk.NewError(types.DefaultCodespace, types.CodeDuplicatedOrder, errString).Result()
 	}
 
+	// the orders of the block are only known in DeliverTx
+	if ctx.IsDeliverTx() {
+		if err := dexKeeper.checkOrderThrottle(ctx, msg.Sender); err != nil {
+			return sdk.NewError(types.DefaultCodespace, types.CodeTooManyOrdersInBlock, err.Error()).Result()
+		}
+	}
+	if err := dexKeeper.checkTotalOrders(ctx); err != nil {
+		return sdk.NewError(types.DefaultCodespace, types.CodeTooManyOrders, err.Error()).Result()
+	}
+	recent, sdkErr := dexKeeper.checkDuplicateOrder(ctx, msg)
+	if sdkErr != nil {
+		return sdkErr.Result()
+	}
+	if err := dexKeeper.checkPairSession(ctx, msg.Symbol); err != nil {
+		return err.Result()
+	}
+	if err := dexKeeper.checkTokenTrading(ctx, msg.Symbol); err != nil {
+		return err.Result()
+	}
+
 	acc := dexKeeper.am.GetAccount(ctx, msg.Sender).(common.NamedAccount)
+	if err := validateMinBalance(ctx, dexKeeper, acc); err != nil {
+		return sdk.NewError(types.DefaultCodespace, types.CodeBalanceTooLowToOrder, err.Error()).Result()
+	}
+
 	if !ctx.IsReCheckTx() {
 		//for recheck:
 		// 1. sequence is verified in anteHandler
@@ -157,6 +303,8 @@ func handleNewOrder(
 			if err != nil {
 				return sdk.NewError(types.DefaultCodespace, types.CodeFailInsertOrder, err.Error()).Result()
 			}
+			dexKeeper.countOrderInBlock(ctx, msg.Sender)
+			dexKeeper.recordOrderFingerprint(ctx, msg.NewOrderMsg, recent)
 		} else {
 			panic("cannot get txHash from ctx")
 		}
@@ -193,6 +341,13 @@ func handleCancelOrder(
 		return sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, errString).Result()
 	}
 
+	if err := checkCancelCooldown(ctx, dexKeeper, origOrd); err != nil {
+		return sdk.NewError(types.DefaultCodespace, types.CodeCancelInCooldown, err.Error()).Result()
+	}
+	if err := checkMinOrderLifetime(ctx, dexKeeper, origOrd); err != nil {
+		return sdk.NewError(types.DefaultCodespace, types.CodeCancelBeforeMinLifetime, err.Error()).Result()
+	}
+
 	ord, err := dexKeeper.GetOrder(origOrd.Id, origOrd.Symbol, origOrd.Side, origOrd.Price)
 	if err != nil {
 		return sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, err.Error()).Result()
@@ -203,7 +358,7 @@ func handleCancelOrder(
 		return sdkError.Result()
 	}
 	fee := sdk.Fee{}
-	if !transfer.FeeFree() {
+	if !transfer.FeeFree() && !dexKeeper.IsFeeExempt(ctx, msg.Sender) {
 		acc := dexKeeper.am.GetAccount(ctx, msg.Sender)
 		fee = dexKeeper.FeeManager.CalcFixedFee(acc.GetCoins(), transfer.eventType, transfer.inAsset, dexKeeper.GetEngines())
 		_ = acc.SetCoins(acc.GetCoins().Minus(fee.Tokens))
@@ -217,6 +372,7 @@ func handleCancelOrder(
 		} else {
 			// add fee to pool, even it's free
 			fees.Pool.AddFee(txHash, fee)
+			bncfees.Tracker.Add(txHash, msg.Sender, fee.Tokens)
 		}
 		//remove order from cache and order book
 		err := dexKeeper.RemoveOrder(origOrd.Id, origOrd.Symbol, func(ord me.OrderPart) {
@@ -229,6 +385,7 @@ func handleCancelOrder(
 		if err != nil {
 			return sdk.NewError(types.DefaultCodespace, types.CodeFailCancelOrder, err.Error()).Result()
 		}
+		dexKeeper.recentCancels.add(&origOrd, Canceled, ctx.BlockHeight(), ctx.BlockHeader().Time.UnixNano())
 	}
 
 	return sdk.Result{}
@@ -242,7 +399,8 @@ func validateOrder(ctx sdk.Context, dexKeeper *DexKeeper, acc sdk.Account, msg N
 
 	seq := acc.GetSequence()
 	expectedID := GenerateOrderID(seq, msg.Sender)
-	if expectedID != msg.Id {
+	// the legacy format is still accepted after the order id versioning, as the clients may not be aware of the upgrade
+	if expectedID != msg.Id && GenerateVersionedOrderID(OrderIDVersionLegacy, seq, msg.Sender) != msg.Id {
 		return fmt.Errorf("the order ID(%s) given did not match the expected one: `%s`", msg.Id, expectedID)
 	}
 
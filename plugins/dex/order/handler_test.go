@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"math"
 	"testing"
+	"time"
 
+	"github.com/cosmos/cosmos-sdk/baseapp"
 	cstore "github.com/cosmos/cosmos-sdk/store"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/fees"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/stretchr/testify/require"
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -14,9 +17,13 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/testutils"
+	cmntypes "github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/plugins/dex/store"
 	"github.com/bnb-chain/node/plugins/dex/types"
 	dextypes "github.com/bnb-chain/node/plugins/dex/types"
+	tokenStore "github.com/bnb-chain/node/plugins/tokens/store"
 	"github.com/bnb-chain/node/wire"
 )
 
@@ -192,3 +199,501 @@ func TestHandler_ValidateOrder_MaxNotional(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, "notional value of the order is too large(cannot fit in int64)", err.Error())
 }
+
+func TestHandler_NewOrder_ThrottlePerAccountPerBlock(t *testing.T) {
+	ctx, am, keeper := setup()
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	keeper.setParams(ctx, types.DexParams{MaxOrdersPerAccountPerBlock: 3})
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	_, acc2 := testutils.NewAccount(ctx, am, 100e8)
+
+	placeOrder := func(ctx sdk.Context, addr sdk.AccAddress, seq int64) sdk.Result {
+		acc := am.GetAccount(ctx, addr)
+		require.NoError(t, acc.SetSequence(seq))
+		am.SetAccount(ctx, acc)
+		msg := NewNewOrderMsg(addr, GenerateOrderID(seq, addr), Side.BUY, "AAA-000_BNB", 1e8, 1e8)
+		return handleNewOrder(ctx, keeper, msg)
+	}
+
+	ctx = ctx.WithBlockHeight(100).WithValue(baseapp.TxHashKey, "")
+	for seq := int64(0); seq < 3; seq++ {
+		res := placeOrder(ctx, acc.GetAddress(), seq)
+		require.True(t, res.IsOK(), res.Log)
+	}
+	res := placeOrder(ctx, acc.GetAddress(), 3)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeTooManyOrdersInBlock), res.Code, res.Log)
+	require.Len(t, keeper.GetOpenOrders("AAA-000_BNB", acc.GetAddress()), 3)
+
+	// other accounts are not affected
+	res = placeOrder(ctx, acc2.GetAddress(), 0)
+	require.True(t, res.IsOK(), res.Log)
+
+	// the counter is reset in the next block
+	ctx = ctx.WithBlockHeight(101)
+	res = placeOrder(ctx, acc.GetAddress(), 3)
+	require.True(t, res.IsOK(), res.Log)
+	require.Len(t, keeper.GetOpenOrders("AAA-000_BNB", acc.GetAddress()), 4)
+}
+
+func TestHandler_NewOrder_MinBalanceToPlaceOrder(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	keeper.setParams(ctx, types.DexParams{MinBalanceToPlaceOrder: 10e8})
+	_, acc := testutils.NewAccount(ctx, am, 10e8)
+	ctx = ctx.WithBlockHeight(100).WithValue(baseapp.TxHashKey, "")
+
+	placeOrder := func(seq int64) (NewOrderMsg, sdk.Result) {
+		acc := am.GetAccount(ctx, acc.GetAddress())
+		require.NoError(t, acc.SetSequence(seq))
+		am.SetAccount(ctx, acc)
+		msg := NewNewOrderMsg(acc.GetAddress(), GenerateOrderID(seq, acc.GetAddress()), Side.BUY, "AAA-000_BNB", 1e8, 1e8)
+		return msg, handleNewOrder(ctx, keeper, msg)
+	}
+
+	// exactly at the threshold
+	msg, res := placeOrder(0)
+	require.True(t, res.IsOK(), res.Log)
+
+	// 1e8 BNB is locked by the order, so the free balance is below the threshold
+	_, res = placeOrder(1)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeBalanceTooLowToOrder), res.Code, res.Log)
+	require.Len(t, keeper.GetOpenOrders("AAA-000_BNB", acc.GetAddress()), 1)
+
+	// the orders can still be cancelled
+	res = handleCancelOrder(ctx, keeper, NewCancelOrderMsg(acc.GetAddress(), "AAA-000_BNB", msg.Id))
+	require.True(t, res.IsOK(), res.Log)
+	require.Len(t, keeper.GetOpenOrders("AAA-000_BNB", acc.GetAddress()), 0)
+
+	// disabled by default
+	keeper.setParams(ctx, types.DefaultDexParams())
+	_, res = placeOrder(1)
+	require.True(t, res.IsOK(), res.Log)
+}
+
+func TestHandler_NewOrder_MaxTotalOrders(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	keeper.setParams(ctx, types.DexParams{MaxTotalOrders: 2})
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	ctx = ctx.WithBlockHeight(100).WithValue(baseapp.TxHashKey, "")
+
+	placeOrder := func(seq int64) (NewOrderMsg, sdk.Result) {
+		acc := am.GetAccount(ctx, acc.GetAddress())
+		require.NoError(t, acc.SetSequence(seq))
+		am.SetAccount(ctx, acc)
+		msg := NewNewOrderMsg(acc.GetAddress(), GenerateOrderID(seq, acc.GetAddress()), Side.BUY, "AAA-000_BNB", 1e8, 1e8)
+		return msg, handleNewOrder(ctx, keeper, msg)
+	}
+
+	msg, res := placeOrder(0)
+	require.True(t, res.IsOK(), res.Log)
+	_, res = placeOrder(1)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, int64(2), keeper.GetTotalOrders())
+
+	// the cap is reached, the existing orders are kept
+	_, res = placeOrder(2)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeTooManyOrders), res.Code, res.Log)
+	require.Equal(t, int64(2), keeper.GetTotalOrders())
+
+	// there is room again once an order is cancelled
+	res = handleCancelOrder(ctx, keeper, NewCancelOrderMsg(acc.GetAddress(), "AAA-000_BNB", msg.Id))
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, int64(1), keeper.GetTotalOrders())
+	_, res = placeOrder(2)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, int64(2), keeper.GetTotalOrders())
+
+	// the filled orders are counted out once matched, the sell order fills both of the buy orders placed in the
+	// same block at the same price, which share its quantity
+	sell := NewNewOrderMsg(acc.GetAddress(), "sell", Side.SELL, "AAA-000_BNB", 1e8, 2e8)
+	require.NoError(t, keeper.AddOrder(OrderInfo{sell, 100, 0, 100, 0, 0, "", 0}, false))
+	require.Equal(t, int64(3), keeper.GetTotalOrders())
+	keeper.MatchSymbols(100, 0, true)
+	require.Equal(t, int64(0), keeper.GetTotalOrders())
+}
+
+func TestHandler_CancelOrder_Cooldown(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	keeper.setParams(ctx, types.DexParams{CancelCooldownBlocks: 3})
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	ctx = ctx.WithBlockHeight(100).WithValue(baseapp.TxHashKey, "")
+
+	msg := NewNewOrderMsg(acc.GetAddress(), GenerateOrderID(0, acc.GetAddress()), Side.BUY, "AAA-000_BNB", 1e8, 1e8)
+	res := handleNewOrder(ctx, keeper, msg)
+	require.True(t, res.IsOK(), res.Log)
+	cancel := NewCancelOrderMsg(acc.GetAddress(), "AAA-000_BNB", msg.Id)
+
+	// within the cooldown, the order is kept
+	for _, height := range []int64{100, 102} {
+		res = handleCancelOrder(ctx.WithBlockHeight(height), keeper, cancel)
+		require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeCancelInCooldown), res.Code, res.Log)
+		require.Contains(t, res.Log, "can not be cancelled until height 103")
+		_, exists := keeper.OrderExists("AAA-000_BNB", msg.Id)
+		require.True(t, exists)
+	}
+
+	// after the cooldown
+	res = handleCancelOrder(ctx.WithBlockHeight(103), keeper, cancel)
+	require.True(t, res.IsOK(), res.Log)
+	_, exists := keeper.OrderExists("AAA-000_BNB", msg.Id)
+	require.False(t, exists)
+
+	// no cooldown by default
+	keeper.setParams(ctx, types.DefaultDexParams())
+	account := am.GetAccount(ctx, acc.GetAddress())
+	require.NoError(t, account.SetSequence(1))
+	am.SetAccount(ctx, account)
+	msg = NewNewOrderMsg(acc.GetAddress(), GenerateOrderID(1, acc.GetAddress()), Side.BUY, "AAA-000_BNB", 1e8, 1e8)
+	res = handleNewOrder(ctx, keeper, msg)
+	require.True(t, res.IsOK(), res.Log)
+	res = handleCancelOrder(ctx, keeper, NewCancelOrderMsg(acc.GetAddress(), "AAA-000_BNB", msg.Id))
+	require.True(t, res.IsOK(), res.Log)
+}
+
+func TestHandler_CancelOrder_MinOrderLifetime(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	keeper.setParams(ctx, types.DexParams{MinOrderLifetimeBlocks: 3})
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	ctx = ctx.WithBlockHeight(100).WithValue(baseapp.TxHashKey, "")
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 10)
+	upgrade.Mgr.SetHeight(100)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+	}()
+
+	msg := NewNewOrderMsg(acc.GetAddress(), GenerateOrderID(0, acc.GetAddress()), Side.BUY, "AAA-000_BNB", 1e8, 1e8)
+	res := handleNewOrder(ctx, keeper, msg)
+	require.True(t, res.IsOK(), res.Log)
+	cancel := NewCancelOrderMsg(acc.GetAddress(), "AAA-000_BNB", msg.Id)
+
+	// not matched into the order book yet
+	res = handleCancelOrder(ctx, keeper, cancel)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeCancelBeforeMinLifetime), res.Code, res.Log)
+	require.Contains(t, res.Log, "not matched into the order book yet")
+
+	// resting since the matching of the block it's placed in
+	keeper.engines["AAA-000_BNB"].LastMatchHeight = 100
+	for _, height := range []int64{100, 102} {
+		res = handleCancelOrder(ctx.WithBlockHeight(height), keeper, cancel)
+		require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeCancelBeforeMinLifetime), res.Code, res.Log)
+		require.Contains(t, res.Log, "can not be cancelled until height 103")
+		_, exists := keeper.OrderExists("AAA-000_BNB", msg.Id)
+		require.True(t, exists)
+	}
+
+	res = handleCancelOrder(ctx.WithBlockHeight(103), keeper, cancel)
+	require.True(t, res.IsOK(), res.Log)
+	_, exists := keeper.OrderExists("AAA-000_BNB", msg.Id)
+	require.False(t, exists)
+}
+
+func TestHandler_CancelOrder_RecentCancels(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	ctx = ctx.WithBlockHeight(100).WithValue(baseapp.TxHashKey, "")
+	require.Empty(t, keeper.GetRecentCancels(acc.GetAddress(), 100))
+
+	var ids []string
+	for seq := int64(0); seq < 2; seq++ {
+		account := am.GetAccount(ctx, acc.GetAddress())
+		require.NoError(t, account.SetSequence(seq))
+		am.SetAccount(ctx, account)
+		msg := NewNewOrderMsg(acc.GetAddress(), GenerateOrderID(seq, acc.GetAddress()), Side.BUY, "AAA-000_BNB", 1e8, 1e8)
+		res := handleNewOrder(ctx, keeper, msg)
+		require.True(t, res.IsOK(), res.Log)
+		ids = append(ids, msg.Id)
+	}
+
+	res := handleCancelOrder(ctx, keeper, NewCancelOrderMsg(acc.GetAddress(), "AAA-000_BNB", ids[0]))
+	require.True(t, res.IsOK(), res.Log)
+	keeper.DelistTradingPair(ctx.WithBlockHeight(101), "AAA-000_BNB", nil)
+	fees.Pool.Clear()
+
+	cancels := keeper.GetRecentCancels(acc.GetAddress(), 101)
+	require.Len(t, cancels, 2)
+	require.Equal(t, ids[0], cancels[0].Id)
+	require.Equal(t, Canceled.String(), cancels[0].Reason)
+	require.Equal(t, int64(100), cancels[0].Height)
+	require.Equal(t, ids[1], cancels[1].Id)
+	require.Equal(t, Delisted.String(), cancels[1].Reason)
+	require.Equal(t, int64(101), cancels[1].Height)
+
+	// out of the window
+	require.Len(t, keeper.GetRecentCancels(acc.GetAddress(), 100+RecentCancelsWindowBlocks), 1)
+	require.Empty(t, keeper.GetRecentCancels(acc.GetAddress(), 101+RecentCancelsWindowBlocks))
+
+	keeper.SetRecentCancelsCacheSize(0)
+	require.False(t, keeper.RecentCancelsEnabled())
+	require.Empty(t, keeper.GetRecentCancels(acc.GetAddress(), 101))
+}
+
+func TestHandler_NewOrder_DuplicateOrderWindow(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	keeper.setParams(ctx, types.DexParams{DuplicateOrderWindowBlocks: 3})
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "")
+
+	seq := int64(0)
+	placeOrder := func(height, qty int64) sdk.Result {
+		ctx := ctx.WithBlockHeight(height)
+		account := am.GetAccount(ctx, acc.GetAddress())
+		require.NoError(t, account.SetSequence(seq))
+		am.SetAccount(ctx, account)
+		msg := NewNewOrderMsg(acc.GetAddress(), GenerateOrderID(seq, acc.GetAddress()), Side.BUY, "AAA-000_BNB", 1e8, qty)
+		res := handleNewOrder(ctx, keeper, msg)
+		if res.IsOK() {
+			seq++
+		}
+		return res
+	}
+
+	res := placeOrder(100, 1e8)
+	require.True(t, res.IsOK(), res.Log)
+	// the same order within the window
+	res = placeOrder(102, 1e8)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeRepeatedOrder), res.Code, res.Log)
+	require.Contains(t, res.Log, "placed at height 100")
+	// other params are fine
+	res = placeOrder(102, 2e8)
+	require.True(t, res.IsOK(), res.Log)
+	// out of the window
+	res = placeOrder(103, 1e8)
+	require.True(t, res.IsOK(), res.Log)
+	res = placeOrder(104, 2e8)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeRepeatedOrder), res.Code, res.Log)
+
+	// the breathe blocks drop the orders out of the window, and the accounts left with none
+	keeper.PruneRecentOrders(ctx, 105)
+	require.Len(t, keeper.getRecentOrders(ctx, acc.GetAddress()), 1)
+	keeper.PruneRecentOrders(ctx, 106)
+	require.Nil(t, ctx.KVStore(keeper.storeKey).Get(recentOrdersKey(acc.GetAddress())))
+	res = placeOrder(106, 1e8)
+	require.True(t, res.IsOK(), res.Log)
+
+	// disabled by default
+	keeper.setParams(ctx, types.DefaultDexParams())
+	res = placeOrder(104, 2e8)
+	require.True(t, res.IsOK(), res.Log)
+}
+
+func TestHandler_NewOrder_PairSession(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	pair.SessionOpen, pair.SessionClose = 3600, 7200
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "")
+
+	seq := int64(0)
+	placeOrder := func(unixSec int64) (NewOrderMsg, sdk.Result) {
+		ctx := ctx.WithBlockTime(time.Unix(unixSec, 0))
+		account := am.GetAccount(ctx, acc.GetAddress())
+		require.NoError(t, account.SetSequence(seq))
+		am.SetAccount(ctx, account)
+		msg := NewNewOrderMsg(acc.GetAddress(), GenerateOrderID(seq, acc.GetAddress()), Side.BUY, "AAA-000_BNB", 1e8, 1e8)
+		res := handleNewOrder(ctx, keeper, msg)
+		if res.IsOK() {
+			seq++
+		}
+		return msg, res
+	}
+
+	_, res := placeOrder(3599)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodePairOutOfSession), res.Code, res.Log)
+	msg, res := placeOrder(3600)
+	require.True(t, res.IsOK(), res.Log)
+	_, res = placeOrder(7200)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodePairOutOfSession), res.Code, res.Log)
+
+	// the orders can still be cancelled out of the session
+	res = handleCancelOrder(ctx.WithBlockTime(time.Unix(7200, 0)), keeper, NewCancelOrderMsg(acc.GetAddress(), "AAA-000_BNB", msg.Id))
+	require.True(t, res.IsOK(), res.Log)
+	require.Len(t, keeper.GetOpenOrders("AAA-000_BNB", acc.GetAddress()), 0)
+}
+
+func TestHandler_NewOrder_UnknownTradingPair(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	keeper.SetLogUnknownPairOrders(true)
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	ctx = ctx.WithBlockHeight(100).WithValue(baseapp.TxHashKey, "")
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.OrderUnknownPairCode, 10)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+	}()
+	msg := NewNewOrderMsg(acc.GetAddress(), GenerateOrderID(0, acc.GetAddress()), Side.BUY, "BBB-000_BNB", 1e8, 1e8)
+
+	// the generic code before the upgrade
+	upgrade.Mgr.SetHeight(5)
+	res := handleNewOrder(ctx, keeper, msg)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeInvalidOrderParam), res.Code, res.Log)
+
+	upgrade.Mgr.SetHeight(10)
+	res = handleNewOrder(ctx, keeper, msg)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeUnknownTradingPair), res.Code, res.Log)
+	require.Empty(t, keeper.GetOpenOrders("BBB-000_BNB", acc.GetAddress()))
+	require.Equal(t, int64(100e8), am.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("BNB"))
+
+	msg = NewNewOrderMsg(acc.GetAddress(), GenerateOrderID(0, acc.GetAddress()), Side.BUY, "AAA-000_BNB", 1e8, 1e8)
+	res = handleNewOrder(ctx, keeper, msg)
+	require.True(t, res.IsOK(), res.Log)
+}
+
+func TestHandler_NewOrder_InsufficientFeePolicy(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	keeper.setParams(ctx, types.DexParams{InsufficientFeePolicy: types.InsufficientFeePolicyReject})
+	// enough for the principal of the order below but not its trade fee, 1e8 * 500 / 1e6 BNB
+	_, acc := testutils.NewAccount(ctx, am, 1e8)
+	ctx = ctx.WithBlockHeight(100).WithValue(baseapp.TxHashKey, "")
+
+	placeOrder := func(seq int64, side int8) sdk.Result {
+		acc := am.GetAccount(ctx, acc.GetAddress())
+		require.NoError(t, acc.SetSequence(seq))
+		am.SetAccount(ctx, acc)
+		msg := NewNewOrderMsg(acc.GetAddress(), GenerateOrderID(seq, acc.GetAddress()), side, "AAA-000_BNB", 1e8, 1e8)
+		return handleNewOrder(ctx, keeper, msg)
+	}
+
+	res := placeOrder(0, Side.BUY)
+	require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeInvalidOrderParam), res.Code, res.Log)
+	require.Contains(t, res.Log, "not enough for the trade fee 50000")
+	require.Equal(t, int64(1e8), am.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("BNB"))
+
+	// the orders receiving BNB pay the fees out of it
+	account := am.GetAccount(ctx, acc.GetAddress())
+	require.NoError(t, account.SetCoins(sdk.Coins{sdk.NewCoin("AAA-000", 1e8)}))
+	am.SetAccount(ctx, account)
+	res = placeOrder(0, Side.SELL)
+	require.True(t, res.IsOK(), res.Log)
+
+	// exactly enough for both
+	account = am.GetAccount(ctx, acc.GetAddress())
+	require.NoError(t, account.SetCoins(sdk.Coins{sdk.NewCoin("BNB", 1e8+50000)}))
+	am.SetAccount(ctx, account)
+	res = placeOrder(1, Side.BUY)
+	require.True(t, res.IsOK(), res.Log)
+
+	// the fee is charged from the received asset by default
+	keeper.setParams(ctx, types.DefaultDexParams())
+	account = am.GetAccount(ctx, acc.GetAddress())
+	require.NoError(t, account.SetCoins(sdk.Coins{sdk.NewCoin("BNB", 1e8)}))
+	am.SetAccount(ctx, account)
+	res = placeOrder(2, Side.BUY)
+	require.True(t, res.IsOK(), res.Log)
+}
+
+func TestHandler_NewOrder_TokenTradingDisabled(t *testing.T) {
+	ms, capKey, capKey2, capKey3 := testutils.SetupThreeMultiStoreForUnitTest()
+	cdc := MakeCodec()
+	cdc.RegisterConcrete(dextypes.TradingPair{}, "dex/TradingPair", nil)
+	am := auth.NewAccountKeeper(cdc, capKey, cmntypes.ProtoAppAccount)
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid"}, sdk.RunTxModeDeliver, log.NewNopLogger()).
+		WithAccountCache(getAccountCache(cdc, ms, capKey)).WithValue(baseapp.TxHashKey, "")
+	keeper := NewDexKeeper(capKey2, am, store.NewTradingPairMapper(cdc, common.PairStoreKey),
+		sdk.NewCodespacer().RegisterNext(dextypes.DefaultCodespace), 2, cdc, false)
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	keeper.CollectOrderInfoForPublish = true
+	tokenMapper := tokenStore.NewMapper(cdc, capKey3)
+	keeper.SetTokenMapper(tokenMapper)
+
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	for _, symbol := range []string{"AAA-000", "BBB-000"} {
+		token, err := cmntypes.NewToken(symbol[:3], symbol, 1e10, acc.GetAddress(), false)
+		require.NoError(t, err)
+		require.NoError(t, tokenMapper.NewToken(ctx, token))
+	}
+	account := am.GetAccount(ctx, acc.GetAddress())
+	require.NoError(t, account.SetCoins(sdk.Coins{sdk.NewCoin("AAA-000", 100e8), sdk.NewCoin("BBB-000", 100e8), sdk.NewCoin("BNB", 100e8)}))
+	am.SetAccount(ctx, account)
+	for _, pair := range []types.TradingPair{
+		types.NewTradingPair("AAA-000", "BNB", 1e8),
+		types.NewTradingPair("BBB-000", "AAA-000", 1e8),
+		types.NewTradingPair("BBB-000", "BNB", 1e8),
+	} {
+		require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+		keeper.AddEngine(pair)
+	}
+
+	seq := int64(0)
+	placeOrder := func(symbol string) (NewOrderMsg, sdk.Result) {
+		account := am.GetAccount(ctx, acc.GetAddress())
+		require.NoError(t, account.SetSequence(seq))
+		am.SetAccount(ctx, account)
+		msg := NewNewOrderMsg(acc.GetAddress(), GenerateOrderID(seq, acc.GetAddress()), Side.BUY, symbol, 1e8, 1e8)
+		res := handleNewOrder(ctx, keeper, msg)
+		if res.IsOK() {
+			seq++
+		}
+		return msg, res
+	}
+
+	msg, res := placeOrder("AAA-000_BNB")
+	require.True(t, res.IsOK(), res.Log)
+
+	require.NoError(t, keeper.SetTokenTrading(ctx, "AAA-000", true, 1))
+	require.True(t, tokenMapper.IsTradingDisabled(ctx, "AAA-000"))
+	require.False(t, tokenMapper.IsTradingDisabled(ctx, "BBB-000"))
+	require.Equal(t, []TokenTradingEvent{{"AAA-000_BNB", "AAA-000", true}, {"BBB-000_AAA-000", "AAA-000", true}},
+		keeper.GetTokenTradingEvents())
+	require.Empty(t, keeper.GetSessionEvents())
+
+	// all the pairs of the token, as either the base or the quote asset, take no new orders
+	for _, symbol := range []string{"AAA-000_BNB", "BBB-000_AAA-000"} {
+		_, res = placeOrder(symbol)
+		require.Equal(t, sdk.ToABCICode(types.DefaultCodespace, types.CodeTokenTradingDisabled), res.Code, res.Log)
+		require.Contains(t, res.Log, "AAA-000")
+	}
+	_, res = placeOrder("BBB-000_BNB")
+	require.True(t, res.IsOK(), res.Log)
+
+	// the open orders can still be cancelled
+	res = handleCancelOrder(ctx, keeper, NewCancelOrderMsg(acc.GetAddress(), "AAA-000_BNB", msg.Id))
+	require.True(t, res.IsOK(), res.Log)
+	require.Len(t, keeper.GetOpenOrders("AAA-000_BNB", acc.GetAddress()), 0)
+
+	// an older proposal can't be replayed to revert a newer one
+	require.NoError(t, keeper.SetTokenTrading(ctx, "AAA-000", false, 2))
+	require.Error(t, keeper.SetTokenTrading(ctx, "AAA-000", true, 1))
+	require.False(t, tokenMapper.IsTradingDisabled(ctx, "AAA-000"))
+	for _, symbol := range []string{"AAA-000_BNB", "BBB-000_AAA-000"} {
+		_, res = placeOrder(symbol)
+		require.True(t, res.IsOK(), res.Log)
+	}
+	require.Error(t, keeper.SetTokenTrading(ctx, "CCC-000", true, 3))
+}
@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	dbm "github.com/tendermint/tendermint/libs/db"
@@ -20,6 +21,7 @@ import (
 	paramhub "github.com/cosmos/cosmos-sdk/x/paramHub/keeper"
 	paramTypes "github.com/cosmos/cosmos-sdk/x/paramHub/types"
 
+	bncfees "github.com/bnb-chain/node/common/fees"
 	bnclog "github.com/bnb-chain/node/common/log"
 	"github.com/bnb-chain/node/common/types"
 	"github.com/bnb-chain/node/common/upgrade"
@@ -28,6 +30,7 @@ import (
 	"github.com/bnb-chain/node/plugins/dex/store"
 	dexTypes "github.com/bnb-chain/node/plugins/dex/types"
 	dexUtils "github.com/bnb-chain/node/plugins/dex/utils"
+	tokenStore "github.com/bnb-chain/node/plugins/tokens/store"
 	"github.com/bnb-chain/node/wire"
 )
 
@@ -35,6 +38,9 @@ const (
 	BEP2TypeValue        = 1
 	MiniTypeValue        = 2
 	preferencePriceLevel = 500
+
+	effectiveDays   = 3  // GTE orders are expired after this number of days
+	forceExpireDays = 30 // orders on the preferred price levels are expired after this number of days (BEP67)
 )
 
 type SymbolPairType int8
@@ -64,6 +70,49 @@ type DexKeeper struct {
 	poolSize                   uint // number of concurrent channels, counted in the pow of 2
 	cdc                        *wire.Codec
 	OrderKeepers               []DexOrderKeeper
+	blockOrders                blockOrderCounter // orders placed by each account in the current block
+	orderFills                 *orderFillsCache  // fills of the recently traded orders
+	traderVolumes              *traderVolumes    // traded volumes of the accounts in the recent days
+	symbolActivities           *symbolActivities // trades and traded volumes of the symbols in the recent days
+	twaps                      *priceTWAPs       // last trade prices of the symbols in the recent window
+	bookUpdates                *bookUpdates      // order book updates of the symbols in the recent window
+	imbalanceTrends            *imbalanceTrends  // order book imbalances of the symbols in the recent blocks
+	tradeRates                 *tradeRates       // numbers of trades of the symbols in the recent blocks
+	lastMatches                *lastMatches      // heights of the last blocks in which the symbols produced trades
+	pairMatchIntervals         map[string]int64  // symbol -> match interval of the pair, see TradingPair.MatchInterval
+	pairPublicationDepths      map[string]int    // symbol -> publication depth of the pair, only the ones overriding the global depth
+	pairGTCTTLDays             map[string]int    // symbol -> GTC TTL days of the pair, only the ones overriding the global TTL
+	roundDeferredSymbols       []string          // symbols whose round orders are kept for a later matching
+	roundMakerRebate           *makerRebate      // maker rebate params of the current matching, nil if disabled
+	matchBatchSize             int64             // max number of round orders matched in a block, 0 means unlimited
+	matchBacklog               atomic.Value      // store.MatchBacklog of the last matching, for query usage
+	openInterests              atomic.Value      // symbol -> store.OpenInterest as of the last block, for query usage
+	orderCounts                atomic.Value      // store.OrderCount as of the last block, for query usage
+	orderBookHistoryRetention  int               // number of the most recent breathe block snapshots being served
+	maxOrderBookDepth          int               // max number of price levels served by the dex/orderbook query
+
+	accountTrades *accountTradesCache // recent trades of the accounts
+	recentCancels *recentCancelsCache // recently cancelled orders of the accounts
+
+	pairSessions   map[string]dexTypes.TradingSession // symbol -> trading session of the pair, only the ones not trading all day
+	pairsInSession map[string]bool                    // symbol -> whether the pair was in its session in the last block
+
+	logUnknownPairOrders bool // whether to log the orders of unknown trading pairs
+
+	tokenMapper tokenStore.Mapper // for the tokens whose trading is disabled, nil if not set
+
+	totalOrders       int64                    // number of the open orders of all the pairs, updated atomically, see dropOrder
+	pairOpenInterests map[string]*openInterest // symbol -> resting quantities of the pair, see addOpenInterest
+	pairsVersion      int64                    // bumped once a pair is listed or delisted, updated atomically, see PairsVersion
+
+	orderRejections    orderRecorder          // orders rejected in the current block, for publication usage
+	orderAcks          orderRecorder          // orders accepted in the current block, for publication usage
+	pairSizesUpdates   []dexTypes.TradingPair // pairs whose tick/lot sizes are changed in the current block, for publication usage
+	sessionEvents      []SessionEvent         // trading sessions opened or closed in the current block, for publication usage
+	tokenTradingEvents []TokenTradingEvent    // pairs whose token is disabled or enabled from trading in the current block, for publication usage
+
+	dustFeeConversions []DustFeeConversion // dust fee conversions placed in the current block
+	dustFeeAccount     sdk.AccAddress      // fee account of the dust fee conversions, whose trades are not charged
 }
 
 func NewDexKeeper(key sdk.StoreKey, am auth.AccountKeeper, tradingPairMapper store.TradingPairMapper, codespace sdk.CodespaceType, concurrency uint, cdc *wire.Codec, collectOrderInfoForPublish bool) *DexKeeper {
@@ -89,10 +138,30 @@ func NewDexKeeper(key sdk.StoreKey, am auth.AccountKeeper, tradingPairMapper sto
 		cdc:                        cdc,
 		logger:                     logger,
 		OrderKeepers:               []DexOrderKeeper{bep2OrderKeeper, miniOrderKeeper},
+		orderFills:                 newOrderFillsCache(DefaultOrderFillsCacheSize),
+		accountTrades:              newAccountTradesCache(DefaultAccountTradesCacheSize),
+		recentCancels:              newRecentCancelsCache(DefaultRecentCancelsCacheSize),
+		traderVolumes:              newTraderVolumes(),
+		symbolActivities:           newSymbolActivities(),
+		twaps:                      newPriceTWAPs(),
+		bookUpdates:                newBookUpdates(),
+		imbalanceTrends:            newImbalanceTrends(),
+		tradeRates:                 newTradeRates(),
+		lastMatches:                newLastMatches(),
+		orderAcks:                  orderRecorder{keepSucceeded: true},
+		pairMatchIntervals:         make(map[string]int64),
+		pairPublicationDepths:      make(map[string]int),
+		pairGTCTTLDays:             make(map[string]int),
+		pairOpenInterests:          make(map[string]*openInterest),
+		pairSessions:               make(map[string]dexTypes.TradingSession),
+		pairsInSession:             make(map[string]bool),
+		orderBookHistoryRetention:  DefaultOrderBookHistoryRetention,
+		maxOrderBookDepth:          DefaultMaxOrderBookDepth,
 	}
 }
 
 func (kp *DexKeeper) Init(ctx sdk.Context, blockInterval, daysBack int, blockStore *tmstore.BlockStore, stateDB dbm.DB, lastHeight int64, txDecoder sdk.TxDecoder) {
+	kp.loadMatchBatchSize(ctx)
 	kp.initOrderBook(ctx, blockInterval, daysBack, blockStore, stateDB, lastHeight, txDecoder)
 	kp.InitRecentPrices(ctx)
 }
@@ -105,6 +174,12 @@ func (kp *DexKeeper) SetBUSDSymbol(symbol string) {
 	BUSDSymbol = symbol
 }
 
+// SetLogUnknownPairOrders sets whether to log the orders of unknown trading pairs, for the operators to spot the
+// misconfigured clients.
+func (kp *DexKeeper) SetLogUnknownPairOrders(enabled bool) {
+	kp.logUnknownPairOrders = enabled
+}
+
 func (kp *DexKeeper) EnablePublish() {
 	kp.CollectOrderInfoForPublish = true
 	for i := range kp.OrderKeepers {
@@ -156,6 +231,10 @@ func (kp *DexKeeper) UpdateTickSizeAndLotSize(ctx sdk.Context) {
 	tradingPairs := kp.PairMapper.ListAllTradingPairs(ctx)
 	lotSizeCache := make(map[string]int64) // baseAsset -> lotSize
 	for _, pair := range tradingPairs {
+		if pair.SizesFixed {
+			// the sizes are set by governance
+			continue
+		}
 		if prices, ok := kp.recentPrices[pair.GetSymbol()]; ok && prices.Count() >= minimalNumPrices {
 			priceWMA := dexUtils.CalcPriceWMA(prices)
 			tickSize, lotSize := kp.determineTickAndLotSize(pair, priceWMA, lotSizeCache)
@@ -167,6 +246,7 @@ func (kp *DexKeeper) UpdateTickSizeAndLotSize(ctx sdk.Context) {
 				pair.TickSize = utils.Fixed8(tickSize)
 				pair.LotSize = utils.Fixed8(lotSize)
 				kp.PairMapper.AddTradingPair(ctx, pair)
+				kp.recordPairSizesUpdate(pair)
 			}
 			kp.UpdateLotSize(pair.GetSymbol(), lotSize)
 		} else {
@@ -262,11 +342,17 @@ func (kp *DexKeeper) AddEngine(pair dexTypes.TradingPair) *me.MatchEng {
 	symbol := strings.ToUpper(pair.GetSymbol())
 	eng := CreateMatchEng(symbol, pair.ListPrice.ToInt64(), pair.LotSize.ToInt64())
 	kp.engines[symbol] = eng
+	kp.pairOpenInterests[symbol] = &openInterest{}
+	atomic.AddInt64(&kp.pairsVersion, 1)
 	pairType := PairType.BEP2
 	if dexUtils.IsMiniTokenTradingPair(symbol) {
 		pairType = PairType.MINI
 	}
 	kp.pairsType[symbol] = pairType
+	kp.pairMatchIntervals[symbol] = pair.MatchInterval
+	kp.setPairPublicationDepth(symbol, pair.PublicationDepth)
+	kp.setPairGTCTTLDays(symbol, pair.GTCTTLDays)
+	kp.setPairSession(symbol, pair.GetSession())
 	for i := range kp.OrderKeepers {
 		if kp.OrderKeepers[i].supportPairType(pairType) {
 			kp.OrderKeepers[i].initOrders(symbol)
@@ -277,6 +363,16 @@ func (kp *DexKeeper) AddEngine(pair dexTypes.TradingPair) *me.MatchEng {
 }
 
 func (kp *DexKeeper) AddOrder(info OrderInfo, isRecovery bool) (err error) {
+	if info.Quantity <= 0 {
+		return fmt.Errorf("quantity of order %s should be positive, got %d", info.Id, info.Quantity)
+	}
+	if info.Price <= 0 {
+		return fmt.Errorf("price of order %s should be positive, got %d", info.Id, info.Price)
+	}
+	if dexUtils.IsExceedMaxNotional(info.Price, info.Quantity) {
+		// the quote asset locked and transferred for the order are calculated from its notional
+		return dexTypes.ErrNotionalOverflow(info.Price, info.Quantity)
+	}
 	//try update order book first
 	symbol := strings.ToUpper(info.Symbol)
 	eng, ok := kp.engines[symbol]
@@ -289,8 +385,14 @@ func (kp *DexKeeper) AddOrder(info OrderInfo, isRecovery bool) (err error) {
 	if err != nil {
 		return err
 	}
+	kp.addOpenInterest(symbol, info.Side, info.Quantity)
 
-	kp.mustGetOrderKeeper(symbol).addOrder(symbol, info, isRecovery)
+	orderKeeper := kp.mustGetOrderKeeper(symbol)
+	if _, exists := orderKeeper.orderExists(symbol, info.Id); !exists {
+		kp.addTotalOrders(1)
+	}
+	orderKeeper.addOrder(symbol, info, isRecovery)
+	kp.bookUpdates.add(symbol, 1, 0, 0)
 	kp.logger.Debug("Added orders", "symbol", symbol, "id", info.Id)
 	return nil
 }
@@ -302,10 +404,14 @@ func orderNotFound(symbol, id string) error {
 func (kp *DexKeeper) RemoveOrder(id string, symbol string, postCancelHandler func(ord me.OrderPart)) error {
 	symbol = strings.ToUpper(symbol)
 	if dexOrderKeeper, err := kp.getOrderKeeper(symbol); err == nil {
+		info, _ := dexOrderKeeper.orderExists(symbol, id)
 		ord, err := dexOrderKeeper.removeOrder(kp, id, symbol)
 		if err != nil {
 			return err
 		}
+		kp.addTotalOrders(-1)
+		kp.addOpenInterest(symbol, info.Side, -ord.LeavesQty())
+		kp.bookUpdates.add(symbol, 0, 1, 0)
 		if postCancelHandler != nil {
 			postCancelHandler(ord)
 		}
@@ -349,13 +455,21 @@ func channelHash(accAddress sdk.AccAddress, bucketNumber int) int {
 
 func (kp *DexKeeper) SubscribeParamChange(hub *paramhub.Keeper) {
 	hub.SubscribeParamChange(
-		func(_ sdk.Context, iChange interface{}) {
+		func(ctx sdk.Context, iChange interface{}) {
 			switch change := iChange.(type) {
 			case []paramTypes.FeeParam:
 				feeConfig := ParamToFeeConfig(change)
 				if feeConfig != nil {
 					kp.FeeManager.UpdateConfig(*feeConfig)
 				}
+				for _, p := range change {
+					switch p := p.(type) {
+					case *dexTypes.DexParams:
+						kp.updateParams(ctx, *p)
+					case *dexTypes.PairParamsChange:
+						kp.updatePairParams(ctx, *p)
+					}
+				}
 			default:
 				kp.logger.Debug("Receive param changes that not interested.")
 			}
@@ -410,6 +524,128 @@ func (kp *DexKeeper) GetOrderBookLevels(pair string, maxLevels int) (orderbook [
 	return orderbook, pendingMatch
 }
 
+// GetCappedOrderBookLevels is GetOrderBookLevels with maxLevels capped by the max order book depth, truncated
+// is true if fewer levels than requested are returned because of the cap.
+func (kp *DexKeeper) GetCappedOrderBookLevels(pair string, maxLevels int) (orderbook []store.OrderBookLevel, pendingMatch, truncated bool) {
+	if maxLevels > kp.maxOrderBookDepth {
+		maxLevels, truncated = kp.maxOrderBookDepth, true
+	}
+	orderbook, pendingMatch = kp.GetOrderBookLevels(pair, maxLevels)
+	return orderbook, pendingMatch, truncated
+}
+
+// GetAggregatedOrderBookLevels is GetCappedOrderBookLevels with the price levels aggregated into the price buckets
+// of bucketSize. The buy levels are aggregated into the floor of their buckets and the sell levels into the ceiling,
+// so that the aggregated book never crosses. The raw price levels are returned if bucketSize is 0.
+func (kp *DexKeeper) GetAggregatedOrderBookLevels(pair string, maxLevels int, bucketSize int64) (orderbook []store.OrderBookLevel, pendingMatch, truncated bool) {
+	if bucketSize <= 0 {
+		return kp.GetCappedOrderBookLevels(pair, maxLevels)
+	}
+	if maxLevels > kp.maxOrderBookDepth {
+		maxLevels, truncated = kp.maxOrderBookDepth, true
+	}
+	orderbook = make([]store.OrderBookLevel, maxLevels)
+	eng, ok := kp.engines[pair]
+	if !ok {
+		return orderbook, false, truncated
+	}
+	// the raw levels are walked through the end of the book, as any number of them may fall into a bucket
+	i, j := -1, -1
+	eng.Book.ShowDepth(math.MaxInt32, func(p *me.PriceLevel, levelIndex int) {
+		price := utils.Fixed8(p.Price / bucketSize * bucketSize)
+		if i < 0 || orderbook[i].BuyPrice != price {
+			if i+1 >= maxLevels {
+				return
+			}
+			i++
+			orderbook[i].BuyPrice = price
+		}
+		orderbook[i].BuyQty += utils.Fixed8(p.TotalLeavesQty())
+	}, func(p *me.PriceLevel, levelIndex int) {
+		price := utils.Fixed8((p.Price + bucketSize - 1) / bucketSize * bucketSize)
+		if j < 0 || orderbook[j].SellPrice != price {
+			if j+1 >= maxLevels {
+				return
+			}
+			j++
+			orderbook[j].SellPrice = price
+		}
+		orderbook[j].SellQty += utils.Fixed8(p.TotalLeavesQty())
+	})
+	roundOrders := kp.mustGetOrderKeeper(pair).getRoundOrdersForPair(pair)
+	return orderbook, len(roundOrders) > 0, truncated
+}
+
+// LimitOrderBookLevelsByNotional keeps the levels of each side of the order book from the best price until their
+// cumulative quote notional reaches the depth, the level reaching it included, and clears the levels beyond. It
+// returns the cumulative notional of the buy and sell levels kept, which is short of the depth if the levels run out.
+func LimitOrderBookLevelsByNotional(levels []store.OrderBookLevel, depth int64) (buyNotional, sellNotional int64) {
+	accumulate := func(cumulative int64, price, qty utils.Fixed8) int64 {
+		notional, ok := dexUtils.CalNotionalInt64Checked(price.ToInt64(), qty.ToInt64())
+		if !ok || cumulative > math.MaxInt64-notional {
+			return math.MaxInt64
+		}
+		return cumulative + notional
+	}
+	for i := range levels {
+		if buyNotional >= depth {
+			levels[i].BuyPrice, levels[i].BuyQty = 0, 0
+		} else if levels[i].BuyQty > 0 {
+			buyNotional = accumulate(buyNotional, levels[i].BuyPrice, levels[i].BuyQty)
+		}
+		if sellNotional >= depth {
+			levels[i].SellPrice, levels[i].SellQty = 0, 0
+		} else if levels[i].SellQty > 0 {
+			sellNotional = accumulate(sellNotional, levels[i].SellPrice, levels[i].SellQty)
+		}
+	}
+	return buyNotional, sellNotional
+}
+
+// GetSpread returns the spread of the best bid and ask of the pair, the spread is store.NoSpread if either
+// side of the order book is empty.
+func (kp *DexKeeper) GetSpread(pair string) store.Spread {
+	levels, _ := kp.GetOrderBookLevels(pair, 1)
+	spread := store.Spread{
+		Symbol:    pair,
+		BestBid:   levels[0].BuyPrice,
+		BestAsk:   levels[0].SellPrice,
+		SpreadBps: store.NoSpread,
+	}
+	bid, ask := spread.BestBid.ToInt64(), spread.BestAsk.ToInt64()
+	if bid == 0 || ask == 0 {
+		return spread
+	}
+	if ask <= bid {
+		spread.SpreadBps = 0
+		return spread
+	}
+	// (ask - bid) / ((ask + bid) / 2) * 10000
+	var bps big.Int
+	bps.Mul(big.NewInt(ask-bid), big.NewInt(20000))
+	bps.Quo(&bps, new(big.Int).Add(big.NewInt(ask), big.NewInt(bid)))
+	spread.SpreadBps = bps.Int64()
+	return spread
+}
+
+// GetQuote returns the best bid and ask of the pair along with the last trade price, it's marked as unknown if the
+// pair has no match engine.
+func (kp *DexKeeper) GetQuote(pair string) store.Quote {
+	eng, ok := kp.engines[pair]
+	if !ok {
+		return store.Quote{Symbol: pair, Unknown: true}
+	}
+	levels, _ := kp.GetOrderBookLevels(pair, 1)
+	return store.Quote{
+		Symbol:     pair,
+		BestBid:    levels[0].BuyPrice,
+		BestBidQty: levels[0].BuyQty,
+		BestAsk:    levels[0].SellPrice,
+		BestAskQty: levels[0].SellQty,
+		LastPrice:  utils.Fixed8(eng.LastTradePrice),
+	}
+}
+
 func (kp *DexKeeper) GetOpenOrders(pair string, addr sdk.AccAddress) []store.OpenOrder {
 	if dexOrderKeeper, err := kp.getOrderKeeper(pair); err == nil {
 		return dexOrderKeeper.getOpenOrders(pair, addr)
@@ -417,7 +653,27 @@ func (kp *DexKeeper) GetOpenOrders(pair string, addr sdk.AccAddress) []store.Ope
 	return make([]store.OpenOrder, 0)
 }
 
+// GetOrderBooks returns the order books of all the pairs for publication usage, each of maxLevels price levels per
+// side unless the pair has its own publication depth.
 func (kp *DexKeeper) GetOrderBooks(maxLevels int) ChangedPriceLevelsMap {
+	return kp.getOrderBooks(func(pair string) int {
+		return kp.GetPublicationDepth(pair, maxLevels)
+	})
+}
+
+// GetOrderBookSnapshots returns the order books of all the pairs for the snapshot publication, each of `depth` price
+// levels per side, but no fewer than the levels published by GetOrderBooks, so that the snapshots cover all the levels
+// the order book changes are published for.
+func (kp *DexKeeper) GetOrderBookSnapshots(maxLevels, depth int) ChangedPriceLevelsMap {
+	return kp.getOrderBooks(func(pair string) int {
+		if levels := kp.GetPublicationDepth(pair, maxLevels); levels > depth {
+			return levels
+		}
+		return depth
+	})
+}
+
+func (kp *DexKeeper) getOrderBooks(levelsOf func(pair string) int) ChangedPriceLevelsMap {
 	var res = make(ChangedPriceLevelsMap)
 	for pair, eng := range kp.engines {
 		buys := make(map[int64]int64)
@@ -425,7 +681,7 @@ func (kp *DexKeeper) GetOrderBooks(maxLevels int) ChangedPriceLevelsMap {
 		res[pair] = ChangedPriceLevelsPerSymbol{buys, sells}
 
 		// TODO: check considered bucket splitting?
-		eng.Book.ShowDepth(maxLevels, func(p *me.PriceLevel, levelIndex int) {
+		eng.Book.ShowDepth(levelsOf(pair), func(p *me.PriceLevel, levelIndex int) {
 			buys[p.Price] = p.TotalLeavesQty()
 		}, func(p *me.PriceLevel, levelIndex int) {
 			sells[p.Price] = p.TotalLeavesQty()
@@ -463,6 +719,7 @@ func (kp *DexKeeper) GetLastTradesForPair(pair string) ([]me.Trade, int64) {
 func (kp *DexKeeper) ClearOrderBook(pair string) {
 	if eng, ok := kp.engines[pair]; ok {
 		eng.Book.Clear()
+		kp.pairOpenInterests[pair] = &openInterest{}
 	}
 }
 
@@ -502,11 +759,13 @@ func (kp *DexKeeper) doTransfer(ctx sdk.Context, tran *Transfer) sdk.Error {
 }
 
 func (kp *DexKeeper) ClearAfterMatch() {
+	deferred := kp.getDeferredRoundOrders()
 	for _, orderKeeper := range kp.OrderKeepers {
 		if orderKeeper.supportUpgradeVersion() {
 			orderKeeper.clearAfterMatch()
 		}
 	}
+	kp.restoreDeferredRoundOrders(deferred)
 }
 
 func (kp *DexKeeper) StoreTradePrices(ctx sdk.Context) {
@@ -525,10 +784,13 @@ func (kp *DexKeeper) StoreTradePrices(ctx sdk.Context) {
 	}
 }
 
-func (kp *DexKeeper) allocate(ctx sdk.Context, tranCh <-chan Transfer, postAllocateHandler func(tran Transfer)) (
-	sdk.Fee, map[string]*sdk.Fee) {
+// allocate also returns the trade transfers of the makers eligible for the maker rebate. The fee exempt accounts are
+// keyed by the string of the addr bytes.
+func (kp *DexKeeper) allocate(ctx sdk.Context, tranCh <-chan Transfer, postAllocateHandler func(tran Transfer),
+	ordering string, exemptAccounts map[string]struct{}) (sdk.Fee, map[string]*sdk.Fee, []*Transfer) {
 	if !sdk.IsUpgrade(upgrade.BEP19) {
-		return kp.allocateBeforeGalileo(ctx, tranCh, postAllocateHandler)
+		fee, feesPerAcc := kp.allocateBeforeGalileo(ctx, tranCh, postAllocateHandler)
+		return fee, feesPerAcc, nil
 	}
 
 	// use string of the addr as the key since map makes a fast path for string key.
@@ -539,12 +801,16 @@ func (kp *DexKeeper) allocate(ctx sdk.Context, tranCh <-chan Transfer, postAlloc
 	// we need to distinguish different expire event, IOCExpire or Expire. only one of the two will exist.
 	var expireEventType transferEventType
 	var totalFee sdk.Fee
+	var rebateMakers []*Transfer
 	for tran := range tranCh {
 		kp.doTransfer(ctx, &tran)
 		if !tran.FeeFree() {
 			addrStr := string(tran.accAddress.Bytes())
 			// need a copy of tran as it is reused
 			tranCp := tran
+			if tran.makerRebate {
+				rebateMakers = append(rebateMakers, &tranCp)
+			}
 			if tran.IsExpiredWithFee() {
 				expireEventType = tran.eventType
 				if _, ok := expireTransfers[addrStr]; !ok {
@@ -559,7 +825,7 @@ func (kp *DexKeeper) allocate(ctx sdk.Context, tranCh <-chan Transfer, postAlloc
 					tradeTransfers[addrStr] = append(tradeTransfers[addrStr], &tranCp)
 				}
 			}
-		} else if tran.IsExpire() {
+		} else if tran.IsExpire() || tran.IsDelisted() || tran.IsTokenDisabled() {
 			if postAllocateHandler != nil {
 				postAllocateHandler(tran)
 			}
@@ -567,10 +833,18 @@ func (kp *DexKeeper) allocate(ctx sdk.Context, tranCh <-chan Transfer, postAlloc
 	}
 
 	feesPerAcc := make(map[string]*sdk.Fee)
+	isExempt := func(addrStr string) bool {
+		_, ok := exemptAccounts[addrStr]
+		return ok
+	}
 	for addrStr, trans := range tradeTransfers {
+		if isExempt(addrStr) {
+			waiveTradeFees(trans)
+			continue
+		}
 		addr := sdk.AccAddress(addrStr)
 		acc := kp.am.GetAccount(ctx, addr)
-		fees := kp.FeeManager.CalcTradesFee(acc.GetCoins(), trans, kp.engines)
+		fees := kp.FeeManager.CalcTradesFee(acc.GetCoins(), trans, kp.engines, ordering)
 		if !fees.IsEmpty() {
 			feesPerAcc[addrStr] = &fees
 			acc.SetCoins(acc.GetCoins().Minus(fees.Tokens))
@@ -580,6 +854,14 @@ func (kp *DexKeeper) allocate(ctx sdk.Context, tranCh <-chan Transfer, postAlloc
 	}
 
 	for addrStr, trans := range expireTransfers {
+		if isExempt(addrStr) {
+			for _, tran := range trans {
+				if postAllocateHandler != nil {
+					postAllocateHandler(*tran)
+				}
+			}
+			continue
+		}
 		addr := sdk.AccAddress(addrStr)
 		acc := kp.am.GetAccount(ctx, addr)
 
@@ -595,7 +877,7 @@ func (kp *DexKeeper) allocate(ctx sdk.Context, tranCh <-chan Transfer, postAlloc
 			totalFee.AddFee(fees)
 		}
 	}
-	return totalFee, feesPerAcc
+	return totalFee, feesPerAcc, rebateMakers
 }
 
 // DEPRECATED
@@ -689,11 +971,20 @@ func (kp *DexKeeper) allocateAndCalcFee(
 	wg.Add(concurrency)
 	feesPerCh := make([]sdk.Fee, concurrency)
 	feesPerAcc := make([]map[string]*sdk.Fee, concurrency)
+	rebateMakersPerCh := make([][]*Transfer, concurrency)
+	// the store is not read by the allocations running concurrently
+	ordering := kp.GetParams(ctx).AllocationOrdering
+	exemptAccounts := kp.getFeeExemptAccounts(ctx)
+	// the fees of the dust fee conversions would only go back to the fees being converted
+	if kp.dustFeeAccount != nil {
+		exemptAccounts[string(kp.dustFeeAccount.Bytes())] = struct{}{}
+	}
 	allocatePerCh := func(index int, tranCh <-chan Transfer) {
 		defer wg.Done()
-		fee, feeByAcc := kp.allocate(ctx, tranCh, postAlloTransHandler)
+		fee, feeByAcc, rebateMakers := kp.allocate(ctx, tranCh, postAlloTransHandler, ordering, exemptAccounts)
 		feesPerCh[index].AddFee(fee)
 		feesPerAcc[index] = feeByAcc
+		rebateMakersPerCh[index] = rebateMakers
 	}
 
 	for i, tradeTranCh := range tradeOuts {
@@ -704,9 +995,17 @@ func (kp *DexKeeper) allocateAndCalcFee(
 	for i := 0; i < concurrency; i++ {
 		totalFee.AddFee(feesPerCh[i])
 	}
-	if kp.CollectOrderInfoForPublish {
-		for _, m := range feesPerAcc {
-			for k, v := range m {
+	// the rebates are paid once the fees of both sides of the trades are charged
+	if kp.roundMakerRebate != nil {
+		for i := 0; i < concurrency; i++ {
+			rebates := kp.payMakerRebates(ctx, kp.roundMakerRebate.rate, rebateMakersPerCh[i], feesPerAcc[i])
+			totalFee.Tokens = totalFee.Tokens.Minus(rebates)
+		}
+	}
+	for _, m := range feesPerAcc {
+		for k, v := range m {
+			bncfees.Tracker.AddAndCommit(sdk.AccAddress(k), v.Tokens)
+			if kp.CollectOrderInfoForPublish {
 				kp.updateRoundOrderFee(k, *v)
 			}
 		}
@@ -731,6 +1030,9 @@ func (kp *DexKeeper) expireOrders(ctx sdk.Context, blockTime time.Time) []chan T
 	if err != nil {
 		return nil
 	}
+	// the pairs with their own TTLs expire the orders created before the breathe block of their TTL days ago,
+	// which are looked up before the concurrent expiry as the store is not safe for concurrent use.
+	pairExpireHeights := kp.getPairExpireHeights(ctx, blockTime)
 
 	channelSize := size >> kp.poolSize
 	concurrency := 1 << kp.poolSize
@@ -744,14 +1046,16 @@ func (kp *DexKeeper) expireOrders(ctx sdk.Context, blockTime time.Time) []chan T
 		transferChs[i] = make(chan Transfer, channelSize*2)
 	}
 
-	expire := func(orders map[string]*OrderInfo, engine *me.MatchEng, side int8) {
+	expire := func(symbol string, orders map[string]*OrderInfo, engine *me.MatchEng, expireHeight int64, side int8) {
 		removeCallback := func(ord me.OrderPart) {
 			// gen transfer
 			if ordMsg, ok := orders[ord.Id]; ok && ordMsg != nil {
 				h := channelHash(ordMsg.Sender, concurrency)
 				transferChs[h] <- TransferFromExpired(ord, *ordMsg)
+				kp.recentCancels.add(ordMsg, Expired, ctx.BlockHeight(), blockTime.UnixNano())
 				// delete from allOrders
-				delete(orders, ord.Id)
+				kp.dropOrder(orders, ord.Id)
+				kp.addOpenInterest(symbol, side, -ord.LeavesQty())
 			} else {
 				kp.logger.Error("failed to locate order to remove in order book", "oid", ord.Id)
 			}
@@ -774,8 +1078,12 @@ func (kp *DexKeeper) expireOrders(ctx sdk.Context, blockTime time.Time) []chan T
 			for symbol := range symbolCh {
 				engine := kp.engines[symbol]
 				orders := allOrders[symbol]
-				expire(orders, engine, me.BUYSIDE)
-				expire(orders, engine, me.SELLSIDE)
+				height := expireHeight
+				if h, ok := pairExpireHeights[symbol]; ok {
+					height = h
+				}
+				expire(symbol, orders, engine, height, me.BUYSIDE)
+				expire(symbol, orders, engine, height, me.SELLSIDE)
 			}
 		}, func() {
 			for _, transferCh := range transferChs {
@@ -787,7 +1095,6 @@ func (kp *DexKeeper) expireOrders(ctx sdk.Context, blockTime time.Time) []chan T
 }
 
 func (kp *DexKeeper) getExpireHeight(ctx sdk.Context, blockTime time.Time) (expireHeight, forceExpireHeight int64, noBreatheBlock error) {
-	const effectiveDays = 3
 	expireHeight, noBreatheBlock = kp.GetBreatheBlockHeight(ctx, blockTime, effectiveDays)
 	if noBreatheBlock != nil {
 		// breathe block not found, that should only happens in in the first three days, just log it and ignore.
@@ -796,7 +1103,6 @@ func (kp *DexKeeper) getExpireHeight(ctx sdk.Context, blockTime time.Time) (expi
 	}
 
 	if sdk.IsUpgrade(upgrade.BEP67) {
-		const forceExpireDays = 30
 		var err error
 		forceExpireHeight, err = kp.GetBreatheBlockHeight(ctx, blockTime, forceExpireDays)
 		if err != nil {
@@ -836,12 +1142,20 @@ func (kp *DexKeeper) MarkBreatheBlock(ctx sdk.Context, height int64, blockTime t
 }
 
 func (kp *DexKeeper) GetBreatheBlockHeight(ctx sdk.Context, timeNow time.Time, daysBack int) (int64, error) {
-	store := ctx.KVStore(kp.storeKey)
 	t := timeNow.AddDate(0, 0, -daysBack).Unix()
 	day := t / utils.SecondsPerDay
+	height, ok := kp.getBreatheBlockHeightOfDay(ctx, day)
+	if !ok {
+		return 0, fmt.Errorf("breathe block not found for day %v", day)
+	}
+	return height, nil
+}
+
+func (kp *DexKeeper) getBreatheBlockHeightOfDay(ctx sdk.Context, day int64) (int64, bool) {
+	store := ctx.KVStore(kp.storeKey)
 	bz := store.Get(utils.Int642Bytes(day))
 	if bz == nil {
-		return 0, fmt.Errorf("breathe block not found for day %v", day)
+		return 0, false
 	}
 
 	var height int64
@@ -849,7 +1163,7 @@ func (kp *DexKeeper) GetBreatheBlockHeight(ctx sdk.Context, timeNow time.Time, d
 	if err != nil {
 		panic(err)
 	}
-	return height, nil
+	return height, true
 }
 
 func (kp *DexKeeper) GetLastBreatheBlockHeight(ctx sdk.Context, latestBlockHeight int64, timeNow time.Time, blockInterval, daysBack int) int64 {
@@ -938,15 +1252,31 @@ func (kp *DexKeeper) DelistTradingPair(ctx sdk.Context, symbol string, postAlloc
 		return
 	}
 
-	transferChs := kp.expireAllOrders(ctx, symbol)
+	toTransfer := TransferFromExpired
+	if kp.GetParams(ctx).DelistFeeFree {
+		toTransfer = TransferFromDelisted
+	}
+	transferChs := kp.expireAllOrders(ctx, symbol, toTransfer, Delisted)
 	if transferChs != nil {
 		totalFee := kp.allocateAndCalcFee(ctx, transferChs, postAllocTransHandler)
 		fees.Pool.AddAndCommitFee(fmt.Sprintf("DELIST_%s", symbol), totalFee)
 	}
 
 	delete(kp.engines, symbol)
+	delete(kp.pairOpenInterests, symbol)
+	atomic.AddInt64(&kp.pairsVersion, 1)
+	delete(kp.pairMatchIntervals, symbol)
+	delete(kp.pairPublicationDepths, symbol)
+	delete(kp.pairGTCTTLDays, symbol)
+	delete(kp.pairSessions, symbol)
+	delete(kp.pairsInSession, symbol)
+	kp.twaps.delete(symbol)
+	kp.imbalanceTrends.delete(symbol)
+	kp.lastMatches.delete(symbol)
 	kp.deleteRecentPrices(ctx, symbol)
-	kp.mustGetOrderKeeper(symbol).deleteOrdersForPair(symbol)
+	orderKeeper := kp.mustGetOrderKeeper(symbol)
+	kp.addTotalOrders(-int64(len(orderKeeper.getAllOrdersForPair(symbol))))
+	orderKeeper.deleteOrdersForPair(symbol)
 
 	baseAsset, quoteAsset := dexUtils.TradingPair2AssetsSafe(symbol)
 	err := kp.PairMapper.DeleteTradingPair(ctx, baseAsset, quoteAsset)
@@ -960,7 +1290,8 @@ func (kp *DexKeeper) deleteRecentPrices(ctx sdk.Context, symbol string) {
 	kp.PairMapper.DeleteRecentPrices(ctx, symbol)
 }
 
-func (kp *DexKeeper) expireAllOrders(ctx sdk.Context, symbol string) []chan Transfer {
+func (kp *DexKeeper) expireAllOrders(ctx sdk.Context, symbol string,
+	toTransfer func(ord me.OrderPart, ordMsg OrderInfo) Transfer, reason ChangeType) []chan Transfer {
 	ordersOfSymbol := make(map[string]*OrderInfo)
 	if dexOrderKeeper, err := kp.getOrderKeeper(symbol); err == nil {
 		ordersOfSymbol = dexOrderKeeper.getAllOrdersForPair(symbol)
@@ -985,7 +1316,10 @@ func (kp *DexKeeper) expireAllOrders(ctx sdk.Context, symbol string) []chan Tran
 			// gen transfer
 			if ordMsg, ok := orders[ord.Id]; ok && ordMsg != nil {
 				h := channelHash(ordMsg.Sender, concurrency)
-				transferChs[h] <- TransferFromExpired(ord, *ordMsg)
+				transferChs[h] <- toTransfer(ord, *ordMsg)
+				kp.recentCancels.add(ordMsg, reason, ctx.BlockHeight(), ctx.BlockHeader().Time.UnixNano())
+				kp.dropOrder(orders, ord.Id)
+				kp.addOpenInterest(symbol, side, -ord.LeavesQty())
 			} else {
 				kp.logger.Error("failed to locate order to remove in order book", "oid", ord.Id)
 			}
@@ -1059,7 +1393,11 @@ func (kp *DexKeeper) GetAllOrdersForPair(symbol string) map[string]*OrderInfo {
 }
 
 func (kp *DexKeeper) ReloadOrder(symbol string, orderInfo *OrderInfo, height int64) {
-	kp.mustGetOrderKeeper(symbol).reloadOrder(symbol, orderInfo, height)
+	orderKeeper := kp.mustGetOrderKeeper(symbol)
+	if _, exists := orderKeeper.orderExists(symbol, orderInfo.Id); !exists {
+		kp.addTotalOrders(1)
+	}
+	orderKeeper.reloadOrder(symbol, orderInfo, height)
 }
 
 func (kp *DexKeeper) GetOrderChanges(pairType SymbolPairType) OrderChanges {
@@ -1125,6 +1463,13 @@ func (kp *DexKeeper) ShouldPublishOrder() bool {
 func (kp *DexKeeper) GetEngines() map[string]*me.MatchEng {
 	return kp.engines
 }
+
+// PairsVersion changes whenever a pair is listed or delisted, so the query caches derived from the listed pairs
+// know when to be rebuilt.
+func (kp *DexKeeper) PairsVersion() int64 {
+	return atomic.LoadInt64(&kp.pairsVersion)
+}
+
 func appendAllOrdersMap(ms ...map[string]map[string]*OrderInfo) map[string]map[string]*OrderInfo {
 	res := make(map[string]map[string]*OrderInfo)
 	for _, m := range ms {
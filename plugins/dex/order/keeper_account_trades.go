@@ -0,0 +1,171 @@
+package order
+
+import (
+	"sort"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+const (
+	DefaultAccountTradesCacheSize = 10000
+	// MaxAccountTrades is the max number of the recent trades kept for an account, the older ones are dropped
+	MaxAccountTrades = 1000
+	// AccountTradesWindowBlocks is the number of the recent blocks whose trades are kept for the accounts
+	AccountTradesWindowBlocks = 100000
+)
+
+// accountTradesCache keeps the recent trades of the accounts, it's kept in memory by the node only and the
+// least recently traded accounts are evicted once there are more accounts than the cache size. The trades of
+// a block are collected while matching and added once their fees are charged and they are numbered.
+type accountTradesCache struct {
+	mtx     sync.Mutex
+	cache   *lru.Cache // string of the address bytes -> []store.AccountTrade, in the order of execution
+	pending []pendingAccountTrade
+	height  int64 // the height of the last added trades
+}
+
+type pendingAccountTrade struct {
+	addr  sdk.AccAddress
+	trade *me.Trade
+	index int // the index of the trade among the trades of the symbol
+	store.AccountTrade
+}
+
+func newAccountTradesCache(size int) *accountTradesCache {
+	c := &accountTradesCache{}
+	c.resize(size)
+	return c
+}
+
+// resize resets the cache with the new size, the cache is disabled if size is not positive.
+func (c *accountTradesCache) resize(size int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.pending = nil
+	if size <= 0 {
+		c.cache = nil
+		return
+	}
+	cache, err := lru.New(size)
+	if err != nil {
+		panic(err)
+	}
+	c.cache = cache
+}
+
+func (c *accountTradesCache) enabled() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.cache != nil
+}
+
+// collectTrades keeps both sides of the trades of a symbol matched at the height, until their fees are charged.
+// The orders must be looked up before the filled ones are dropped.
+func (c *accountTradesCache) collectTrades(symbol string, height, timestamp int64, trades []me.Trade,
+	orders map[string]*OrderInfo) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.cache == nil {
+		return
+	}
+	for i := range trades {
+		t := &trades[i]
+		record := store.AccountTrade{
+			Symbol:    symbol,
+			Price:     utils.Fixed8(t.LastPx),
+			Quantity:  utils.Fixed8(t.LastQty),
+			Height:    height,
+			Timestamp: timestamp,
+		}
+		if buy, ok := orders[t.Bid]; ok {
+			record.Side = Side.BUY
+			c.pending = append(c.pending, pendingAccountTrade{buy.Sender, t, i, record})
+		}
+		if sell, ok := orders[t.Sid]; ok {
+			record.Side = Side.SELL
+			c.pending = append(c.pending, pendingAccountTrade{sell.Sender, t, i, record})
+		}
+	}
+}
+
+// commit adds the collected trades in the order of their ids with the fees charged on them, and drops the trades
+// of the touched accounts out of the window.
+func (c *accountTradesCache) commit(height int64, offsets tradeIdOffsets) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.cache == nil {
+		return
+	}
+	c.height = height
+	// the symbols are matched concurrently, while an account may trade on several of them
+	sort.SliceStable(c.pending, func(i, j int) bool {
+		return offsets[c.pending[i].Symbol]+c.pending[i].index < offsets[c.pending[j].Symbol]+c.pending[j].index
+	})
+	for _, p := range c.pending {
+		p.TradeId = offsets.tradeId(height, p.Symbol, p.index)
+		fee := p.trade.SellerFee
+		if p.Side == Side.BUY {
+			fee = p.trade.BuyerFee
+		}
+		if fee != nil {
+			p.Fee = fee.Tokens
+		}
+		key := string(p.addr.Bytes())
+		var trades []store.AccountTrade
+		if existing, ok := c.cache.Get(key); ok {
+			trades = existing.([]store.AccountTrade)
+		}
+		trades = append(c.inWindow(trades), p.AccountTrade)
+		if len(trades) > MaxAccountTrades {
+			trades = append([]store.AccountTrade(nil), trades[len(trades)-MaxAccountTrades:]...)
+		}
+		c.cache.Add(key, trades)
+	}
+	c.pending = nil
+}
+
+func (c *accountTradesCache) inWindow(trades []store.AccountTrade) []store.AccountTrade {
+	for i, t := range trades {
+		if t.Height > c.height-AccountTradesWindowBlocks {
+			return trades[i:]
+		}
+	}
+	return nil
+}
+
+func (c *accountTradesCache) get(addr sdk.AccAddress) []store.AccountTrade {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.cache == nil {
+		return nil
+	}
+	existing, ok := c.cache.Peek(string(addr.Bytes()))
+	if !ok {
+		return nil
+	}
+	return c.inWindow(existing.([]store.AccountTrade))
+}
+
+// SetAccountTradesCacheSize sets the max number of accounts whose recent trades are kept for the
+// dex/accounttrades query, the trades are not kept if size is not positive.
+func (kp *DexKeeper) SetAccountTradesCacheSize(size int) {
+	kp.accountTrades.resize(size)
+}
+
+func (kp *DexKeeper) AccountTradesEnabled() bool {
+	return kp.accountTrades.enabled()
+}
+
+// GetAccountTrades returns the trades of the account in the last AccountTradesWindowBlocks blocks in the order
+// of execution, at most MaxAccountTrades of them. It's empty if the account has no recent trade, or it has been
+// evicted from the cache.
+func (kp *DexKeeper) GetAccountTrades(addr sdk.AccAddress) []store.AccountTrade {
+	return kp.accountTrades.get(addr)
+}
@@ -0,0 +1,52 @@
+package order
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// OrderAck is a new order accepted by the dex handler in DeliverTx, before it's matched in EndBlocker.
+// The acks are only collected for publication, they never affect the state.
+type OrderAck struct {
+	OrderId     string
+	Symbol      string
+	Sender      sdk.AccAddress
+	Side        int8
+	Price       int64
+	Quantity    int64
+	TimeInForce int8
+	TxHash      string
+}
+
+// EnableAckPublish starts collecting the accepted orders of each block for publication.
+func (kp *DexKeeper) EnableAckPublish() {
+	kp.orderAcks.enabled = true
+}
+
+func (kp *DexKeeper) recordOrderAck(ctx sdk.Context, msg NewOrderMsg, res sdk.Result) {
+	kp.orderAcks.record(ctx, msg, res)
+}
+
+// GetOrderAcks returns the orders accepted in the current block whose txs succeeded, in the order of the txs.
+func (kp *DexKeeper) GetOrderAcks() []OrderAck {
+	if len(kp.orderAcks.records) == 0 {
+		return nil
+	}
+	acks := make([]OrderAck, len(kp.orderAcks.records))
+	for i, r := range kp.orderAcks.records {
+		acks[i] = OrderAck{
+			OrderId:     r.msg.Id,
+			Symbol:      r.msg.Symbol,
+			Sender:      r.msg.Sender,
+			Side:        r.msg.Side,
+			Price:       r.msg.Price,
+			Quantity:    r.msg.Quantity,
+			TimeInForce: r.msg.TimeInForce,
+			TxHash:      r.txHash,
+		}
+	}
+	return acks
+}
+
+func (kp *DexKeeper) ClearOrderAcks() {
+	kp.orderAcks.clear()
+}
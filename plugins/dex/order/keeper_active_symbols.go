@@ -0,0 +1,108 @@
+package order
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	dexUtils "github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+const MaxActiveSymbolsWindowDays = 7
+
+// symbolActivities accumulates the number of trades and the traded volume of each symbol, in the quote asset, by the
+// days between breathe blocks. Like traderVolumes, it's kept in memory by the node only.
+type symbolActivities struct {
+	mtx  sync.Mutex
+	days []map[string]*store.ActiveSymbol // days[0] is the current day, symbol -> activity
+}
+
+func newSymbolActivities() *symbolActivities {
+	return &symbolActivities{days: []map[string]*store.ActiveSymbol{make(map[string]*store.ActiveSymbol)}}
+}
+
+func (a *symbolActivities) addTrades(symbol string, trades []me.Trade) {
+	if len(trades) == 0 {
+		return
+	}
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	activity, ok := a.days[0][symbol]
+	if !ok {
+		activity = &store.ActiveSymbol{Symbol: symbol}
+		a.days[0][symbol] = activity
+	}
+	for i := range trades {
+		notional := dexUtils.CalBigNotionalInt64(trades[i].LastPx, trades[i].LastQty)
+		activity.NumTrades++
+		activity.Volume = utils.Fixed8(addVolume(activity.Volume.ToInt64(), notional))
+	}
+}
+
+// roll starts a new day, the days out of the max window are dropped.
+func (a *symbolActivities) roll() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	days := append([]map[string]*store.ActiveSymbol{make(map[string]*store.ActiveSymbol)}, a.days...)
+	if len(days) > MaxActiveSymbolsWindowDays {
+		days = days[:MaxActiveSymbolsWindowDays]
+	}
+	a.days = days
+}
+
+// active returns the symbols traded in the window accepted by the filter, sorted by the volume descending.
+func (a *symbolActivities) active(windowDays int, filter func(symbol string) bool) []store.ActiveSymbol {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if windowDays > len(a.days) {
+		windowDays = len(a.days)
+	}
+	total := make(map[string]*store.ActiveSymbol)
+	for _, day := range a.days[:windowDays] {
+		for symbol, activity := range day {
+			if !filter(symbol) {
+				continue
+			}
+			sum, ok := total[symbol]
+			if !ok {
+				sum = &store.ActiveSymbol{Symbol: symbol}
+				total[symbol] = sum
+			}
+			sum.NumTrades += activity.NumTrades
+			sum.Volume = utils.Fixed8(addVolume(sum.Volume.ToInt64(), activity.Volume.ToInt64()))
+		}
+	}
+
+	symbols := make([]store.ActiveSymbol, 0, len(total))
+	for _, activity := range total {
+		symbols = append(symbols, *activity)
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].Volume != symbols[j].Volume {
+			return symbols[i].Volume > symbols[j].Volume
+		}
+		return symbols[i].Symbol < symbols[j].Symbol
+	})
+	return symbols
+}
+
+// RollSymbolActivities starts accumulating the activities of the symbols of a new day, it's called in the breathe blocks.
+func (kp *DexKeeper) RollSymbolActivities() {
+	kp.symbolActivities.roll()
+}
+
+// GetActiveSymbols returns the listed symbols of the pair type with at least one trade over the current day and the
+// (windowDays - 1) days before it, sorted by the traded volume in the quote asset descending. The days are separated
+// by the breathe blocks.
+func (kp *DexKeeper) GetActiveSymbols(pairType SymbolPairType, windowDays int) ([]store.ActiveSymbol, error) {
+	if windowDays <= 0 || windowDays > MaxActiveSymbolsWindowDays {
+		return nil, fmt.Errorf("window should be in [1, %d] days", MaxActiveSymbolsWindowDays)
+	}
+	return kp.symbolActivities.active(windowDays, func(symbol string) bool {
+		_, listed := kp.engines[symbol]
+		return listed && kp.GetPairType(symbol) == pairType
+	}), nil
+}
@@ -0,0 +1,44 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+func Test_symbolActivities(t *testing.T) {
+	all := func(string) bool { return true }
+	activities := newSymbolActivities()
+	activities.addTrades("XYZ-000_BNB", []me.Trade{
+		{LastPx: 1e8, LastQty: 2e8},
+		{LastPx: 1e8, LastQty: 1e8},
+	})
+	activities.addTrades("ABC-000_BNB", []me.Trade{{LastPx: 2e8, LastQty: 1e8}})
+	activities.addTrades("EFG-000_BNB", nil)
+	require.Equal(t, []store.ActiveSymbol{
+		{Symbol: "XYZ-000_BNB", NumTrades: 2, Volume: utils.Fixed8(3e8)},
+		{Symbol: "ABC-000_BNB", NumTrades: 1, Volume: utils.Fixed8(2e8)},
+	}, activities.active(1, all))
+
+	activities.roll()
+	activities.addTrades("ABC-000_BNB", []me.Trade{{LastPx: 2e8, LastQty: 1e8}})
+	require.Equal(t, []store.ActiveSymbol{
+		{Symbol: "ABC-000_BNB", NumTrades: 1, Volume: utils.Fixed8(2e8)},
+	}, activities.active(1, all))
+	require.Equal(t, []store.ActiveSymbol{
+		{Symbol: "ABC-000_BNB", NumTrades: 2, Volume: utils.Fixed8(4e8)},
+		{Symbol: "XYZ-000_BNB", NumTrades: 2, Volume: utils.Fixed8(3e8)},
+	}, activities.active(MaxActiveSymbolsWindowDays, all))
+	require.Equal(t, []store.ActiveSymbol{
+		{Symbol: "XYZ-000_BNB", NumTrades: 2, Volume: utils.Fixed8(3e8)},
+	}, activities.active(2, func(symbol string) bool { return symbol == "XYZ-000_BNB" }))
+
+	for i := 0; i < MaxActiveSymbolsWindowDays; i++ {
+		activities.roll()
+	}
+	require.Empty(t, activities.active(MaxActiveSymbolsWindowDays, all))
+}
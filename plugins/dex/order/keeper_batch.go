@@ -0,0 +1,119 @@
+package order
+
+import (
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+var matchBatchSizeKey = []byte("dexMatchBatchSize")
+
+// UpdateMatchBatchSize puts the match batch size of the dex params in effect. It's only called in the breathe
+// blocks, so the batch size stays the same in all the blocks replayed since the last breathe block when the
+// order book is recovered.
+func (kp *DexKeeper) UpdateMatchBatchSize(ctx sdk.Context) {
+	size := kp.GetParams(ctx).MatchBatchSize
+	if size == kp.matchBatchSize {
+		return
+	}
+	kvStore := ctx.KVStore(kp.storeKey)
+	if size == 0 {
+		kvStore.Delete(matchBatchSizeKey)
+	} else {
+		kvStore.Set(matchBatchSizeKey, kp.cdc.MustMarshalBinaryBare(size))
+	}
+	kp.logger.Info("update match batch size", "from", kp.matchBatchSize, "to", size)
+	kp.matchBatchSize = size
+}
+
+func (kp *DexKeeper) loadMatchBatchSize(ctx sdk.Context) {
+	bz := ctx.KVStore(kp.storeKey).Get(matchBatchSizeKey)
+	if bz == nil {
+		kp.matchBatchSize = 0
+		return
+	}
+	kp.cdc.MustUnmarshalBinaryBare(bz, &kp.matchBatchSize)
+}
+
+// deferByBatchSize keeps the number of the round orders matched in a block within the match batch size, by
+// deferring the symbols beyond it to the next blocks. The symbols are taken in FIFO order, i.e. by the height
+// since which they are deferred and then by the symbol, until one doesn't fit. The first symbol is always
+// matched, so that the backlog drains even if a single symbol has more round orders than the batch size.
+// The priorities of the orders within a symbol are kept by its order book as usual.
+func (kp *DexKeeper) deferByBatchSize(height int64, symbols []string) []string {
+	if kp.matchBatchSize <= 0 {
+		kp.clearBatchDeferrals(height)
+		return symbols
+	}
+
+	since := make(map[string]int64, len(symbols))
+	for _, symbol := range symbols {
+		since[symbol] = kp.deferredSince(symbol, height)
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		si, sj := since[symbols[i]], since[symbols[j]]
+		if si != sj {
+			return si < sj
+		}
+		return symbols[i] < symbols[j]
+	})
+
+	selected := make([]string, 0, len(symbols))
+	backlog := store.MatchBacklog{Height: height, BatchSize: kp.matchBatchSize}
+	var numOrders int64
+	for _, symbol := range symbols {
+		n := int64(len(kp.mustGetOrderKeeper(symbol).getRoundOrdersForPair(symbol)))
+		if len(backlog.Symbols) == 0 && (len(selected) == 0 || numOrders+n <= kp.matchBatchSize) {
+			selected = append(selected, symbol)
+			numOrders += n
+			continue
+		}
+		kp.roundDeferredSymbols = append(kp.roundDeferredSymbols, symbol)
+		backlog.Symbols = append(backlog.Symbols, store.DeferredSymbol{
+			Symbol: symbol,
+			Orders: n,
+			Since:  since[symbol],
+		})
+	}
+	if len(backlog.Symbols) != 0 {
+		kp.logger.Info("defer symbols by match batch size", "batchSize", kp.matchBatchSize,
+			"matchedOrders", numOrders, "deferredSymbols", len(backlog.Symbols))
+	}
+	kp.setMatchBacklog(backlog)
+	return selected
+}
+
+// deferredSince returns the height since which the round orders of the symbol wait for the matching, i.e. the
+// creation height of the oldest one still open. It's derived from the open orders rather than kept aside, so the
+// recovered order book defers the symbols in the same way.
+func (kp *DexKeeper) deferredSince(symbol string, height int64) int64 {
+	orderKeeper := kp.mustGetOrderKeeper(symbol)
+	orders := orderKeeper.getAllOrdersForPair(symbol)
+	since := height
+	for _, id := range orderKeeper.getRoundOrdersForPair(symbol) {
+		if ord, ok := orders[id]; ok && ord.CreatedHeight < since {
+			since = ord.CreatedHeight
+		}
+	}
+	return since
+}
+
+// clearBatchDeferrals is called when all the symbols are matched, e.g. in the breathe blocks.
+func (kp *DexKeeper) clearBatchDeferrals(height int64) {
+	kp.setMatchBacklog(store.MatchBacklog{Height: height, BatchSize: kp.matchBatchSize})
+}
+
+func (kp *DexKeeper) setMatchBacklog(backlog store.MatchBacklog) {
+	kp.matchBacklog.Store(backlog)
+}
+
+// GetMatchBacklog returns the symbols deferred by the match batch size at the last matching, in the order
+// they are going to be matched.
+func (kp *DexKeeper) GetMatchBacklog() store.MatchBacklog {
+	if backlog, ok := kp.matchBacklog.Load().(store.MatchBacklog); ok {
+		return backlog
+	}
+	return store.MatchBacklog{BatchSize: kp.matchBatchSize}
+}
@@ -0,0 +1,123 @@
+package order
+
+import (
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/utils"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+const (
+	DefaultRecentCancelsCacheSize = 10000
+	// MaxRecentCancels is the max number of the recently cancelled orders kept for an account, the older ones are dropped
+	MaxRecentCancels = 1000
+	// RecentCancelsWindowBlocks is the number of the recent blocks whose cancelled orders are kept for the accounts
+	RecentCancelsWindowBlocks = 100000
+)
+
+// recentCancelsCache keeps the recently cancelled orders of the accounts, either cancelled by the accounts or by
+// the chain, i.e. expired in the breathe blocks or by the delisting of the pairs. It's kept in memory by the node
+// only and the least recently cancelling accounts are evicted once there are more accounts than the cache size.
+type recentCancelsCache struct {
+	mtx   sync.Mutex
+	cache *lru.Cache // string of the address bytes -> []store.CancelledOrder, in the order of cancellation
+}
+
+func newRecentCancelsCache(size int) *recentCancelsCache {
+	c := &recentCancelsCache{}
+	c.resize(size)
+	return c
+}
+
+// resize resets the cache with the new size, the cache is disabled if size is not positive.
+func (c *recentCancelsCache) resize(size int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if size <= 0 {
+		c.cache = nil
+		return
+	}
+	cache, err := lru.New(size)
+	if err != nil {
+		panic(err)
+	}
+	c.cache = cache
+}
+
+func (c *recentCancelsCache) enabled() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.cache != nil
+}
+
+// add records the order cancelled at the height, and drops the cancelled orders of the account out of the window.
+func (c *recentCancelsCache) add(order *OrderInfo, reason ChangeType, height, timestamp int64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.cache == nil {
+		return
+	}
+	key := string(order.Sender.Bytes())
+	var cancels []store.CancelledOrder
+	if existing, ok := c.cache.Get(key); ok {
+		cancels = existing.([]store.CancelledOrder)
+	}
+	cancels = append(inCancelsWindow(cancels, height), store.CancelledOrder{
+		Id:        order.Id,
+		Symbol:    order.Symbol,
+		Side:      order.Side,
+		Price:     utils.Fixed8(order.Price),
+		Quantity:  utils.Fixed8(order.Quantity),
+		CumQty:    utils.Fixed8(order.CumQty),
+		Reason:    reason.String(),
+		Height:    height,
+		Timestamp: timestamp,
+	})
+	if len(cancels) > MaxRecentCancels {
+		cancels = append([]store.CancelledOrder(nil), cancels[len(cancels)-MaxRecentCancels:]...)
+	}
+	c.cache.Add(key, cancels)
+}
+
+func inCancelsWindow(cancels []store.CancelledOrder, height int64) []store.CancelledOrder {
+	for i, cancel := range cancels {
+		if cancel.Height > height-RecentCancelsWindowBlocks {
+			return cancels[i:]
+		}
+	}
+	return nil
+}
+
+func (c *recentCancelsCache) get(addr sdk.AccAddress, height int64) []store.CancelledOrder {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.cache == nil {
+		return nil
+	}
+	existing, ok := c.cache.Peek(string(addr.Bytes()))
+	if !ok {
+		return nil
+	}
+	return inCancelsWindow(existing.([]store.CancelledOrder), height)
+}
+
+// SetRecentCancelsCacheSize sets the max number of accounts whose recently cancelled orders are kept for the
+// dex/cancelled query, the cancelled orders are not kept if size is not positive.
+func (kp *DexKeeper) SetRecentCancelsCacheSize(size int) {
+	kp.recentCancels.resize(size)
+}
+
+func (kp *DexKeeper) RecentCancelsEnabled() bool {
+	return kp.recentCancels.enabled()
+}
+
+// GetRecentCancels returns the orders of the account cancelled in the last RecentCancelsWindowBlocks blocks
+// before the height in the order of cancellation, at most MaxRecentCancels of them. It's empty if the account
+// has no recent cancellation, or it has been evicted from the cache.
+func (kp *DexKeeper) GetRecentCancels(addr sdk.AccAddress, height int64) []store.CancelledOrder {
+	return kp.recentCancels.get(addr, height)
+}
@@ -0,0 +1,119 @@
+package order
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	dexTypes "github.com/bnb-chain/node/plugins/dex/types"
+)
+
+var recentOrdersKeyPrefix = []byte("dexRecentOrders:")
+
+// MaxRecentOrderFingerprints is the max number of the recent orders kept per account for the duplicate order check.
+const MaxRecentOrderFingerprints = 100
+
+// OrderFingerprint is the params of an order placed by an account at the height, which the next orders of the
+// account are checked against within the duplicate order window.
+type OrderFingerprint struct {
+	Symbol   string
+	Side     int8
+	Price    int64
+	Quantity int64
+	Height   int64
+}
+
+func (f OrderFingerprint) matches(msg NewOrderMsg) bool {
+	return f.Symbol == msg.Symbol && f.Side == msg.Side && f.Price == msg.Price && f.Quantity == msg.Quantity
+}
+
+// recentOrders is the duplicate order window and the recent orders of an account read by the check of an order,
+// which the order is recorded with once it's placed, so that they are read only once per order.
+type recentOrders struct {
+	window       int64
+	fingerprints []OrderFingerprint
+}
+
+// checkDuplicateOrder rejects the order if the account placed one of the same params within the duplicate order
+// window. The recent orders are kept in the store, so they are the same on all the nodes and across the restarts.
+func (kp *DexKeeper) checkDuplicateOrder(ctx sdk.Context, msg NewOrderMsg) (recentOrders, sdk.Error) {
+	window := kp.GetParams(ctx).DuplicateOrderWindowBlocks
+	if window <= 0 {
+		return recentOrders{}, nil
+	}
+	recent := recentOrders{window, kp.getRecentOrders(ctx, msg.Sender)}
+	for _, f := range recent.fingerprints {
+		if f.Height > ctx.BlockHeight()-window && f.matches(msg) {
+			return recent, dexTypes.ErrRepeatedOrder(msg.Symbol, f.Height)
+		}
+	}
+	return recent, nil
+}
+
+// recordOrderFingerprint keeps the order placed for the duplicate order check, and drops the ones of the account
+// out of the window. Nothing is kept while the check is disabled.
+func (kp *DexKeeper) recordOrderFingerprint(ctx sdk.Context, msg NewOrderMsg, recent recentOrders) {
+	if recent.window <= 0 {
+		return
+	}
+	height := ctx.BlockHeight()
+	kept := keepRecentOrders(recent.fingerprints, height-recent.window, len(recent.fingerprints)+1)
+	kept = append(kept, OrderFingerprint{msg.Symbol, msg.Side, msg.Price, msg.Quantity, height})
+	if len(kept) > MaxRecentOrderFingerprints {
+		kept = kept[len(kept)-MaxRecentOrderFingerprints:]
+	}
+	ctx.KVStore(kp.storeKey).Set(recentOrdersKey(msg.Sender), kp.cdc.MustMarshalBinaryBare(kept))
+}
+
+// PruneRecentOrders drops the recent orders out of the duplicate order window as of the height, and the accounts
+// left with none, so that the accounts not trading anymore are not kept forever. It's called in the breathe blocks.
+func (kp *DexKeeper) PruneRecentOrders(ctx sdk.Context, height int64) {
+	window := kp.GetParams(ctx).DuplicateOrderWindowBlocks
+	store := ctx.KVStore(kp.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, recentOrdersKeyPrefix)
+	var toDelete [][]byte
+	toUpdate := make(map[string][]OrderFingerprint)
+	for ; iter.Valid(); iter.Next() {
+		var recent []OrderFingerprint
+		kp.cdc.MustUnmarshalBinaryBare(iter.Value(), &recent)
+		var kept []OrderFingerprint
+		if window > 0 {
+			kept = keepRecentOrders(recent, height-window, len(recent))
+		}
+		if len(kept) == 0 {
+			toDelete = append(toDelete, iter.Key())
+		} else if len(kept) != len(recent) {
+			toUpdate[string(iter.Key())] = kept
+		}
+	}
+	iter.Close()
+	for _, key := range toDelete {
+		store.Delete(key)
+	}
+	for key, kept := range toUpdate {
+		store.Set([]byte(key), kp.cdc.MustMarshalBinaryBare(kept))
+	}
+}
+
+// keepRecentOrders returns the orders placed after the height, in a slice of the capacity.
+func keepRecentOrders(recent []OrderFingerprint, after int64, capacity int) []OrderFingerprint {
+	kept := make([]OrderFingerprint, 0, capacity)
+	for _, f := range recent {
+		if f.Height > after {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+func (kp *DexKeeper) getRecentOrders(ctx sdk.Context, addr sdk.AccAddress) []OrderFingerprint {
+	bz := ctx.KVStore(kp.storeKey).Get(recentOrdersKey(addr))
+	if bz == nil {
+		return nil
+	}
+	var recent []OrderFingerprint
+	kp.cdc.MustUnmarshalBinaryBare(bz, &recent)
+	return recent
+}
+
+func recentOrdersKey(addr sdk.AccAddress) []byte {
+	return append(append([]byte{}, recentOrdersKeyPrefix...), addr.Bytes()...)
+}
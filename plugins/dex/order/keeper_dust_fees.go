@@ -0,0 +1,124 @@
+package order
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	dexUtils "github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+// DustFeeConversion is the conversion of an asset of the accumulated fees into the native token, by an IOC sell
+// order of the fee account at the best bid of the pair against the native token.
+type DustFeeConversion struct {
+	Symbol   string
+	OrderId  string
+	Quantity int64 // quantity of the asset put on sale, rounded down to the lot size
+	Sold     int64 // quantity of the asset filled in the matching
+	Received int64 // native token received for the filled quantity
+}
+
+// PlaceDustFeeConversions places the IOC sell orders converting the assets of the fee account worth no more than
+// the DustFeeConversionThreshold into the native token, it's called in the breathe blocks before the matching, so
+// that they are matched against the order books like any other order of the block. The assets are picked in the
+// order of the denoms and priced at the best bids, so the conversions are the same on all the nodes. The quantity
+// below the lot size is left to the later conversions. The conversions have to be finished by
+// FinishDustFeeConversions in the same block.
+func (kp *DexKeeper) PlaceDustFeeConversions(ctx sdk.Context, feeAddr sdk.AccAddress) {
+	threshold := kp.GetParams(ctx).DustFeeConversionThreshold
+	height := ctx.BlockHeader().Height
+	if threshold <= 0 || kp.IsMatchingPaused(ctx, height) {
+		return
+	}
+	acc, ok := kp.am.GetAccount(ctx, feeAddr).(types.NamedAccount)
+	if !ok {
+		return
+	}
+	timestamp := ctx.BlockHeader().Time.UnixNano()
+	var toLock sdk.Coins
+	for _, coin := range acc.GetCoins() {
+		if coin.Denom == types.NativeTokenSymbol {
+			continue
+		}
+		symbol := dexUtils.Assets2TradingPair(coin.Denom, types.NativeTokenSymbol)
+		eng, ok := kp.engines[symbol]
+		if !ok || kp.checkTokenTrading(ctx, symbol) != nil {
+			continue
+		}
+		var bestBid int64
+		eng.Book.ShowDepth(1, func(p *me.PriceLevel, levelIndex int) {
+			bestBid = p.Price
+		}, func(p *me.PriceLevel, levelIndex int) {})
+		qty := coin.Amount - coin.Amount%eng.LotSize
+		if bestBid <= 0 || qty <= 0 || dexUtils.CalBigNotionalInt64(bestBid, qty) > threshold {
+			continue
+		}
+
+		id := GenerateOrderID(acc.GetSequence(), feeAddr)
+		_ = acc.SetSequence(acc.GetSequence() + 1)
+		msg := NewOrderMsg{
+			Sender:      feeAddr,
+			Id:          id,
+			Symbol:      symbol,
+			OrderType:   OrderType.LIMIT,
+			Side:        Side.SELL,
+			Price:       bestBid,
+			Quantity:    qty,
+			TimeInForce: TimeInForce.IOC,
+		}
+		if err := kp.AddOrder(OrderInfo{msg, height, timestamp, height, timestamp, 0, "", 0}, false); err != nil {
+			kp.logger.Error("failed to place dust fee conversion", "symbol", symbol, "qty", qty, "err", err.Error())
+			continue
+		}
+		toLock = toLock.Plus(sdk.Coins{sdk.NewCoin(coin.Denom, qty)})
+		kp.dustFeeConversions = append(kp.dustFeeConversions, DustFeeConversion{Symbol: symbol, OrderId: id, Quantity: qty})
+	}
+	if len(kp.dustFeeConversions) == 0 {
+		return
+	}
+	_ = acc.SetCoins(acc.GetCoins().Minus(toLock))
+	acc.SetLockedCoins(acc.GetLockedCoins().Plus(toLock))
+	kp.am.SetAccount(ctx, acc)
+	kp.dustFeeAccount = feeAddr
+	kp.logger.Info("placed dust fee conversions", "height", height, "conversions", len(kp.dustFeeConversions))
+}
+
+// FinishDustFeeConversions returns the conversions placed in the block with their fills, it's called right after
+// the matching. The conversions not matched, e.g. of a pair out of its session, are cancelled and refunded, so
+// that no order of the fee account is left in the order books.
+func (kp *DexKeeper) FinishDustFeeConversions(ctx sdk.Context) []DustFeeConversion {
+	conversions := kp.dustFeeConversions
+	kp.dustFeeConversions = nil
+	kp.dustFeeAccount = nil
+	for i := range conversions {
+		conversion := &conversions[i]
+		trades, _ := kp.GetLastTrades(ctx.BlockHeader().Height, conversion.Symbol)
+		for _, trade := range trades {
+			if trade.Sid == conversion.OrderId {
+				conversion.Sold += trade.LastQty
+				conversion.Received += dexUtils.CalBigNotionalInt64(trade.LastPx, trade.LastQty)
+			}
+		}
+		info, ok := kp.OrderExists(conversion.Symbol, conversion.OrderId)
+		if !ok {
+			continue
+		}
+		ord, err := kp.GetOrder(info.Id, info.Symbol, info.Side, info.Price)
+		if err != nil {
+			kp.logger.Error("failed to locate dust fee conversion", "id", info.Id, "err", err.Error())
+			continue
+		}
+		transfer := TransferFromCanceled(ord, info, false)
+		if err := kp.doTransfer(ctx, &transfer); err != nil {
+			panic(err)
+		}
+		if err := kp.RemoveOrder(info.Id, info.Symbol, func(ord me.OrderPart) {
+			if kp.ShouldPublishOrder() {
+				kp.UpdateOrderChangeSync(OrderChange{info.Id, Canceled, "", nil}, info.Symbol)
+			}
+		}); err != nil {
+			panic(err)
+		}
+	}
+	return conversions
+}
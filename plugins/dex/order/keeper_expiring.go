@@ -0,0 +1,153 @@
+package order
+
+import (
+	"math"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// expireThreshold describes what an upcoming breathe block would expire:
+// orders created before expireHeight, or before forceExpireHeight if they are on a preferred price level.
+// The pairs with their own GTC TTLs expire the orders created before the heights of their TTL days instead.
+type expireThreshold struct {
+	breatheHeight     int64
+	expireHeight      int64
+	forceExpireHeight int64
+	ttlExpireHeights  map[int]int64 // GTC TTL days of the pairs -> expire height
+}
+
+func (t expireThreshold) expireHeightOf(ttlDays int) int64 {
+	if height, ok := t.ttlExpireHeights[ttlDays]; ok {
+		return height
+	}
+	return t.expireHeight
+}
+
+// GetExpiringOrders returns the GTE orders that would be expired by the breathe blocks expected
+// within the next `withinBlocks` blocks, optionally filtered by the owner.
+// The heights of the upcoming breathe blocks are estimated from the distance between the latest two
+// breathe blocks, so the result is a hint for clients rather than a guarantee.
+func (kp *DexKeeper) GetExpiringOrders(ctx sdk.Context, withinBlocks int64, owner sdk.AccAddress) []store.ExpiringOrder {
+	expiring := make([]store.ExpiringOrder, 0)
+	thresholds := kp.upcomingExpireThresholds(ctx, ctx.BlockHeight()+withinBlocks)
+	if len(thresholds) == 0 {
+		return expiring
+	}
+
+	for _, orderKeeper := range kp.OrderKeepers {
+		if !orderKeeper.supportUpgradeVersion() {
+			continue
+		}
+		for symbol, orders := range orderKeeper.getAllOrders() {
+			engine, ok := kp.engines[symbol]
+			if !ok {
+				continue
+			}
+			ttlDays := kp.GetGTCTTLDays(symbol)
+			collect := func(pl *me.PriceLevel, levelIndex int) {
+				preferred := sdk.IsUpgrade(upgrade.BEP67) && levelIndex < preferencePriceLevel
+				for _, ord := range pl.Orders {
+					info, ok := orders[ord.Id]
+					if !ok || (owner != nil && !owner.Equals(info.Sender)) {
+						continue
+					}
+					for _, t := range thresholds {
+						limit := t.expireHeightOf(ttlDays)
+						if preferred {
+							limit = t.forceExpireHeight
+						}
+						if ord.Time < limit {
+							expiring = append(expiring, store.ExpiringOrder{
+								Id:               info.Id,
+								Symbol:           info.Symbol,
+								Owner:            info.Sender,
+								Side:             info.Side,
+								Price:            utils.Fixed8(info.Price),
+								Quantity:         utils.Fixed8(info.Quantity),
+								CumQty:           utils.Fixed8(info.CumQty),
+								CreatedHeight:    info.CreatedHeight,
+								CreatedTimestamp: info.CreatedTimestamp,
+								ExpireHeight:     t.breatheHeight,
+							})
+							break
+						}
+					}
+				}
+			}
+			engine.Book.UpdateForEachPriceLevel(me.BUYSIDE, collect)
+			engine.Book.UpdateForEachPriceLevel(me.SELLSIDE, collect)
+		}
+	}
+
+	sort.Slice(expiring, func(i, j int) bool {
+		if expiring[i].ExpireHeight != expiring[j].ExpireHeight {
+			return expiring[i].ExpireHeight < expiring[j].ExpireHeight
+		}
+		if expiring[i].Symbol != expiring[j].Symbol {
+			return expiring[i].Symbol < expiring[j].Symbol
+		}
+		return expiring[i].Id < expiring[j].Id
+	})
+	return expiring
+}
+
+// upcomingExpireThresholds estimates the breathe blocks until `untilHeight` and what each of them would expire.
+func (kp *DexKeeper) upcomingExpireThresholds(ctx sdk.Context, untilHeight int64) []expireThreshold {
+	lastDay := ctx.BlockHeader().Time.Unix() / utils.SecondsPerDay
+	lastHeight, ok := kp.getBreatheBlockHeightOfDay(ctx, lastDay)
+	if !ok {
+		lastDay--
+		if lastHeight, ok = kp.getBreatheBlockHeightOfDay(ctx, lastDay); !ok {
+			return nil
+		}
+	}
+	prevHeight, ok := kp.getBreatheBlockHeightOfDay(ctx, lastDay-1)
+	if !ok || prevHeight >= lastHeight {
+		return nil
+	}
+	blocksPerDay := lastHeight - prevHeight
+
+	// a breathe block expires the orders created before the breathe block of `daysBack` days ago.
+	thresholdOf := func(day int64, daysBack int64) int64 {
+		if day-daysBack > lastDay {
+			// the breathe block has not happened yet, so every existing order is before it.
+			return math.MaxInt64
+		}
+		if height, ok := kp.getBreatheBlockHeightOfDay(ctx, day-daysBack); ok {
+			return height
+		}
+		return -1
+	}
+
+	thresholds := make([]expireThreshold, 0)
+	for k := int64(1); k <= forceExpireDays+1; k++ {
+		breatheHeight := lastHeight + k*blocksPerDay
+		if breatheHeight > untilHeight {
+			break
+		}
+		day := lastDay + k
+		t := expireThreshold{
+			breatheHeight: breatheHeight,
+			expireHeight:  thresholdOf(day, effectiveDays),
+		}
+		for _, days := range kp.pairGTCTTLDays {
+			if t.ttlExpireHeights == nil {
+				t.ttlExpireHeights = make(map[int]int64)
+			}
+			t.ttlExpireHeights[days] = thresholdOf(day, int64(days))
+		}
+		if sdk.IsUpgrade(upgrade.BEP67) {
+			t.forceExpireHeight = thresholdOf(day, forceExpireDays)
+		} else {
+			t.forceExpireHeight = t.expireHeight
+		}
+		thresholds = append(thresholds, t)
+	}
+	return thresholds
+}
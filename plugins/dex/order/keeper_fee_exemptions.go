@@ -0,0 +1,79 @@
+package order
+
+import (
+	"bytes"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	feeExemptKeyPrefix            = "dexFeeExempt:"
+	feeExemptionProposalKeyPrefix = "dexFeeExemptionProposal:"
+)
+
+// SetFeeExempt exempts the account from the trade fees and tx fees or removes the exemption, per the passed fee
+// exemptions change proposal. The proposals of an account are applied in the order of their ids, so an older
+// proposal can't be replayed to revert a newer one.
+func (kp *DexKeeper) SetFeeExempt(ctx sdk.Context, addr sdk.AccAddress, exempt bool, proposalId int64) error {
+	store := ctx.KVStore(kp.storeKey)
+	proposalKey := calcFeeExemptKey(feeExemptionProposalKeyPrefix, addr)
+	if bz := store.Get(proposalKey); bz != nil {
+		var last int64
+		kp.cdc.MustUnmarshalBinaryBare(bz, &last)
+		if proposalId <= last {
+			return fmt.Errorf("the fee exemption of %s has been changed by proposal %d", addr.String(), last)
+		}
+	}
+	if exempt {
+		store.Set(calcFeeExemptKey(feeExemptKeyPrefix, addr), []byte{1})
+	} else {
+		store.Delete(calcFeeExemptKey(feeExemptKeyPrefix, addr))
+	}
+	store.Set(proposalKey, kp.cdc.MustMarshalBinaryBare(proposalId))
+	kp.logger.Info("apply fee exemption", "proposalId", proposalId, "account", addr.String(), "exempt", exempt)
+	return nil
+}
+
+// IsFeeExempt tells whether the account pays no trade fees nor tx fees.
+func (kp *DexKeeper) IsFeeExempt(ctx sdk.Context, addr sdk.AccAddress) bool {
+	return ctx.KVStore(kp.storeKey).Has(calcFeeExemptKey(feeExemptKeyPrefix, addr))
+}
+
+// getFeeExemptAccounts returns all the fee exempt accounts, keyed by the string of the addr bytes.
+func (kp *DexKeeper) getFeeExemptAccounts(ctx sdk.Context) map[string]struct{} {
+	accounts := make(map[string]struct{})
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(kp.storeKey), []byte(feeExemptKeyPrefix))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		accounts[string(iter.Key()[len(feeExemptKeyPrefix):])] = struct{}{}
+	}
+	return accounts
+}
+
+func calcFeeExemptKey(prefix string, addr sdk.AccAddress) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(prefix)
+	buf.Write(addr.Bytes())
+	return buf.Bytes()
+}
+
+// waiveTradeFees charges no fee on the trades of a fee exempt account. The waived fees are empty and typed as
+// sdk.FeeFree, which no charged trade fee is, so the trades are published with the fees flagged as exempt.
+func waiveTradeFees(trans TradeTransfers) {
+	for _, tran := range trans {
+		fee := sdk.NewFee(sdk.Coins{}, sdk.FeeFree)
+		tran.Fee = fee
+		tran.feeExempt = true
+		if tran.IsBuyer() {
+			tran.Trade.BuyerFee = &fee
+		} else {
+			tran.Trade.SellerFee = &fee
+		}
+	}
+}
+
+// IsExemptTradeFee tells whether the fee of a side of a trade is waived for the fee exemption.
+func IsExemptTradeFee(fee *sdk.Fee) bool {
+	return fee != nil && fee.Type == sdk.FeeFree
+}
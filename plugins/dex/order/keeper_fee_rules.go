@@ -0,0 +1,44 @@
+package order
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	dexTypes "github.com/bnb-chain/node/plugins/dex/types"
+)
+
+// GetFeeRules returns the fee rules in effect, it has to be kept in line with how the FeeManager charges the fees.
+func (kp *DexKeeper) GetFeeRules(ctx sdk.Context) store.FeeRules {
+	config := kp.FeeManager.GetConfig()
+	params := kp.GetParams(ctx)
+	rules := store.FeeRules{
+		NativeAsset:           types.NativeTokenSymbol,
+		FeeRateDecimals:       feeRateDecimals,
+		FeeRate:               config.FeeRate,
+		FeeRateNative:         config.FeeRateNative,
+		ExpireFee:             config.ExpireFee,
+		ExpireFeeNative:       config.ExpireFeeNative,
+		IOCExpireFee:          config.IOCExpireFee,
+		IOCExpireFeeNative:    config.IOCExpireFeeNative,
+		CancelFee:             config.CancelFee,
+		CancelFeeNative:       config.CancelFeeNative,
+		AllocationOrdering:    params.AllocationOrdering,
+		MakerRebateRate:       params.MakerRebateRate,
+		MakerRebateMaxSpread:  params.MakerRebateMaxSpread,
+		DelistFeeFree:         params.DelistFeeFree,
+		InsufficientFeePolicy: params.InsufficientFeePolicy,
+		MinTradeFees:          config.MinTradeFees,
+	}
+	if rules.AllocationOrdering == "" {
+		rules.AllocationOrdering = dexTypes.AllocationOrderingMatch
+	}
+	if rules.InsufficientFeePolicy == "" {
+		rules.InsufficientFeePolicy = dexTypes.InsufficientFeePolicyChargeReceived
+	}
+	if sdk.IsUpgrade(upgrade.BEP70) {
+		rules.BusdSymbol = BUSDSymbol
+	}
+	return rules
+}
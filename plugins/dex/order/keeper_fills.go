@@ -0,0 +1,127 @@
+package order
+
+import (
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+const DefaultOrderFillsCacheSize = 10000
+
+// orderFillsCache keeps the fills of the recently traded orders, it's kept in memory by the node only
+// and the least recently filled orders are evicted once there are more orders than the cache size. The fills
+// of a block are collected while matching and added once all the symbols are matched and the trades numbered.
+type orderFillsCache struct {
+	mtx     sync.Mutex
+	cache   *lru.Cache // order id -> []store.OrderFill
+	pending []pendingOrderFill
+}
+
+type pendingOrderFill struct {
+	orderId string
+	index   int // the index of the trade among the trades of the symbol
+	store.OrderFill
+}
+
+func newOrderFillsCache(size int) *orderFillsCache {
+	c := &orderFillsCache{}
+	c.resize(size)
+	return c
+}
+
+// resize resets the cache with the new size, the cache is disabled if size is not positive.
+func (c *orderFillsCache) resize(size int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.pending = nil
+	if size <= 0 {
+		c.cache = nil
+		return
+	}
+	cache, err := lru.New(size)
+	if err != nil {
+		panic(err)
+	}
+	c.cache = cache
+}
+
+func (c *orderFillsCache) enabled() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.cache != nil
+}
+
+// collectTrades keeps both sides of the trades of a symbol matched at the height, until they are numbered.
+func (c *orderFillsCache) collectTrades(symbol string, height, timestamp int64, trades []me.Trade) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.cache == nil {
+		return
+	}
+	for i := range trades {
+		t := &trades[i]
+		fill := store.OrderFill{
+			Symbol:    symbol,
+			Price:     utils.Fixed8(t.LastPx),
+			Quantity:  utils.Fixed8(t.LastQty),
+			Height:    height,
+			Timestamp: timestamp,
+		}
+		buyFill, sellFill := fill, fill
+		buyFill.CounterpartOrderId = t.Sid
+		sellFill.CounterpartOrderId = t.Bid
+		c.pending = append(c.pending, pendingOrderFill{t.Bid, i, buyFill}, pendingOrderFill{t.Sid, i, sellFill})
+	}
+}
+
+// commit adds the collected fills with the ids of their trades. The fills of an order are all of its own symbol,
+// so they are collected in the order of execution.
+func (c *orderFillsCache) commit(height int64, offsets tradeIdOffsets) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.cache == nil {
+		return
+	}
+	for _, p := range c.pending {
+		p.TradeId = offsets.tradeId(height, p.Symbol, p.index)
+		var fills []store.OrderFill
+		if existing, ok := c.cache.Get(p.orderId); ok {
+			fills = existing.([]store.OrderFill)
+		}
+		c.cache.Add(p.orderId, append(fills, p.OrderFill))
+	}
+	c.pending = nil
+}
+
+func (c *orderFillsCache) get(id string) ([]store.OrderFill, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.cache == nil {
+		return nil, false
+	}
+	existing, ok := c.cache.Peek(id)
+	if !ok {
+		return nil, false
+	}
+	return existing.([]store.OrderFill), true
+}
+
+// SetOrderFillsCacheSize sets the max number of orders whose fills are kept for the dex/orderfills query,
+// the fills are not kept if size is not positive.
+func (kp *DexKeeper) SetOrderFillsCacheSize(size int) {
+	kp.orderFills.resize(size)
+}
+
+func (kp *DexKeeper) OrderFillsEnabled() bool {
+	return kp.orderFills.enabled()
+}
+
+// GetOrderFills returns the fills of the order in the order of execution,
+// false is returned if the order has no fill or has been evicted from the cache.
+func (kp *DexKeeper) GetOrderFills(id string) ([]store.OrderFill, bool) {
+	return kp.orderFills.get(id)
+}
@@ -0,0 +1,60 @@
+package order
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var scheduledHaltKey = []byte("dexScheduledHalt")
+
+// The events of the halt schedule, published with the execution results of the block.
+const (
+	HaltStarted = "HaltStarted"
+	HaltEnded   = "HaltEnded"
+)
+
+// ScheduledHalt is the state of the matching by the halt schedule of the dex params, Height is the height at which
+// the state is changed last time.
+type ScheduledHalt struct {
+	Halted bool
+	Height int64
+}
+
+// UpdateScheduledHalt halts or resumes the matching by the halt schedule against the time of the block header.
+// It's called in every block before the matching. The halts are recorded in the same history as the pauses by
+// governance, so the matching stays paused as long as either of them is in effect.
+func (kp *DexKeeper) UpdateScheduledHalt(ctx sdk.Context) {
+	params := kp.GetParams(ctx)
+	halted := params.IsHaltedAt(ctx.BlockHeader().Time.Unix())
+	if halted == kp.getScheduledHalt(ctx).Halted {
+		return
+	}
+	kp.logger.Info("update scheduled halt of matching", "halted", halted, "height", ctx.BlockHeight())
+	kp.setScheduledHalt(ctx, ScheduledHalt{Halted: halted, Height: ctx.BlockHeight()})
+	kp.updateMatchingPauses(ctx, ctx.BlockHeight(), halted || params.MatchingPaused)
+}
+
+// GetScheduledHaltEvent returns HaltStarted or HaltEnded if the halt schedule takes effect at the height,
+// otherwise an empty string.
+func (kp *DexKeeper) GetScheduledHaltEvent(ctx sdk.Context, height int64) string {
+	state := kp.getScheduledHalt(ctx)
+	if state.Height != height {
+		return ""
+	}
+	if state.Halted {
+		return HaltStarted
+	}
+	return HaltEnded
+}
+
+func (kp *DexKeeper) getScheduledHalt(ctx sdk.Context) ScheduledHalt {
+	var state ScheduledHalt
+	bz := ctx.KVStore(kp.storeKey).Get(scheduledHaltKey)
+	if bz != nil {
+		kp.cdc.MustUnmarshalBinaryBare(bz, &state)
+	}
+	return state
+}
+
+func (kp *DexKeeper) setScheduledHalt(ctx sdk.Context, state ScheduledHalt) {
+	ctx.KVStore(kp.storeKey).Set(scheduledHaltKey, kp.cdc.MustMarshalBinaryBare(state))
+}
@@ -0,0 +1,83 @@
+package order
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/utils"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// DefaultOrderBookHistoryRetention is the default number of the most recent breathe block snapshots
+// served by the dex/history query.
+const DefaultOrderBookHistoryRetention = 30
+
+// SetOrderBookHistoryRetention sets the number of the most recent breathe block snapshots served by the
+// dex/history query, the query is disabled if retention is not positive. The order book is saved into the
+// dex store at every breathe block for recovery, so the snapshots are part of the state and the retention
+// only bounds the days being served, the older order books are expected to be looked up in the archival export.
+func (kp *DexKeeper) SetOrderBookHistoryRetention(retention int) {
+	kp.orderBookHistoryRetention = retention
+}
+
+// DefaultMaxOrderBookDepth is the default max number of price levels served by the dex/orderbook query.
+const DefaultMaxOrderBookDepth = 1000
+
+// SetMaxOrderBookDepth caps the number of price levels served by the dex/orderbook query regardless of the
+// requested levels, the default cap is used if depth is not positive.
+func (kp *DexKeeper) SetMaxOrderBookDepth(depth int) {
+	if depth <= 0 {
+		depth = DefaultMaxOrderBookDepth
+	}
+	kp.maxOrderBookDepth = depth
+}
+
+func (kp *DexKeeper) OrderBookHistoryEnabled() bool {
+	return kp.orderBookHistoryRetention > 0
+}
+
+// GetHistoricalOrderBook returns the order book of the symbol saved in the breathe block of the day.
+func (kp *DexKeeper) GetHistoricalOrderBook(ctx sdk.Context, date time.Time, symbol string) (store.HistoricalOrderBook, error) {
+	day := date.Unix() / utils.SecondsPerDay
+	today := ctx.BlockHeader().Time.Unix() / utils.SecondsPerDay
+	if day > today || day <= today-int64(kp.orderBookHistoryRetention) {
+		return store.HistoricalOrderBook{}, fmt.Errorf(
+			"order book of %s is out of the retention of the last %d breathe blocks, please look it up in the archival export",
+			date.Format(store.HistoricalOrderBookDateFormat), kp.orderBookHistoryRetention)
+	}
+	height, ok := kp.getBreatheBlockHeightOfDay(ctx, day)
+	if !ok {
+		return store.HistoricalOrderBook{}, fmt.Errorf("breathe block not found for %s", date.Format(store.HistoricalOrderBookDateFormat))
+	}
+	var snapshot OrderBookSnapshot
+	ok, err := loadCompressed(kp.cdc, genOrderBookSnapshotKey(height, symbol), ctx.KVStore(kp.storeKey), &snapshot)
+	if err != nil {
+		return store.HistoricalOrderBook{}, fmt.Errorf("failed to load order book snapshot of %s at height %d: %v", symbol, height, err)
+	}
+	if !ok {
+		return store.HistoricalOrderBook{}, fmt.Errorf("order book snapshot of %s not found at height %d", symbol, height)
+	}
+
+	size := len(snapshot.Buys)
+	if len(snapshot.Sells) > size {
+		size = len(snapshot.Sells)
+	}
+	levels := make([]store.OrderBookLevel, size)
+	for i, pl := range snapshot.Buys {
+		levels[i].BuyPrice = utils.Fixed8(pl.Price)
+		levels[i].BuyQty = utils.Fixed8(pl.TotalLeavesQty())
+	}
+	for i, pl := range snapshot.Sells {
+		levels[i].SellPrice = utils.Fixed8(pl.Price)
+		levels[i].SellQty = utils.Fixed8(pl.TotalLeavesQty())
+	}
+	return store.HistoricalOrderBook{
+		Symbol:         symbol,
+		Date:           date.Format(store.HistoricalOrderBookDateFormat),
+		Height:         height,
+		LastTradePrice: utils.Fixed8(snapshot.LastTradePrice),
+		Levels:         levels,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package order
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// MaxImbalanceTrendBlocks is the number of the recent blocks of which the order book imbalances are kept.
+const MaxImbalanceTrendBlocks = 100
+
+// imbalanceTrends keeps the order book imbalances of the symbols as of the recent blocks, up to
+// MaxImbalanceTrendBlocks per symbol. The imbalances are taken from the order books at the end of the blocks and
+// never stored, so a node only has the ones of the blocks it executed since it started, and only if it serves
+// them, see EnableImbalanceTrends.
+type imbalanceTrends struct {
+	mtx     sync.Mutex
+	enabled bool
+	series  map[string][]store.BookImbalance // symbol -> imbalances of the recent blocks, the oldest first
+}
+
+func newImbalanceTrends() *imbalanceTrends {
+	return &imbalanceTrends{series: make(map[string][]store.BookImbalance)}
+}
+
+func (t *imbalanceTrends) record(symbol string, imbalance store.BookImbalance) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	series := append(t.series[symbol], imbalance)
+	if len(series) > MaxImbalanceTrendBlocks {
+		// copied rather than resliced, so that the dropped ones are not held by the backing array
+		series = append([]store.BookImbalance(nil), series[len(series)-MaxImbalanceTrendBlocks:]...)
+	}
+	t.series[symbol] = series
+}
+
+func (t *imbalanceTrends) delete(symbol string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	delete(t.series, symbol)
+}
+
+// last returns the latest `blocks` imbalances of the symbol, the oldest first.
+func (t *imbalanceTrends) last(symbol string, blocks int) []store.BookImbalance {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	series := t.series[symbol]
+	if blocks < len(series) {
+		series = series[len(series)-blocks:]
+	}
+	return append(make([]store.BookImbalance, 0, len(series)), series...)
+}
+
+// calcBookImbalance returns the imbalance of the bid and ask quantities, i.e. (bid - ask) / (bid + ask) in
+// [-1, 1], which is 0 if both sides are empty.
+func calcBookImbalance(bidQty, askQty int64) int64 {
+	if bidQty+askQty == 0 {
+		return 0
+	}
+	var imbalance big.Int
+	imbalance.Mul(big.NewInt(bidQty-askQty), big.NewInt(1e8))
+	return imbalance.Quo(&imbalance, big.NewInt(bidQty+askQty)).Int64()
+}
+
+// EnableImbalanceTrends starts recording the order book imbalances of each block for the dex/imbalancetrend query.
+func (kp *DexKeeper) EnableImbalanceTrends() {
+	kp.imbalanceTrends.enabled = true
+}
+
+// ImbalanceTrendsEnabled tells whether the order book imbalances are recorded.
+func (kp *DexKeeper) ImbalanceTrendsEnabled() bool {
+	return kp.imbalanceTrends.enabled
+}
+
+// RecordImbalances keeps the imbalances of the order books of all the pairs as of the height, over the price levels
+// of the pairs published, it's called once per block in the EndBlocker after the matching. It walks all the order
+// books, so it does nothing unless the imbalances are served.
+func (kp *DexKeeper) RecordImbalances(height int64) {
+	if !kp.imbalanceTrends.enabled {
+		return
+	}
+	for symbol, engine := range kp.engines {
+		var bidQty, askQty int64
+		engine.Book.ShowDepth(kp.GetPublicationDepth(symbol, DefaultPublicationDepth), func(p *me.PriceLevel, levelIndex int) {
+			bidQty += p.TotalLeavesQty()
+		}, func(p *me.PriceLevel, levelIndex int) {
+			askQty += p.TotalLeavesQty()
+		})
+		kp.imbalanceTrends.record(symbol, store.BookImbalance{
+			Height:    height,
+			BidQty:    utils.Fixed8(bidQty),
+			AskQty:    utils.Fixed8(askQty),
+			Imbalance: utils.Fixed8(calcBookImbalance(bidQty, askQty)),
+		})
+	}
+}
+
+// GetImbalanceTrend returns the imbalances of the order book of the pair as of the latest `blocks` blocks, the oldest
+// first. The imbalances are only known since the node started, so there may be fewer of them.
+func (kp *DexKeeper) GetImbalanceTrend(symbol string, blocks int) store.ImbalanceTrend {
+	return store.ImbalanceTrend{Symbol: symbol, Imbalances: kp.imbalanceTrends.last(symbol, blocks)}
+}
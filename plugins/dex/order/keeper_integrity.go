@@ -0,0 +1,120 @@
+package order
+
+import (
+	"bytes"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+// LockedMismatch is an account whose locked coins differ from the coins reserved by its open orders.
+type LockedMismatch struct {
+	Address  sdk.AccAddress
+	Reserved sdk.Coins
+	Locked   sdk.Coins
+}
+
+// lockRoundingTolerance is the difference allowed between the coins locked by an open order and the ones it
+// reserves, as the notional of the buy orders placed by the earlier versions may be rounded differently.
+const lockRoundingTolerance = 1
+
+// reservedByOpenOrders sums the order locks of each account, see GetOrderLocks, along with the number of the open
+// orders reserving each asset, which the rounding tolerance is in proportion to.
+func (kp *DexKeeper) reservedByOpenOrders() (reserved map[string]sdk.Coins, orderNums map[string]map[string]int64) {
+	reserved = make(map[string]sdk.Coins)
+	orderNums = make(map[string]map[string]int64)
+	kp.iterateOrderLocks(func(ord *OrderInfo, coin sdk.Coin) {
+		addr := string(ord.Sender.Bytes())
+		reserved[addr] = reserved[addr].Plus(sdk.Coins{coin})
+		if _, ok := orderNums[addr]; !ok {
+			orderNums[addr] = make(map[string]int64)
+		}
+		orderNums[addr][coin.Denom]++
+	})
+	return reserved, orderNums
+}
+
+// iterateOrderLocks calls the handler with the coin reserved by each open order.
+func (kp *DexKeeper) iterateOrderLocks(handler func(ord *OrderInfo, coin sdk.Coin)) {
+	for _, orderKeeper := range kp.OrderKeepers {
+		for symbol, orders := range orderKeeper.getAllOrders() {
+			baseAsset, quoteAsset, err := utils.TradingPair2Assets(symbol)
+			if err != nil {
+				kp.logger.Error("invalid symbol of open orders", "symbol", symbol)
+				continue
+			}
+			for _, ord := range orders {
+				handler(ord, reservedByOrder(ord, baseAsset, quoteAsset))
+			}
+		}
+	}
+}
+
+// reservedByOrder returns the coin reserved by an open order.
+func reservedByOrder(ord *OrderInfo, baseAsset, quoteAsset string) sdk.Coin {
+	if ord.Side == Side.BUY {
+		return sdk.NewCoin(quoteAsset, utils.CalBigNotionalInt64(ord.Price, ord.Quantity)-
+			utils.CalBigNotionalInt64(ord.Price, ord.CumQty))
+	}
+	return sdk.NewCoin(baseAsset, ord.Quantity-ord.CumQty)
+}
+
+// GetOrderLocks returns the coins reserved by each open order of the account sorted by the order id, they add
+// up to the locked coins of the account.
+func (kp *DexKeeper) GetOrderLocks(addr sdk.AccAddress) []types.AccountLock {
+	locks := make([]types.AccountLock, 0)
+	kp.iterateOrderLocks(func(ord *OrderInfo, coin sdk.Coin) {
+		if ord.Sender.Equals(addr) {
+			locks = append(locks, types.AccountLock{Reason: types.LockReasonOrder, Ref: ord.Id, Amount: sdk.Coins{coin}})
+		}
+	})
+	sort.Slice(locks, func(i, j int) bool { return locks[i].Ref < locks[j].Ref })
+	return locks
+}
+
+// VerifyLockedCoins checks the locked coins of all the accounts against the order locks, and returns the mismatched
+// accounts sorted by the address. The coins are only locked by the orders, so they should be the same but for the
+// rounding of the notional, lockRoundingTolerance per order. It goes through all the accounts and open orders, so
+// it's expensive.
+func (kp *DexKeeper) VerifyLockedCoins(ctx sdk.Context) []LockedMismatch {
+	reserved, orderNums := kp.reservedByOpenOrders()
+	var mismatches []LockedMismatch
+	kp.am.IterateAccounts(ctx, func(acc sdk.Account) bool {
+		namedAcc, ok := acc.(types.NamedAccount)
+		if !ok {
+			return false
+		}
+		key := string(acc.GetAddress().Bytes())
+		locked, expected := namedAcc.GetLockedCoins(), reserved[key]
+		delete(reserved, key)
+		if !withinRoundingTolerance(locked, expected, orderNums[key]) {
+			mismatches = append(mismatches, LockedMismatch{acc.GetAddress(), expected, locked})
+		}
+		return false
+	})
+	// the open orders of the accounts not found
+	for addr, expected := range reserved {
+		mismatches = append(mismatches, LockedMismatch{Address: sdk.AccAddress(addr), Reserved: expected})
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		return bytes.Compare(mismatches[i].Address, mismatches[j].Address) < 0
+	})
+	return mismatches
+}
+
+// withinRoundingTolerance checks the locked coins differ from the reserved ones by the rounding of the orders only.
+func withinRoundingTolerance(locked, reserved sdk.Coins, orderNums map[string]int64) bool {
+	for _, coin := range locked.Minus(reserved) {
+		diff := coin.Amount
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > orderNums[coin.Denom]*lockRoundingTolerance {
+			return false
+		}
+	}
+	return true
+}
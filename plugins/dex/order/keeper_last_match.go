@@ -0,0 +1,57 @@
+package order
+
+import (
+	"sync"
+
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// lastMatches keeps the height of the last block in which each symbol produced trades. The heights are only seen by
+// matching the blocks, so a restarted node doesn't know them for the symbols not traded since. The symbols are
+// deleted once delisted, so it's bounded by the listed pairs.
+type lastMatches struct {
+	mtx     sync.Mutex
+	heights map[string]int64
+}
+
+func newLastMatches() *lastMatches {
+	return &lastMatches{heights: make(map[string]int64)}
+}
+
+func (m *lastMatches) record(height int64, symbols []string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	for _, symbol := range symbols {
+		m.heights[symbol] = height
+	}
+}
+
+func (m *lastMatches) get(symbol string) int64 {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.heights[symbol]
+}
+
+func (m *lastMatches) delete(symbol string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	delete(m.heights, symbol)
+}
+
+// RecordLastMatches keeps the height as the last match height of the pairs that produced trades at it, it's called
+// once per block in the EndBlocker after the matching.
+func (kp *DexKeeper) RecordLastMatches(height int64) {
+	symbols := make([]string, 0)
+	for symbol := range kp.engines {
+		if trades, _ := kp.GetLastTrades(height, symbol); len(trades) > 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+	kp.lastMatches.record(height, symbols)
+}
+
+// GetLastMatch returns the height of the last block in which the pair produced trades, which is 0 if it never did
+// since the node started.
+func (kp *DexKeeper) GetLastMatch(symbol string) store.LastMatch {
+	return store.LastMatch{Symbol: symbol, Height: kp.lastMatches.get(symbol)}
+}
@@ -1,6 +1,9 @@
 package order
 
 import (
+	"fmt"
+	"sort"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/fees"
 
@@ -8,8 +11,10 @@ import (
 	"github.com/bnb-chain/node/common/utils"
 )
 
-func (kp *DexKeeper) SelectSymbolsToMatch(height int64, matchAllSymbols bool) []string {
+// SelectSymbolsToMatch selects the symbols to match in the block of the height and the time in unix nanoseconds.
+func (kp *DexKeeper) SelectSymbolsToMatch(height, timestamp int64, matchAllSymbols bool) []string {
 	var symbolsToMatch []string
+	kp.roundDeferredSymbols = nil
 	if sdk.IsUpgradeHeight(upgrade.BEP8) {
 		symbolsToMatch = make([]string, 0, len(kp.engines))
 		for symbol := range kp.engines {
@@ -22,32 +27,75 @@ func (kp *DexKeeper) SelectSymbolsToMatch(height int64, matchAllSymbols bool) []
 				symbolsToMatch = append(symbolsToMatch, orderKeeper.selectSymbolsToMatch(height, matchAllSymbols)...)
 			}
 		}
+		symbolsToMatch = kp.deferBySession(timestamp, symbolsToMatch)
+		if !matchAllSymbols {
+			symbolsToMatch = kp.deferByMatchInterval(height, symbolsToMatch)
+			symbolsToMatch = kp.deferByBatchSize(height, symbolsToMatch)
+		} else {
+			kp.clearBatchDeferrals(height)
+		}
 	}
 	return symbolsToMatch
 }
 
+// deferAllSymbols defers all the symbols with round orders in a block in which the matching is paused.
+func (kp *DexKeeper) deferAllSymbols(height int64) {
+	kp.roundDeferredSymbols = nil
+	for _, orderKeeper := range kp.OrderKeepers {
+		if orderKeeper.supportUpgradeVersion() {
+			kp.roundDeferredSymbols = append(kp.roundDeferredSymbols, orderKeeper.selectSymbolsToMatch(height, true)...)
+		}
+	}
+}
+
+// getDeferredIOCSymbols returns the deferred symbols with IOC orders in this round. The round orders of the deferred
+// symbols are kept for a later matching, but their IOC orders are expired in their own block, see expireRoundIOCOrders.
+func (kp *DexKeeper) getDeferredIOCSymbols() []string {
+	var symbols []string
+	for _, symbol := range kp.roundDeferredSymbols {
+		if len(kp.mustGetOrderKeeper(symbol).getRoundIOCOrdersForPair(symbol)) != 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
 func (kp *DexKeeper) MatchAndAllocateSymbols(ctx sdk.Context, postAlloTransHandler TransferHandler, matchAllSymbols bool) {
 	kp.logger.Debug("Start Matching for all...", "height", ctx.BlockHeader().Height)
 	blockHeader := ctx.BlockHeader()
 	timestamp := blockHeader.Time.UnixNano()
 
-	symbolsToMatch := kp.SelectSymbolsToMatch(blockHeader.Height, matchAllSymbols)
+	var symbolsToMatch []string
+	if kp.IsMatchingPaused(ctx, blockHeader.Height) {
+		// orders of this round are kept and will be matched once the matching is resumed
+		kp.logger.Info("Matching is paused by governance", "height", blockHeader.Height)
+		kp.deferAllSymbols(blockHeader.Height)
+	} else {
+		if kp.IsMatchingResumedAt(ctx, blockHeader.Height) {
+			// match all the orders accumulated during the pause
+			matchAllSymbols = true
+		}
+		symbolsToMatch = kp.SelectSymbolsToMatch(blockHeader.Height, timestamp, matchAllSymbols)
+	}
 
 	kp.logger.Info("symbols to match", "symbols", symbolsToMatch)
 	var tradeOuts []chan Transfer
-	if len(symbolsToMatch) == 0 {
+	if len(symbolsToMatch) == 0 && len(kp.getDeferredIOCSymbols()) == 0 {
 		kp.logger.Info("No order comes in for the block")
 	} else {
+		kp.roundMakerRebate = newMakerRebate(kp.GetParams(ctx))
 		tradeOuts = kp.matchAndDistributeTrades(true, blockHeader.Height, timestamp, symbolsToMatch)
 	}
 
 	totalFee := kp.allocateAndCalcFee(ctx, tradeOuts, postAlloTransHandler)
+	kp.roundMakerRebate = nil
 	fees.Pool.AddAndCommitFee("MATCH", totalFee)
+	kp.commitTrades(blockHeader.Height)
 	kp.ClearAfterMatch()
 }
 
 // please note if distributeTrade this method will work in async mode, otherwise in sync mode.
-// Always run kp.SelectSymbolsToMatch(ctx.BlockHeader().Height, matchAllSymbols) before matchAndDistributeTrades
+// Always run kp.SelectSymbolsToMatch(ctx.BlockHeader().Height, timestamp, matchAllSymbols) before matchAndDistributeTrades
 func (kp *DexKeeper) matchAndDistributeTrades(distributeTrade bool, height, timestamp int64, symbolsToMatch []string) []chan Transfer {
 	concurrency := 1 << kp.poolSize
 	tradeOuts := make([]chan Transfer, concurrency)
@@ -64,18 +112,26 @@ func (kp *DexKeeper) matchAndDistributeTrades(distributeTrade bool, height, time
 		}
 	}
 
+	deferredIOCSymbols := kp.getDeferredIOCSymbols()
 	symbolCh := make(chan string, concurrency)
+	expiryCh := make(chan string, concurrency)
 	producer := func() {
 		for _, symbol := range symbolsToMatch {
 			symbolCh <- symbol
 		}
 		close(symbolCh)
+		for _, symbol := range deferredIOCSymbols {
+			expiryCh <- symbol
+		}
+		close(expiryCh)
 	}
 	matchWorker := func() {
 		for symbol := range symbolCh {
 			kp.matchAndDistributeTradesForSymbol(symbol, height, timestamp, distributeTrade, tradeOuts)
 		}
-
+		for symbol := range expiryCh {
+			kp.expireRoundIOCOrders(symbol, distributeTrade, tradeOuts)
+		}
 	}
 
 	if distributeTrade {
@@ -90,35 +146,117 @@ func (kp *DexKeeper) matchAndDistributeTrades(distributeTrade bool, height, time
 	return tradeOuts
 }
 
+// GetRoundOrdersNum returns the number of the orders accrued for the matching of all the pairs.
+func (kp *DexKeeper) GetRoundOrdersNum() int {
+	num := 0
+	for i := range kp.OrderKeepers {
+		num += kp.OrderKeepers[i].getRoundOrdersNum()
+	}
+	return num
+}
+
 func (kp *DexKeeper) MatchSymbols(height, timestamp int64, matchAllSymbols bool) {
-	symbolsToMatch := kp.SelectSymbolsToMatch(height, matchAllSymbols)
+	kp.matchSymbols(kp.SelectSymbolsToMatch(height, timestamp, matchAllSymbols), height, timestamp)
+}
+
+// MatchSymbolsPaused is MatchSymbols of a block in which the matching is paused, only the IOC orders are expired.
+func (kp *DexKeeper) MatchSymbolsPaused(height, timestamp int64) {
+	kp.deferAllSymbols(height)
+	kp.matchSymbols(nil, height, timestamp)
+}
+
+func (kp *DexKeeper) matchSymbols(symbolsToMatch []string, height, timestamp int64) {
 	kp.logger.Debug("symbols to match", "symbols", symbolsToMatch)
 
-	if len(symbolsToMatch) == 0 {
+	if len(symbolsToMatch) == 0 && len(kp.getDeferredIOCSymbols()) == 0 {
 		kp.logger.Info("No order comes in for the block")
 	} else {
 		kp.matchAndDistributeTrades(false, height, timestamp, symbolsToMatch)
 	}
 
+	// the fees are not charged here, so the trades are kept without fees
+	kp.commitTrades(height)
 	kp.ClearAfterMatch()
 }
 
+// TradeId is the id of the trade of the index among all the trades matched at the height, see GetMatchedSymbols.
+func TradeId(height int64, index int) string {
+	return fmt.Sprintf("%d-%d", height, index)
+}
+
+// GetMatchedSymbols returns the symbols with trades matched at the height in alphabetical order, which is the order
+// the trades of the block are numbered in.
+func (kp *DexKeeper) GetMatchedSymbols(height int64) []string {
+	var symbols []string
+	for symbol, engine := range kp.engines {
+		if engine.LastMatchHeight == height && len(engine.Trades) != 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// tradeIdOffsets is the index of the first trade of each symbol among all the trades matched in a block.
+type tradeIdOffsets map[string]int
+
+func (kp *DexKeeper) getTradeIdOffsets(height int64) tradeIdOffsets {
+	offsets := make(tradeIdOffsets)
+	index := 0
+	for _, symbol := range kp.GetMatchedSymbols(height) {
+		offsets[symbol] = index
+		index += len(kp.engines[symbol].Trades)
+	}
+	return offsets
+}
+
+// tradeId is the id of the trade of the index among the trades of the symbol matched at the height.
+func (o tradeIdOffsets) tradeId(height int64, symbol string, index int) string {
+	return TradeId(height, o[symbol]+index)
+}
+
+// commitTrades adds the trades matched at the height to the caches of the queries, the trades can only be
+// numbered once all the symbols are matched.
+func (kp *DexKeeper) commitTrades(height int64) {
+	offsets := kp.getTradeIdOffsets(height)
+	kp.orderFills.commit(height, offsets)
+	kp.accountTrades.commit(height, offsets)
+}
+
 func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, timestamp int64, distributeTrade bool,
 	tradeOuts []chan Transfer) {
 	engine := kp.engines[symbol]
 	concurrency := len(tradeOuts)
 	orderKeeper := kp.mustGetOrderKeeper(symbol)
 	orders := orderKeeper.getAllOrdersForPair(symbol)
+	// the book the makers are resting at is gone after the match
+	var mid int64
+	rebate := kp.roundMakerRebate
+	if distributeTrade && rebate != nil {
+		var ok bool
+		if mid, ok = restingMid(engine, len(orderKeeper.getRoundOrdersForPair(symbol))); !ok {
+			rebate = nil
+		}
+	}
 	// please note there is no logging in matching, expecting to see the order book details
 	// from the exchange's order book stream.
 	if engine.Match(height) {
 		kp.logger.Debug("Match finish:", "symbol", symbol, "lastTradePrice", engine.LastTradePrice)
+		kp.orderFills.collectTrades(symbol, height, timestamp, engine.Trades)
+		kp.accountTrades.collectTrades(symbol, height, timestamp, engine.Trades, orders)
+		kp.traderVolumes.addTrades(symbol, engine.Trades, orders)
+		kp.symbolActivities.addTrades(symbol, engine.Trades)
+		kp.bookUpdates.add(symbol, 0, 0, int64(len(engine.Trades)))
+		kp.addTradesOpenInterest(symbol, engine.Trades)
 		for i := range engine.Trades {
 			t := &engine.Trades[i]
 			updateOrderMsg(orders[t.Bid], t.BuyCumQty, height, timestamp)
 			updateOrderMsg(orders[t.Sid], t.SellCumQty, height, timestamp)
 			if distributeTrade {
 				t1, t2 := TransferFromTrade(t, symbol, orders)
+				if rebate != nil {
+					rebate.markMakerRebate(t, mid, orders, &t1, &t2)
+				}
 				c := channelHash(t1.accAddress, concurrency)
 				tradeOuts[c] <- t1
 				c = channelHash(t2.accAddress, concurrency)
@@ -127,7 +265,7 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 		}
 		droppedIds := engine.DropFilledOrder() //delete from order books
 		for _, id := range droppedIds {
-			delete(orders, id) //delete from order cache
+			kp.dropOrder(orders, id) //delete from order cache
 		}
 		kp.logger.Debug("Drop filled orders", "total", droppedIds)
 	} else {
@@ -141,9 +279,10 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 		thisRoundIds := orderKeeper.getRoundOrdersForPair(symbol)
 		for _, id := range thisRoundIds {
 			msg := orders[id]
-			delete(orders, id)
+			kp.dropOrder(orders, id)
 			if ord, err := engine.Book.RemoveOrder(id, msg.Side, msg.Price); err == nil {
 				kp.logger.Info("Removed due to match failure", "ordID", msg.Id)
+				kp.addOpenInterest(symbol, msg.Side, -ord.LeavesQty())
 				if distributeTrade {
 					c := channelHash(msg.Sender, concurrency)
 					tradeOuts[c] <- TransferFromCanceled(ord, *msg, true)
@@ -160,12 +299,23 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 		}
 		return // no need to handle IOC
 	}
+	kp.expireRoundIOCOrders(symbol, distributeTrade, tradeOuts)
+}
+
+// expireRoundIOCOrders removes the IOC orders of this round of the symbol that are left in the order book, whether
+// the symbol is matched or deferred in this block.
+func (kp *DexKeeper) expireRoundIOCOrders(symbol string, distributeTrade bool, tradeOuts []chan Transfer) {
+	engine := kp.engines[symbol]
+	concurrency := len(tradeOuts)
+	orderKeeper := kp.mustGetOrderKeeper(symbol)
+	orders := orderKeeper.getAllOrdersForPair(symbol)
 	iocIDs := orderKeeper.getRoundIOCOrdersForPair(symbol)
 	for _, id := range iocIDs {
 		if msg, ok := orders[id]; ok {
-			delete(orders, id)
+			kp.dropOrder(orders, id)
 			if ord, err := engine.Book.RemoveOrder(id, msg.Side, msg.Price); err == nil {
 				kp.logger.Debug("Removed unclosed IOC order", "ordID", msg.Id)
+				kp.addOpenInterest(symbol, msg.Side, -ord.LeavesQty())
 				if distributeTrade {
 					c := channelHash(msg.Sender, concurrency)
 					tradeOuts[c] <- TransferFromExpired(ord, *msg)
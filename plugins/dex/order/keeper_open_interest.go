@@ -0,0 +1,87 @@
+package order
+
+import (
+	"sync/atomic"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// openInterest is the resting quantities of each side of the order book of a pair. It's kept up to date as the
+// orders are added, filled and removed, and the pairs are matched and expired concurrently, so it's updated
+// atomically.
+type openInterest struct {
+	bidQty int64
+	askQty int64
+}
+
+func (oi *openInterest) add(side int8, qty int64) {
+	if side == me.BUYSIDE {
+		atomic.AddInt64(&oi.bidQty, qty)
+	} else {
+		atomic.AddInt64(&oi.askQty, qty)
+	}
+}
+
+// addOpenInterest adds the quantity to the resting quantity of the side of the pair, it's negative for the orders
+// filled or removed.
+func (kp *DexKeeper) addOpenInterest(symbol string, side int8, qty int64) {
+	if oi, ok := kp.pairOpenInterests[symbol]; ok {
+		oi.add(side, qty)
+	}
+}
+
+// addTradesOpenInterest takes the quantities of the trades of the pair off both sides.
+func (kp *DexKeeper) addTradesOpenInterest(symbol string, trades []me.Trade) {
+	oi, ok := kp.pairOpenInterests[symbol]
+	if !ok {
+		return
+	}
+	for i := range trades {
+		oi.add(me.BUYSIDE, -trades[i].LastQty)
+		oi.add(me.SELLSIDE, -trades[i].LastQty)
+	}
+}
+
+// resetOpenInterest sums the resting quantities of the order book of the pair from scratch, it's only needed once
+// the order book is loaded as a whole, e.g. from the snapshot.
+func (kp *DexKeeper) resetOpenInterest(symbol string) {
+	engine, ok := kp.engines[symbol]
+	if !ok {
+		return
+	}
+	var bidQty, askQty int64
+	engine.Book.UpdateForEachPriceLevel(me.BUYSIDE, func(p *me.PriceLevel, levelIndex int) {
+		bidQty += p.TotalLeavesQty()
+	})
+	engine.Book.UpdateForEachPriceLevel(me.SELLSIDE, func(p *me.PriceLevel, levelIndex int) {
+		askQty += p.TotalLeavesQty()
+	})
+	kp.pairOpenInterests[symbol] = &openInterest{bidQty: bidQty, askQty: askQty}
+}
+
+// RefreshOpenInterests takes the open interests of all the listed pairs as of the height, which the queries are
+// served from. It's called once per block in the EndBlocker. The result is replaced as a whole, so it only has
+// the pairs listed at the height.
+func (kp *DexKeeper) RefreshOpenInterests(height int64) {
+	openInterests := make(map[string]store.OpenInterest, len(kp.pairOpenInterests))
+	for symbol, oi := range kp.pairOpenInterests {
+		openInterests[symbol] = store.OpenInterest{
+			Symbol: symbol,
+			Height: height,
+			BidQty: utils.Fixed8(atomic.LoadInt64(&oi.bidQty)),
+			AskQty: utils.Fixed8(atomic.LoadInt64(&oi.askQty)),
+		}
+	}
+	kp.openInterests.Store(openInterests)
+}
+
+// GetOpenInterest returns the open interest of the pair as of the last block, ok is false if the pair was not
+// listed then.
+func (kp *DexKeeper) GetOpenInterest(symbol string) (openInterest store.OpenInterest, ok bool) {
+	if openInterests, loaded := kp.openInterests.Load().(map[string]store.OpenInterest); loaded {
+		openInterest, ok = openInterests[symbol]
+	}
+	return openInterest, ok
+}
@@ -0,0 +1,50 @@
+package order
+
+import (
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// orderRecord is a new order of a tx delivered in the block, with the result of the dex handler.
+type orderRecord struct {
+	msg    NewOrderMsg
+	txHash string
+	res    sdk.Result
+}
+
+// orderRecorder collects the new orders of the txs delivered in a block for publication, they never affect the state.
+// The order of a tx is pending until the tx is delivered, and it's kept only if the tx succeeded, or only if it
+// failed, per keepSucceeded. The txs are delivered one by one, so there is one pending order at most.
+type orderRecorder struct {
+	enabled       bool
+	keepSucceeded bool
+	pending       *orderRecord
+	records       []orderRecord
+}
+
+func (r *orderRecorder) record(ctx sdk.Context, msg NewOrderMsg, res sdk.Result) {
+	if !r.enabled || !ctx.IsDeliverTx() || res.IsOK() != r.keepSucceeded {
+		return
+	}
+	txHash, _ := ctx.Value(baseapp.TxHashKey).(string)
+	r.pending = &orderRecord{msg, txHash, res}
+}
+
+func (r *orderRecorder) commit(succeeded bool) {
+	if r.pending != nil && succeeded == r.keepSucceeded {
+		r.records = append(r.records, *r.pending)
+	}
+	r.pending = nil
+}
+
+func (r *orderRecorder) clear() {
+	r.pending = nil
+	r.records = nil
+}
+
+// CommitOrderRecords keeps or drops the order of the tx just delivered for publication per the result of the tx,
+// it's called once the tx is delivered.
+func (kp *DexKeeper) CommitOrderRecords(succeeded bool) {
+	kp.orderRejections.commit(succeeded)
+	kp.orderAcks.commit(succeeded)
+}
@@ -0,0 +1,227 @@
+package order
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/plugins/dex/store"
+	dexTypes "github.com/bnb-chain/node/plugins/dex/types"
+	dexUtils "github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+// updatePairParams puts a trading pair params change of a passed fee change proposal in effect. It's called by the
+// param hub at the end of the breathe blocks, so the params of the pairs stay the same in all the blocks replayed
+// since the last breathe block when the order book is recovered.
+func (kp *DexKeeper) updatePairParams(ctx sdk.Context, change dexTypes.PairParamsChange) {
+	baseAsset, quoteAsset, err := dexUtils.TradingPair2Assets(change.Symbol)
+	if err != nil {
+		kp.logger.Error("failed to apply pair params change", "symbol", change.Symbol, "err", err.Error())
+		return
+	}
+	// the pair may have been delisted or never listed, it's not checked when the proposal is submitted
+	pair, err := kp.PairMapper.GetTradingPair(ctx, baseAsset, quoteAsset)
+	if err != nil {
+		kp.logger.Error("failed to apply pair params change", "symbol", change.Symbol, "err", err.Error())
+		return
+	}
+	updated := change.Apply(pair)
+	symbol := strings.ToUpper(updated.GetSymbol())
+	_, hasEngine := kp.engines[symbol]
+	if change.ChangesSizes() && hasEngine {
+		if err := kp.checkRestingOrdersOnSizes(symbol, updated.TickSize.ToInt64(), updated.LotSize.ToInt64()); err != nil {
+			kp.logger.Error("failed to apply pair params change", "symbol", change.Symbol, "err", err.Error())
+			return
+		}
+	}
+	if err := kp.PairMapper.AddTradingPair(ctx, updated); err != nil {
+		kp.logger.Error("failed to apply pair params change", "symbol", change.Symbol, "err", err.Error())
+		return
+	}
+	if hasEngine {
+		kp.pairMatchIntervals[symbol] = updated.MatchInterval
+		kp.setPairPublicationDepth(symbol, updated.PublicationDepth)
+		kp.setPairGTCTTLDays(symbol, updated.GTCTTLDays)
+		kp.setPairSession(symbol, updated.GetSession())
+		if change.ChangesSizes() {
+			kp.UpdateLotSize(symbol, updated.LotSize.ToInt64())
+			kp.recordPairSizesUpdate(updated)
+		}
+	}
+	kp.logger.Info("apply pair params change", "pair", updated)
+}
+
+// checkRestingOrdersOnSizes makes sure all the resting orders of the pair are on the tick size and lot size,
+// otherwise their prices or remaining quantities would never fit a match after the sizes are changed.
+func (kp *DexKeeper) checkRestingOrdersOnSizes(symbol string, tickSize, lotSize int64) error {
+	var offTick, offLot int
+	for _, ord := range kp.mustGetOrderKeeper(symbol).getAllOrdersForPair(symbol) {
+		if ord.Price%tickSize != 0 {
+			offTick++
+		}
+		if (ord.Quantity-ord.CumQty)%lotSize != 0 {
+			offLot++
+		}
+	}
+	if offTick != 0 || offLot != 0 {
+		return fmt.Errorf("%d resting orders of %s are not on the tick size %d and %d are not on the lot size %d",
+			offTick, symbol, tickSize, offLot, lotSize)
+	}
+	return nil
+}
+
+// recordPairSizesUpdate keeps the pairs whose tick size or lot size is changed in the breathe block, for
+// publication usage.
+func (kp *DexKeeper) recordPairSizesUpdate(pair dexTypes.TradingPair) {
+	if kp.CollectOrderInfoForPublish {
+		kp.pairSizesUpdates = append(kp.pairSizesUpdates, pair)
+	}
+}
+
+// GetPairSizesUpdates returns the pairs whose tick size or lot size is changed in the current block.
+func (kp *DexKeeper) GetPairSizesUpdates() []dexTypes.TradingPair {
+	return kp.pairSizesUpdates
+}
+
+func (kp *DexKeeper) ClearPairSizesUpdates() {
+	kp.pairSizesUpdates = nil
+}
+
+// deferByMatchInterval drops the symbols that are not due to match at the height according to their
+// match intervals. Their round orders are kept and matched together in a later block, except the IOC orders
+// which are expired in their own block.
+func (kp *DexKeeper) deferByMatchInterval(height int64, symbols []string) []string {
+	selected := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		if interval := kp.pairMatchIntervals[symbol]; interval > 1 && height%interval != 0 {
+			kp.roundDeferredSymbols = append(kp.roundDeferredSymbols, symbol)
+			continue
+		}
+		selected = append(selected, symbol)
+	}
+	return selected
+}
+
+// GetEngineConfig returns the configuration of the match engines being in effect.
+func (kp *DexKeeper) GetEngineConfig(ctx sdk.Context) store.EngineConfig {
+	params := kp.GetParams(ctx)
+	intervals := make([]store.PairMatchInterval, 0)
+	for symbol, interval := range kp.pairMatchIntervals {
+		if interval > 1 {
+			intervals = append(intervals, store.PairMatchInterval{Symbol: symbol, MatchInterval: interval})
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].Symbol < intervals[j].Symbol
+	})
+	return store.EngineConfig{
+		Concurrency:                 kp.poolSize,
+		MatchingPaused:              params.MatchingPaused,
+		MaxOrdersPerAccountPerBlock: params.MaxOrdersPerAccountPerBlock,
+		MaxTotalOrders:              params.MaxTotalOrders,
+		MinBalanceToPlaceOrder:      params.MinBalanceToPlaceOrder,
+		PairMatchIntervals:          intervals,
+		MatchBatchSize:              kp.matchBatchSize,
+		HaltSchedule:                params.HaltSchedule,
+		ScheduledHalt:               kp.getScheduledHalt(ctx).Halted,
+	}
+}
+
+type deferredRoundOrders struct {
+	symbol string
+	orders []string
+}
+
+// getDeferredRoundOrders returns the round orders of the deferred symbols left in the order books, i.e. without the
+// IOC orders expired in this round.
+func (kp *DexKeeper) getDeferredRoundOrders() []deferredRoundOrders {
+	deferred := make([]deferredRoundOrders, 0, len(kp.roundDeferredSymbols))
+	for _, symbol := range kp.roundDeferredSymbols {
+		orderKeeper := kp.mustGetOrderKeeper(symbol)
+		allOrders := orderKeeper.getAllOrdersForPair(symbol)
+		roundOrders := orderKeeper.getRoundOrdersForPair(symbol)
+		orders := make([]string, 0, len(roundOrders))
+		for _, id := range roundOrders {
+			if _, ok := allOrders[id]; ok {
+				orders = append(orders, id)
+			}
+		}
+		deferred = append(deferred, deferredRoundOrders{symbol: symbol, orders: orders})
+	}
+	return deferred
+}
+
+func (kp *DexKeeper) restoreDeferredRoundOrders(deferred []deferredRoundOrders) {
+	for _, d := range deferred {
+		kp.mustGetOrderKeeper(d.symbol).restoreRoundOrdersForPair(d.symbol, d.orders)
+	}
+	kp.roundDeferredSymbols = nil
+}
+
+// DefaultPublicationDepth is the number of the price levels per side of the order books published, for the pairs
+// without their own publication depth.
+const DefaultPublicationDepth = 100
+
+// setPairPublicationDepth keeps the publication depth of the pair, the pairs of the global depth are not kept.
+func (kp *DexKeeper) setPairPublicationDepth(symbol string, depth int64) {
+	if depth <= 0 {
+		delete(kp.pairPublicationDepths, symbol)
+		return
+	}
+	kp.pairPublicationDepths[symbol] = int(depth)
+}
+
+// GetPublicationDepth returns the number of the price levels per side of the order book of the pair published, i.e.
+// the publication depth of the pair if it's set, otherwise the global depth.
+func (kp *DexKeeper) GetPublicationDepth(symbol string, globalDepth int) int {
+	if depth, ok := kp.pairPublicationDepths[symbol]; ok {
+		return depth
+	}
+	return globalDepth
+}
+
+// setPairGTCTTLDays keeps the GTC TTL of the pair, the pairs of the global TTL are not kept.
+func (kp *DexKeeper) setPairGTCTTLDays(symbol string, days int64) {
+	if days <= 0 {
+		delete(kp.pairGTCTTLDays, symbol)
+		return
+	}
+	kp.pairGTCTTLDays[symbol] = int(days)
+}
+
+// GetGTCTTLDays returns the number of days after which the GTE orders of the pair are expired, i.e. the GTC TTL of
+// the pair if it's set, otherwise the global TTL.
+func (kp *DexKeeper) GetGTCTTLDays(symbol string) int {
+	if days, ok := kp.pairGTCTTLDays[symbol]; ok {
+		return days
+	}
+	return effectiveDays
+}
+
+// getPairExpireHeights returns the heights before which the orders of the pairs with their own GTC TTLs are expired
+// by the breathe block at blockTime. If there was no breathe block on the day of the TTL days ago, e.g. the chain was
+// halted through the day, the nearest earlier breathe block is taken. The expire height of a pair is -1 if there is
+// no breathe block since forceExpireDays days ago either, i.e. the chain is younger than the TTL, so that none of
+// its orders is expired but the force expiry.
+func (kp *DexKeeper) getPairExpireHeights(ctx sdk.Context, blockTime time.Time) map[string]int64 {
+	heights := make(map[string]int64, len(kp.pairGTCTTLDays))
+	byDays := make(map[int]int64)
+	for symbol, days := range kp.pairGTCTTLDays {
+		height, ok := byDays[days]
+		if !ok {
+			height = -1
+			for daysBack := days; daysBack <= forceExpireDays; daysBack++ {
+				if h, err := kp.GetBreatheBlockHeight(ctx, blockTime, daysBack); err == nil {
+					height = h
+					break
+				}
+			}
+			byDays[days] = height
+		}
+		heights[symbol] = height
+	}
+	return heights
+}
@@ -0,0 +1,123 @@
+package order
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	dexTypes "github.com/bnb-chain/node/plugins/dex/types"
+)
+
+var (
+	paramsKey         = []byte("dexParams")
+	matchingPausesKey = []byte("dexMatchingPauses")
+)
+
+// MatchingPause is the range of heights [From, To) in which the matching is paused,
+// To is 0 if the matching has not been resumed yet.
+type MatchingPause struct {
+	From int64
+	To   int64
+}
+
+func (kp *DexKeeper) GetParams(ctx sdk.Context) dexTypes.DexParams {
+	bz := ctx.KVStore(kp.storeKey).Get(paramsKey)
+	if bz == nil {
+		return dexTypes.DefaultDexParams()
+	}
+	var params dexTypes.DexParams
+	kp.cdc.MustUnmarshalBinaryBare(bz, &params)
+	return params
+}
+
+func (kp *DexKeeper) setParams(ctx sdk.Context, params dexTypes.DexParams) {
+	ctx.KVStore(kp.storeKey).Set(paramsKey, kp.cdc.MustMarshalBinaryBare(params))
+}
+
+// updateParams puts the dex params of a passed fee change proposal in effect. It's called by the param hub at the
+// end of the breathe blocks, i.e. after the matching, so the changes take effect since the next block.
+func (kp *DexKeeper) updateParams(ctx sdk.Context, updated dexTypes.DexParams) {
+	old := kp.GetParams(ctx)
+	kp.logger.Info("apply dex params change", "params", updated)
+	kp.setParams(ctx, updated)
+	if old.MatchingPaused != updated.MatchingPaused {
+		kp.updateMatchingPauses(ctx, ctx.BlockHeight()+1, updated.MatchingPaused || kp.getScheduledHalt(ctx).Halted)
+	}
+	if old.MatchBatchSize != updated.MatchBatchSize {
+		kp.UpdateMatchBatchSize(ctx)
+	}
+}
+
+// updateMatchingPauses starts or ends the ongoing pause at the height, the matching is paused either by governance
+// or by the halt schedule.
+func (kp *DexKeeper) updateMatchingPauses(ctx sdk.Context, height int64, paused bool) {
+	pauses := kp.getMatchingPauses(ctx)
+	ongoing := len(pauses) > 0 && pauses[len(pauses)-1].To == 0
+	if paused == ongoing {
+		return
+	}
+	if paused {
+		pauses = append(pauses, MatchingPause{From: height})
+	} else {
+		pauses[len(pauses)-1].To = height
+	}
+	kp.setMatchingPauses(ctx, pauses)
+}
+
+// IsMatchingPaused tells whether the matching is paused at the height. The history of the pauses is kept
+// since the last breathe block so that the order book can be replayed in the same way.
+func (kp *DexKeeper) IsMatchingPaused(ctx sdk.Context, height int64) bool {
+	_, paused := kp.getMatchingPausedFrom(ctx, height)
+	return paused
+}
+
+// IsMatchingResumedAt tells whether the matching is resumed at the height.
+func (kp *DexKeeper) IsMatchingResumedAt(ctx sdk.Context, height int64) bool {
+	for _, pause := range kp.getMatchingPauses(ctx) {
+		if pause.To == height {
+			return true
+		}
+	}
+	return false
+}
+
+func (kp *DexKeeper) getMatchingPausedFrom(ctx sdk.Context, height int64) (int64, bool) {
+	for _, pause := range kp.getMatchingPauses(ctx) {
+		if pause.From <= height && (pause.To == 0 || height < pause.To) {
+			return pause.From, true
+		}
+	}
+	return 0, false
+}
+
+// PruneMatchingPauses drops the pauses that ended before the height, they are not needed to replay
+// the blocks after the breathe block at the height.
+func (kp *DexKeeper) PruneMatchingPauses(ctx sdk.Context, height int64) {
+	pauses := kp.getMatchingPauses(ctx)
+	kept := make([]MatchingPause, 0, len(pauses))
+	for _, pause := range pauses {
+		if pause.To == 0 || pause.To > height {
+			kept = append(kept, pause)
+		}
+	}
+	if len(kept) != len(pauses) {
+		kp.setMatchingPauses(ctx, kept)
+	}
+}
+
+func (kp *DexKeeper) getMatchingPauses(ctx sdk.Context) []MatchingPause {
+	bz := ctx.KVStore(kp.storeKey).Get(matchingPausesKey)
+	if bz == nil {
+		return nil
+	}
+	var pauses []MatchingPause
+	kp.cdc.MustUnmarshalBinaryBare(bz, &pauses)
+	return pauses
+}
+
+func (kp *DexKeeper) setMatchingPauses(ctx sdk.Context, pauses []MatchingPause) {
+	store := ctx.KVStore(kp.storeKey)
+	if len(pauses) == 0 {
+		store.Delete(matchingPausesKey)
+		return
+	}
+	store.Set(matchingPausesKey, kp.cdc.MustMarshalBinaryBare(pauses))
+}
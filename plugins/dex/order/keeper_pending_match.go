@@ -0,0 +1,70 @@
+package order
+
+import (
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// SimulateMatch runs the matching of the pair at the height against a copy of its current order book, with the
+// orders accrued but not matched yet, and returns the would-be trades and the top of the book after the matching.
+// The unfilled IOC orders are dropped from the book after the matching as the real one does. The order book and
+// the match engine of the pair are left untouched.
+func (kp *DexKeeper) SimulateMatch(pair string, height int64) store.PendingMatch {
+	res := store.PendingMatch{Symbol: pair, Height: height, Trades: []store.PendingTrade{}}
+	eng, ok := kp.engines[pair]
+	if !ok {
+		return res
+	}
+	engine := copyMatchEng(pair, eng)
+	res.LastPrice = utils.Fixed8(engine.LastTradePrice)
+	if !engine.Match(height) {
+		// the real matching cancels the round orders instead
+		return res
+	}
+	for _, trade := range engine.Trades {
+		res.Trades = append(res.Trades, store.PendingTrade{
+			BuyOrderId:  trade.Bid,
+			SellOrderId: trade.Sid,
+			Price:       utils.Fixed8(trade.LastPx),
+			Quantity:    utils.Fixed8(trade.LastQty),
+		})
+	}
+	res.LastPrice = utils.Fixed8(engine.LastTradePrice)
+	engine.DropFilledOrder()
+	orderKeeper := kp.mustGetOrderKeeper(pair)
+	orders := orderKeeper.getAllOrdersForPair(pair)
+	for _, id := range orderKeeper.getRoundIOCOrdersForPair(pair) {
+		if msg, ok := orders[id]; ok {
+			_, _ = engine.Book.RemoveOrder(id, msg.Side, msg.Price)
+		}
+	}
+	engine.Book.ShowDepth(1, func(p *me.PriceLevel, levelIndex int) {
+		res.BestBid, res.BestBidQty = utils.Fixed8(p.Price), utils.Fixed8(p.TotalLeavesQty())
+	}, func(p *me.PriceLevel, levelIndex int) {
+		res.BestAsk, res.BestAskQty = utils.Fixed8(p.Price), utils.Fixed8(p.TotalLeavesQty())
+	})
+	return res
+}
+
+// copyMatchEng copies the match engine along with its order book, the orders are copied too so that matching the
+// copy doesn't fill the orders of the original one.
+func copyMatchEng(pair string, eng *me.MatchEng) *me.MatchEng {
+	engine := me.NewMatchEng(pair, eng.LastTradePrice, eng.LotSize, eng.PriceLimitPct)
+	engine.LastMatchHeight = eng.LastMatchHeight
+	buys, sells := eng.Book.GetAllLevels()
+	for _, levels := range []struct {
+		side   int8
+		levels []me.PriceLevel
+	}{{me.BUYSIDE, buys}, {me.SELLSIDE, sells}} {
+		for i := range levels.levels {
+			pl := me.PriceLevel{
+				Price:  levels.levels[i].Price,
+				Orders: append([]me.OrderPart(nil), levels.levels[i].Orders...),
+			}
+			// the levels are copied from a valid book, so they always fit in
+			_ = engine.Book.InsertPriceLevel(&pl, levels.side)
+		}
+	}
+	return engine
+}
@@ -0,0 +1,132 @@
+package order
+
+import (
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	dexTypes "github.com/bnb-chain/node/plugins/dex/types"
+)
+
+// makerRebate is the maker rebate params in effect for the matching of a block.
+type makerRebate struct {
+	rate      int64 // share of the taker's fee credited to the maker, in 1/dexTypes.MakerRebateRateBase
+	maxSpread int64 // max distance of the maker's price from the mid, in bps of the mid
+}
+
+// newMakerRebate returns nil if the maker rebate is disabled by the params.
+func newMakerRebate(params dexTypes.DexParams) *makerRebate {
+	if params.MakerRebateRate <= 0 {
+		return nil
+	}
+	return &makerRebate{rate: params.MakerRebateRate, maxSpread: params.MakerRebateMaxSpread}
+}
+
+// restingMid returns the mid of the best bid and ask of the resting orders of the book, i.e. the orders placed
+// before the last match, which are the quotes the makers of the coming match are resting at. The levels above
+// the best resting ones only have the orders of this round, so at most numRoundOrders levels are skipped.
+// ok is false if either side has no resting order.
+func restingMid(engine *me.MatchEng, numRoundOrders int) (mid int64, ok bool) {
+	bestResting := func(levels []me.PriceLevel) (int64, bool) {
+		for _, level := range levels {
+			for _, ord := range level.Orders {
+				if ord.Time <= engine.LastMatchHeight {
+					return level.Price, true
+				}
+			}
+		}
+		return 0, false
+	}
+	buys := make([]me.PriceLevel, 0, numRoundOrders+1)
+	sells := make([]me.PriceLevel, 0, numRoundOrders+1)
+	engine.Book.ShowDepth(numRoundOrders+1, func(p *me.PriceLevel, levelIndex int) {
+		buys = append(buys, *p)
+	}, func(p *me.PriceLevel, levelIndex int) {
+		sells = append(sells, *p)
+	})
+	bid, hasBid := bestResting(buys)
+	ask, hasAsk := bestResting(sells)
+	if !hasBid || !hasAsk {
+		return 0, false
+	}
+	return bid + (ask-bid)/2, true
+}
+
+// isEligible tells whether a maker resting at the price is within the max spread of the mid.
+func (r *makerRebate) isEligible(price, mid int64) bool {
+	distance := price - mid
+	if distance < 0 {
+		distance = -distance
+	}
+	// distance / mid * 10000 <= maxSpread
+	var lhs, rhs big.Int
+	lhs.Mul(big.NewInt(distance), big.NewInt(10000))
+	rhs.Mul(big.NewInt(mid), big.NewInt(r.maxSpread))
+	return lhs.Cmp(&rhs) <= 0
+}
+
+// markMakerRebate marks the transfer of the maker of the trade as eligible for the rebate, if the trade has a
+// maker resting within the max spread of the mid.
+func (r *makerRebate) markMakerRebate(trade *me.Trade, mid int64, orders map[string]*OrderInfo, sellTran, buyTran *Transfer) {
+	var maker *Transfer
+	switch trade.TickType {
+	case me.SellTaker:
+		maker = buyTran
+	case me.BuyTaker:
+		maker = sellTran
+	default:
+		// both orders are placed since the last match
+		return
+	}
+	if ord, ok := orders[maker.Oid]; ok && r.isEligible(ord.Price, mid) {
+		maker.makerRebate = true
+	}
+}
+
+// payMakerRebates waives the fee of each eligible maker on the trade and credits it a share of the taker's fee
+// instead, so the fee of the maker on the trade is negative, i.e. the rebate. Both are paid out of the collected
+// fees, it returns the total.
+func (kp *DexKeeper) payMakerRebates(ctx sdk.Context, rate int64, makers []*Transfer, feesPerAcc map[string]*sdk.Fee) sdk.Coins {
+	var total sdk.Coins
+	for _, tran := range makers {
+		if tran.feeExempt {
+			continue
+		}
+		makerFee, takerFee := tran.Trade.SellerFee, tran.Trade.BuyerFee
+		if tran.IsBuyer() {
+			makerFee, takerFee = takerFee, makerFee
+		}
+		if takerFee == nil || makerFee == nil {
+			continue
+		}
+		var rebate sdk.Coins
+		for _, fee := range takerFee.Tokens {
+			var amount big.Int
+			amount.Mul(big.NewInt(fee.Amount), big.NewInt(rate))
+			amount.Quo(&amount, big.NewInt(dexTypes.MakerRebateRateBase))
+			if amount.Int64() > 0 {
+				rebate = append(rebate, sdk.NewCoin(fee.Denom, amount.Int64()))
+			}
+		}
+		if rebate.IsZero() {
+			continue
+		}
+		refund := makerFee.Tokens.Plus(rebate)
+
+		acc := kp.am.GetAccount(ctx, tran.accAddress)
+		_ = acc.SetCoins(acc.GetCoins().Plus(refund))
+		kp.am.SetAccount(ctx, acc)
+
+		*makerFee = sdk.NewFee(rebate.Negative(), makerFee.Type)
+		tran.Fee = *makerFee
+		addrStr := string(tran.accAddress.Bytes())
+		if fees, ok := feesPerAcc[addrStr]; ok {
+			fees.Tokens = fees.Tokens.Minus(refund)
+		} else {
+			feesPerAcc[addrStr] = &sdk.Fee{Tokens: refund.Negative(), Type: sdk.FeeForProposer}
+		}
+		total = total.Plus(refund)
+	}
+	return total
+}
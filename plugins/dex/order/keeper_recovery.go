@@ -62,6 +62,23 @@ func compressAndSave(snapshot interface{}, cdc *wire.Codec, key string, kv sdk.K
 	return nil
 }
 
+// loadCompressed reads the snapshot saved by compressAndSave, ok is false if there is nothing under the key.
+func loadCompressed(cdc *wire.Codec, key string, kv sdk.KVStore, snapshot interface{}) (ok bool, err error) {
+	bz := kv.Get([]byte(key))
+	if bz == nil {
+		return false, nil
+	}
+	r, err := zlib.NewReader(bytes.NewBuffer(bz))
+	if err != nil {
+		return true, err
+	}
+	var bw bytes.Buffer
+	// the copy error is ignored like in LoadOrderBookSnapshot: utils.Compress returns the bytes before the zlib
+	// trailer is written, so the copy always ends with an unexpected EOF, and a broken snapshot fails to unmarshal
+	_, _ = io.Copy(&bw, r)
+	return true, cdc.UnmarshalBinaryLengthPrefixed(bw.Bytes(), snapshot)
+}
+
 func (kp *DexKeeper) SnapShotOrderBook(ctx sdk.Context, height int64) (effectedStoreKeys []string, err error) {
 	kvstore := ctx.KVStore(kp.storeKey)
 	effectedStoreKeys = make([]string, 0)
@@ -158,6 +175,7 @@ func (kp *DexKeeper) LoadOrderBookSnapshot(ctx sdk.Context, latestBlockHeight in
 				panic(fmt.Sprintf("failed to insert sell price level [%s], err: %v", key, err))
 			}
 		}
+		kp.resetOpenInterest(symbol)
 		eng.LastTradePrice = ob.LastTradePrice
 		if sdk.IsUpgrade(upgrade.BEP8) {
 			eng.LastMatchHeight = ob.LastMatchHeight
@@ -184,17 +202,30 @@ func (kp *DexKeeper) LoadOrderBookSnapshot(ctx sdk.Context, latestBlockHeight in
 	if err != nil {
 		panic(fmt.Sprintf("failed to unmarshal snapshort for active orders [%s]", key))
 	}
+	pausedFrom, paused := kp.getMatchingPausedFrom(ctx, height)
 	for _, m := range ao.Orders {
 		orderHolder := m
 		symbol := strings.ToUpper(m.Symbol)
 		kp.ReloadOrder(symbol, &orderHolder, height)
+		// the orders not matched yet, i.e. accumulated while the matching is paused or since the last matching of
+		// a deferred pair, are still to be matched, BEP2 orders of the snapshot height have been reloaded as round
+		// orders already.
+		waiting := paused && m.CreatedHeight >= pausedFrom
+		if eng, ok := kp.engines[symbol]; ok && m.CreatedHeight > eng.LastMatchHeight {
+			waiting = true
+		}
+		if waiting && (m.CreatedHeight != height || kp.GetPairType(symbol) == PairType.MINI) {
+			kp.mustGetOrderKeeper(symbol).addRoundOrders(symbol, orderHolder)
+		}
 	}
 	ctx.Logger().Info("Recovered active orders. Snapshot is fully loaded")
 	return height, nil
 }
 
+// replayOneBlocks replays the dex txs of the block against the order book. With forPublish, the order changes are
+// collected for publication as if the block were delivered, otherwise they are dropped as in the recovery.
 func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, stateDB dbm.DB, txDecoder sdk.TxDecoder,
-	height int64, timestamp time.Time) {
+	height int64, timestamp time.Time, matchingPaused, matchingResumed, forPublish bool) {
 	if block == nil {
 		logger.Error("No block is loaded. Ignore replay for orderbook")
 		return
@@ -235,14 +266,16 @@ func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, st
 					height, t,
 					height, t,
 					0, txHash.String(), txSource}
-				err := kp.AddOrder(orderInfo, true)
+				err := kp.AddOrder(orderInfo, !forPublish)
 				if err != nil {
 					logger.Error("Failed to replay NreOrderMsg", "err", err)
 				}
 				logger.Info("Added Order", "order", msg)
 			case CancelOrderMsg:
 				err := kp.RemoveOrder(msg.RefId, msg.Symbol, func(ord me.OrderPart) {
-					if kp.CollectOrderInfoForPublish {
+					if forPublish {
+						kp.UpdateOrderChangeSync(OrderChange{Id: msg.RefId, Tpe: Canceled}, msg.Symbol)
+					} else if kp.CollectOrderInfoForPublish {
 						bnclog.Debug("deleted order from order changes map", "orderId", msg.RefId, "isRecovery", true)
 						kp.RemoveOrderInfosForPub(msg.Symbol, msg.RefId)
 					}
@@ -258,8 +291,13 @@ func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, st
 			}
 		}
 	}
+	if matchingPaused {
+		logger.Info("replayed all tx. Matching is paused", "height", height)
+		kp.MatchSymbolsPaused(height, t)
+		return
+	}
 	logger.Info("replayed all tx. Starting match", "height", height)
-	kp.MatchSymbols(height, t, false) //no need to check result
+	kp.MatchSymbols(height, t, matchingResumed) //no need to check result
 }
 
 func (kp *DexKeeper) ReplayOrdersFromBlock(ctx sdk.Context, bc *tmstore.BlockStore, stateDb dbm.DB, lastHeight, breatheHeight int64,
@@ -268,11 +306,22 @@ func (kp *DexKeeper) ReplayOrdersFromBlock(ctx sdk.Context, bc *tmstore.BlockSto
 		block := bc.LoadBlock(i)
 		ctx.Logger().Info("Relaying block for order book", "height", i)
 		upgrade.Mgr.SetHeight(i)
-		kp.replayOneBlocks(ctx.Logger(), block, stateDb, txDecoder, i, block.Time)
+		kp.replayOneBlocks(ctx.Logger(), block, stateDb, txDecoder, i, block.Time,
+			kp.IsMatchingPaused(ctx, i), kp.IsMatchingResumedAt(ctx, i), false)
 	}
 	return nil
 }
 
+// ReplayBlockForPublish replays the dex txs of the block against the order book, and collects the order changes
+// for publication. The order changes caused by the transfers, e.g. the expiries of the IOC orders and the fees,
+// are not collected, as the accounts are not replayed. The expiries of the breathe blocks are not replayed either.
+func (kp *DexKeeper) ReplayBlockForPublish(ctx sdk.Context, block *tmtypes.Block, stateDb dbm.DB, txDecoder sdk.TxDecoder) {
+	height := block.Height
+	upgrade.Mgr.SetHeight(height)
+	kp.replayOneBlocks(ctx.Logger(), block, stateDb, txDecoder, height, block.Time,
+		kp.IsMatchingPaused(ctx, height), kp.IsMatchingResumedAt(ctx, height), true)
+}
+
 func (kp *DexKeeper) initOrderBook(ctx sdk.Context, blockInterval, daysBack int, blockStore *tmstore.BlockStore, stateDB dbm.DB, lastHeight int64, txDecoder sdk.TxDecoder) {
 	var timeOfLatestBlock time.Time
 	if lastHeight == 0 {
@@ -0,0 +1,56 @@
+package order
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// OrderRejection is a new order rejected by the dex handler in DeliverTx, together with the reason.
+// The rejections are only collected for publication, they never affect the state.
+type OrderRejection struct {
+	OrderId     string
+	Symbol      string
+	Sender      sdk.AccAddress
+	Side        int8
+	Price       int64
+	Quantity    int64
+	TimeInForce int8
+	TxHash      string
+	Code        sdk.ABCICodeType
+	Reason      string
+}
+
+// EnableRejectionPublish starts collecting the rejected orders of each block for publication.
+func (kp *DexKeeper) EnableRejectionPublish() {
+	kp.orderRejections.enabled = true
+}
+
+func (kp *DexKeeper) recordOrderRejection(ctx sdk.Context, msg NewOrderMsg, res sdk.Result) {
+	kp.orderRejections.record(ctx, msg, res)
+}
+
+// GetOrderRejections returns the orders rejected in the current block, in the order of the txs.
+func (kp *DexKeeper) GetOrderRejections() []OrderRejection {
+	if len(kp.orderRejections.records) == 0 {
+		return nil
+	}
+	rejections := make([]OrderRejection, len(kp.orderRejections.records))
+	for i, r := range kp.orderRejections.records {
+		rejections[i] = OrderRejection{
+			OrderId:     r.msg.Id,
+			Symbol:      r.msg.Symbol,
+			Sender:      r.msg.Sender,
+			Side:        r.msg.Side,
+			Price:       r.msg.Price,
+			Quantity:    r.msg.Quantity,
+			TimeInForce: r.msg.TimeInForce,
+			TxHash:      r.txHash,
+			Code:        r.res.Code,
+			Reason:      r.res.Log,
+		}
+	}
+	return rejections
+}
+
+func (kp *DexKeeper) ClearOrderRejections() {
+	kp.orderRejections.clear()
+}
@@ -0,0 +1,101 @@
+package order
+
+import (
+	"math"
+	"math/big"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/utils"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	dexUtils "github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+// DefaultAccountRiskMaxOrders bounds the number of open orders counted by GetAccountRisk.
+const DefaultAccountRiskMaxOrders = 10000
+
+// GetAccountRisk summarizes the open orders of the account per symbol: the number of orders and the notional
+// of their remaining quantity, both in the quote asset and in the native token as the reference asset.
+// At most maxOrders orders are counted, in the order of symbols and order ids, and Truncated is set if there are more.
+func (kp *DexKeeper) GetAccountRisk(addr sdk.AccAddress, maxOrders int) store.AccountRisk {
+	ordersPerSymbol := make(map[string][]*OrderInfo)
+	for _, orderKeeper := range kp.OrderKeepers {
+		for symbol, orders := range orderKeeper.getAllOrders() {
+			for _, ord := range orders {
+				if ord.Sender.Equals(addr) {
+					ordersPerSymbol[symbol] = append(ordersPerSymbol[symbol], ord)
+				}
+			}
+		}
+	}
+	symbols := make([]string, 0, len(ordersPerSymbol))
+	for symbol := range ordersPerSymbol {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	risk := store.AccountRisk{Address: addr, Symbols: make([]store.SymbolRisk, 0, len(symbols))}
+	var total big.Int
+	counted := 0
+	for _, symbol := range symbols {
+		orders := ordersPerSymbol[symbol]
+		sort.Slice(orders, func(i, j int) bool { return orders[i].Id < orders[j].Id })
+		if counted+len(orders) > maxOrders {
+			orders = orders[:maxOrders-counted]
+			risk.Truncated = true
+		}
+		if len(orders) == 0 {
+			break
+		}
+		counted += len(orders)
+
+		var notional big.Int
+		for _, ord := range orders {
+			notional.Add(&notional, dexUtils.CalBigNotional(ord.Price, ord.Quantity-ord.CumQty))
+		}
+		symbolRisk := store.SymbolRisk{
+			Symbol:     symbol,
+			OpenOrders: int64(len(orders)),
+			Notional:   cappedFixed8(&notional),
+		}
+		if refNotional, ok := kp.refNotional(symbol, &notional); ok {
+			symbolRisk.RefNotional = cappedFixed8(refNotional)
+			total.Add(&total, refNotional)
+		} else {
+			symbolRisk.RefNotionalUnavailable = true
+		}
+		risk.Symbols = append(risk.Symbols, symbolRisk)
+		risk.OpenOrders += symbolRisk.OpenOrders
+		if risk.Truncated {
+			break
+		}
+	}
+	risk.RefAsset = types.NativeTokenSymbol
+	risk.RefNotional = cappedFixed8(&total)
+	return risk
+}
+
+// refNotional converts the notional in the quote asset of the symbol into the native token
+// by the last trade price of the quote asset against the native token.
+func (kp *DexKeeper) refNotional(symbol string, notional *big.Int) (*big.Int, bool) {
+	_, quoteAsset, err := dexUtils.TradingPair2Assets(symbol)
+	if err != nil {
+		return nil, false
+	}
+	if quoteAsset == types.NativeTokenSymbol {
+		return notional, true
+	}
+	if !notional.IsInt64() {
+		return nil, false
+	}
+	return kp.FeeManager.calcNotional(quoteAsset, notional.Int64(), types.NativeTokenSymbol, kp.engines)
+}
+
+func cappedFixed8(amount *big.Int) utils.Fixed8 {
+	if !amount.IsInt64() {
+		return utils.Fixed8(math.MaxInt64)
+	}
+	return utils.Fixed8(amount.Int64())
+}
@@ -0,0 +1,96 @@
+package order
+
+import (
+	"sort"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	dexTypes "github.com/bnb-chain/node/plugins/dex/types"
+)
+
+// The events of the trading sessions of the pairs, published with the execution results of the block.
+const (
+	SessionOpened = "SessionOpened"
+	SessionClosed = "SessionClosed"
+)
+
+// SessionEvent is the opening or closing of the trading session of a pair in a block.
+type SessionEvent struct {
+	Symbol string
+	Event  string
+}
+
+// setPairSession keeps the trading session of the pair, the pairs trading all day are not kept.
+func (kp *DexKeeper) setPairSession(symbol string, session dexTypes.TradingSession) {
+	if session.IsAllDay() {
+		delete(kp.pairSessions, symbol)
+		return
+	}
+	kp.pairSessions[symbol] = session
+}
+
+// isPairInSession tells whether the pair takes new orders and is matched at the time in unix nanoseconds.
+// The time is always the one of the block header, so that it's the same on all the nodes.
+func (kp *DexKeeper) isPairInSession(symbol string, timestamp int64) bool {
+	session, ok := kp.pairSessions[symbol]
+	return !ok || session.IsOpenAt(time.Unix(0, timestamp).Unix())
+}
+
+// checkPairSession rejects the new orders of the pair outside its trading session, the cancels are always allowed.
+func (kp *DexKeeper) checkPairSession(ctx sdk.Context, symbol string) sdk.Error {
+	if kp.isPairInSession(symbol, ctx.BlockHeader().Time.UnixNano()) {
+		return nil
+	}
+	return dexTypes.ErrPairOutOfSession(symbol, kp.pairSessions[symbol])
+}
+
+// deferBySession drops the symbols outside their trading sessions at the time in unix nanoseconds. Their round
+// orders are kept and matched once the sessions open again, except the IOC orders which are expired in their own
+// block.
+func (kp *DexKeeper) deferBySession(timestamp int64, symbols []string) []string {
+	if len(kp.pairSessions) == 0 {
+		return symbols
+	}
+	selected := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		if !kp.isPairInSession(symbol, timestamp) {
+			kp.roundDeferredSymbols = append(kp.roundDeferredSymbols, symbol)
+			continue
+		}
+		selected = append(selected, symbol)
+	}
+	return selected
+}
+
+// UpdatePairSessions checks the trading sessions of the pairs against the time of the block header, and records
+// the sessions opened or closed since the last block for publication usage. The states are only kept in memory,
+// so no event is recorded for the first block after the node starts.
+func (kp *DexKeeper) UpdatePairSessions(ctx sdk.Context) {
+	timestamp := ctx.BlockHeader().Time.UnixNano()
+	for symbol := range kp.engines {
+		inSession := kp.isPairInSession(symbol, timestamp)
+		last, known := kp.pairsInSession[symbol]
+		kp.pairsInSession[symbol] = inSession
+		if !known || last == inSession || !kp.CollectOrderInfoForPublish {
+			continue
+		}
+		event := SessionClosed
+		if inSession {
+			event = SessionOpened
+		}
+		kp.sessionEvents = append(kp.sessionEvents, SessionEvent{Symbol: symbol, Event: event})
+	}
+	sort.Slice(kp.sessionEvents, func(i, j int) bool {
+		return kp.sessionEvents[i].Symbol < kp.sessionEvents[j].Symbol
+	})
+}
+
+// GetSessionEvents returns the trading sessions opened or closed in the current block, sorted by the symbol.
+func (kp *DexKeeper) GetSessionEvents() []SessionEvent {
+	return kp.sessionEvents
+}
+
+func (kp *DexKeeper) ClearSessionEvents() {
+	kp.sessionEvents = nil
+}
@@ -0,0 +1,47 @@
+package order
+
+import (
+	"math"
+	"sort"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// MaxOrderSizeBoundaries is the max number of the bucket boundaries of the dex/sizedist query.
+const MaxOrderSizeBoundaries = 32
+
+// DefaultOrderSizeBoundaries are the bucket boundaries of the dex/sizedist query if none is given, i.e. the orders
+// below 1, 10, 100, 1000, 10000 and the ones beyond.
+var DefaultOrderSizeBoundaries = []int64{1e8, 1e9, 1e10, 1e11, 1e12}
+
+// GetOrderSizeDistribution returns the histogram of the remaining quantities of the resting orders of the pair per
+// side, bucketed by the ascending boundaries. It's computed from the order book at the time of the call.
+func (kp *DexKeeper) GetOrderSizeDistribution(pair string, boundaries []int64) store.OrderSizeDistribution {
+	dist := store.OrderSizeDistribution{
+		Symbol:     pair,
+		Boundaries: make([]utils.Fixed8, len(boundaries)),
+		Bids:       make([]store.OrderSizeBucket, len(boundaries)+1),
+		Asks:       make([]store.OrderSizeBucket, len(boundaries)+1),
+	}
+	for i, boundary := range boundaries {
+		dist.Boundaries[i] = utils.Fixed8(boundary)
+	}
+	eng, ok := kp.engines[pair]
+	if !ok {
+		return dist
+	}
+	count := func(buckets []store.OrderSizeBucket) me.LevelIter {
+		return func(p *me.PriceLevel, levelIndex int) {
+			for i := range p.Orders {
+				qty := p.Orders[i].LeavesQty()
+				bucket := &buckets[sort.Search(len(boundaries), func(j int) bool { return boundaries[j] > qty })]
+				bucket.Orders++
+				bucket.Quantity += utils.Fixed8(qty)
+			}
+		}
+	}
+	eng.Book.ShowDepth(math.MaxInt32, count(dist.Bids), count(dist.Asks))
+	return dist
+}
@@ -0,0 +1,69 @@
+package order
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// EstimateSlippage walks the opposite side of the order book of the pair from the best price as an order of the
+// side and quantity would take it, and returns the average fill price against the best price. The order book is
+// only read, the orders of the current block not matched yet are not taken into account.
+func (kp *DexKeeper) EstimateSlippage(pair string, side int8, quantity int64) store.SlippageEstimate {
+	estimate := store.SlippageEstimate{
+		Symbol:      pair,
+		Side:        side,
+		Quantity:    utils.Fixed8(quantity),
+		SlippageBps: store.NoSpread,
+	}
+	eng, ok := kp.engines[pair]
+	if !ok {
+		estimate.InsufficientLiquidity = true
+		return estimate
+	}
+
+	var bestPrice, filled int64
+	var notional big.Int
+	take := func(p *me.PriceLevel, levelIndex int) {
+		if filled >= quantity {
+			return
+		}
+		if bestPrice == 0 {
+			bestPrice = p.Price
+		}
+		qty := p.TotalLeavesQty()
+		if qty > quantity-filled {
+			qty = quantity - filled
+		}
+		notional.Add(&notional, new(big.Int).Mul(big.NewInt(p.Price), big.NewInt(qty)))
+		filled += qty
+	}
+	skip := func(p *me.PriceLevel, levelIndex int) {}
+	if side == Side.BUY {
+		eng.Book.ShowDepth(math.MaxInt32, skip, take)
+	} else {
+		eng.Book.ShowDepth(math.MaxInt32, take, skip)
+	}
+
+	estimate.FilledQty = utils.Fixed8(filled)
+	estimate.InsufficientLiquidity = filled < quantity
+	if filled == 0 {
+		return estimate
+	}
+	avgPrice := notional.Quo(&notional, big.NewInt(filled)).Int64()
+	estimate.BestPrice = utils.Fixed8(bestPrice)
+	estimate.AvgPrice = utils.Fixed8(avgPrice)
+	// |avg - best| / best * 10000, the buys fill at or above the best ask, and the sells at or below the best bid
+	diff := avgPrice - bestPrice
+	if diff < 0 {
+		diff = -diff
+	}
+	var bps big.Int
+	bps.Mul(big.NewInt(diff), big.NewInt(10000))
+	bps.Quo(&bps, big.NewInt(bestPrice))
+	estimate.SlippageBps = bps.Int64()
+	return estimate
+}
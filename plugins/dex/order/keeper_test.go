@@ -1,6 +1,8 @@
 package order
 
 import (
+	"fmt"
+	"math"
 	"os"
 	"testing"
 	"time"
@@ -8,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/cosmos/cosmos-sdk/baseapp"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdkstore "github.com/cosmos/cosmos-sdk/store"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -33,6 +36,7 @@ import (
 	"github.com/bnb-chain/node/plugins/dex/store"
 	dextypes "github.com/bnb-chain/node/plugins/dex/types"
 	"github.com/bnb-chain/node/plugins/tokens"
+	tokenStore "github.com/bnb-chain/node/plugins/tokens/store"
 	"github.com/bnb-chain/node/wire"
 )
 
@@ -109,7 +113,7 @@ func TestKeeper_MatchFailure(t *testing.T) {
 	msg = NewNewOrderMsg(accAdd, "123462", Side.BUY, "XYZ-000_BNB", 99000, 15000000)
 	ord = OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}
 	keeper.AddOrder(ord, false)
-	symbolsToMatch := keeper.SelectSymbolsToMatch(ctx.BlockHeader().Height, false)
+	symbolsToMatch := keeper.SelectSymbolsToMatch(ctx.BlockHeader().Height, 0, false)
 	logger.Info("symbols to match", "symbols", symbolsToMatch)
 	tradeOuts := keeper.matchAndDistributeTrades(true, 42, 0, symbolsToMatch)
 	c := channelHash(accAdd, 4)
@@ -255,6 +259,42 @@ func TestKeeper_SnapShotOrderBook(t *testing.T) {
 	assert.Equal(int64(98000), sells[2].Price)
 }
 
+func TestKeeper_SnapShotOrderBookWithVersionedOrderIDs(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, logger)
+	accAdd, _ := MakeAddress()
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	keeper.AddEngine(tradingPair)
+
+	legacyID := GenerateVersionedOrderID(OrderIDVersionLegacy, 1, accAdd)
+	v1ID := GenerateVersionedOrderID(OrderIDVersion1, 2, accAdd)
+	msg := NewNewOrderMsg(accAdd, legacyID, Side.BUY, "XYZ-000_BNB", 102000, 3000000)
+	keeper.AddOrder(OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, v1ID, Side.SELL, "XYZ-000_BNB", 103000, 1000000)
+	keeper.AddOrder(OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}, false)
+
+	_, err := keeper.SnapShotOrderBook(ctx, 43)
+	assert.Nil(err)
+	keeper.MarkBreatheBlock(ctx, 43, time.Now())
+	keeper2 := MakeKeeper(cdc)
+	h, err := keeper2.LoadOrderBookSnapshot(ctx, 43, utils.Now(), 0, 10)
+	assert.Nil(err)
+	assert.Equal(int64(43), h)
+	assert.Equal(2, len(keeper2.GetAllOrdersForPair("XYZ-000_BNB")))
+	for _, id := range []string{legacyID, v1ID} {
+		_, ok := keeper2.OrderExists("XYZ-000_BNB", id)
+		assert.True(ok, id)
+		err = keeper2.RemoveOrder(id, "XYZ-000_BNB", nil)
+		assert.Nil(err, id)
+	}
+	assert.Equal(0, len(keeper2.GetAllOrdersForPair("XYZ-000_BNB")))
+}
+
 func TestKeeper_SnapShotAndLoadAfterMatch(t *testing.T) {
 	assert := assert.New(t)
 	cdc := MakeCodec()
@@ -303,6 +343,279 @@ func TestKeeper_SnapShotAndLoadAfterMatch(t *testing.T) {
 	assert.Equal(int64(102000), buys[0].Price)
 }
 
+func TestKeeper_SnapShotAndLoadDeferredPair(t *testing.T) {
+	setChainVersion()
+	defer resetChainVersion()
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, logger)
+	accAdd, _ := MakeAddress()
+	symbol := "XYZ-000_BNB"
+	pair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	// 01:00 - 02:00 UTC
+	pair.SessionOpen, pair.SessionClose = 3600, 7200
+	keeper.PairMapper.AddTradingPair(ctx, pair)
+	keeper.AddEngine(pair)
+
+	msg := NewNewOrderMsg(accAdd, "1", Side.BUY, symbol, 1e8, 1e8)
+	keeper.AddOrder(OrderInfo{msg, 41, 0, 41, 0, 0, "", 0}, false)
+	keeper.MatchSymbols(41, 3500e9, false)
+	msg = NewNewOrderMsg(accAdd, "2", Side.SELL, symbol, 1e8, 1e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	keeper.MatchSymbols(42, 3550e9, false)
+	// the pair is out of its session in the breathe block, so the orders are still not matched at the snapshot
+	keeper.MatchSymbols(43, 3599e9, true)
+	_, err := keeper.SnapShotOrderBook(ctx, 43)
+	assert.Nil(err)
+	keeper.MarkBreatheBlock(ctx, 43, time.Now())
+
+	// they are reloaded as round orders as they have never been matched, and matched once the session opens
+	keeper2 := MakeKeeper(cdc)
+	_, err = keeper2.LoadOrderBookSnapshot(ctx, 43, utils.Now(), 0, 10)
+	assert.Nil(err)
+	assert.ElementsMatch([]string{"1", "2"}, keeper2.mustGetOrderKeeper(symbol).getRoundOrdersForPair(symbol))
+	keeper2.MatchSymbols(44, 3600e9, false)
+	_, ok := keeper2.GetOrderFills("1")
+	assert.True(ok)
+	assert.Empty(keeper2.GetAllOrdersForPair(symbol))
+}
+
+func TestKeeper_OrderFills(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, logger)
+	accAdd, _ := MakeAddress()
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	keeper.AddEngine(tradingPair)
+
+	msg := NewNewOrderMsg(accAdd, "123456", Side.BUY, "XYZ-000_BNB", 102000, 3000000)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "123457", Side.SELL, "XYZ-000_BNB", 102000, 1000000)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	keeper.MatchSymbols(42, 84, false)
+	msg = NewNewOrderMsg(accAdd, "123458", Side.SELL, "XYZ-000_BNB", 102000, 500000)
+	keeper.AddOrder(OrderInfo{msg, 43, 0, 43, 0, 0, "", 0}, false)
+	keeper.MatchSymbols(43, 86, false)
+
+	fills, ok := keeper.GetOrderFills("123456")
+	assert.True(ok)
+	assert.Equal([]store.OrderFill{
+		{TradeId: "42-0", Symbol: "XYZ-000_BNB", Price: 102000, Quantity: 1000000, CounterpartOrderId: "123457", Height: 42, Timestamp: 84},
+		{TradeId: "43-0", Symbol: "XYZ-000_BNB", Price: 102000, Quantity: 500000, CounterpartOrderId: "123458", Height: 43, Timestamp: 86},
+	}, fills)
+	fills, ok = keeper.GetOrderFills("123458")
+	assert.True(ok)
+	assert.Equal([]store.OrderFill{
+		{TradeId: "43-0", Symbol: "XYZ-000_BNB", Price: 102000, Quantity: 500000, CounterpartOrderId: "123456", Height: 43, Timestamp: 86},
+	}, fills)
+	_, ok = keeper.GetOrderFills("123459")
+	assert.False(ok)
+
+	// the least recently filled orders are evicted
+	keeper.SetOrderFillsCacheSize(1)
+	msg = NewNewOrderMsg(accAdd, "123459", Side.SELL, "XYZ-000_BNB", 102000, 500000)
+	keeper.AddOrder(OrderInfo{msg, 44, 0, 44, 0, 0, "", 0}, false)
+	keeper.MatchSymbols(44, 88, false)
+	_, ok = keeper.GetOrderFills("123456")
+	assert.False(ok)
+	fills, ok = keeper.GetOrderFills("123459")
+	assert.True(ok)
+	assert.Equal(1, len(fills))
+
+	// the trades of a block are numbered across the symbols in alphabetical order, as they are published
+	keeper.SetOrderFillsCacheSize(10)
+	otherPair := dextypes.NewTradingPair("AAA-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, otherPair)
+	keeper.AddEngine(otherPair)
+	msg = NewNewOrderMsg(accAdd, "123460", Side.SELL, "XYZ-000_BNB", 102000, 500000)
+	keeper.AddOrder(OrderInfo{msg, 45, 0, 45, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "123461", Side.BUY, "AAA-000_BNB", 102000, 500000)
+	keeper.AddOrder(OrderInfo{msg, 45, 0, 45, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "123462", Side.SELL, "AAA-000_BNB", 102000, 500000)
+	keeper.AddOrder(OrderInfo{msg, 45, 0, 45, 0, 0, "", 0}, false)
+	keeper.MatchSymbols(45, 90, true)
+	assert.Equal([]string{"AAA-000_BNB", "XYZ-000_BNB"}, keeper.GetMatchedSymbols(45))
+	fills, ok = keeper.GetOrderFills("123462")
+	assert.True(ok)
+	assert.Equal("45-0", fills[0].TradeId)
+	fills, ok = keeper.GetOrderFills("123460")
+	assert.True(ok)
+	assert.Equal("45-1", fills[0].TradeId)
+
+	keeper.SetOrderFillsCacheSize(0)
+	assert.False(keeper.OrderFillsEnabled())
+	_, ok = keeper.GetOrderFills("123456")
+	assert.False(ok)
+}
+
+func TestKeeper_GetHistoricalOrderBook(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	breatheTime := time.Date(2020, 3, 1, 0, 0, 5, 0, time.UTC)
+	ctx := sdk.NewContext(cms, abci.Header{Time: breatheTime}, sdk.RunTxModeCheck, logger)
+	accAdd, _ := MakeAddress()
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	keeper.AddEngine(tradingPair)
+
+	msg := NewNewOrderMsg(accAdd, "123456", Side.BUY, "XYZ-000_BNB", 102000, 3000000)
+	keeper.AddOrder(OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "123457", Side.BUY, "XYZ-000_BNB", 101000, 1000000)
+	keeper.AddOrder(OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "123458", Side.SELL, "XYZ-000_BNB", 103000, 2000000)
+	keeper.AddOrder(OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}, false)
+	keeper.MarkBreatheBlock(ctx, 43, breatheTime)
+	_, err := keeper.SnapShotOrderBook(ctx, 43)
+	assert.Nil(err)
+
+	// the order book changes after the breathe block
+	keeper.RemoveOrder("123457", "XYZ-000_BNB", nil)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: breatheTime.AddDate(0, 0, 1)})
+
+	book, err := keeper.GetHistoricalOrderBook(ctx, time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), "XYZ-000_BNB")
+	assert.Nil(err)
+	assert.Equal(store.HistoricalOrderBook{
+		Symbol:         "XYZ-000_BNB",
+		Date:           "2020-03-01",
+		Height:         43,
+		LastTradePrice: 1e8, // the list price, as nothing is traded yet
+		Levels: []store.OrderBookLevel{
+			{BuyPrice: 102000, BuyQty: 3000000, SellPrice: 103000, SellQty: 2000000},
+			{BuyPrice: 101000, BuyQty: 1000000},
+		},
+	}, book)
+
+	_, err = keeper.GetHistoricalOrderBook(ctx, time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC), "XYZ-000_BNB")
+	assert.EqualError(err, "breathe block not found for 2020-03-02")
+	_, err = keeper.GetHistoricalOrderBook(ctx, time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), "ZCB-000_BNB")
+	assert.EqualError(err, "order book snapshot of ZCB-000_BNB not found at height 43")
+
+	// out of the retention
+	keeper.SetOrderBookHistoryRetention(1)
+	_, err = keeper.GetHistoricalOrderBook(ctx, time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), "XYZ-000_BNB")
+	assert.EqualError(err, "order book of 2020-03-01 is out of the retention of the last 1 breathe blocks, please look it up in the archival export")
+}
+
+func TestKeeper_PairMatchInterval(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	slowPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	slowPair.MatchInterval = 3
+	keeper.AddEngine(slowPair)
+	keeper.AddEngine(dextypes.NewTradingPair("ZCB-000", "BNB", 1e8))
+
+	addCrossedOrders := func(symbol, idPrefix string, height int64) {
+		msg := NewNewOrderMsg(accAdd, idPrefix+"1", Side.BUY, symbol, 1e8, 1e8)
+		keeper.AddOrder(OrderInfo{msg, height, 0, height, 0, 0, "", 0}, false)
+		msg = NewNewOrderMsg(accAdd, idPrefix+"2", Side.SELL, symbol, 1e8, 1e8)
+		keeper.AddOrder(OrderInfo{msg, height, 0, height, 0, 0, "", 0}, false)
+	}
+
+	addCrossedOrders("XYZ-000_BNB", "x1", 43)
+	addCrossedOrders("ZCB-000_BNB", "z1", 43)
+	keeper.MatchSymbols(43, 86, false)
+	// the pair with the match interval is not due at height 43
+	levels, pendingMatch := keeper.GetOrderBookLevels("XYZ-000_BNB", 1)
+	assert.Equal(int64(1e8), levels[0].BuyQty.ToInt64())
+	assert.True(pendingMatch)
+	_, pendingMatch = keeper.GetOrderBookLevels("ZCB-000_BNB", 1)
+	assert.False(pendingMatch)
+	_, ok := keeper.GetOrderFills("z11")
+	assert.True(ok)
+	_, ok = keeper.GetOrderFills("x11")
+	assert.False(ok)
+
+	// the IOC orders of the pair are expired in their own block even if the pair is not due
+	ioc := NewNewOrderMsg(accAdd, "x1ioc", Side.SELL, "XYZ-000_BNB", 1e8, 1e8)
+	ioc.TimeInForce = TimeInForce.IOC
+	keeper.AddOrder(OrderInfo{ioc, 44, 0, 44, 0, 0, "", 0}, false)
+	keeper.MatchSymbols(44, 88, false)
+	_, pendingMatch = keeper.GetOrderBookLevels("XYZ-000_BNB", 1)
+	assert.True(pendingMatch)
+	_, ok = keeper.OrderExists("XYZ-000_BNB", "x1ioc")
+	assert.False(ok)
+	assert.Len(keeper.mustGetOrderKeeper("XYZ-000_BNB").getRoundOrdersForPair("XYZ-000_BNB"), 2)
+	assert.Empty(keeper.mustGetOrderKeeper("XYZ-000_BNB").getRoundIOCOrdersForPair("XYZ-000_BNB"))
+
+	// the round orders of the previous blocks are matched once the pair is due
+	keeper.MatchSymbols(45, 90, false)
+	_, pendingMatch = keeper.GetOrderBookLevels("XYZ-000_BNB", 1)
+	assert.False(pendingMatch)
+	_, ok = keeper.GetOrderFills("x11")
+	assert.True(ok)
+
+	// all the pairs are matched in the breathe block
+	addCrossedOrders("XYZ-000_BNB", "x2", 46)
+	keeper.MatchSymbols(46, 92, true)
+	_, pendingMatch = keeper.GetOrderBookLevels("XYZ-000_BNB", 1)
+	assert.False(pendingMatch)
+	_, ok = keeper.GetOrderFills("x21")
+	assert.True(ok)
+}
+
+func TestKeeper_GetAccountRisk(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	otherAdd, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 5e7))
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "XYZ-000", 1e8))
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "BTC-000", 1e8))
+
+	msg := NewNewOrderMsg(accAdd, "1", Side.BUY, "XYZ-000_BNB", 1e8, 2e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "2", Side.SELL, "XYZ-000_BNB", 2e8, 3e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 2e8, "", 0}, false)
+	msg = NewNewOrderMsg(otherAdd, "3", Side.BUY, "XYZ-000_BNB", 1e8, 5e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "4", Side.BUY, "ABC-000_XYZ-000", 1e8, 4e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "5", Side.BUY, "ABC-000_BTC-000", 1e8, 1e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+
+	risk := keeper.GetAccountRisk(accAdd, DefaultAccountRiskMaxOrders)
+	assert.Equal(store.AccountRisk{
+		Address: accAdd,
+		Symbols: []store.SymbolRisk{
+			{Symbol: "ABC-000_BTC-000", OpenOrders: 1, Notional: 1e8, RefNotionalUnavailable: true},
+			{Symbol: "ABC-000_XYZ-000", OpenOrders: 1, Notional: 4e8, RefNotional: 2e8},
+			{Symbol: "XYZ-000_BNB", OpenOrders: 2, Notional: 4e8, RefNotional: 4e8},
+		},
+		OpenOrders:  4,
+		RefAsset:    "BNB",
+		RefNotional: 6e8,
+	}, risk)
+
+	risk = keeper.GetAccountRisk(accAdd, 3)
+	assert.True(risk.Truncated)
+	assert.Equal(int64(3), risk.OpenOrders)
+	assert.Equal(3, len(risk.Symbols))
+	assert.Equal(int64(1), risk.Symbols[2].OpenOrders)
+	assert.Equal(utils.Fixed8(2e8), risk.Symbols[2].Notional)
+
+	risk = keeper.GetAccountRisk(accAdd, 2)
+	assert.True(risk.Truncated)
+	assert.Equal(2, len(risk.Symbols))
+
+	otherRisk := keeper.GetAccountRisk(otherAdd, DefaultAccountRiskMaxOrders)
+	assert.Equal(int64(1), otherRisk.OpenOrders)
+	assert.False(otherRisk.Truncated)
+}
+
 func TestKeeper_SnapShotOrderBookEmpty(t *testing.T) {
 	assert := assert.New(t)
 	cdc := MakeCodec()
@@ -349,6 +662,23 @@ func TestKeeper_LoadOrderBookSnapshot(t *testing.T) {
 	assert.Nil(err)
 }
 
+func TestKeeper_LoadOrderBookSnapshot_PairGTCTTL(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, log.NewNopLogger())
+
+	pair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	pair.GTCTTLDays = 7
+	keeper.PairMapper.AddTradingPair(ctx, pair)
+	keeper.PairMapper.AddTradingPair(ctx, dextypes.NewTradingPair("ABC-000", "BNB", 1e8))
+	_, err := keeper.LoadOrderBookSnapshot(ctx, 0, utils.Now(), 0, 10)
+	assert.Nil(err)
+	assert.Equal(7, keeper.GetGTCTTLDays("XYZ-000_BNB"))
+	assert.Equal(effectiveDays, keeper.GetGTCTTLDays("ABC-000_BNB"))
+}
+
 func NewMockBlock(txs []auth.StdTx, height int64, commit *tmtypes.Commit, cdc *wire.Codec) *tmtypes.Block {
 	tmTxs := make([]tmtypes.Tx, len(txs))
 	for i, tx := range txs {
@@ -658,6 +988,141 @@ func TestKeeper_ExpireOrdersBasedOnPrice(t *testing.T) {
 	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP67, 0)
 }
 
+func TestKeeper_ExpireOrders_PairGTCTTL(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, acc := testutils.NewAccount(ctx, am, 0)
+	addr := acc.GetAddress()
+	short := dextypes.NewTradingPair("ABC-000", "BNB", 1e8)
+	short.GTCTTLDays = 1
+	long := dextypes.NewTradingPair("DEF-000", "BNB", 1e8)
+	long.GTCTTLDays = 5
+	keeper.AddEngine(short)
+	keeper.AddEngine(long)
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	require.Equal(t, 1, keeper.GetGTCTTLDays("ABC-000_BNB"))
+	require.Equal(t, 5, keeper.GetGTCTTLDays("DEF-000_BNB"))
+	require.Equal(t, effectiveDays, keeper.GetGTCTTLDays("XYZ-000_BNB"))
+	for _, symbol := range []string{"ABC-000_BNB", "DEF-000_BNB", "XYZ-000_BNB"} {
+		msg := NewNewOrderMsg(addr, symbol, Side.BUY, symbol, 1e8, 1e8)
+		keeper.AddOrder(OrderInfo{msg, 15000, 0, 15000, 0, 0, "", 0}, false)
+	}
+	acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{sdk.NewCoin("BNB", 3e8)})
+	am.SetAccount(ctx, acc)
+
+	breathTime, _ := time.Parse(time.RFC3339, "2018-01-01T00:00:01Z")
+	keeper.MarkBreatheBlock(ctx, 5000, breathTime)
+	keeper.MarkBreatheBlock(ctx, 10000, breathTime.AddDate(0, 0, 1))
+	keeper.MarkBreatheBlock(ctx, 20000, breathTime.AddDate(0, 0, 2))
+	keeper.MarkBreatheBlock(ctx, 30000, breathTime.AddDate(0, 0, 3))
+	keeper.MarkBreatheBlock(ctx, 40000, breathTime.AddDate(0, 0, 4))
+
+	// the orders of ABC-000_BNB are expired after 1 day
+	keeper.ExpireOrders(ctx, breathTime.AddDate(0, 0, 3), nil)
+	require.Len(t, keeper.GetAllOrdersForPair("ABC-000_BNB"), 0)
+	require.Len(t, keeper.GetAllOrdersForPair("DEF-000_BNB"), 1)
+	require.Len(t, keeper.GetAllOrdersForPair("XYZ-000_BNB"), 1)
+
+	// the orders of XYZ-000_BNB are expired after the global 3 days
+	keeper.ExpireOrders(ctx, breathTime.AddDate(0, 0, 5), nil)
+	require.Len(t, keeper.GetAllOrdersForPair("DEF-000_BNB"), 1)
+	require.Len(t, keeper.GetAllOrdersForPair("XYZ-000_BNB"), 0)
+
+	// the orders of DEF-000_BNB are expired after 5 days
+	keeper.ExpireOrders(ctx, breathTime.AddDate(0, 0, 6), nil)
+	require.Len(t, keeper.GetAllOrdersForPair("DEF-000_BNB"), 1)
+	keeper.ExpireOrders(ctx, breathTime.AddDate(0, 0, 7), nil)
+	require.Len(t, keeper.GetAllOrdersForPair("DEF-000_BNB"), 0)
+	fees.Pool.Clear()
+}
+
+func TestKeeper_ExpireOrders_PairGTCTTL_NoBreatheBlock(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, acc := testutils.NewAccount(ctx, am, 0)
+	pair := dextypes.NewTradingPair("ABC-000", "BNB", 1e8)
+	pair.GTCTTLDays = 2
+	keeper.AddEngine(pair)
+	msg := NewNewOrderMsg(acc.GetAddress(), "1", Side.BUY, "ABC-000_BNB", 1e8, 1e8)
+	keeper.AddOrder(OrderInfo{msg, 8000, 0, 8000, 0, 0, "", 0}, false)
+	acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{sdk.NewCoin("BNB", 1e8)})
+	am.SetAccount(ctx, acc)
+
+	breathTime, _ := time.Parse(time.RFC3339, "2018-01-01T00:00:01Z")
+	keeper.MarkBreatheBlock(ctx, 5000, breathTime)
+	keeper.MarkBreatheBlock(ctx, 10000, breathTime.AddDate(0, 0, 1))
+	// no breathe block on the day 2, e.g. the chain was halted through the day
+	keeper.MarkBreatheBlock(ctx, 30000, breathTime.AddDate(0, 0, 3))
+
+	require.Equal(t, map[string]int64{"ABC-000_BNB": 30000}, keeper.getPairExpireHeights(ctx, breathTime.AddDate(0, 0, 5)))
+	// none is found if the chain is younger than the TTL
+	require.Equal(t, map[string]int64{"ABC-000_BNB": -1}, keeper.getPairExpireHeights(ctx, breathTime.AddDate(0, 0, 1)))
+	// the nearest earlier breathe block is taken for the missing day
+	require.Equal(t, map[string]int64{"ABC-000_BNB": 10000}, keeper.getPairExpireHeights(ctx, breathTime.AddDate(0, 0, 4)))
+	keeper.ExpireOrders(ctx, breathTime.AddDate(0, 0, 4), nil)
+	require.Len(t, keeper.GetAllOrdersForPair("ABC-000_BNB"), 0)
+	fees.Pool.Clear()
+}
+
+func TestKeeper_GetExpiringOrders(t *testing.T) {
+	ctx, am, keeper := setup()
+	_, acc := testutils.NewAccount(ctx, am, 0)
+	_, other := testutils.NewAccount(ctx, am, 0)
+	addr, otherAddr := acc.GetAddress(), other.GetAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "BNB", 1e8))
+	short := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	short.GTCTTLDays = 1
+	keeper.AddEngine(short)
+
+	breathTime, _ := time.Parse(time.RFC3339, "2018-01-01T00:00:01Z")
+	keeper.MarkBreatheBlock(ctx, 1000, breathTime)
+	keeper.MarkBreatheBlock(ctx, 2000, breathTime.AddDate(0, 0, 1))
+	keeper.MarkBreatheBlock(ctx, 3000, breathTime.AddDate(0, 0, 2))
+	ctx = ctx.WithBlockHeight(3500).WithBlockTime(breathTime.AddDate(0, 0, 2).Add(12 * time.Hour))
+	require.Empty(t, keeper.GetExpiringOrders(ctx, 1e4, nil))
+
+	keeper.AddOrder(OrderInfo{NewNewOrderMsg(addr, "1", Side.BUY, "ABC-000_BNB", 1e8, 1e8), 500, 0, 500, 0, 0, "", 0}, false)
+	keeper.AddOrder(OrderInfo{NewNewOrderMsg(otherAddr, "2", Side.SELL, "ABC-000_BNB", 2e8, 1e8), 1500, 0, 1500, 0, 0, "", 0}, false)
+	keeper.AddOrder(OrderInfo{NewNewOrderMsg(addr, "3", Side.BUY, "XYZ-000_BNB", 1e8, 1e8), 2500, 0, 2500, 0, 0, "", 0}, false)
+	keeper.AddOrder(OrderInfo{NewNewOrderMsg(addr, "4", Side.BUY, "ABC-000_BNB", 1e8, 1e8), 3200, 0, 3200, 0, 0, "", 0}, false)
+	ids := func(expiring []store.ExpiringOrder) (res []string) {
+		for _, ord := range expiring {
+			res = append(res, fmt.Sprintf("%s@%d", ord.Id, ord.ExpireHeight))
+		}
+		return res
+	}
+
+	// the breathe blocks are expected every 1000 blocks, the next one at 4000 expires the orders created before
+	// the breathe block of 3 days ago, or of 1 day ago for XYZ-000_BNB
+	require.Empty(t, keeper.GetExpiringOrders(ctx, 499, nil))
+	expiring := keeper.GetExpiringOrders(ctx, 500, nil)
+	require.Equal(t, []string{"1@4000", "3@4000"}, ids(expiring))
+	require.Equal(t, store.ExpiringOrder{
+		Id:               "1",
+		Symbol:           "ABC-000_BNB",
+		Owner:            addr,
+		Side:             Side.BUY,
+		Price:            1e8,
+		Quantity:         1e8,
+		CreatedHeight:    500,
+		CreatedTimestamp: 0,
+		ExpireHeight:     4000,
+	}, expiring[0])
+	require.Equal(t, []string{"1@4000", "3@4000", "2@5000"}, ids(keeper.GetExpiringOrders(ctx, 1500, nil)))
+	// the breathe block at 7000 expires all the orders created before the breathe block at 4000
+	require.Equal(t, []string{"1@4000", "3@4000", "2@5000", "4@7000"}, ids(keeper.GetExpiringOrders(ctx, 3500, nil)))
+	require.Equal(t, []string{"2@5000"}, ids(keeper.GetExpiringOrders(ctx, 3500, otherAddr)))
+
+	// the orders on the preferred price levels are only force expired after 30 days since BEP67
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP67, -1)
+	require.Empty(t, keeper.GetExpiringOrders(ctx, 3500, nil))
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP67, 0)
+
+	// the breathe blocks can't be estimated without the latest two of them
+	ctx = ctx.WithBlockTime(breathTime.AddDate(0, 0, 10))
+	require.Empty(t, keeper.GetExpiringOrders(ctx, 3500, nil))
+}
+
 func TestKeeper_DetermineLotSize(t *testing.T) {
 	assert := assert.New(t)
 	ctx, _, keeper := setup()
@@ -928,6 +1393,126 @@ func TestKeeper_DelistTradingPair(t *testing.T) {
 	require.Equal(t, expectFees, fees.Pool.BlockFees())
 }
 
+func TestKeeper_DelistTradingPair_FeeFree(t *testing.T) {
+	ctx, am, keeper := setup()
+	fees.Pool.Clear()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	params := keeper.GetParams(ctx)
+	params.DelistFeeFree = true
+	keeper.setParams(ctx, params)
+	_, acc := testutils.NewAccount(ctx, am, 0)
+	addr := acc.GetAddress()
+	ctx = ctx.WithBlockHeight(2000)
+
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	keeper.AddEngine(tradingPair)
+
+	acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{
+		sdk.NewCoin("BNB", 3e4),
+		sdk.NewCoin("XYZ-000", 2e4),
+	}.Sort())
+	acc.(types.NamedAccount).SetCoins(sdk.Coins{
+		sdk.NewCoin("XYZ-000", 4e5),
+	}.Sort())
+	am.SetAccount(ctx, acc)
+
+	msg := NewNewOrderMsg(addr, "123456", Side.BUY, "XYZ-000_BNB", 1e6, 1e6)
+	keeper.AddOrder(OrderInfo{msg, 2000, 84, 42, 84, 0, "", 0}, false)
+	msg = NewNewOrderMsg(addr, "123457", Side.BUY, "XYZ-000_BNB", 2e6, 1e6)
+	keeper.AddOrder(OrderInfo{msg, 2000, 84, 42, 84, 0, "", 0}, false)
+	msg = NewNewOrderMsg(addr, "123458", Side.SELL, "XYZ-000_BNB", 5e6, 1e4)
+	keeper.AddOrder(OrderInfo{msg, 2000, 84, 42, 84, 0, "", 0}, false)
+	msg = NewNewOrderMsg(addr, "123459", Side.SELL, "XYZ-000_BNB", 6e6, 1e4)
+	keeper.AddOrder(OrderInfo{msg, 2000, 84, 42, 84, 0, "", 0}, false)
+	require.Len(t, keeper.GetAllOrdersForPair("XYZ-000_BNB"), 4)
+
+	var delisted []string
+	keeper.DelistTradingPair(ctx, "XYZ-000_BNB", func(tran Transfer) {
+		require.True(t, tran.IsDelisted())
+		require.True(t, tran.Fee.IsEmpty())
+		delisted = append(delisted, tran.Oid)
+	})
+	require.ElementsMatch(t, []string{"123456", "123457", "123458", "123459"}, delisted)
+	require.Empty(t, keeper.GetAllOrders())
+	require.Empty(t, keeper.engines)
+	require.False(t, keeper.PairMapper.Exists(ctx, "XYZ-000", "BNB"))
+
+	// all the locked coins are refunded without any fee
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 3e4), sdk.NewCoin("XYZ-000", 42e4)}, acc.GetCoins())
+	require.Empty(t, acc.(types.NamedAccount).GetLockedCoins())
+	require.True(t, fees.Pool.BlockFees().Tokens.IsZero())
+}
+
+func TestKeeper_ExpireDisabledTokenOrders(t *testing.T) {
+	ms, capKey, capKey2, capKey3 := testutils.SetupThreeMultiStoreForUnitTest()
+	cdc := MakeCodec()
+	cdc.RegisterConcrete(dextypes.TradingPair{}, "dex/TradingPair", nil)
+	am := auth.NewAccountKeeper(cdc, capKey, types.ProtoAppAccount)
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid"}, sdk.RunTxModeDeliver, log.NewNopLogger()).
+		WithAccountCache(getAccountCache(cdc, ms, capKey)).WithBlockHeight(2000)
+	keeper := NewDexKeeper(capKey2, am, store.NewTradingPairMapper(cdc, common.PairStoreKey),
+		sdk.NewCodespacer().RegisterNext(dextypes.DefaultCodespace), 2, cdc, false)
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	tokenMapper := tokenStore.NewMapper(cdc, capKey3)
+	keeper.SetTokenMapper(tokenMapper)
+	fees.Pool.Clear()
+
+	_, acc := testutils.NewAccount(ctx, am, 0)
+	addr := acc.GetAddress()
+	for _, symbol := range []string{"AAA-000", "BBB-000"} {
+		token, err := types.NewToken(symbol[:3], symbol, 1e10, addr, false)
+		require.NoError(t, err)
+		require.NoError(t, tokenMapper.NewToken(ctx, token))
+		pair := dextypes.NewTradingPair(symbol, "BNB", 1e8)
+		require.NoError(t, keeper.PairMapper.AddTradingPair(ctx, pair))
+		keeper.AddEngine(pair)
+	}
+	acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{
+		sdk.NewCoin("AAA-000", 1e4),
+		sdk.NewCoin("BNB", 2e4),
+	}.Sort())
+	acc.(types.NamedAccount).SetCoins(sdk.Coins{sdk.NewCoin("BNB", 1e8)})
+	am.SetAccount(ctx, acc)
+
+	msg := NewNewOrderMsg(addr, "1", Side.BUY, "AAA-000_BNB", 1e6, 1e6)
+	keeper.AddOrder(OrderInfo{msg, 2000, 84, 42, 84, 0, "", 0}, false)
+	msg = NewNewOrderMsg(addr, "2", Side.SELL, "AAA-000_BNB", 2e6, 1e4)
+	keeper.AddOrder(OrderInfo{msg, 2000, 84, 42, 84, 0, "", 0}, false)
+	msg = NewNewOrderMsg(addr, "3", Side.BUY, "BBB-000_BNB", 1e6, 1e6)
+	keeper.AddOrder(OrderInfo{msg, 2000, 84, 42, 84, 0, "", 0}, false)
+	require.NoError(t, tokenMapper.SetTradingDisabled(ctx, "AAA-000", true, 1))
+
+	// disabling the token only blocks the new orders by default
+	keeper.ExpireDisabledTokenOrders(ctx, func(tran Transfer) {
+		t.Fatalf("unexpected transfer of order %s", tran.Oid)
+	})
+	require.Len(t, keeper.GetAllOrdersForPair("AAA-000_BNB"), 2)
+
+	params := keeper.GetParams(ctx)
+	params.ExpireDisabledTokenOrders = true
+	keeper.setParams(ctx, params)
+	var expired []string
+	keeper.ExpireDisabledTokenOrders(ctx, func(tran Transfer) {
+		require.True(t, tran.IsTokenDisabled())
+		require.True(t, tran.Fee.IsEmpty())
+		expired = append(expired, tran.Oid)
+	})
+	require.ElementsMatch(t, []string{"1", "2"}, expired)
+	require.Empty(t, keeper.GetAllOrdersForPair("AAA-000_BNB"))
+	require.Len(t, keeper.GetAllOrdersForPair("BBB-000_BNB"), 1)
+	// the pair stays listed
+	require.Contains(t, keeper.engines, "AAA-000_BNB")
+	require.True(t, keeper.PairMapper.Exists(ctx, "AAA-000", "BNB"))
+
+	// the locked coins of the expired orders are refunded without any fee
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("AAA-000", 1e4), sdk.NewCoin("BNB", 1e8+1e4)}, acc.GetCoins())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 1e4)}, acc.(types.NamedAccount).GetLockedCoins())
+	require.True(t, fees.Pool.BlockFees().Tokens.IsZero())
+}
+
 func TestKeeper_DelistMiniTradingPair(t *testing.T) {
 	setChainVersion()
 	defer resetChainVersion()
@@ -1172,3 +1757,1375 @@ func setChainVersion() {
 func resetChainVersion() {
 	upgrade.Mgr.Config.HeightMap = nil
 }
+
+func TestKeeper_AddOrderZeroPriceOrQty(t *testing.T) {
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.AddEngine(tradingPair)
+
+	for _, msg := range []NewOrderMsg{
+		NewNewOrderMsg(accAdd, "123456", Side.BUY, "XYZ-000_BNB", 0, 3000000),
+		NewNewOrderMsg(accAdd, "123457", Side.BUY, "XYZ-000_BNB", -99000, 3000000),
+		NewNewOrderMsg(accAdd, "123458", Side.SELL, "XYZ-000_BNB", 99000, 0),
+	} {
+		require.Error(t, keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false))
+		_, exists := keeper.OrderExists("XYZ-000_BNB", msg.Id)
+		require.False(t, exists)
+	}
+	buys, sells := keeper.engines["XYZ-000_BNB"].Book.GetAllLevels()
+	require.Empty(t, buys)
+	require.Empty(t, sells)
+}
+
+func TestKeeper_GetSpread(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+
+	spread := keeper.GetSpread("XYZ-000_BNB")
+	assert.Equal(store.NoSpread, spread.SpreadBps)
+
+	msg := NewNewOrderMsg(accAdd, "1", Side.BUY, "XYZ-000_BNB", 99e6, 1e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	spread = keeper.GetSpread("XYZ-000_BNB")
+	assert.Equal(int64(99e6), spread.BestBid.ToInt64())
+	assert.Equal(store.NoSpread, spread.SpreadBps)
+
+	msg = NewNewOrderMsg(accAdd, "2", Side.SELL, "XYZ-000_BNB", 101e6, 1e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "3", Side.SELL, "XYZ-000_BNB", 102e6, 1e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	spread = keeper.GetSpread("XYZ-000_BNB")
+	assert.Equal(store.Spread{"XYZ-000_BNB", 99e6, 101e6, 200}, spread)
+
+	// crossed before matching
+	msg = NewNewOrderMsg(accAdd, "4", Side.BUY, "XYZ-000_BNB", 101e6, 1e6)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	assert.Equal(int64(0), keeper.GetSpread("XYZ-000_BNB").SpreadBps)
+}
+
+func TestKeeper_GetCappedOrderBookLevels(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	for i, id := range []string{"1", "2", "3", "4", "5"} {
+		msg := NewNewOrderMsg(accAdd, id, Side.BUY, "XYZ-000_BNB", 90e6+int64(i)*1e6, 1e8)
+		keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	}
+
+	levels, _, truncated := keeper.GetCappedOrderBookLevels("XYZ-000_BNB", 100)
+	assert.False(truncated)
+	assert.Len(levels, 100)
+
+	keeper.SetMaxOrderBookDepth(3)
+	levels, _, truncated = keeper.GetCappedOrderBookLevels("XYZ-000_BNB", 100)
+	assert.True(truncated)
+	assert.Len(levels, 3)
+	assert.Equal(int64(94e6), levels[0].BuyPrice.ToInt64())
+	assert.Equal(int64(92e6), levels[2].BuyPrice.ToInt64())
+
+	levels, _, truncated = keeper.GetCappedOrderBookLevels("XYZ-000_BNB", 2)
+	assert.False(truncated)
+	assert.Len(levels, 2)
+
+	keeper.SetMaxOrderBookDepth(0)
+	_, _, truncated = keeper.GetCappedOrderBookLevels("XYZ-000_BNB", DefaultMaxOrderBookDepth+1)
+	assert.True(truncated)
+}
+
+func TestKeeper_GetAggregatedOrderBookLevels(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	for i, id := range []string{"1", "2", "3", "4", "5"} {
+		msg := NewNewOrderMsg(accAdd, id, Side.BUY, "XYZ-000_BNB", 90e6+int64(i)*1e6, 1e8)
+		keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	}
+	for i, price := range []int64{101e6, 1025e5, 104e6, 110e6} {
+		msg := NewNewOrderMsg(accAdd, []string{"6", "7", "8", "9"}[i], Side.SELL, "XYZ-000_BNB", price, 1e8)
+		keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	}
+	level := func(buyPrice, buyQty, sellPrice, sellQty int64) store.OrderBookLevel {
+		return store.OrderBookLevel{
+			BuyPrice: utils.Fixed8(buyPrice), BuyQty: utils.Fixed8(buyQty),
+			SellPrice: utils.Fixed8(sellPrice), SellQty: utils.Fixed8(sellQty),
+		}
+	}
+
+	// the raw price levels
+	levels, _, truncated := keeper.GetAggregatedOrderBookLevels("XYZ-000_BNB", 5, 0)
+	assert.False(truncated)
+	raw, _ := keeper.GetOrderBookLevels("XYZ-000_BNB", 5)
+	assert.Equal(raw, levels)
+	assert.Equal(level(94e6, 1e8, 101e6, 1e8), levels[0])
+	assert.Equal(level(90e6, 1e8, 0, 0), levels[4])
+
+	levels, _, _ = keeper.GetAggregatedOrderBookLevels("XYZ-000_BNB", 5, 2e6)
+	assert.Equal([]store.OrderBookLevel{
+		level(94e6, 1e8, 102e6, 1e8),
+		level(92e6, 2e8, 104e6, 2e8),
+		level(90e6, 2e8, 110e6, 1e8),
+		{}, {},
+	}, levels)
+
+	levels, _, _ = keeper.GetAggregatedOrderBookLevels("XYZ-000_BNB", 1, 5e6)
+	assert.Equal([]store.OrderBookLevel{level(90e6, 5e8, 105e6, 3e8)}, levels)
+
+	levels, _, _ = keeper.GetAggregatedOrderBookLevels("XYZ-000_BNB", 2, 10e6)
+	assert.Equal([]store.OrderBookLevel{level(90e6, 5e8, 110e6, 4e8), {}}, levels)
+
+	keeper.SetMaxOrderBookDepth(2)
+	levels, _, truncated = keeper.GetAggregatedOrderBookLevels("XYZ-000_BNB", 5, 2e6)
+	assert.True(truncated)
+	assert.Equal([]store.OrderBookLevel{level(94e6, 1e8, 102e6, 1e8), level(92e6, 2e8, 104e6, 2e8)}, levels)
+}
+
+func TestLimitOrderBookLevelsByNotional(t *testing.T) {
+	assert := assert.New(t)
+	level := func(buyPrice, buyQty, sellPrice, sellQty int64) store.OrderBookLevel {
+		return store.OrderBookLevel{
+			BuyPrice: utils.Fixed8(buyPrice), BuyQty: utils.Fixed8(buyQty),
+			SellPrice: utils.Fixed8(sellPrice), SellQty: utils.Fixed8(sellQty),
+		}
+	}
+	// the buy levels are worth 1, 2 and 3, and the sell levels 1.1 and 2.4 in the quote asset
+	book := func() []store.OrderBookLevel {
+		return []store.OrderBookLevel{
+			level(1e8, 1e8, 110e6, 1e8),
+			level(1e8, 2e8, 120e6, 2e8),
+			level(1e8, 3e8, 0, 0),
+			{},
+		}
+	}
+
+	levels := book()
+	buyNotional, sellNotional := LimitOrderBookLevelsByNotional(levels, 1e8)
+	assert.Equal([]store.OrderBookLevel{level(1e8, 1e8, 110e6, 1e8), {}, {}, {}}, levels)
+	assert.Equal(int64(1e8), buyNotional)
+	assert.Equal(int64(110e6), sellNotional)
+
+	// the level reaching the depth is kept
+	levels = book()
+	buyNotional, sellNotional = LimitOrderBookLevelsByNotional(levels, 2e8)
+	assert.Equal([]store.OrderBookLevel{level(1e8, 1e8, 110e6, 1e8), level(1e8, 2e8, 120e6, 2e8), {}, {}}, levels)
+	assert.Equal(int64(3e8), buyNotional)
+	assert.Equal(int64(350e6), sellNotional)
+
+	// the levels run out before the depth
+	levels = book()
+	buyNotional, sellNotional = LimitOrderBookLevelsByNotional(levels, 5e8)
+	assert.Equal(book(), levels)
+	assert.Equal(int64(6e8), buyNotional)
+	assert.Equal(int64(350e6), sellNotional)
+
+	// the cumulative notional saturates rather than overflows
+	levels = []store.OrderBookLevel{level(math.MaxInt64, math.MaxInt64, 0, 0), level(1e8, 1e8, 0, 0)}
+	buyNotional, _ = LimitOrderBookLevelsByNotional(levels, math.MaxInt64)
+	assert.Equal(int64(math.MaxInt64), buyNotional)
+	assert.Equal(store.OrderBookLevel{}, levels[1])
+}
+
+func TestKeeper_GetTWAP(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+
+	twap := keeper.GetTWAP("XYZ-000_BNB", 1000, 100)
+	assert.True(twap.InsufficientData)
+
+	keeper.RecordTWAPPrices(1000)
+	twap = keeper.GetTWAP("XYZ-000_BNB", 1000, 100)
+	assert.True(twap.InsufficientData)
+	assert.Equal(int64(1e8), twap.TWAP.ToInt64())
+
+	keeper.engines["XYZ-000_BNB"].LastTradePrice = 2e8
+	keeper.RecordTWAPPrices(1060)
+	keeper.RecordTWAPPrices(1080) // unchanged
+	keeper.engines["XYZ-000_BNB"].LastTradePrice = 4e8
+	keeper.RecordTWAPPrices(1090)
+	// 1e8 in [1000, 1060), 2e8 in [1060, 1090), 4e8 in [1090, 1100)
+	twap = keeper.GetTWAP("XYZ-000_BNB", 1100, 100)
+	assert.Equal(store.TWAP{"XYZ-000_BNB", 100, 100, 16e7, false}, twap)
+	// 1e8 in [1050, 1060), 2e8 in [1060, 1090), 4e8 in [1090, 1100)
+	twap = keeper.GetTWAP("XYZ-000_BNB", 1100, 50)
+	assert.Equal(store.TWAP{"XYZ-000_BNB", 50, 50, 22e7, false}, twap)
+	twap = keeper.GetTWAP("XYZ-000_BNB", 1100, 200)
+	assert.Equal(store.TWAP{"XYZ-000_BNB", 200, 100, 16e7, true}, twap)
+
+	// the changes out of the max window are dropped except the one in effect at the start of the window
+	keeper.RecordTWAPPrices(1090 + MaxTWAPWindowSeconds)
+	keeper.engines["XYZ-000_BNB"].LastTradePrice = 3e8
+	keeper.RecordTWAPPrices(1100 + MaxTWAPWindowSeconds)
+	assert.Len(keeper.twaps.samples["XYZ-000_BNB"], 2)
+	twap = keeper.GetTWAP("XYZ-000_BNB", 1100+MaxTWAPWindowSeconds, MaxTWAPWindowSeconds)
+	assert.False(twap.InsufficientData)
+}
+
+func TestKeeper_GetQuote(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+
+	assert.Equal(store.Quote{Symbol: "ABC-000_BNB", Unknown: true}, keeper.GetQuote("ABC-000_BNB"))
+
+	msg := NewNewOrderMsg(accAdd, "1", Side.BUY, "XYZ-000_BNB", 99e6, 1e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "2", Side.BUY, "XYZ-000_BNB", 99e6, 2e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "3", Side.SELL, "XYZ-000_BNB", 101e6, 1e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	quote := keeper.GetQuote("XYZ-000_BNB")
+	assert.False(quote.Unknown)
+	assert.Equal(int64(99e6), quote.BestBid.ToInt64())
+	assert.Equal(int64(3e8), quote.BestBidQty.ToInt64())
+	assert.Equal(int64(101e6), quote.BestAsk.ToInt64())
+	assert.Equal(int64(1e8), quote.BestAskQty.ToInt64())
+	assert.Equal(int64(1e8), quote.LastPrice.ToInt64())
+}
+
+func TestKeeper_SimulateMatch(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+
+	for id, price := range map[string]int64{"1": 100e6, "3": 105e6} {
+		msg := NewNewOrderMsg(accAdd, id, Side.SELL, "XYZ-000_BNB", price, 1e8)
+		keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	}
+	// nothing crosses
+	res := keeper.SimulateMatch("XYZ-000_BNB", 43)
+	assert.Empty(res.Trades)
+	assert.Equal(int64(0), res.BestBid.ToInt64())
+	assert.Equal(int64(100e6), res.BestAsk.ToInt64())
+
+	msg := NewNewOrderMsg(accAdd, "2", Side.BUY, "XYZ-000_BNB", 101e6, 2e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	levels, _ := keeper.GetOrderBookLevels("XYZ-000_BNB", 5)
+
+	res = keeper.SimulateMatch("XYZ-000_BNB", 43)
+	assert.Equal("XYZ-000_BNB", res.Symbol)
+	assert.Equal(int64(43), res.Height)
+	assert.Len(res.Trades, 1)
+	assert.Equal("2", res.Trades[0].BuyOrderId)
+	assert.Equal("1", res.Trades[0].SellOrderId)
+	assert.Equal(int64(1e8), res.Trades[0].Quantity.ToInt64())
+	assert.True(res.Trades[0].Price.ToInt64() >= 100e6 && res.Trades[0].Price.ToInt64() <= 101e6)
+	assert.Equal(res.Trades[0].Price, res.LastPrice)
+	// the rest of the buy order is at the top of the book after the matching
+	assert.Equal(int64(101e6), res.BestBid.ToInt64())
+	assert.Equal(int64(1e8), res.BestBidQty.ToInt64())
+	assert.Equal(int64(105e6), res.BestAsk.ToInt64())
+	assert.Equal(int64(1e8), res.BestAskQty.ToInt64())
+
+	// the book is left untouched
+	after, _ := keeper.GetOrderBookLevels("XYZ-000_BNB", 5)
+	assert.Equal(levels, after)
+	assert.Equal(res, keeper.SimulateMatch("XYZ-000_BNB", 43))
+	eng := keeper.engines["XYZ-000_BNB"]
+	assert.Empty(eng.Trades)
+	assert.Equal(int64(0), eng.LastMatchHeight)
+	assert.Equal(int64(1e8), eng.LastTradePrice)
+
+	res = keeper.SimulateMatch("ABC-000_BNB", 43)
+	assert.Empty(res.Trades)
+}
+
+func TestKeeper_GetOrderBooks_PublicationDepth(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	deep := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	deep.PublicationDepth = 3
+	keeper.AddEngine(deep)
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "BNB", 1e8))
+	for i, price := range []int64{99e6, 98e6, 97e6, 96e6} {
+		for _, symbol := range []string{"XYZ-000_BNB", "ABC-000_BNB"} {
+			msg := NewNewOrderMsg(accAdd, symbol+string(rune('a'+i)), Side.BUY, symbol, price, 1e8)
+			keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+		}
+	}
+	assert.Equal(3, keeper.GetPublicationDepth("XYZ-000_BNB", 2))
+	assert.Equal(2, keeper.GetPublicationDepth("ABC-000_BNB", 2))
+
+	books := keeper.GetOrderBooks(2)
+	assert.Len(books["XYZ-000_BNB"].Buys, 3)
+	assert.Len(books["ABC-000_BNB"].Buys, 2)
+
+	// back to the global depth once the override is unset
+	keeper.setPairPublicationDepth("XYZ-000_BNB", 0)
+	books = keeper.GetOrderBooks(2)
+	assert.Len(books["XYZ-000_BNB"].Buys, 2)
+}
+
+func TestKeeper_GetOrderBookSnapshots(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	deep := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	deep.PublicationDepth = 3
+	keeper.AddEngine(deep)
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "BNB", 1e8))
+	for i := int64(0); i < 5; i++ {
+		for _, symbol := range []string{"XYZ-000_BNB", "ABC-000_BNB"} {
+			msg := NewNewOrderMsg(accAdd, fmt.Sprintf("%s-b%d", symbol, i), Side.BUY, symbol, 99e6-i*1e6, (i+1)*1e8)
+			keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+			msg = NewNewOrderMsg(accAdd, fmt.Sprintf("%s-s%d", symbol, i), Side.SELL, symbol, 101e6+i*1e6, (i+1)*1e8)
+			keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+		}
+	}
+
+	// the best levels of the snapshot depth
+	snapshots := keeper.GetOrderBookSnapshots(2, 4)
+	for _, symbol := range []string{"XYZ-000_BNB", "ABC-000_BNB"} {
+		assert.Equal(map[int64]int64{99e6: 1e8, 98e6: 2e8, 97e6: 3e8, 96e6: 4e8}, snapshots[symbol].Buys, symbol)
+		assert.Equal(map[int64]int64{101e6: 1e8, 102e6: 2e8, 103e6: 3e8, 104e6: 4e8}, snapshots[symbol].Sells, symbol)
+	}
+
+	// no fewer than the levels published, of the global depth or the one of the pair
+	snapshots = keeper.GetOrderBookSnapshots(2, 0)
+	assert.Equal(map[int64]int64{99e6: 1e8, 98e6: 2e8, 97e6: 3e8}, snapshots["XYZ-000_BNB"].Buys)
+	assert.Equal(map[int64]int64{101e6: 1e8, 102e6: 2e8, 103e6: 3e8}, snapshots["XYZ-000_BNB"].Sells)
+	assert.Equal(map[int64]int64{99e6: 1e8, 98e6: 2e8}, snapshots["ABC-000_BNB"].Buys)
+	assert.Equal(map[int64]int64{101e6: 1e8, 102e6: 2e8}, snapshots["ABC-000_BNB"].Sells)
+
+	// the whole books if they are shallower than the snapshot depth
+	snapshots = keeper.GetOrderBookSnapshots(2, 10)
+	assert.Len(snapshots["XYZ-000_BNB"].Buys, 5)
+	assert.Len(snapshots["ABC-000_BNB"].Sells, 5)
+}
+
+func TestKeeper_AddOrder_NotionalOverflow(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+
+	msg := NewNewOrderMsg(accAdd, "1", Side.BUY, "XYZ-000_BNB", math.MaxInt64, 1e8)
+	assert.NoError(keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false))
+
+	msg = NewNewOrderMsg(accAdd, "2", Side.SELL, "XYZ-000_BNB", math.MaxInt64, 1e8+1)
+	err := keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	assert.Error(err)
+	assert.Equal(dextypes.CodeNotionalOverflow, err.(sdk.Error).Code())
+	_, ok := keeper.OrderExists("XYZ-000_BNB", "2")
+	assert.False(ok)
+	assert.Len(keeper.GetAllOrdersForPair("XYZ-000_BNB"), 1)
+}
+
+func TestKeeper_GetImbalanceTrend(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	keeper.AddOrder(OrderInfo{NewNewOrderMsg(accAdd, "1", Side.BUY, "XYZ-000_BNB", 99e6, 3e8), 42, 0, 42, 0, 0, "", 0}, false)
+	keeper.AddOrder(OrderInfo{NewNewOrderMsg(accAdd, "2", Side.SELL, "XYZ-000_BNB", 101e6, 1e8), 42, 0, 42, 0, 0, "", 0}, false)
+
+	// not recorded unless enabled
+	keeper.RecordImbalances(0)
+	assert.Empty(keeper.GetImbalanceTrend("XYZ-000_BNB", MaxImbalanceTrendBlocks).Imbalances)
+
+	keeper.EnableImbalanceTrends()
+	keeper.RecordImbalances(1)
+
+	trend := keeper.GetImbalanceTrend("XYZ-000_BNB", MaxImbalanceTrendBlocks)
+	assert.Equal("XYZ-000_BNB", trend.Symbol)
+	assert.Equal([]store.BookImbalance{{Height: 1, BidQty: 3e8, AskQty: 1e8, Imbalance: 5e7}}, trend.Imbalances)
+
+	keeper.AddOrder(OrderInfo{NewNewOrderMsg(accAdd, "3", Side.SELL, "XYZ-000_BNB", 102e6, 8e8), 42, 0, 42, 0, 0, "", 0}, false)
+	for h := int64(2); h <= MaxImbalanceTrendBlocks+2; h++ {
+		keeper.RecordImbalances(h)
+	}
+	trend = keeper.GetImbalanceTrend("XYZ-000_BNB", MaxImbalanceTrendBlocks)
+	assert.Len(trend.Imbalances, MaxImbalanceTrendBlocks)
+	assert.Equal(int64(3), trend.Imbalances[0].Height)
+	assert.Equal(int64(MaxImbalanceTrendBlocks+2), trend.Imbalances[MaxImbalanceTrendBlocks-1].Height)
+	assert.Equal(utils.Fixed8(-5e7), trend.Imbalances[0].Imbalance)
+
+	trend = keeper.GetImbalanceTrend("XYZ-000_BNB", 2)
+	assert.Len(trend.Imbalances, 2)
+	assert.Equal(int64(MaxImbalanceTrendBlocks+1), trend.Imbalances[0].Height)
+	assert.Empty(keeper.GetImbalanceTrend("ABC-000_BNB", 2).Imbalances)
+}
+
+func TestKeeper_GetTradeRate(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	for _, base := range []string{"XYZ-000", "ABC-000"} {
+		keeper.AddEngine(dextypes.NewTradingPair(base, "BNB", 1e8))
+		symbol := base + "_BNB"
+		keeper.AddOrder(OrderInfo{NewNewOrderMsg(accAdd, symbol+"b", Side.BUY, symbol, 1e8, 2e8), 1, 0, 1, 0, 0, "", 0}, false)
+		keeper.AddOrder(OrderInfo{NewNewOrderMsg(accAdd, symbol+"s1", Side.SELL, symbol, 1e8, 1e8), 1, 0, 1, 0, 0, "", 0}, false)
+		keeper.AddOrder(OrderInfo{NewNewOrderMsg(accAdd, symbol+"s2", Side.SELL, symbol, 1e8, 1e8), 1, 0, 1, 0, 0, "", 0}, false)
+	}
+	keeper.engines["XYZ-000_BNB"].Match(1)
+	keeper.engines["ABC-000_BNB"].Match(1)
+	keeper.RecordTradeCounts(1)
+	keeper.RecordTradeCounts(2)
+
+	rate := keeper.GetTradeRate(PairType.BEP2, "", 10)
+	assert.Equal("", rate.Symbol)
+	assert.Equal([]store.BlockTradeCount{{Height: 1, Trades: 4}, {Height: 2, Trades: 0}}, rate.Blocks)
+	rate = keeper.GetTradeRate(PairType.BEP2, "XYZ-000_BNB", 1)
+	assert.Equal("XYZ-000_BNB", rate.Symbol)
+	assert.Equal([]store.BlockTradeCount{{Height: 2, Trades: 0}}, rate.Blocks)
+	rate = keeper.GetTradeRate(PairType.BEP2, "XYZ-000_BNB", 2)
+	assert.Equal([]store.BlockTradeCount{{Height: 1, Trades: 2}, {Height: 2, Trades: 0}}, rate.Blocks)
+	rate = keeper.GetTradeRate(PairType.MINI, "", 10)
+	assert.Equal([]store.BlockTradeCount{{Height: 1, Trades: 0}, {Height: 2, Trades: 0}}, rate.Blocks)
+
+	// only the latest blocks are kept
+	for h := int64(3); h <= MaxTradeRateBlocks+5; h++ {
+		keeper.RecordTradeCounts(h)
+	}
+	rate = keeper.GetTradeRate(PairType.BEP2, "", MaxTradeRateBlocks)
+	assert.Len(rate.Blocks, MaxTradeRateBlocks)
+	assert.Equal(int64(6), rate.Blocks[0].Height)
+	assert.Equal(int64(MaxTradeRateBlocks+5), rate.Blocks[MaxTradeRateBlocks-1].Height)
+}
+
+func TestKeeper_GetLastMatch(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	for _, base := range []string{"XYZ-000", "ABC-000", "ZCB-000"} {
+		keeper.AddEngine(dextypes.NewTradingPair(base, "BNB", 1e8))
+		symbol := base + "_BNB"
+		keeper.AddOrder(OrderInfo{NewNewOrderMsg(accAdd, symbol+"b", Side.BUY, symbol, 1e8, 2e8), 1, 0, 1, 0, 0, "", 0}, false)
+		if base != "ZCB-000" {
+			keeper.AddOrder(OrderInfo{NewNewOrderMsg(accAdd, symbol+"s", Side.SELL, symbol, 1e8, 1e8), 1, 0, 1, 0, 0, "", 0}, false)
+		}
+	}
+	keeper.engines["XYZ-000_BNB"].Match(1)
+	keeper.RecordLastMatches(1)
+	keeper.RecordLastMatches(2)
+	assert.Equal(store.LastMatch{Symbol: "XYZ-000_BNB", Height: 1}, keeper.GetLastMatch("XYZ-000_BNB"))
+	// never matched
+	assert.Equal(store.LastMatch{Symbol: "ABC-000_BNB", Height: 0}, keeper.GetLastMatch("ABC-000_BNB"))
+
+	keeper.engines["ABC-000_BNB"].Match(3)
+	// matched without any trade
+	keeper.engines["ZCB-000_BNB"].Match(3)
+	keeper.RecordLastMatches(3)
+	assert.Equal(int64(1), keeper.GetLastMatch("XYZ-000_BNB").Height)
+	assert.Equal(int64(3), keeper.GetLastMatch("ABC-000_BNB").Height)
+	assert.Equal(int64(0), keeper.GetLastMatch("ZCB-000_BNB").Height)
+
+	// the delisted pairs are not kept
+	keeper.lastMatches.delete("XYZ-000_BNB")
+	assert.Equal(int64(0), keeper.GetLastMatch("XYZ-000_BNB").Height)
+}
+
+func TestKeeper_GetOrderSizeDistribution(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	for _, o := range []struct {
+		id    string
+		side  int8
+		price int64
+		qty   int64
+	}{
+		{"1", Side.BUY, 99e6, 5e7},
+		{"2", Side.BUY, 99e6, 2e8},
+		{"3", Side.BUY, 98e6, 1e9},
+		{"4", Side.BUY, 97e6, 3e10},
+		{"5", Side.SELL, 101e6, 1e8},
+		{"6", Side.SELL, 102e6, 9e8},
+	} {
+		msg := NewNewOrderMsg(accAdd, o.id, o.side, "XYZ-000_BNB", o.price, o.qty)
+		keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	}
+
+	dist := keeper.GetOrderSizeDistribution("XYZ-000_BNB", []int64{1e8, 1e9})
+	assert.Equal("XYZ-000_BNB", dist.Symbol)
+	assert.Equal([]utils.Fixed8{1e8, 1e9}, dist.Boundaries)
+	// below 1, [1, 10) and at or above 10
+	assert.Equal([]store.OrderSizeBucket{{Orders: 1, Quantity: 5e7}, {Orders: 1, Quantity: 2e8}, {Orders: 2, Quantity: 31e9}}, dist.Bids)
+	assert.Equal([]store.OrderSizeBucket{{}, {Orders: 2, Quantity: 10e8}, {}}, dist.Asks)
+
+	// the remaining quantities are counted
+	keeper.engines["XYZ-000_BNB"].Book.GetPriceLevel(102e6, me.SELLSIDE).Orders[0].CumQty = 85e7
+	dist = keeper.GetOrderSizeDistribution("XYZ-000_BNB", DefaultOrderSizeBoundaries)
+	assert.Len(dist.Asks, len(DefaultOrderSizeBoundaries)+1)
+	assert.Equal(store.OrderSizeBucket{Orders: 1, Quantity: 5e7}, dist.Asks[0])
+	assert.Equal(store.OrderSizeBucket{Orders: 1, Quantity: 1e8}, dist.Asks[1])
+	assert.Equal(store.OrderSizeBucket{Orders: 1, Quantity: 3e10}, dist.Bids[3])
+
+	dist = keeper.GetOrderSizeDistribution("ABC-000_BNB", nil)
+	assert.Equal([]store.OrderSizeBucket{{}}, dist.Bids)
+}
+
+func TestKeeper_EstimateSlippage(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+
+	estimate := keeper.EstimateSlippage("XYZ-000_BNB", Side.BUY, 1e8)
+	assert.True(estimate.InsufficientLiquidity)
+	assert.Equal(store.NoSpread, estimate.SlippageBps)
+
+	for id, price := range map[string]int64{"1": 100e6, "2": 101e6, "3": 104e6} {
+		msg := NewNewOrderMsg(accAdd, id, Side.SELL, "XYZ-000_BNB", price, 1e8)
+		keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	}
+	// filled at the best ask
+	estimate = keeper.EstimateSlippage("XYZ-000_BNB", Side.BUY, 1e8)
+	assert.Equal(store.SlippageEstimate{"XYZ-000_BNB", Side.BUY, 1e8, 100e6, 100e6, 1e8, 0, false}, estimate)
+	// (100 + 101 + 104 / 2) / 2.5 = 101.2
+	estimate = keeper.EstimateSlippage("XYZ-000_BNB", Side.BUY, 25e7)
+	assert.Equal(store.SlippageEstimate{"XYZ-000_BNB", Side.BUY, 25e7, 100e6, 1012e5, 25e7, 120, false}, estimate)
+	// the same estimate in the decimal format
+	assert.Equal(store.SlippageEstimateDecimal{"XYZ-000_BNB", Side.BUY, "2.50000000", "1.00000000", "1.01200000", "2.50000000", 120, false}, estimate.ToDecimal())
+	// beyond the liquidity
+	estimate = keeper.EstimateSlippage("XYZ-000_BNB", Side.BUY, 4e8)
+	assert.True(estimate.InsufficientLiquidity)
+	assert.Equal(int64(3e8), estimate.FilledQty.ToInt64())
+	assert.Equal(int64(101666666), estimate.AvgPrice.ToInt64())
+	// no bids
+	estimate = keeper.EstimateSlippage("XYZ-000_BNB", Side.SELL, 1e8)
+	assert.True(estimate.InsufficientLiquidity)
+	assert.Equal(int64(0), estimate.FilledQty.ToInt64())
+	// the book is not changed
+	assert.Equal(int64(100e6), keeper.GetSpread("XYZ-000_BNB").BestAsk.ToInt64())
+	assert.Equal(int64(1e8), keeper.GetPriceLevel("XYZ-000_BNB", Side.SELL, 100e6).TotalLeavesQty())
+}
+
+func TestKeeper_MatchBatchSize(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, logger)
+	accAdd, _ := MakeAddress()
+	for _, base := range []string{"AAA-000", "XYZ-000", "ZCB-000"} {
+		keeper.AddEngine(dextypes.NewTradingPair(base, "BNB", 1e8))
+	}
+	params := dextypes.DefaultDexParams()
+	params.MatchBatchSize = 3
+	keeper.setParams(ctx, params)
+	// not in effect until the breathe block
+	assert.Equal(int64(0), keeper.GetEngineConfig(ctx).MatchBatchSize)
+	keeper.UpdateMatchBatchSize(ctx)
+	assert.Equal(int64(3), keeper.GetEngineConfig(ctx).MatchBatchSize)
+
+	addCrossedOrders := func(symbol, idPrefix string, height int64) {
+		msg := NewNewOrderMsg(accAdd, idPrefix+"1", Side.BUY, symbol, 1e8, 1e8)
+		keeper.AddOrder(OrderInfo{msg, height, 0, height, 0, 0, "", 0}, false)
+		msg = NewNewOrderMsg(accAdd, idPrefix+"2", Side.SELL, symbol, 1e8, 1e8)
+		keeper.AddOrder(OrderInfo{msg, height, 0, height, 0, 0, "", 0}, false)
+	}
+	matched := func(orderId string) bool {
+		_, ok := keeper.GetOrderFills(orderId)
+		return ok
+	}
+
+	addCrossedOrders("AAA-000_BNB", "a1", 43)
+	addCrossedOrders("XYZ-000_BNB", "x1", 43)
+	addCrossedOrders("ZCB-000_BNB", "z1", 43)
+	keeper.MatchSymbols(43, 86, false)
+	assert.True(matched("a11"))
+	// the next symbol doesn't fit, and the ones after it wait in the line even if they would fit
+	assert.False(matched("x11"))
+	assert.False(matched("z11"))
+	assert.Equal(store.MatchBacklog{Height: 43, BatchSize: 3, Symbols: []store.DeferredSymbol{
+		{Symbol: "XYZ-000_BNB", Orders: 2, Since: 43},
+		{Symbol: "ZCB-000_BNB", Orders: 2, Since: 43},
+	}}, keeper.GetMatchBacklog())
+
+	// the deferred symbols go first
+	addCrossedOrders("AAA-000_BNB", "a2", 44)
+	keeper.MatchSymbols(44, 88, false)
+	assert.True(matched("x11"))
+	assert.False(matched("z11"))
+	assert.False(matched("a21"))
+	assert.Equal([]store.DeferredSymbol{
+		{Symbol: "ZCB-000_BNB", Orders: 2, Since: 43},
+		{Symbol: "AAA-000_BNB", Orders: 2, Since: 44},
+	}, keeper.GetMatchBacklog().Symbols)
+
+	// everything is matched in the breathe blocks
+	keeper.MatchSymbols(45, 90, true)
+	assert.True(matched("z11"))
+	assert.True(matched("a21"))
+	assert.Empty(keeper.GetMatchBacklog().Symbols)
+	_, pendingMatch := keeper.GetOrderBookLevels("AAA-000_BNB", 1)
+	assert.False(pendingMatch)
+}
+
+func TestKeeper_ScheduledHalt(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	accAdd, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	symbol := "XYZ-000_BNB"
+	upgrade.Mgr.AddUpgradeHeight(upgrade.GovernedParams, -1)
+	defer resetChainVersion()
+	params := dextypes.DefaultDexParams()
+	params.HaltSchedule = []dextypes.HaltWindow{{Start: 1000, End: 2000}}
+	assert.NoError(params.Check())
+	invalid := params
+	invalid.HaltSchedule = []dextypes.HaltWindow{{Start: 2000, End: 2000}}
+	assert.Error(invalid.Check())
+	ctxAt := func(height, unixSec int64) sdk.Context {
+		return sdk.NewContext(cms, abci.Header{Height: height, Time: time.Unix(unixSec, 0)}, sdk.RunTxModeDeliver, logger)
+	}
+	ctx := ctxAt(42, 999)
+	keeper.setParams(ctx, params)
+
+	keeper.UpdateScheduledHalt(ctx)
+	assert.False(keeper.IsMatchingPaused(ctx, 42))
+	assert.Equal("", keeper.GetScheduledHaltEvent(ctx, 42))
+
+	// the window starts, the orders are still accepted but not matched
+	ctx = ctxAt(43, 1000)
+	keeper.UpdateScheduledHalt(ctx)
+	assert.True(keeper.IsMatchingPaused(ctx, 43))
+	assert.Equal(HaltStarted, keeper.GetScheduledHaltEvent(ctx, 43))
+	assert.True(keeper.GetEngineConfig(ctx).ScheduledHalt)
+	msg := NewNewOrderMsg(accAdd, "1", Side.BUY, symbol, 1e8, 1e8)
+	assert.NoError(keeper.AddOrder(OrderInfo{msg, 43, 0, 43, 0, 0, "", 0}, false))
+	msg = NewNewOrderMsg(accAdd, "2", Side.SELL, symbol, 1e8, 1e8)
+	assert.NoError(keeper.AddOrder(OrderInfo{msg, 43, 0, 43, 0, 0, "", 0}, false))
+
+	ctx = ctxAt(44, 1999)
+	keeper.UpdateScheduledHalt(ctx)
+	assert.True(keeper.IsMatchingPaused(ctx, 44))
+	assert.Equal("", keeper.GetScheduledHaltEvent(ctx, 44))
+
+	// the window ends, the orders accumulated during the halt are matched
+	ctx = ctxAt(45, 2000)
+	keeper.UpdateScheduledHalt(ctx)
+	assert.False(keeper.IsMatchingPaused(ctx, 45))
+	assert.True(keeper.IsMatchingResumedAt(ctx, 45))
+	assert.Equal(HaltEnded, keeper.GetScheduledHaltEvent(ctx, 45))
+	keeper.MatchSymbols(45, 2000e9, true)
+	_, ok := keeper.GetOrderFills("1")
+	assert.True(ok)
+
+	// the matching stays paused by governance after the window ends
+	params.HaltSchedule = []dextypes.HaltWindow{{Start: 3000, End: 4000}}
+	keeper.setParams(ctx, params)
+	ctx = ctxAt(46, 3000)
+	keeper.UpdateScheduledHalt(ctx)
+	paused := params
+	paused.MatchingPaused = true
+	keeper.updateParams(ctxAt(47, 3500), paused)
+	ctx = ctxAt(48, 4000)
+	keeper.UpdateScheduledHalt(ctx)
+	assert.Equal(HaltEnded, keeper.GetScheduledHaltEvent(ctx, 48))
+	assert.True(keeper.IsMatchingPaused(ctx, 48))
+	assert.Equal([]MatchingPause{{From: 43, To: 45}, {From: 46}}, keeper.getMatchingPauses(ctx))
+}
+
+func TestKeeper_MatchingPausedByParams(t *testing.T) {
+	setChainVersion()
+	defer resetChainVersion()
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.FixZeroBalance, -1)
+	defer fees.Pool.Clear()
+	assert := assert.New(t)
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	symbol := "XYZ-000_BNB"
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	ctxAt := func(height int64) sdk.Context {
+		return ctx.WithBlockHeight(height)
+	}
+
+	// the dex params can't be changed before the upgrade
+	params := dextypes.DefaultDexParams()
+	params.MatchingPaused = true
+	upgrade.Mgr.AddUpgradeHeight(upgrade.GovernedParams, math.MaxInt64)
+	assert.Error(params.Check())
+	upgrade.Mgr.AddUpgradeHeight(upgrade.GovernedParams, -1)
+	assert.NoError(params.Check())
+
+	newAccount := func(locked sdk.Coin) sdk.AccAddress {
+		_, acc := testutils.NewAccount(ctx, am, 1e8)
+		acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{locked})
+		am.SetAccount(ctx, acc)
+		return acc.GetAddress()
+	}
+	buyer := newAccount(sdk.NewCoin("BNB", 1e8))
+	seller := newAccount(sdk.NewCoin("XYZ-000", 2e8))
+	addOrder := func(addr sdk.AccAddress, id string, side int8, timeInForce int8, height int64) {
+		msg := NewNewOrderMsg(addr, id, side, symbol, 1e8, 1e8)
+		msg.TimeInForce = timeInForce
+		assert.NoError(keeper.AddOrder(OrderInfo{msg, height, 0, height, 0, 0, "", 0}, false))
+	}
+	orderExists := func(id string) bool {
+		_, ok := keeper.OrderExists(symbol, id)
+		return ok
+	}
+
+	// the pause passed in the breathe block takes effect since the next block
+	keeper.updateParams(ctxAt(10), params)
+	assert.True(keeper.GetParams(ctx).MatchingPaused)
+	assert.False(keeper.IsMatchingPaused(ctx, 10))
+	assert.True(keeper.IsMatchingPaused(ctx, 11))
+
+	// the GTE order is kept for the matching after the pause, the IOC order is expired in its own block
+	addOrder(buyer, "b1", Side.BUY, TimeInForce.GTE, 11)
+	addOrder(seller, "s1", Side.SELL, TimeInForce.IOC, 11)
+	keeper.MatchAndAllocateSymbols(ctxAt(11), nil, false)
+	assert.True(orderExists("b1"))
+	assert.False(orderExists("s1"))
+	assert.Equal(int64(1e8), am.GetAccount(ctx, seller).(types.NamedAccount).GetLockedCoins().AmountOf("XYZ-000"))
+	assert.Equal([]string{"b1"}, keeper.mustGetOrderKeeper(symbol).getRoundOrdersForPair(symbol))
+	assert.Empty(keeper.mustGetOrderKeeper(symbol).getRoundIOCOrdersForPair(symbol))
+
+	addOrder(seller, "s2", Side.SELL, TimeInForce.GTE, 12)
+	keeper.MatchAndAllocateSymbols(ctxAt(12), nil, false)
+	assert.True(orderExists("b1"))
+	assert.True(orderExists("s2"))
+
+	// the orders accumulated during the pause are matched once it's resumed
+	params.MatchingPaused = false
+	keeper.updateParams(ctxAt(12), params)
+	assert.True(keeper.IsMatchingResumedAt(ctx, 13))
+	keeper.MatchAndAllocateSymbols(ctxAt(13), nil, false)
+	assert.False(orderExists("b1"))
+	assert.False(orderExists("s2"))
+	assert.Len(keeper.engines[symbol].Trades, 1)
+	assert.Equal([]MatchingPause{{From: 11, To: 13}}, keeper.getMatchingPauses(ctx))
+}
+
+func TestKeeper_PairSession(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	keeper.CollectOrderInfoForPublish = true
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	accAdd, _ := MakeAddress()
+	symbol := "XYZ-000_BNB"
+	pair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	// 01:00 - 02:00 UTC
+	pair.SessionOpen, pair.SessionClose = 3600, 7200
+	keeper.AddEngine(pair)
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "BNB", 1e8))
+	ctxAt := func(height, unixSec int64) sdk.Context {
+		return sdk.NewContext(cms, abci.Header{Height: height, Time: time.Unix(unixSec, 0)}, sdk.RunTxModeDeliver, logger)
+	}
+
+	// before the session, no event for the first block
+	ctx := ctxAt(42, 3599)
+	keeper.UpdatePairSessions(ctx)
+	assert.Empty(keeper.GetSessionEvents())
+	err := keeper.checkPairSession(ctx, symbol)
+	assert.Equal(dextypes.CodePairOutOfSession, err.Code())
+	assert.Nil(keeper.checkPairSession(ctx, "ABC-000_BNB"))
+	msg := NewNewOrderMsg(accAdd, "1", Side.BUY, symbol, 1e8, 1e8)
+	assert.NoError(keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false))
+	msg = NewNewOrderMsg(accAdd, "2", Side.SELL, symbol, 1e8, 1e8)
+	assert.NoError(keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false))
+	msg = NewNewOrderMsg(accAdd, "3", Side.SELL, symbol, 1e8, 1e8)
+	msg.TimeInForce = TimeInForce.IOC
+	assert.NoError(keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false))
+	// not matched even in a breathe block, the round orders are kept but the IOC order is expired
+	keeper.MatchSymbols(42, 3599e9, true)
+	_, ok := keeper.GetOrderFills("1")
+	assert.False(ok)
+	_, ok = keeper.OrderExists(symbol, "3")
+	assert.False(ok)
+	assert.Len(keeper.mustGetOrderKeeper(symbol).getRoundOrdersForPair(symbol), 2)
+
+	// the session opens
+	ctx = ctxAt(43, 3600)
+	keeper.UpdatePairSessions(ctx)
+	assert.Equal([]SessionEvent{{symbol, SessionOpened}}, keeper.GetSessionEvents())
+	keeper.ClearSessionEvents()
+	assert.Nil(keeper.checkPairSession(ctx, symbol))
+	keeper.MatchSymbols(43, 3600e9, false)
+	_, ok = keeper.GetOrderFills("1")
+	assert.True(ok)
+
+	ctx = ctxAt(44, 7199)
+	keeper.UpdatePairSessions(ctx)
+	assert.Empty(keeper.GetSessionEvents())
+
+	// the session closes, the next day it opens again
+	ctx = ctxAt(45, 7200)
+	keeper.UpdatePairSessions(ctx)
+	assert.Equal([]SessionEvent{{symbol, SessionClosed}}, keeper.GetSessionEvents())
+	keeper.ClearSessionEvents()
+	assert.NotNil(keeper.checkPairSession(ctx, symbol))
+	assert.Nil(keeper.checkPairSession(ctxAt(46, dextypes.SecondsPerDay+3600), symbol))
+
+	// a session over the midnight
+	session := dextypes.TradingSession{Open: 82800, Close: 3600}
+	assert.True(session.IsOpenAt(82800))
+	assert.True(session.IsOpenAt(dextypes.SecondsPerDay + 3599))
+	assert.False(session.IsOpenAt(3600))
+	assert.False(session.IsOpenAt(82799))
+	assert.True(dextypes.TradingSession{Open: 100, Close: 100}.IsOpenAt(0))
+
+	// the session is removed by governance
+	upgrade.Mgr.AddUpgradeHeight(upgrade.GovernedParams, -1)
+	defer resetChainVersion()
+	sessionOpen, sessionClose := int64(0), int64(0)
+	change := dextypes.PairParamsChange{Symbol: symbol, SessionOpen: &sessionOpen, SessionClose: &sessionClose}
+	assert.NoError(change.Check())
+	updated := change.Apply(pair)
+	keeper.setPairSession(symbol, updated.GetSession())
+	assert.Nil(keeper.checkPairSession(ctx, symbol))
+	sessionClose = dextypes.SecondsPerDay
+	assert.Error(change.Check())
+}
+
+func TestKeeper_CheckRestingOrdersOnSizes(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	pair := dextypes.NewTradingPairWithLotSize("XYZ-000", "BNB", 1e8, 1e6)
+	keeper.AddEngine(pair)
+	symbol := pair.GetSymbol()
+
+	msg := NewNewOrderMsg(accAdd, "1", Side.BUY, symbol, 99e6, 12e6)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(accAdd, "2", Side.SELL, symbol, 1011e5, 15e6)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+
+	// finer sizes always keep the resting orders valid
+	assert.NoError(keeper.checkRestingOrdersOnSizes(symbol, 1, 1))
+	assert.NoError(keeper.checkRestingOrdersOnSizes(symbol, 1e5, 1e6))
+	// coarser sizes are rejected once any resting order is off them
+	assert.Error(keeper.checkRestingOrdersOnSizes(symbol, 1e6, 1e6))
+	assert.Error(keeper.checkRestingOrdersOnSizes(symbol, 1e5, 1e7))
+
+	// only the remaining quantity needs to be on the lot size
+	orders := keeper.GetAllOrdersForPair(symbol)
+	orders["1"].CumQty = 2e6
+	assert.Error(keeper.checkRestingOrdersOnSizes(symbol, 1e5, 1e7))
+	orders["2"].CumQty = 5e6
+	assert.NoError(keeper.checkRestingOrdersOnSizes(symbol, 1e5, 1e7))
+
+	// the sizes changed by governance are no longer adjusted automatically
+	tickSize, lotSize := int64(1e5), int64(1e7)
+	change := dextypes.PairParamsChange{Symbol: symbol, TickSize: &tickSize, LotSize: &lotSize}
+	assert.Error(change.Check())
+	upgrade.Mgr.AddUpgradeHeight(upgrade.GovernedParams, -1)
+	defer resetChainVersion()
+	assert.NoError(change.Check())
+	updated := change.Apply(pair)
+	assert.True(updated.SizesFixed)
+	assert.Equal(int64(1e5), updated.TickSize.ToInt64())
+	assert.Equal(int64(1e7), updated.LotSize.ToInt64())
+
+	// the change is applied by the param hub, unless a resting order is off the new sizes
+	ctx := sdk.NewContext(MakeCMS(nil), abci.Header{Height: 50}, sdk.RunTxModeDeliver, log.NewNopLogger())
+	assert.NoError(keeper.PairMapper.AddTradingPair(ctx, pair))
+	coarse := int64(1e6)
+	keeper.updatePairParams(ctx, dextypes.PairParamsChange{Symbol: symbol, TickSize: &coarse})
+	stored, err := keeper.PairMapper.GetTradingPair(ctx, "XYZ-000", "BNB")
+	assert.NoError(err)
+	assert.Equal(pair, stored)
+	assert.Empty(keeper.GetPairSizesUpdates())
+	keeper.updatePairParams(ctx, change)
+	stored, err = keeper.PairMapper.GetTradingPair(ctx, "XYZ-000", "BNB")
+	assert.NoError(err)
+	assert.Equal(updated, stored)
+	assert.Equal([]dextypes.TradingPair{updated}, keeper.GetPairSizesUpdates())
+
+	tickSize = 3e5
+	assert.Error(change.Check())
+}
+
+func TestKeeper_GetAccountTrades(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	buyer, _ := MakeAddress()
+	seller, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+
+	assert.Empty(keeper.GetAccountTrades(buyer))
+
+	msg := NewNewOrderMsg(buyer, "1", Side.BUY, "XYZ-000_BNB", 1e8, 3e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(seller, "2", Side.SELL, "XYZ-000_BNB", 1e8, 1e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	keeper.MatchSymbols(42, 84, false)
+
+	trades := keeper.GetAccountTrades(buyer)
+	assert.Equal([]store.AccountTrade{{TradeId: "42-0", Symbol: "XYZ-000_BNB", Side: Side.BUY,
+		Price: 1e8, Quantity: 1e8, Height: 42, Timestamp: 84}}, trades)
+	trades = keeper.GetAccountTrades(seller)
+	assert.Len(trades, 1)
+	assert.Equal(Side.SELL, trades[0].Side)
+
+	msg = NewNewOrderMsg(seller, "3", Side.SELL, "XYZ-000_BNB", 1e8, 2e8)
+	keeper.AddOrder(OrderInfo{msg, 43, 0, 43, 0, 0, "", 0}, false)
+	keeper.MatchSymbols(43, 86, false)
+	trades = keeper.GetAccountTrades(buyer)
+	assert.Len(trades, 2)
+	assert.Equal(int64(43), trades[1].Height)
+	assert.Equal(int64(2e8), trades[1].Quantity.ToInt64())
+
+	// the trades out of the window are dropped
+	keeper.MatchSymbols(42+AccountTradesWindowBlocks, 90, false)
+	trades = keeper.GetAccountTrades(buyer)
+	assert.Len(trades, 1)
+	assert.Equal(int64(43), trades[0].Height)
+
+	// the trades of a block are numbered across the symbols in alphabetical order, as they are published
+	keeper.AddEngine(dextypes.NewTradingPair("AAA-000", "BNB", 1e8))
+	height := int64(43 + AccountTradesWindowBlocks)
+	for i, symbol := range []string{"XYZ-000_BNB", "AAA-000_BNB"} {
+		msg = NewNewOrderMsg(buyer, fmt.Sprintf("%d", 4+2*i), Side.BUY, symbol, 1e8, 1e8)
+		keeper.AddOrder(OrderInfo{msg, height, 0, height, 0, 0, "", 0}, false)
+		msg = NewNewOrderMsg(seller, fmt.Sprintf("%d", 5+2*i), Side.SELL, symbol, 1e8, 1e8)
+		keeper.AddOrder(OrderInfo{msg, height, 0, height, 0, 0, "", 0}, false)
+	}
+	keeper.MatchSymbols(height, 92, false)
+	trades = keeper.GetAccountTrades(buyer)
+	assert.Len(trades, 2)
+	assert.Equal(fmt.Sprintf("%d-0", height), trades[0].TradeId)
+	assert.Equal("AAA-000_BNB", trades[0].Symbol)
+	assert.Equal(fmt.Sprintf("%d-1", height), trades[1].TradeId)
+	assert.Equal("XYZ-000_BNB", trades[1].Symbol)
+
+	keeper.SetAccountTradesCacheSize(0)
+	assert.False(keeper.AccountTradesEnabled())
+	assert.Empty(keeper.GetAccountTrades(buyer))
+}
+
+func TestKeeper_OrderRecords(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeDeliver, log.NewNopLogger()).
+		WithValue(baseapp.TxHashKey, "txhash")
+	keeper.EnableAckPublish()
+	keeper.EnableRejectionPublish()
+	accAdd, _ := MakeAddress()
+	msg := NewNewOrderMsg(accAdd, "1", Side.BUY, "XYZ-000_BNB", 1e8, 1e8)
+	rejected := sdk.ErrUnknownRequest("rejected").Result()
+
+	// the orders are kept per the result of their txs
+	keeper.recordOrderAck(ctx, msg, sdk.Result{})
+	keeper.CommitOrderRecords(false)
+	assert.Empty(keeper.GetOrderAcks())
+	keeper.recordOrderAck(ctx, msg, sdk.Result{})
+	keeper.CommitOrderRecords(true)
+	assert.Equal([]OrderAck{{"1", "XYZ-000_BNB", accAdd, Side.BUY, 1e8, 1e8, TimeInForce.GTE, "txhash"}}, keeper.GetOrderAcks())
+	keeper.recordOrderRejection(ctx, msg, rejected)
+	keeper.CommitOrderRecords(false)
+	assert.Len(keeper.GetOrderRejections(), 1)
+	assert.Equal(rejected.Log, keeper.GetOrderRejections()[0].Reason)
+	assert.Len(keeper.GetOrderAcks(), 1)
+
+	// nor collected in CheckTx
+	keeper.recordOrderAck(ctx.WithRunTxMode(sdk.RunTxModeCheck), msg, sdk.Result{})
+	keeper.CommitOrderRecords(true)
+	assert.Len(keeper.GetOrderAcks(), 1)
+
+	keeper.ClearOrderAcks()
+	keeper.ClearOrderRejections()
+	assert.Empty(keeper.GetOrderAcks())
+	assert.Empty(keeper.GetOrderRejections())
+}
+
+func TestKeeper_VerifyLockedCoins(t *testing.T) {
+	assert := assert.New(t)
+	ctx, am, keeper := setup()
+	_, buyer := testutils.NewAccount(ctx, am, 0)
+	_, seller := testutils.NewAccount(ctx, am, 0)
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	// the accounts are verified as committed, i.e. written through the account cache
+	verify := func() []LockedMismatch {
+		ctx.AccountCache().Write()
+		return keeper.VerifyLockedCoins(ctx)
+	}
+
+	msg := NewNewOrderMsg(buyer.GetAddress(), "1", Side.BUY, "XYZ-000_BNB", 1e8, 2e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(seller.GetAddress(), "2", Side.SELL, "XYZ-000_BNB", 2e8, 3e8)
+	keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	buyer.(types.NamedAccount).SetLockedCoins(sdk.Coins{sdk.NewCoin("BNB", 2e8)})
+	am.SetAccount(ctx, buyer)
+	seller.(types.NamedAccount).SetLockedCoins(sdk.Coins{sdk.NewCoin("XYZ-000", 3e8)})
+	am.SetAccount(ctx, seller)
+	assert.Empty(verify())
+
+	// a partially filled order only reserves its remaining quantity
+	keeper.GetAllOrdersForPair("XYZ-000_BNB")["1"].CumQty = 5e7
+	buyer.(types.NamedAccount).SetLockedCoins(sdk.Coins{sdk.NewCoin("BNB", 15e7)})
+	am.SetAccount(ctx, buyer)
+	assert.Empty(verify())
+
+	// the notional may be rounded differently, by up to lockRoundingTolerance per order
+	buyer.(types.NamedAccount).SetLockedCoins(sdk.Coins{sdk.NewCoin("BNB", 15e7+lockRoundingTolerance)})
+	am.SetAccount(ctx, buyer)
+	assert.Empty(verify())
+	buyer.(types.NamedAccount).SetLockedCoins(sdk.Coins{sdk.NewCoin("BNB", 15e7+2*lockRoundingTolerance)})
+	am.SetAccount(ctx, buyer)
+	assert.Len(verify(), 1)
+	buyer.(types.NamedAccount).SetLockedCoins(sdk.Coins{sdk.NewCoin("BNB", 15e7)})
+	am.SetAccount(ctx, buyer)
+
+	// inject a mismatch
+	seller.(types.NamedAccount).SetLockedCoins(sdk.Coins{sdk.NewCoin("XYZ-000", 1e8)})
+	am.SetAccount(ctx, seller)
+	mismatches := verify()
+	assert.Equal([]LockedMismatch{{
+		Address:  seller.GetAddress(),
+		Reserved: sdk.Coins{sdk.NewCoin("XYZ-000", 3e8)},
+		Locked:   sdk.Coins{sdk.NewCoin("XYZ-000", 1e8)},
+	}}, mismatches)
+
+	// coins locked without any open order
+	keeper.RemoveOrder("2", "XYZ-000_BNB", nil)
+	mismatches = verify()
+	assert.Len(mismatches, 1)
+	assert.Empty(mismatches[0].Reserved)
+}
+
+func TestKeeper_MakerRebate(t *testing.T) {
+	setChainVersion()
+	defer resetChainVersion()
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.FixZeroBalance, -1)
+	defer fees.Pool.Clear()
+	assert := assert.New(t)
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "BNB", 1e8))
+	params := dextypes.DefaultDexParams()
+	params.MakerRebateRate = 5000
+	params.MakerRebateMaxSpread = 200
+	keeper.setParams(ctx, params)
+
+	newAccount := func(locked sdk.Coin) sdk.AccAddress {
+		_, acc := testutils.NewAccount(ctx, am, 1e8)
+		acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{locked})
+		am.SetAccount(ctx, acc)
+		return acc.GetAddress()
+	}
+	nearMaker := newAccount(sdk.NewCoin("BNB", 99e6))
+	farMaker := newAccount(sdk.NewCoin("BNB", 96e6))
+	asker := newAccount(sdk.NewCoin("XYZ-000", 1e8))
+	xyzTaker := newAccount(sdk.NewCoin("XYZ-000", 1e8))
+	abcTaker := newAccount(sdk.NewCoin("ABC-000", 1e8))
+	addOrder := func(addr sdk.AccAddress, id string, side int8, symbol string, price int64, height int64) {
+		msg := NewNewOrderMsg(addr, id, side, symbol, price, 1e8)
+		assert.NoError(keeper.AddOrder(OrderInfo{msg, height, 0, height, 0, 0, "", 0}, false))
+	}
+
+	// the mid of the resting books is 1e8 for both pairs
+	addOrder(nearMaker, "m1", Side.BUY, "XYZ-000_BNB", 99e6, 10)
+	addOrder(asker, "m2", Side.SELL, "XYZ-000_BNB", 101e6, 10)
+	addOrder(farMaker, "m3", Side.BUY, "ABC-000_BNB", 96e6, 10)
+	addOrder(asker, "m4", Side.SELL, "ABC-000_BNB", 104e6, 10)
+	keeper.MatchSymbols(10, 0, false)
+
+	addOrder(xyzTaker, "t1", Side.SELL, "XYZ-000_BNB", 99e6, 11)
+	addOrder(abcTaker, "t2", Side.SELL, "ABC-000_BNB", 96e6, 11)
+	keeper.MatchAndAllocateSymbols(ctx.WithBlockHeader(abci.Header{Height: 11}), nil, false)
+
+	// the maker 100 bps away from the mid gets half of the taker's fee instead of paying its own
+	trade := keeper.engines["XYZ-000_BNB"].Trades[0]
+	assert.Equal(int8(me.SellTaker), trade.TickType)
+	assert.Equal("BNB:49500", trade.SellerFee.String())
+	assert.Equal("BNB:-24750", trade.BuyerFee.String())
+	assert.Equal(int64(1e8+24750), am.GetAccount(ctx, nearMaker).GetCoins().AmountOf("BNB"))
+	assert.Equal(int64(1e8+99e6-49500), am.GetAccount(ctx, xyzTaker).GetCoins().AmountOf("BNB"))
+
+	// the maker 400 bps away from the mid is not eligible
+	trade = keeper.engines["ABC-000_BNB"].Trades[0]
+	assert.Equal(int8(me.SellTaker), trade.TickType)
+	assert.Equal("BNB:48000", trade.SellerFee.String())
+	assert.Equal("BNB:48000", trade.BuyerFee.String())
+	assert.Equal(int64(1e8-48000), am.GetAccount(ctx, farMaker).GetCoins().AmountOf("BNB"))
+
+	// the rebate is paid out of the collected fees
+	assert.Equal(sdk.NewFee(sdk.Coins{sdk.NewCoin("BNB", 49500-24750+2*48000)}, sdk.FeeForProposer), fees.Pool.BlockFees())
+}
+
+func TestKeeper_FeeExemption(t *testing.T) {
+	setChainVersion()
+	defer resetChainVersion()
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.FixZeroBalance, -1)
+	defer fees.Pool.Clear()
+	assert := assert.New(t)
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+
+	newAccount := func(locked sdk.Coin) sdk.AccAddress {
+		_, acc := testutils.NewAccount(ctx, am, 1e8)
+		acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{locked})
+		am.SetAccount(ctx, acc)
+		return acc.GetAddress()
+	}
+	buyer := newAccount(sdk.NewCoin("BNB", 1e8))
+	seller := newAccount(sdk.NewCoin("XYZ-000", 1e8))
+	assert.NoError(keeper.SetFeeExempt(ctx, buyer, true, 1))
+	assert.True(keeper.IsFeeExempt(ctx, buyer))
+	assert.False(keeper.IsFeeExempt(ctx, seller))
+
+	buy := NewNewOrderMsg(buyer, "b1", Side.BUY, "XYZ-000_BNB", 1e8, 1e8)
+	assert.NoError(keeper.AddOrder(OrderInfo{buy, 10, 0, 10, 0, 0, "", 0}, false))
+	sell := NewNewOrderMsg(seller, "s1", Side.SELL, "XYZ-000_BNB", 1e8, 1e8)
+	assert.NoError(keeper.AddOrder(OrderInfo{sell, 10, 0, 10, 0, 0, "", 0}, false))
+	keeper.MatchAndAllocateSymbols(ctx.WithBlockHeader(abci.Header{Height: 10}), nil, false)
+
+	trade := keeper.engines["XYZ-000_BNB"].Trades[0]
+	assert.True(IsExemptTradeFee(trade.BuyerFee))
+	assert.Equal("", trade.BuyerFee.String())
+	assert.False(IsExemptTradeFee(trade.SellerFee))
+	assert.Equal("BNB:50000", trade.SellerFee.String())
+	assert.Equal(int64(1e8), am.GetAccount(ctx, buyer).GetCoins().AmountOf("BNB"))
+	assert.Equal(int64(1e8), am.GetAccount(ctx, buyer).GetCoins().AmountOf("XYZ-000"))
+	assert.Equal(int64(2e8-50000), am.GetAccount(ctx, seller).GetCoins().AmountOf("BNB"))
+	assert.Equal(sdk.NewFee(sdk.Coins{sdk.NewCoin("BNB", 50000)}, sdk.FeeForProposer), fees.Pool.BlockFees())
+
+	assert.NoError(keeper.SetFeeExempt(ctx, buyer, false, 3))
+	assert.False(keeper.IsFeeExempt(ctx, buyer))
+	// the older proposals can't be replayed
+	assert.Error(keeper.SetFeeExempt(ctx, buyer, true, 1))
+	assert.Error(keeper.SetFeeExempt(ctx, buyer, true, 3))
+	assert.False(keeper.IsFeeExempt(ctx, buyer))
+	assert.NoError(keeper.SetFeeExempt(ctx, buyer, true, 4))
+	assert.True(keeper.IsFeeExempt(ctx, buyer))
+}
+
+func TestKeeper_DustFeeConversion(t *testing.T) {
+	setChainVersion()
+	defer resetChainVersion()
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.FixZeroBalance, -1)
+	defer fees.Pool.Clear()
+	assert := assert.New(t)
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "BNB", 1e8))
+
+	newAccount := func(locked sdk.Coin) sdk.AccAddress {
+		_, acc := testutils.NewAccount(ctx, am, 1e8)
+		acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{locked})
+		am.SetAccount(ctx, acc)
+		return acc.GetAddress()
+	}
+	xyzBuyer := newAccount(sdk.NewCoin("BNB", 99e6*2+98e6*5))
+	abcBuyer := newAccount(sdk.NewCoin("BNB", 200e8))
+	_, feeAcc := testutils.NewAccount(ctx, am, 0)
+	_ = feeAcc.SetCoins(sdk.Coins{sdk.NewCoin("ABC-000", 100e8), sdk.NewCoin("BNB", 1e8), sdk.NewCoin("XYZ-000", 3e8+12345)})
+	am.SetAccount(ctx, feeAcc)
+	feeAddr := feeAcc.GetAddress()
+
+	addOrder := func(id string, symbol string, price, qty int64) {
+		addr := xyzBuyer
+		if symbol == "ABC-000_BNB" {
+			addr = abcBuyer
+		}
+		msg := NewNewOrderMsg(addr, id, Side.BUY, symbol, price, qty)
+		assert.NoError(keeper.AddOrder(OrderInfo{msg, 10, 0, 10, 0, 0, "", 0}, false))
+	}
+	addOrder("b1", "XYZ-000_BNB", 99e6, 2e8)
+	addOrder("b2", "XYZ-000_BNB", 98e6, 5e8)
+	addOrder("b3", "ABC-000_BNB", 1e8, 200e8)
+	keeper.MatchSymbols(10, 0, false)
+
+	// disabled by default
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 20})
+	keeper.PlaceDustFeeConversions(ctx, feeAddr)
+	assert.Empty(keeper.FinishDustFeeConversions(ctx))
+
+	params := dextypes.DefaultDexParams()
+	params.DustFeeConversionThreshold = 5e8
+	keeper.setParams(ctx, params)
+	keeper.PlaceDustFeeConversions(ctx, feeAddr)
+	// ABC-000 is worth 100 BNB at the best bid, so only XYZ-000 is converted, down to the lot size
+	id := GenerateOrderID(0, feeAddr)
+	ord, ok := keeper.OrderExists("XYZ-000_BNB", id)
+	assert.True(ok)
+	assert.Equal(Side.SELL, ord.Side)
+	assert.Equal(TimeInForce.IOC, ord.TimeInForce)
+	assert.Equal(int64(99e6), ord.Price)
+	assert.Equal(int64(3e8), ord.Quantity)
+	assert.Equal(int64(1), am.GetAccount(ctx, feeAddr).GetSequence())
+	assert.Equal(int64(3e8), am.GetAccount(ctx, feeAddr).(types.NamedAccount).GetLockedCoins().AmountOf("XYZ-000"))
+
+	keeper.MatchAndAllocateSymbols(ctx, nil, true)
+	conversions := keeper.FinishDustFeeConversions(ctx)
+	// only the bid at the best price is filled, the rest of the IOC order is expired and refunded
+	assert.Equal([]DustFeeConversion{{"XYZ-000_BNB", id, 3e8, 2e8, 198e6}}, conversions)
+	trade := keeper.engines["XYZ-000_BNB"].Trades[0]
+	assert.Equal(id, trade.Sid)
+	assert.Equal(int64(2e8), trade.LastQty)
+	assert.True(IsExemptTradeFee(trade.SellerFee))
+	acc := am.GetAccount(ctx, feeAddr).(types.NamedAccount)
+	assert.Equal(int64(1e8+198e6), acc.GetCoins().AmountOf("BNB"))
+	assert.Equal(int64(1e8+12345), acc.GetCoins().AmountOf("XYZ-000"))
+	assert.Equal(int64(100e8), acc.GetCoins().AmountOf("ABC-000"))
+	assert.True(acc.GetLockedCoins().IsZero())
+	_, ok = keeper.OrderExists("XYZ-000_BNB", id)
+	assert.False(ok)
+	// the buyer still pays its fee
+	assert.Equal("BNB:99000", trade.BuyerFee.String())
+
+	// the conversions not matched are cancelled and refunded
+	keeper.PlaceDustFeeConversions(ctx.WithBlockHeader(abci.Header{Height: 21}), feeAddr)
+	id = GenerateOrderID(1, feeAddr)
+	conversions = keeper.FinishDustFeeConversions(ctx.WithBlockHeader(abci.Header{Height: 21}))
+	assert.Equal([]DustFeeConversion{{"XYZ-000_BNB", id, 1e8, 0, 0}}, conversions)
+	_, ok = keeper.OrderExists("XYZ-000_BNB", id)
+	assert.False(ok)
+	acc = am.GetAccount(ctx, feeAddr).(types.NamedAccount)
+	assert.Equal(int64(1e8+12345), acc.GetCoins().AmountOf("XYZ-000"))
+	assert.True(acc.GetLockedCoins().IsZero())
+}
+
+func TestKeeper_OpenInterests(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	keeper.AddEngine(dextypes.NewTradingPair("ZCB-000", "BNB", 1e8))
+	_, ok := keeper.GetOpenInterest("XYZ-000_BNB")
+	assert.False(ok)
+
+	for _, ord := range []struct {
+		id       string
+		side     int8
+		price    int64
+		quantity int64
+	}{{"1", Side.BUY, 99e6, 1e8}, {"2", Side.BUY, 98e6, 2e8}, {"3", Side.BUY, 98e6, 3e8}, {"4", Side.SELL, 101e6, 4e8}} {
+		msg := NewNewOrderMsg(accAdd, ord.id, ord.side, "XYZ-000_BNB", ord.price, ord.quantity)
+		keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	}
+	keeper.RefreshOpenInterests(42)
+	openInterest, ok := keeper.GetOpenInterest("XYZ-000_BNB")
+	assert.True(ok)
+	assert.Equal(store.OpenInterest{Symbol: "XYZ-000_BNB", Height: 42, BidQty: 6e8, AskQty: 4e8}, openInterest)
+	openInterest, ok = keeper.GetOpenInterest("ZCB-000_BNB")
+	assert.True(ok)
+	assert.Equal(store.OpenInterest{Symbol: "ZCB-000_BNB", Height: 42}, openInterest)
+
+	// the open interests are updated as the orders are removed and filled
+	assert.NoError(keeper.RemoveOrder("2", "XYZ-000_BNB", nil))
+	msg := NewNewOrderMsg(accAdd, "5", Side.SELL, "XYZ-000_BNB", 99e6, 5e7)
+	assert.NoError(keeper.AddOrder(OrderInfo{msg, 43, 0, 43, 0, 0, "", 0}, false))
+	keeper.MatchSymbols(43, 0, false)
+	assert.Len(keeper.engines["XYZ-000_BNB"].Trades, 1)
+	keeper.RefreshOpenInterests(43)
+	openInterest, _ = keeper.GetOpenInterest("XYZ-000_BNB")
+	assert.Equal(store.OpenInterest{Symbol: "XYZ-000_BNB", Height: 43, BidQty: 35e7, AskQty: 4e8}, openInterest)
+	// the same as summed from the order book
+	keeper.resetOpenInterest("XYZ-000_BNB")
+	keeper.RefreshOpenInterests(43)
+	recounted, _ := keeper.GetOpenInterest("XYZ-000_BNB")
+	assert.Equal(openInterest, recounted)
+
+	// the delisted pairs are dropped
+	delete(keeper.engines, "ZCB-000_BNB")
+	delete(keeper.pairOpenInterests, "ZCB-000_BNB")
+	keeper.RefreshOpenInterests(44)
+	_, ok = keeper.GetOpenInterest("ZCB-000_BNB")
+	assert.False(ok)
+	openInterest, _ = keeper.GetOpenInterest("XYZ-000_BNB")
+	assert.Equal(int64(44), openInterest.Height)
+}
+
+func TestKeeper_BookUpdateRates(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	keeper.AddEngine(dextypes.NewTradingPair("ZCB-000", "BNB", 1e8))
+	addOrder := func(id string, side int8, symbol string) {
+		msg := NewNewOrderMsg(accAdd, id, side, symbol, 1e8, 1e8)
+		assert.NoError(keeper.AddOrder(OrderInfo{msg, 999, 0, 999, 0, 0, "", 0}, false))
+	}
+	addOrder("1", Side.BUY, "XYZ-000_BNB")
+	addOrder("2", Side.SELL, "XYZ-000_BNB")
+	addOrder("3", Side.BUY, "XYZ-000_BNB")
+	addOrder("4", Side.BUY, "ZCB-000_BNB")
+	assert.NoError(keeper.RemoveOrder("3", "XYZ-000_BNB", nil))
+	keeper.MatchSymbols(999, 0, false)
+
+	// no window has ended yet
+	keeper.EndBookUpdatesBlock(999)
+	assert.Equal(store.BookUpdateRates{Symbols: []store.BookUpdateRate{}}, keeper.GetBookUpdateRates(PairType.BEP2))
+
+	keeper.EndBookUpdatesBlock(1000)
+	assert.Equal(store.BookUpdateRates{StartHeight: 999, EndHeight: 1000, Symbols: []store.BookUpdateRate{
+		{Symbol: "XYZ-000_BNB", Adds: 3, Cancels: 1, Fills: 1, Total: 5},
+		{Symbol: "ZCB-000_BNB", Adds: 1, Total: 1},
+	}}, keeper.GetBookUpdateRates(PairType.BEP2))
+	assert.Empty(keeper.GetBookUpdateRates(PairType.MINI).Symbols)
+
+	// the counts are reset at the window boundary
+	keeper.EndBookUpdatesBlock(2000)
+	assert.Equal(store.BookUpdateRates{StartHeight: 1001, EndHeight: 2000, Symbols: []store.BookUpdateRate{}},
+		keeper.GetBookUpdateRates(PairType.BEP2))
+}
+
+func TestKeeper_OrderCounts(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	accAdd, _ := MakeAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	keeper.AddEngine(dextypes.NewTradingPair("ZCB-000", "BNB", 1e8))
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "BNB", 1e8))
+	_, ok := keeper.GetOrderCounts()
+	assert.False(ok)
+
+	for _, ord := range []struct {
+		id     string
+		symbol string
+	}{{"1", "ZCB-000_BNB"}, {"2", "XYZ-000_BNB"}, {"3", "XYZ-000_BNB"}, {"4", "ABC-000_BNB"}} {
+		msg := NewNewOrderMsg(accAdd, ord.id, Side.BUY, ord.symbol, 99e6, 1e8)
+		keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false)
+	}
+	keeper.RefreshOrderCounts(42)
+	count, ok := keeper.GetOrderCounts()
+	assert.True(ok)
+	assert.Equal(store.OrderCount{Total: 4, Height: 42, Pairs: []store.PairOrderCount{
+		{Symbol: "XYZ-000_BNB", Count: 2}, {Symbol: "ABC-000_BNB", Count: 1}, {Symbol: "ZCB-000_BNB", Count: 1},
+	}}, count)
+
+	// the pairs without open orders are left out
+	keeper.RemoveOrder("1", "ZCB-000_BNB", nil)
+	keeper.RefreshOrderCounts(43)
+	count, _ = keeper.GetOrderCounts()
+	assert.Equal(int64(3), count.Total)
+	assert.Equal([]store.PairOrderCount{{Symbol: "XYZ-000_BNB", Count: 2}, {Symbol: "ABC-000_BNB", Count: 1}}, count.Pairs)
+}
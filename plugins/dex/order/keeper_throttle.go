@@ -0,0 +1,110 @@
+package order
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// blockOrderCounter counts the orders placed by each account in a block, it's reset once the height changes.
+// It's only updated in DeliverTx, so it's the same on all the nodes.
+type blockOrderCounter struct {
+	height int64
+	counts map[string]int64 // str of addr bytes -> number of orders placed in the block
+}
+
+func (c *blockOrderCounter) count(height int64, addr sdk.AccAddress) int64 {
+	if c.height != height {
+		return 0
+	}
+	return c.counts[string(addr.Bytes())]
+}
+
+func (c *blockOrderCounter) increase(height int64, addr sdk.AccAddress) {
+	if c.height != height || c.counts == nil {
+		c.height = height
+		c.counts = make(map[string]int64)
+	}
+	c.counts[string(addr.Bytes())]++
+}
+
+// checkOrderThrottle returns an error if the account has placed as many orders as allowed in the current block.
+func (kp *DexKeeper) checkOrderThrottle(ctx sdk.Context, addr sdk.AccAddress) error {
+	limit := kp.GetParams(ctx).MaxOrdersPerAccountPerBlock
+	if limit <= 0 {
+		return nil
+	}
+	height := ctx.BlockHeight()
+	if kp.blockOrders.count(height, addr) >= limit {
+		return fmt.Errorf("account %s has placed the max number(%d) of orders in block %d", addr, limit, height)
+	}
+	return nil
+}
+
+func (kp *DexKeeper) countOrderInBlock(ctx sdk.Context, addr sdk.AccAddress) {
+	kp.blockOrders.increase(ctx.BlockHeight(), addr)
+}
+
+// GetTotalOrders returns the number of open orders of all the trading pairs.
+func (kp *DexKeeper) GetTotalOrders() int64 {
+	return atomic.LoadInt64(&kp.totalOrders)
+}
+
+func (kp *DexKeeper) addTotalOrders(delta int64) {
+	atomic.AddInt64(&kp.totalOrders, delta)
+}
+
+// dropOrder removes the order from the open orders of its pair and the total orders. The pairs are matched and
+// expired concurrently, so the total orders are updated atomically.
+func (kp *DexKeeper) dropOrder(orders map[string]*OrderInfo, id string) {
+	if _, ok := orders[id]; ok {
+		delete(orders, id)
+		kp.addTotalOrders(-1)
+	}
+}
+
+// RefreshOrderCounts counts the open orders of all the trading pairs. Like RefreshOpenInterests, it's called once
+// per block in the EndBlocker, and the dex/ordercount queries are served from the result.
+func (kp *DexKeeper) RefreshOrderCounts(height int64) {
+	count := store.OrderCount{Height: height}
+	for _, orderKeeper := range kp.OrderKeepers {
+		for symbol, orders := range orderKeeper.getAllOrders() {
+			if len(orders) == 0 {
+				continue
+			}
+			count.Total += int64(len(orders))
+			count.Pairs = append(count.Pairs, store.PairOrderCount{Symbol: symbol, Count: int64(len(orders))})
+		}
+	}
+	sort.Slice(count.Pairs, func(i, j int) bool {
+		if count.Pairs[i].Count != count.Pairs[j].Count {
+			return count.Pairs[i].Count > count.Pairs[j].Count
+		}
+		return count.Pairs[i].Symbol < count.Pairs[j].Symbol
+	})
+	kp.orderCounts.Store(count)
+}
+
+// GetOrderCounts returns the numbers of open orders as of the last block, ok is false before the first block is
+// ended since the node started.
+func (kp *DexKeeper) GetOrderCounts() (count store.OrderCount, ok bool) {
+	count, ok = kp.orderCounts.Load().(store.OrderCount)
+	return count, ok
+}
+
+// checkTotalOrders returns an error if the number of open orders has reached the cap. The existing orders are never
+// evicted to make room, so the order books stay the same on all the nodes.
+func (kp *DexKeeper) checkTotalOrders(ctx sdk.Context) error {
+	limit := kp.GetParams(ctx).MaxTotalOrders
+	if limit <= 0 {
+		return nil
+	}
+	if total := kp.GetTotalOrders(); total >= limit {
+		return fmt.Errorf("there are %d open orders, reaching the max number(%d) of open orders", total, limit)
+	}
+	return nil
+}
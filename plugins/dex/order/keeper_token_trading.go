@@ -0,0 +1,107 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/fees"
+
+	dexTypes "github.com/bnb-chain/node/plugins/dex/types"
+	dexUtils "github.com/bnb-chain/node/plugins/dex/utils"
+	tokenStore "github.com/bnb-chain/node/plugins/tokens/store"
+)
+
+// SetTokenMapper sets the token mapper where the tokens disabled from trading by governance are kept.
+func (kp *DexKeeper) SetTokenMapper(mapper tokenStore.Mapper) {
+	kp.tokenMapper = mapper
+}
+
+// checkTokenTrading rejects the new orders of the pair if the trading of its base or quote token is disabled,
+// the cancels are always allowed.
+func (kp *DexKeeper) checkTokenTrading(ctx sdk.Context, symbol string) sdk.Error {
+	if kp.tokenMapper == nil {
+		return nil
+	}
+	baseAsset, quoteAsset := dexUtils.TradingPair2AssetsSafe(symbol)
+	for _, token := range []string{baseAsset, quoteAsset} {
+		if kp.tokenMapper.IsTradingDisabled(ctx, token) {
+			return dexTypes.ErrTokenTradingDisabled(token, symbol)
+		}
+	}
+	return nil
+}
+
+// TokenTradingEvent is the trading of the base or quote token of a pair disabled or enabled by governance in a
+// block.
+type TokenTradingEvent struct {
+	Symbol   string
+	Token    string
+	Disabled bool
+}
+
+// SetTokenTrading disables or enables the trading of the token, per the passed token trading change proposal,
+// and records it as an event of each listed pair of the token for publication usage.
+func (kp *DexKeeper) SetTokenTrading(ctx sdk.Context, token string, disabled bool, proposalId int64) error {
+	if kp.tokenMapper == nil {
+		return errors.New("token trading is not supported")
+	}
+	if err := kp.tokenMapper.SetTradingDisabled(ctx, token, disabled, proposalId); err != nil {
+		return err
+	}
+	kp.logger.Info("apply token trading change", "proposalId", proposalId, "token", token, "disabled", disabled)
+	if ctx.IsDeliverTx() {
+		kp.recordTokenTradingChange(token, disabled)
+	}
+	return nil
+}
+
+func (kp *DexKeeper) recordTokenTradingChange(token string, disabled bool) {
+	if !kp.CollectOrderInfoForPublish {
+		return
+	}
+	for symbol := range kp.engines {
+		baseAsset, quoteAsset := dexUtils.TradingPair2AssetsSafe(symbol)
+		if baseAsset == token || quoteAsset == token {
+			kp.tokenTradingEvents = append(kp.tokenTradingEvents, TokenTradingEvent{Symbol: symbol, Token: token, Disabled: disabled})
+		}
+	}
+	sort.SliceStable(kp.tokenTradingEvents, func(i, j int) bool {
+		return kp.tokenTradingEvents[i].Symbol < kp.tokenTradingEvents[j].Symbol
+	})
+}
+
+// GetTokenTradingEvents returns the pairs whose token is disabled or enabled from trading in the current block,
+// sorted by the symbol.
+func (kp *DexKeeper) GetTokenTradingEvents() []TokenTradingEvent {
+	return kp.tokenTradingEvents
+}
+
+func (kp *DexKeeper) ClearTokenTradingEvents() {
+	kp.tokenTradingEvents = nil
+}
+
+// ExpireDisabledTokenOrders expires all the open orders of the pairs whose base or quote token is disabled from
+// trading, if ExpireDisabledTokenOrders of the dex params is on. It's called in the breathe blocks, and the orders
+// are refunded without any fee. The pairs stay listed, and take new orders again once the token is enabled.
+func (kp *DexKeeper) ExpireDisabledTokenOrders(ctx sdk.Context, postAllocTransHandler TransferHandler) {
+	if kp.tokenMapper == nil || !kp.GetParams(ctx).ExpireDisabledTokenOrders {
+		return
+	}
+	symbols := make([]string, 0)
+	for symbol := range kp.engines {
+		if kp.checkTokenTrading(ctx, symbol) != nil {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		kp.logger.Info("expire orders of the pair of disabled token", "symbol", symbol)
+		transferChs := kp.expireAllOrders(ctx, symbol, TransferFromTokenDisabled, TokenDisabled)
+		if transferChs != nil {
+			totalFee := kp.allocateAndCalcFee(ctx, transferChs, postAllocTransHandler)
+			fees.Pool.AddAndCommitFee(fmt.Sprintf("TOKEN_DISABLED_%s", symbol), totalFee)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package order
+
+import (
+	"sync"
+
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// MaxTradeRateBlocks is the number of the recent blocks of which the numbers of trades are kept.
+const MaxTradeRateBlocks = 1000
+
+type blockTrades struct {
+	height  int64
+	symbols map[string]int64 // symbol -> number of trades, only the ones traded
+}
+
+// tradeRates keeps the numbers of trades of the symbols in each of the recent blocks, up to MaxTradeRateBlocks
+// blocks in a ring buffer. The counts are not part of the state, so after a restart the rates only cover the blocks
+// matched by the node since.
+type tradeRates struct {
+	mtx    sync.Mutex
+	blocks [MaxTradeRateBlocks]blockTrades
+	next   int // index of the slot of the next block
+	size   int // number of the blocks kept
+}
+
+func newTradeRates() *tradeRates {
+	return &tradeRates{}
+}
+
+func (r *tradeRates) record(height int64, symbols map[string]int64) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.blocks[r.next] = blockTrades{height: height, symbols: symbols}
+	r.next = (r.next + 1) % MaxTradeRateBlocks
+	if r.size < MaxTradeRateBlocks {
+		r.size++
+	}
+}
+
+// last returns the numbers of trades in the latest `blocks` blocks, the oldest first. The trades of the symbols
+// accepted by the filter are counted.
+func (r *tradeRates) last(blocks int, filter func(symbol string) bool) []store.BlockTradeCount {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if blocks > r.size {
+		blocks = r.size
+	}
+	counts := make([]store.BlockTradeCount, 0, blocks)
+	for i := blocks; i > 0; i-- {
+		block := r.blocks[(r.next-i+MaxTradeRateBlocks)%MaxTradeRateBlocks]
+		count := store.BlockTradeCount{Height: block.height}
+		for symbol, trades := range block.symbols {
+			if filter(symbol) {
+				count.Trades += trades
+			}
+		}
+		counts = append(counts, count)
+	}
+	return counts
+}
+
+// RecordTradeCounts keeps the numbers of trades of the pairs matched at the height, it's called once per block in
+// the EndBlocker after the matching.
+func (kp *DexKeeper) RecordTradeCounts(height int64) {
+	symbols := make(map[string]int64)
+	for symbol := range kp.engines {
+		if trades, _ := kp.GetLastTrades(height, symbol); len(trades) > 0 {
+			symbols[symbol] = int64(len(trades))
+		}
+	}
+	kp.tradeRates.record(height, symbols)
+}
+
+// GetTradeRate returns the numbers of trades in each of the latest `blocks` blocks, the oldest first, of the pair if
+// the symbol is given, otherwise of all the pairs of the pair type. The numbers are only known since the node
+// started, so there may be fewer blocks.
+func (kp *DexKeeper) GetTradeRate(pairType SymbolPairType, symbol string, blocks int) store.TradeRate {
+	filter := func(s string) bool {
+		if symbol != "" {
+			return s == symbol
+		}
+		return kp.GetPairType(s) == pairType
+	}
+	return store.TradeRate{Symbol: symbol, Blocks: kp.tradeRates.last(blocks, filter)}
+}
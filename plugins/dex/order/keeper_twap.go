@@ -0,0 +1,112 @@
+package order
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/node/common/utils"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// MaxTWAPWindowSeconds is the max window of the dex/twap query, the prices changed before are dropped.
+const MaxTWAPWindowSeconds = 24 * 60 * 60
+
+type twapSample struct {
+	timestamp int64 // unix seconds of the block since which the price is in effect
+	price     int64
+}
+
+// priceTWAPs keeps the last trade prices of the symbols as of the block times, so that the time-weighted average
+// prices can be calculated over the recent windows. Only the changes of the prices are kept, and like
+// symbolActivities, it's kept in memory by the node only.
+type priceTWAPs struct {
+	mtx     sync.Mutex
+	samples map[string][]twapSample // symbol -> price changes, the oldest first
+}
+
+func newPriceTWAPs() *priceTWAPs {
+	return &priceTWAPs{samples: make(map[string][]twapSample)}
+}
+
+// record keeps the price of the symbol in effect since the timestamp in unix seconds. The changes out of the max
+// window are dropped, except the last one of them, which is the price at the start of the window.
+func (p *priceTWAPs) record(symbol string, timestamp, price int64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	samples := p.samples[symbol]
+	if n := len(samples); n > 0 {
+		if samples[n-1].price == price {
+			return
+		}
+		if samples[n-1].timestamp >= timestamp {
+			// the blocks of the same second
+			samples[n-1].price = price
+			return
+		}
+	}
+	samples = append(samples, twapSample{timestamp, price})
+	start := 0
+	for start+1 < len(samples) && samples[start+1].timestamp <= timestamp-MaxTWAPWindowSeconds {
+		start++
+	}
+	p.samples[symbol] = samples[start:]
+}
+
+func (p *priceTWAPs) delete(symbol string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	delete(p.samples, symbol)
+}
+
+// twap calculates the time-weighted average price of the symbol over the window before now, in unix seconds.
+func (p *priceTWAPs) twap(symbol string, now, window int64) store.TWAP {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	res := store.TWAP{Symbol: symbol, WindowSeconds: window}
+	samples := p.samples[symbol]
+	if len(samples) == 0 {
+		res.InsufficientData = true
+		return res
+	}
+	start := now - window
+	res.InsufficientData = samples[0].timestamp > start
+	var sum big.Int
+	for i, sample := range samples {
+		from, to := sample.timestamp, now
+		if i+1 < len(samples) {
+			to = samples[i+1].timestamp
+		}
+		if from < start {
+			from = start
+		}
+		if to <= from {
+			continue
+		}
+		sum.Add(&sum, new(big.Int).Mul(big.NewInt(sample.price), big.NewInt(to-from)))
+		res.CoveredSeconds += to - from
+	}
+	if res.CoveredSeconds == 0 {
+		// the only price is set in the block of now
+		res.TWAP = utils.Fixed8(samples[len(samples)-1].price)
+		return res
+	}
+	res.TWAP = utils.Fixed8(sum.Quo(&sum, big.NewInt(res.CoveredSeconds)).Int64())
+	return res
+}
+
+// RecordTWAPPrices keeps the last trade prices of all the pairs as of the block time, it's called once per block
+// in the EndBlocker after the matching.
+func (kp *DexKeeper) RecordTWAPPrices(timestamp int64) {
+	for symbol, engine := range kp.engines {
+		if engine.LastTradePrice > 0 {
+			kp.twaps.record(symbol, timestamp, engine.LastTradePrice)
+		}
+	}
+}
+
+// GetTWAP returns the time-weighted average of the last trade price of the pair over the window before now, both
+// in unix seconds. The prices are only known since the node started, so the TWAP is flagged with insufficient
+// data if the window goes beyond, and it's then averaged over the covered part of the window only.
+func (kp *DexKeeper) GetTWAP(symbol string, now, window int64) store.TWAP {
+	return kp.twaps.twap(symbol, now, window)
+}
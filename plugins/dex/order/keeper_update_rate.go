@@ -0,0 +1,95 @@
+package order
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// UpdateRateWindowBlocks is the number of blocks of the windows over which the order book updates are counted.
+// The windows end at the heights of its multiples.
+const UpdateRateWindowBlocks = 1000
+
+// bookUpdates counts the orders added and cancelled and the trades of each symbol over the windows of blocks, the counts
+// of the current window are kept until it ends and then served until the next one ends. Like symbolActivities,
+// it's kept in memory by the node only, so the first window after the node starts is a partial one.
+type bookUpdates struct {
+	mtx         sync.Mutex
+	startHeight int64                            // first height of the current window, 0 if nothing is counted yet
+	current     map[string]*store.BookUpdateRate // symbol -> updates of the current window
+	last        store.BookUpdateRates            // updates of the last ended window
+}
+
+func newBookUpdates() *bookUpdates {
+	return &bookUpdates{current: make(map[string]*store.BookUpdateRate)}
+}
+
+func (u *bookUpdates) add(symbol string, adds, cancels, fills int64) {
+	if adds == 0 && cancels == 0 && fills == 0 {
+		return
+	}
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	rate, ok := u.current[symbol]
+	if !ok {
+		rate = &store.BookUpdateRate{Symbol: symbol}
+		u.current[symbol] = rate
+	}
+	rate.Adds += adds
+	rate.Cancels += cancels
+	rate.Fills += fills
+	rate.Total += adds + cancels + fills
+}
+
+// endBlock ends the current window if the height is at the window boundary, and resets the counts.
+func (u *bookUpdates) endBlock(height int64) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	if u.startHeight == 0 {
+		u.startHeight = height
+	}
+	if height%UpdateRateWindowBlocks != 0 {
+		return
+	}
+	symbols := make([]store.BookUpdateRate, 0, len(u.current))
+	for _, rate := range u.current {
+		symbols = append(symbols, *rate)
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].Total != symbols[j].Total {
+			return symbols[i].Total > symbols[j].Total
+		}
+		return symbols[i].Symbol < symbols[j].Symbol
+	})
+	u.last = store.BookUpdateRates{StartHeight: u.startHeight, EndHeight: height, Symbols: symbols}
+	u.startHeight = height + 1
+	u.current = make(map[string]*store.BookUpdateRate)
+}
+
+func (u *bookUpdates) lastWindow() store.BookUpdateRates {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	return u.last
+}
+
+// EndBookUpdatesBlock is called once per block in the EndBlocker after the matching, it ends the window of the
+// order book updates at the window boundaries.
+func (kp *DexKeeper) EndBookUpdatesBlock(height int64) {
+	kp.bookUpdates.endBlock(height)
+}
+
+// GetBookUpdateRates returns the numbers of the order book updates of the listed symbols of the pair type over the
+// last ended window of UpdateRateWindowBlocks blocks, sorted by the total number descending. The heights are 0 if
+// no window has ended since the node started.
+func (kp *DexKeeper) GetBookUpdateRates(pairType SymbolPairType) store.BookUpdateRates {
+	rates := kp.bookUpdates.lastWindow()
+	symbols := make([]store.BookUpdateRate, 0, len(rates.Symbols))
+	for _, rate := range rates.Symbols {
+		if _, listed := kp.engines[rate.Symbol]; listed && kp.GetPairType(rate.Symbol) == pairType {
+			symbols = append(symbols, rate)
+		}
+	}
+	rates.Symbols = symbols
+	return rates
+}
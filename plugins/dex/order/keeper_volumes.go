@@ -0,0 +1,127 @@
+package order
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	dexUtils "github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+const (
+	MaxTopTraders           = 100
+	MaxTopTradersWindowDays = 7
+)
+
+// traderVolumes accumulates the traded volume of the accounts in each symbol, in the quote asset, by the days
+// between breathe blocks. It's kept in memory by the node only: the current day is rebuilt by replaying the blocks
+// since the last breathe block on start, while the earlier days are lost on restart.
+type traderVolumes struct {
+	mtx  sync.Mutex
+	days []map[string]map[string]int64 // days[0] is the current day, symbol -> string of the address bytes -> volume
+}
+
+func newTraderVolumes() *traderVolumes {
+	return &traderVolumes{days: []map[string]map[string]int64{make(map[string]map[string]int64)}}
+}
+
+// addTrades records the notional of the trades of a symbol to both of the buyer and the seller.
+func (v *traderVolumes) addTrades(symbol string, trades []me.Trade, orders map[string]*OrderInfo) {
+	if len(trades) == 0 {
+		return
+	}
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	volumes, ok := v.days[0][symbol]
+	if !ok {
+		volumes = make(map[string]int64)
+		v.days[0][symbol] = volumes
+	}
+	for i := range trades {
+		t := &trades[i]
+		notional := dexUtils.CalBigNotionalInt64(t.LastPx, t.LastQty)
+		if buy, ok := orders[t.Bid]; ok {
+			addr := string(buy.Sender.Bytes())
+			volumes[addr] = addVolume(volumes[addr], notional)
+		}
+		if sell, ok := orders[t.Sid]; ok {
+			addr := string(sell.Sender.Bytes())
+			volumes[addr] = addVolume(volumes[addr], notional)
+		}
+	}
+}
+
+// roll starts a new day, the days out of the max window are dropped.
+func (v *traderVolumes) roll() {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	days := append([]map[string]map[string]int64{make(map[string]map[string]int64)}, v.days...)
+	if len(days) > MaxTopTradersWindowDays {
+		days = days[:MaxTopTradersWindowDays]
+	}
+	v.days = days
+}
+
+func (v *traderVolumes) top(symbol string, n, windowDays int) []store.TraderVolume {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	if windowDays > len(v.days) {
+		windowDays = len(v.days)
+	}
+	total := make(map[string]int64)
+	for _, day := range v.days[:windowDays] {
+		for addr, volume := range day[symbol] {
+			total[addr] = addVolume(total[addr], volume)
+		}
+	}
+
+	traders := make([]store.TraderVolume, 0, len(total))
+	for addr, volume := range total {
+		traders = append(traders, store.TraderVolume{Address: sdk.AccAddress(addr), Volume: utils.Fixed8(volume)})
+	}
+	sort.Slice(traders, func(i, j int) bool {
+		if traders[i].Volume != traders[j].Volume {
+			return traders[i].Volume > traders[j].Volume
+		}
+		return bytes.Compare(traders[i].Address, traders[j].Address) < 0
+	})
+	if len(traders) > n {
+		traders = traders[:n]
+	}
+	return traders
+}
+
+// addVolume caps the volume at math.MaxInt64 rather than overflowing
+func addVolume(volume, delta int64) int64 {
+	if volume > math.MaxInt64-delta {
+		return math.MaxInt64
+	}
+	return volume + delta
+}
+
+// RollTraderVolumes starts accumulating the volumes of a new day, it's called in the breathe blocks.
+func (kp *DexKeeper) RollTraderVolumes() {
+	kp.traderVolumes.roll()
+}
+
+// GetTopTraders returns at most n accounts with the highest traded volume of the symbol, in the quote asset,
+// over the current day and the (windowDays - 1) days before it, the days are separated by the breathe blocks.
+func (kp *DexKeeper) GetTopTraders(symbol string, n, windowDays int) ([]store.TraderVolume, error) {
+	if n <= 0 || n > MaxTopTraders {
+		return nil, fmt.Errorf("number of traders should be in [1, %d]", MaxTopTraders)
+	}
+	if windowDays <= 0 || windowDays > MaxTopTradersWindowDays {
+		return nil, fmt.Errorf("window should be in [1, %d] days", MaxTopTradersWindowDays)
+	}
+	if _, ok := kp.engines[symbol]; !ok {
+		return nil, fmt.Errorf("trading pair not found: %s", symbol)
+	}
+	return kp.traderVolumes.top(symbol, n, windowDays), nil
+}
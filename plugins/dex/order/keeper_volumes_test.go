@@ -0,0 +1,53 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+func Test_traderVolumes(t *testing.T) {
+	a, _ := MakeAddress()
+	b, _ := MakeAddress()
+	c, _ := MakeAddress()
+	orders := map[string]*OrderInfo{
+		"a-1": {NewOrderMsg: NewOrderMsg{Sender: a, Id: "a-1"}},
+		"b-1": {NewOrderMsg: NewOrderMsg{Sender: b, Id: "b-1"}},
+		"c-1": {NewOrderMsg: NewOrderMsg{Sender: c, Id: "c-1"}},
+	}
+
+	volumes := newTraderVolumes()
+	volumes.addTrades("XYZ-000_BNB", []me.Trade{
+		{Bid: "a-1", Sid: "b-1", LastPx: 1e8, LastQty: 2e8},
+		{Bid: "a-1", Sid: "c-1", LastPx: 1e8, LastQty: 1e8},
+	}, orders)
+	require.Equal(t, []store.TraderVolume{
+		{Address: a, Volume: utils.Fixed8(3e8)},
+		{Address: b, Volume: utils.Fixed8(2e8)},
+	}, volumes.top("XYZ-000_BNB", 2, 1))
+	require.Empty(t, volumes.top("ABC-000_BNB", 2, 1))
+
+	volumes.roll()
+	volumes.addTrades("XYZ-000_BNB", []me.Trade{
+		{Bid: "c-1", Sid: "b-1", LastPx: 1e8, LastQty: 5e8},
+	}, orders)
+	// fewer traders than asked for
+	require.ElementsMatch(t, []store.TraderVolume{
+		{Address: b, Volume: utils.Fixed8(5e8)},
+		{Address: c, Volume: utils.Fixed8(5e8)},
+	}, volumes.top("XYZ-000_BNB", 3, 1))
+	require.Equal(t, []store.TraderVolume{
+		{Address: b, Volume: utils.Fixed8(7e8)},
+		{Address: c, Volume: utils.Fixed8(6e8)},
+		{Address: a, Volume: utils.Fixed8(3e8)},
+	}, volumes.top("XYZ-000_BNB", 3, MaxTopTradersWindowDays))
+
+	for i := 0; i < MaxTopTradersWindowDays; i++ {
+		volumes.roll()
+	}
+	require.Empty(t, volumes.top("XYZ-000_BNB", 3, MaxTopTradersWindowDays))
+}
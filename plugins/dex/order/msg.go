@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	txbuilder "github.com/cosmos/cosmos-sdk/x/auth/client/txbuilder"
 
+	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/plugins/dex/matcheng"
 	"github.com/bnb-chain/node/plugins/dex/types"
 )
@@ -30,10 +32,60 @@ var sideNames = map[string]int8{
 	"SELL": matcheng.SELLSIDE,
 }
 
-// GenerateOrderID generates an order ID
+const (
+	// OrderIDVersionLegacy is the format of the order ids before versioning: <HEX ADDRESS>-<SEQUENCE>
+	OrderIDVersionLegacy = 0
+	// OrderIDVersion1 is the format of V1-<HEX ADDRESS>-<SEQUENCE>
+	OrderIDVersion1 = 1
+
+	orderIDVersionPrefix = "V"
+)
+
+// CurrentOrderIDVersion returns the version of the order ids generated at the current height
+func CurrentOrderIDVersion() int {
+	if sdk.IsUpgrade(upgrade.OrderIDVersioning) {
+		return OrderIDVersion1
+	}
+	return OrderIDVersionLegacy
+}
+
+// GenerateOrderID generates an order ID in the format of the current version
 func GenerateOrderID(sequence int64, addr sdk.AccAddress) string {
-	id := fmt.Sprintf("%X-%d", addr, sequence)
-	return id
+	return GenerateVersionedOrderID(CurrentOrderIDVersion(), sequence, addr)
+}
+
+// GenerateVersionedOrderID generates an order ID in the format of the given version
+func GenerateVersionedOrderID(version int, sequence int64, addr sdk.AccAddress) string {
+	if version == OrderIDVersionLegacy {
+		return fmt.Sprintf("%X-%d", addr, sequence)
+	}
+	return fmt.Sprintf("%s%d-%X-%d", orderIDVersionPrefix, version, addr, sequence)
+}
+
+// ParseOrderID parses an order ID of any version into its version, address and sequence
+func ParseOrderID(id string) (version int, addr sdk.AccAddress, sequence int64, err error) {
+	parts := strings.Split(id, "-")
+	switch {
+	case len(parts) == 2:
+		version = OrderIDVersionLegacy
+	case len(parts) == 3 && strings.HasPrefix(parts[0], orderIDVersionPrefix):
+		version, err = strconv.Atoi(strings.TrimPrefix(parts[0], orderIDVersionPrefix))
+		if err != nil || version <= OrderIDVersionLegacy || version > OrderIDVersion1 {
+			return 0, nil, 0, fmt.Errorf("unsupported version of order id %s", id)
+		}
+		parts = parts[1:]
+	default:
+		return 0, nil, 0, fmt.Errorf("malformed order id %s", id)
+	}
+	addr, err = sdk.AccAddressFromHex(parts[0])
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("malformed address in order id %s: %s", id, err.Error())
+	}
+	sequence, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || sequence < 0 {
+		return 0, nil, 0, fmt.Errorf("malformed sequence in order id %s", id)
+	}
+	return version, addr, sequence, nil
 }
 
 // IsValidSide validates that a side is valid and supported by the matching engine
@@ -114,6 +166,9 @@ func TifStringToTifCode(tif string) (int8, error) {
 	return -1, errors.New("tif `" + upperTif + "` not found or supported")
 }
 
+// MaxOrderMemoLength is the max length in bytes of the memo of an order
+const MaxOrderMemoLength = 64
+
 var _ sdk.Msg = NewOrderMsg{}
 
 type NewOrderMsg struct {
@@ -125,6 +180,7 @@ type NewOrderMsg struct {
 	Price       int64          `json:"price"`
 	Quantity    int64          `json:"quantity"`
 	TimeInForce int8           `json:"timeinforce"`
+	Memo        string         `json:"memo,omitempty"` // for the client tagging only, added since OrderMemo
 }
 
 // NewNewOrderMsg constructs a new NewOrderMsg
@@ -239,11 +295,8 @@ func (msg NewOrderMsg) ValidateBasic() sdk.Error {
 	if len(msg.Sender) == 0 {
 		return sdk.ErrUnknownAddress(msg.Sender.String()).TraceSDK("")
 	}
-	if msg.Quantity <= 0 {
-		return types.ErrInvalidOrderParam("Quantity", fmt.Sprintf("Zero/Negative Number:%d", msg.Quantity))
-	}
-	if msg.Price <= 0 {
-		return types.ErrInvalidOrderParam("Price", fmt.Sprintf("Zero/Negative Number:%d", msg.Price))
+	if err := validateOrderPriceAndQty(msg.Price, msg.Quantity); err != nil {
+		return err
 	}
 	if !IsValidOrderType(msg.OrderType) {
 		return types.ErrInvalidOrderParam("OrderType", fmt.Sprintf("Invalid order type:%d", msg.OrderType))
@@ -254,7 +307,45 @@ func (msg NewOrderMsg) ValidateBasic() sdk.Error {
 	if !IsValidTimeInForce(msg.TimeInForce) {
 		return types.ErrInvalidOrderParam("TimeInForce", fmt.Sprintf("Invalid TimeInForce:%d", msg.TimeInForce))
 	}
+	if err := validateOrderMemo(msg.Memo); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateOrderMemo bounds the memo of an order, which is stored with the order and echoed in its publication
+// without any meaning to the protocol.
+func validateOrderMemo(memo string) sdk.Error {
+	if len(memo) == 0 {
+		return nil
+	}
+	if !sdk.IsUpgrade(upgrade.OrderMemo) {
+		return types.ErrInvalidOrderParam("Memo", "Memo is not supported yet")
+	}
+	if len(memo) > MaxOrderMemoLength {
+		return types.ErrInvalidOrderParam("Memo", fmt.Sprintf("Memo is longer than %d bytes:%d", MaxOrderMemoLength, len(memo)))
+	}
+	return nil
+}
 
+// validateOrderPriceAndQty rejects the zero/negative quantity and price, with their own codes since OrderParamCodes
+func validateOrderPriceAndQty(price, qty int64) sdk.Error {
+	if sdk.IsUpgrade(upgrade.OrderParamCodes) {
+		if qty <= 0 {
+			return types.ErrInvalidOrderQuantity(qty)
+		}
+		if price <= 0 {
+			return types.ErrInvalidOrderPrice(price)
+		}
+		return nil
+	}
+	if qty <= 0 {
+		return types.ErrInvalidOrderParam("Quantity", fmt.Sprintf("Zero/Negative Number:%d", qty))
+	}
+	if price <= 0 {
+		return types.ErrInvalidOrderParam("Price", fmt.Sprintf("Zero/Negative Number:%d", price))
+	}
 	return nil
 }
 
@@ -3,6 +3,7 @@ package order
 import (
 	"fmt"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -17,6 +18,8 @@ import (
 
 	cmn "github.com/bnb-chain/node/common"
 	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/upgrade"
+	dextypes "github.com/bnb-chain/node/plugins/dex/types"
 )
 
 func newCLIContext() context.CLIContext {
@@ -70,6 +73,67 @@ func TestNewOrderMsg_ValidateBasic(t *testing.T) {
 	assert.Regexp(regexp.MustCompile(".*Invalid TimeInForce.*"), msg.ValidateBasic().Error())
 }
 
+func TestNewOrderMsg_ValidateBasic_OrderParamCodes(t *testing.T) {
+	assert := assert.New(t)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.OrderParamCodes, 10)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+	}()
+	_, acct := testutils.PrivAndAddr()
+
+	// before the upgrade
+	upgrade.Mgr.SetHeight(5)
+	err := NewNewOrderMsg(acct, "addr-1", 2, "BTC.B_BNB", 0, 100).ValidateBasic()
+	assert.Equal(dextypes.CodeInvalidOrderParam, err.Code())
+
+	upgrade.Mgr.SetHeight(10)
+	for _, price := range []int64{0, -355} {
+		err = NewNewOrderMsg(acct, "addr-1", 2, "BTC.B_BNB", price, 100).ValidateBasic()
+		assert.Equal(dextypes.CodeInvalidOrderPrice, err.Code())
+		assert.Contains(err.Error(), fmt.Sprintf("Invalid order price: %d", price))
+	}
+	for _, qty := range []int64{0, -100} {
+		err = NewNewOrderMsg(acct, "addr-1", 2, "BTC.B_BNB", 355, qty).ValidateBasic()
+		assert.Equal(dextypes.CodeInvalidOrderQuantity, err.Code())
+		assert.Contains(err.Error(), fmt.Sprintf("Invalid order quantity: %d", qty))
+	}
+	// the quantity is checked first
+	err = NewNewOrderMsg(acct, "addr-1", 2, "BTC.B_BNB", 0, 0).ValidateBasic()
+	assert.Equal(dextypes.CodeInvalidOrderQuantity, err.Code())
+	assert.Nil(NewNewOrderMsg(acct, "addr-1", 2, "BTC.B_BNB", 355, 100).ValidateBasic())
+}
+
+func TestNewOrderMsg_ValidateBasic_Memo(t *testing.T) {
+	assert := assert.New(t)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.OrderMemo, 10)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+	}()
+	_, acct := testutils.PrivAndAddr()
+	msg := NewNewOrderMsg(acct, "addr-1", 2, "BTC.B_BNB", 355, 100)
+
+	// before the upgrade
+	upgrade.Mgr.SetHeight(5)
+	assert.Nil(msg.ValidateBasic())
+	msg.Memo = "client-1"
+	err := msg.ValidateBasic()
+	assert.Equal(dextypes.CodeInvalidOrderParam, err.Code())
+	assert.Contains(err.Error(), "Memo is not supported yet")
+
+	upgrade.Mgr.SetHeight(10)
+	assert.Nil(msg.ValidateBasic())
+	msg.Memo = strings.Repeat("m", MaxOrderMemoLength)
+	assert.Nil(msg.ValidateBasic())
+	msg.Memo = strings.Repeat("m", MaxOrderMemoLength+1)
+	err = msg.ValidateBasic()
+	assert.Equal(dextypes.CodeInvalidOrderParam, err.Code())
+	assert.Contains(err.Error(), fmt.Sprintf("Memo is longer than %d bytes", MaxOrderMemoLength))
+
+	// the sign bytes of the orders without memo are kept
+	msg.Memo = ""
+	assert.NotContains(string(msg.GetSignBytes()), "memo")
+}
+
 func TestCancelOrderMsg_ValidateBasic(t *testing.T) {
 	assert := assert.New(t)
 	msg := NewCancelOrderMsg(sdk.AccAddress{}, "XYZ_BNB", "order1")
@@ -101,3 +165,39 @@ func TestGenerateOrderId(t *testing.T) {
 	expectedID := fmt.Sprintf("%s-5", hexAddr)
 	assert.Equal(t, expectedID, orderID)
 }
+
+func TestGenerateVersionedOrderID(t *testing.T) {
+	addr, _ := sdk.AccAddressFromHex("EFE8D84131468D2DC8966AB31D04C118352BE88A")
+	assert.Equal(t, "EFE8D84131468D2DC8966AB31D04C118352BE88A-5", GenerateVersionedOrderID(OrderIDVersionLegacy, 5, addr))
+	assert.Equal(t, "V1-EFE8D84131468D2DC8966AB31D04C118352BE88A-5", GenerateVersionedOrderID(OrderIDVersion1, 5, addr))
+	// no upgrade configured, the legacy format is kept
+	assert.Equal(t, OrderIDVersionLegacy, CurrentOrderIDVersion())
+	assert.Equal(t, GenerateVersionedOrderID(OrderIDVersionLegacy, 5, addr), GenerateOrderID(5, addr))
+}
+
+func TestParseOrderID(t *testing.T) {
+	addr, _ := sdk.AccAddressFromHex("EFE8D84131468D2DC8966AB31D04C118352BE88A")
+	for _, version := range []int{OrderIDVersionLegacy, OrderIDVersion1} {
+		v, a, seq, err := ParseOrderID(GenerateVersionedOrderID(version, 42, addr))
+		assert.NoError(t, err)
+		assert.Equal(t, version, v)
+		assert.Equal(t, addr, a)
+		assert.Equal(t, int64(42), seq)
+	}
+
+	for _, id := range []string{
+		"",
+		"123456",
+		"EFE8D84131468D2DC8966AB31D04C118352BE88A",
+		"EFE8D84131468D2DC8966AB31D04C118352BE88A-abc",
+		"EFE8D84131468D2DC8966AB31D04C118352BE88A--1",
+		"XYZ-5",
+		"V2-EFE8D84131468D2DC8966AB31D04C118352BE88A-5",
+		"V0-EFE8D84131468D2DC8966AB31D04C118352BE88A-5",
+		"X1-EFE8D84131468D2DC8966AB31D04C118352BE88A-5",
+		"V1-EFE8D84131468D2DC8966AB31D04C118352BE88A-5-1",
+	} {
+		_, _, _, err := ParseOrderID(id)
+		assert.Error(t, err, id)
+	}
+}
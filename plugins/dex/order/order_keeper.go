@@ -22,6 +22,7 @@ const (
 type DexOrderKeeper interface {
 	initOrders(symbol string)
 	addOrder(symbol string, info OrderInfo, isRecovery bool)
+	addRoundOrders(symbol string, info OrderInfo)
 	reloadOrder(symbol string, orderInfo *OrderInfo, height int64)
 	removeOrder(dexKeeper *DexKeeper, id string, symbol string) (ord me.OrderPart, err error)
 	orderExists(symbol, id string) (OrderInfo, bool)
@@ -36,6 +37,7 @@ type DexOrderKeeper interface {
 	getAllOrdersForPair(pair string) map[string]*OrderInfo
 	getRoundOrdersForPair(pair string) []string
 	getRoundIOCOrdersForPair(pair string) []string
+	restoreRoundOrdersForPair(pair string, orders []string)
 	clearAfterMatch()
 	selectSymbolsToMatch(height int64, matchAllSymbols bool) []string
 
@@ -152,6 +154,7 @@ func (kp *BaseOrderKeeper) getOpenOrders(pair string, addr sdk.AccAddress) []sto
 					CreatedTimestamp:     order.CreatedTimestamp,
 					LastUpdatedHeight:    order.LastUpdatedHeight,
 					LastUpdatedTimestamp: order.LastUpdatedTimestamp,
+					Memo:                 order.Memo,
 				})
 		}
 	}
@@ -197,6 +200,13 @@ func (kp *BaseOrderKeeper) getRoundIOCOrdersForPair(pair string) []string {
 	return kp.roundIOCOrders[pair]
 }
 
+// restoreRoundOrdersForPair puts back the round orders of the pair that are not matched in this round.
+func (kp *BaseOrderKeeper) restoreRoundOrdersForPair(pair string, orders []string) {
+	if len(orders) != 0 {
+		kp.roundOrders[pair] = orders
+	}
+}
+
 func (kp *BaseOrderKeeper) getAllOrdersForPair(pair string) map[string]*OrderInfo {
 	return kp.allOrders[pair]
 }
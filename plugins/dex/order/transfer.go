@@ -1,7 +1,9 @@
 package order
 
 import (
+	"encoding/binary"
 	"fmt"
+	"hash/fnv"
 	"sort"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -22,6 +24,8 @@ const (
 	eventFullyCancel
 	eventPartiallyCancel
 	eventCancelForMatchFailure
+	eventDelisted
+	eventTokenDisabled
 )
 
 // Transfer represents a transfer between trade currencies
@@ -37,13 +41,19 @@ type Transfer struct {
 	Fee        sdk.Fee
 	Trade      *me.Trade
 	Symbol     string
+	feeRoundUp bool // whether the trade fee is rounded up rather than truncated
+
+	makerRebate bool // whether the maker of the trade is eligible for the maker rebate
+	feeExempt   bool // whether the account is fee exempt, see DexKeeper.IsFeeExempt
 }
 
 func (tran Transfer) FeeFree() bool {
 	return tran.eventType == eventPartiallyExpire ||
 		tran.eventType == eventIOCPartiallyExpire ||
 		tran.eventType == eventPartiallyCancel ||
-		tran.eventType == eventCancelForMatchFailure
+		tran.eventType == eventCancelForMatchFailure ||
+		tran.eventType == eventDelisted ||
+		tran.eventType == eventTokenDisabled
 }
 
 func (tran Transfer) IsExpire() bool {
@@ -57,6 +67,14 @@ func (tran Transfer) IsExpiredWithFee() bool {
 	return tran.eventType == eventFullyExpire || tran.eventType == eventIOCFullyExpire
 }
 
+func (tran Transfer) IsDelisted() bool {
+	return tran.eventType == eventDelisted
+}
+
+func (tran Transfer) IsTokenDisabled() bool {
+	return tran.eventType == eventTokenDisabled
+}
+
 func (tran Transfer) IsNativeIn() bool {
 	return tran.inAsset == types.NativeTokenSymbol
 }
@@ -69,6 +87,24 @@ func (tran *Transfer) IsBuyer() bool {
 	return tran.Oid == tran.Trade.Bid
 }
 
+// alternateFeeRoundsUp tells whether the fee of the trade transfer is rounded up in the alternate rounding mode.
+// Exactly one side of a trade rounds up, picked by the hash of the trade, which differs for each fill
+// of the same pair of orders.
+func (tran *Transfer) alternateFeeRoundsUp() bool {
+	h := fnv.New32a()
+	h.Write([]byte(tran.Trade.Bid))
+	h.Write([]byte(tran.Trade.Sid))
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(tran.Trade.BuyCumQty))
+	h.Write(buf[:])
+	binary.BigEndian.PutUint64(buf[:], uint64(tran.Trade.SellCumQty))
+	h.Write(buf[:])
+	// the top bit, as the lowest bit of FNV-1a is only the parity of the bytes hashed, which is the same for the
+	// buyer and the seller ids of the same sequence
+	buyerRoundsUp := h.Sum32()>>31 == 0
+	return buyerRoundsUp == tran.IsBuyer()
+}
+
 func (tran *Transfer) String() string {
 	return fmt.Sprintf("Transfer[eventType:%v, oid:%v, inAsset:%v, inQty:%v, outAsset:%v, outQty:%v, unlock:%v, fee:%v]",
 		tran.eventType, tran.Oid, tran.inAsset, tran.in, tran.outAsset, tran.out, tran.unlock, tran.Fee)
@@ -144,6 +180,17 @@ func TransferFromCanceled(ord me.OrderPart, ordMsg OrderInfo, isMatchFailure boo
 	return transferFromOrderRemoved(ord, ordMsg, tranEventType)
 }
 
+// TransferFromDelisted refunds the order of a delisted trading pair, no fee is charged.
+func TransferFromDelisted(ord me.OrderPart, ordMsg OrderInfo) Transfer {
+	return transferFromOrderRemoved(ord, ordMsg, eventDelisted)
+}
+
+// TransferFromTokenDisabled refunds the order of a pair whose base or quote token is disabled from trading, no fee
+// is charged.
+func TransferFromTokenDisabled(ord me.OrderPart, ordMsg OrderInfo) Transfer {
+	return transferFromOrderRemoved(ord, ordMsg, eventTokenDisabled)
+}
+
 func transferFromOrderRemoved(ord me.OrderPart, ordMsg OrderInfo, tranEventType transferEventType) Transfer {
 	//here is a trick to use the same currency as in and out ccy to simulate cancel
 	qty := ord.LeavesQty()
@@ -215,6 +262,18 @@ func (trans TradeTransfers) Less(i, j int) bool {
 
 func (trans *TradeTransfers) Sort() { sort.Stable(trans) }
 
+// SortByOrderId sorts the transfers by the assets as Sort does,
+// but the transfers of the same assets are sorted by the order ids rather than kept in the sequence of matching.
+func (trans *TradeTransfers) SortByOrderId() {
+	t := *trans
+	sort.SliceStable(t, func(i, j int) bool {
+		if t.Less(i, j) || t.Less(j, i) {
+			return t.Less(i, j)
+		}
+		return t[i].Oid < t[j].Oid
+	})
+}
+
 var _ sort.Interface = ExpireTransfers{}
 
 type ExpireTransfers []*Transfer
@@ -20,6 +20,8 @@ const (
 	FullyFill                        // order is fully filled, derived from trade
 	FailedBlocking                   // order tx is failed blocking, we only publish essential message
 	FailedMatching                   // order failed matching
+	Delisted                         // order is cancelled since its trading pair is delisted
+	TokenDisabled                    // order is expired since the trading of its base or quote token is disabled
 )
 
 // True for should not remove order in these status from OrderInfoForPub
@@ -51,6 +53,10 @@ func (tpe ChangeType) String() string {
 		return "FailedBlocking"
 	case FailedMatching:
 		return "FailedMatching"
+	case Delisted:
+		return "Delisted"
+	case TokenDisabled:
+		return "TokenDisabled"
 	default:
 		return "Unknown"
 	}
@@ -21,7 +21,7 @@ const DelayedDaysForDelist = 3
 
 // InitPlugin initializes the dex plugin.
 func InitPlugin(
-	appp app.ChainApp, dexKeeper *DexKeeper, tokenMapper tokens.Mapper, govKeeper gov.Keeper,
+	appp app.ChainApp, dexKeeper *DexKeeper, tokenMapper tokens.Mapper, govKeeper gov.Keeper, symbolAliases *SymbolAliases,
 ) {
 
 	// add msg handlers
@@ -30,15 +30,15 @@ func InitPlugin(
 	}
 
 	// add abci handlers
-	dexHandler := createQueryHandler(dexKeeper, DexAbciQueryPrefix)
+	dexHandler := createQueryHandler(dexKeeper, DexAbciQueryPrefix, symbolAliases)
 	appp.RegisterQueryHandler(DexAbciQueryPrefix, dexHandler)
 	//dex mini handler
-	dexMiniHandler := createQueryHandler(dexKeeper, DexMiniAbciQueryPrefix)
+	dexMiniHandler := createQueryHandler(dexKeeper, DexMiniAbciQueryPrefix, symbolAliases)
 	appp.RegisterQueryHandler(DexMiniAbciQueryPrefix, dexMiniHandler)
 }
 
-func createQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQueryHandler {
-	return createAbciQueryHandler(keeper, abciQueryPrefix)
+func createQueryHandler(keeper *DexKeeper, abciQueryPrefix string, symbolAliases *SymbolAliases) app.AbciQueryHandler {
+	return createAbciQueryHandler(keeper, abciQueryPrefix, symbolAliases)
 }
 
 // EndBreatheBlock processes the breathe block lifecycle event.
@@ -51,6 +51,13 @@ func EndBreatheBlock(ctx sdk.Context, dexKeeper *DexKeeper, govKeeper gov.Keeper
 	logger.Info("Update tick size / lot size")
 	dexKeeper.UpdateTickSizeAndLotSize(ctx)
 
+	logger.Info("Expire orders of disabled tokens")
+	if dexKeeper.ShouldPublishOrder() {
+		pub.ExpireDisabledTokenOrdersForPublish(ctx, dexKeeper)
+	} else {
+		dexKeeper.ExpireDisabledTokenOrders(ctx, nil)
+	}
+
 	logger.Info("Expire stale orders")
 	if dexKeeper.ShouldPublishOrder() {
 		pub.ExpireOrdersForPublish(dexKeeper, ctx, blockTime)
@@ -60,6 +67,11 @@ func EndBreatheBlock(ctx sdk.Context, dexKeeper *DexKeeper, govKeeper gov.Keeper
 
 	logger.Info("Mark BreathBlock", "blockHeight", height)
 	dexKeeper.MarkBreatheBlock(ctx, height, blockTime)
+	dexKeeper.PruneMatchingPauses(ctx, height)
+	dexKeeper.PruneRecentOrders(ctx, height)
+	dexKeeper.UpdateMatchBatchSize(ctx)
+	dexKeeper.RollTraderVolumes()
+	dexKeeper.RollSymbolActivities()
 	logger.Info("Save Orderbook snapshot", "blockHeight", height)
 	if _, err := dexKeeper.SnapShotOrderBook(ctx, height); err != nil {
 		logger.Error("Failed to snapshot order book", "blockHeight", height, "err", err)
@@ -5,8 +5,10 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/gov"
 
+	"github.com/bnb-chain/node/plugins/dex/exemption"
 	"github.com/bnb-chain/node/plugins/dex/list"
 	"github.com/bnb-chain/node/plugins/dex/order"
+	"github.com/bnb-chain/node/plugins/dex/trading"
 	"github.com/bnb-chain/node/plugins/tokens"
 )
 
@@ -17,5 +19,7 @@ func Routes(dexKeeper *DexKeeper, tokenMapper tokens.Mapper, govKeeper gov.Keepe
 	routes[order.RouteNewOrder] = orderHandler
 	routes[order.RouteCancelOrder] = orderHandler
 	routes[types.ListRoute] = list.NewHandler(dexKeeper, tokenMapper, govKeeper)
+	routes[types.FeeExemptionRoute] = exemption.NewHandler(dexKeeper, govKeeper)
+	routes[types.TokenTradingRoute] = trading.NewHandler(dexKeeper, govKeeper)
 	return routes
 }
@@ -57,6 +57,12 @@ func (m mapper) AddTradingPair(ctx sdk.Context, pair types.TradingPair) error {
 		}
 	}
 
+	// a pair of the asset against itself would never trade, and its match engine would lock and unlock the
+	// same coins on both sides
+	if strings.EqualFold(baseAsset, quoteAsset) {
+		return fmt.Errorf("base asset symbol %s should not be identical to quote asset symbol", baseAsset)
+	}
+
 	tradeSymbol := dexUtils.Assets2TradingPair(strings.ToUpper(baseAsset), strings.ToUpper(quoteAsset))
 	key := []byte(tradeSymbol)
 	store := ctx.KVStore(m.key)
@@ -66,6 +66,21 @@ func TestMapper_GetAddTradingPair(t *testing.T) {
 	require.Equal(t, utils.Fixed8(1e8), pair.LotSize)
 }
 
+func TestMapper_AddTradingPair_SelfReferential(t *testing.T) {
+	pairMapper, ctx := setup()
+
+	for _, pair := range []dextypes.TradingPair{
+		dextypes.NewTradingPair(types.NativeTokenSymbol, types.NativeTokenSymbol, 1e8),
+		dextypes.NewTradingPair("XYZ-000", "xyz-000", 1e8),
+	} {
+		err := pairMapper.AddTradingPair(ctx, pair)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "should not be identical to quote asset symbol")
+		require.False(t, pairMapper.Exists(ctx, pair.BaseAssetSymbol, pair.QuoteAssetSymbol))
+	}
+	require.Empty(t, pairMapper.ListAllTradingPairs(ctx))
+}
+
 func TestMapper_Exists(t *testing.T) {
 	pairMapper, ctx := setup()
 
@@ -1,7 +1,10 @@
 package store
 
 import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
 	"github.com/bnb-chain/node/common/utils"
+	"github.com/bnb-chain/node/plugins/dex/types"
 )
 
 // OrderBook represents an order book at the current point block height, which is included in its struct.
@@ -9,6 +12,13 @@ type OrderBook struct {
 	Height       int64
 	Levels       []OrderBookLevel
 	PendingMatch bool
+	Truncated    bool         // fewer levels than requested are returned because of the max depth served by the node
+	BucketSize   utils.Fixed8 // size of the price buckets the levels are aggregated into, 0 for the raw price levels
+	// the quote notional depth each side of the levels is limited to, 0 for no limit, and the cumulative quote
+	// notional of the buy and sell levels returned, only set with a notional depth
+	NotionalDepth utils.Fixed8
+	BuyNotional   utils.Fixed8
+	SellNotional  utils.Fixed8
 }
 
 // OrderBookLevel represents a single order book level.
@@ -29,6 +39,7 @@ type OpenOrder struct {
 	CreatedTimestamp     int64        `json:"createdTimestamp"`
 	LastUpdatedHeight    int64        `json:"lastUpdatedHeight"`
 	LastUpdatedTimestamp int64        `json:"lastUpdatedTimestamp"`
+	Memo                 string       `json:"memo,omitempty"`
 }
 
 type RecentPrice struct {
@@ -47,3 +58,359 @@ func (prices *RecentPrice) removePair(symbolToDelete string) {
 		}
 	}
 }
+
+// ExpiringOrder is an open order that is expected to be expired by the breathe block at ExpireHeight.
+type ExpiringOrder struct {
+	Id               string         `json:"id"`
+	Symbol           string         `json:"symbol"`
+	Owner            sdk.AccAddress `json:"owner"`
+	Side             int8           `json:"side"`
+	Price            utils.Fixed8   `json:"price"`
+	Quantity         utils.Fixed8   `json:"quantity"`
+	CumQty           utils.Fixed8   `json:"cumQty"`
+	CreatedHeight    int64          `json:"createdHeight"`
+	CreatedTimestamp int64          `json:"createdTimestamp"`
+	ExpireHeight     int64          `json:"expireHeight"`
+}
+
+// OrderFill is a single execution of an order against its counterpart order.
+type OrderFill struct {
+	TradeId            string       `json:"tradeId"`
+	Symbol             string       `json:"symbol"`
+	Price              utils.Fixed8 `json:"price"`
+	Quantity           utils.Fixed8 `json:"quantity"`
+	CounterpartOrderId string       `json:"counterpartOrderId"`
+	Height             int64        `json:"height"`
+	Timestamp          int64        `json:"timestamp"`
+}
+
+// AccountTrade is a trade of an account, with the fee charged on the account for it.
+type AccountTrade struct {
+	TradeId   string       `json:"tradeId"`
+	Symbol    string       `json:"symbol"`
+	Side      int8         `json:"side"`
+	Price     utils.Fixed8 `json:"price"`
+	Quantity  utils.Fixed8 `json:"quantity"`
+	Fee       sdk.Coins    `json:"fee"`
+	Height    int64        `json:"height"`
+	Timestamp int64        `json:"timestamp"`
+}
+
+// CancelledOrder is an order of an account cancelled recently, with the reason, i.e. Canceled by the account,
+// Expired in the breathe block, Delisted with the trading pair or TokenDisabled with its base or quote token.
+type CancelledOrder struct {
+	Id        string       `json:"id"`
+	Symbol    string       `json:"symbol"`
+	Side      int8         `json:"side"`
+	Price     utils.Fixed8 `json:"price"`
+	Quantity  utils.Fixed8 `json:"quantity"`
+	CumQty    utils.Fixed8 `json:"cumQty"`
+	Reason    string       `json:"reason"`
+	Height    int64        `json:"height"`
+	Timestamp int64        `json:"timestamp"`
+}
+
+// TraderVolume is the traded volume of an account in a symbol, in the quote asset.
+type TraderVolume struct {
+	Address sdk.AccAddress `json:"address"`
+	Volume  utils.Fixed8   `json:"volume"`
+}
+
+// ActiveSymbol is the number of trades and the traded volume of a symbol in a window, in the quote asset.
+type ActiveSymbol struct {
+	Symbol    string       `json:"symbol"`
+	NumTrades int64        `json:"numTrades"`
+	Volume    utils.Fixed8 `json:"volume"`
+}
+
+// AccountRisk summarizes the open orders of an account.
+type AccountRisk struct {
+	Address     sdk.AccAddress `json:"address"`
+	Symbols     []SymbolRisk   `json:"symbols"`
+	OpenOrders  int64          `json:"openOrders"`
+	RefAsset    string         `json:"refAsset"`
+	RefNotional utils.Fixed8   `json:"refNotional"` // total notional of the open orders in RefAsset
+	Truncated   bool           `json:"truncated"`   // there are more open orders than counted
+}
+
+// SymbolRisk is the number and notional of the open orders of an account in a symbol, the notional is
+// of the remaining quantity at the order price, in the quote asset and in the reference asset.
+type SymbolRisk struct {
+	Symbol                 string       `json:"symbol"`
+	OpenOrders             int64        `json:"openOrders"`
+	Notional               utils.Fixed8 `json:"notional"`
+	RefNotional            utils.Fixed8 `json:"refNotional"`
+	RefNotionalUnavailable bool         `json:"refNotionalUnavailable"` // no price of the quote asset against the reference asset
+}
+
+// NoSpread is the spread of a trading pair with either side of the order book empty
+const NoSpread int64 = -1
+
+// Spread is the spread between the best bid and ask of a trading pair, in bps of the mid price. A crossed
+// order book, which is only possible before it's matched, has the spread of 0.
+type Spread struct {
+	Symbol    string       `json:"symbol"`
+	BestBid   utils.Fixed8 `json:"bestBid"`
+	BestAsk   utils.Fixed8 `json:"bestAsk"`
+	SpreadBps int64        `json:"spreadBps"`
+}
+
+// SlippageEstimate is how an order of the side and quantity would fill against the order book. AvgPrice is the
+// average price of FilledQty, and SlippageBps is its distance from BestPrice in bps, which is NoSpread if nothing
+// would be filled. InsufficientLiquidity is true if the order book can't fill the whole quantity.
+type SlippageEstimate struct {
+	Symbol                string       `json:"symbol"`
+	Side                  int8         `json:"side"`
+	Quantity              utils.Fixed8 `json:"quantity"`
+	BestPrice             utils.Fixed8 `json:"bestPrice"`
+	AvgPrice              utils.Fixed8 `json:"avgPrice"`
+	FilledQty             utils.Fixed8 `json:"filledQty"`
+	SlippageBps           int64        `json:"slippageBps"`
+	InsufficientLiquidity bool         `json:"insufficientLiquidity"`
+}
+
+// SlippageEstimateDecimal is SlippageEstimate with the prices and quantities as decimal strings of the token
+// decimals, served by the slippage query in the decimal format.
+type SlippageEstimateDecimal struct {
+	Symbol                string `json:"symbol"`
+	Side                  int8   `json:"side"`
+	Quantity              string `json:"quantity"`
+	BestPrice             string `json:"bestPrice"`
+	AvgPrice              string `json:"avgPrice"`
+	FilledQty             string `json:"filledQty"`
+	SlippageBps           int64  `json:"slippageBps"`
+	InsufficientLiquidity bool   `json:"insufficientLiquidity"`
+}
+
+// ToDecimal returns the estimate with the prices and quantities as decimal strings. All the assets share the
+// same token decimals, which is the precision of Fixed8.
+func (e SlippageEstimate) ToDecimal() SlippageEstimateDecimal {
+	return SlippageEstimateDecimal{
+		Symbol:                e.Symbol,
+		Side:                  e.Side,
+		Quantity:              e.Quantity.String(),
+		BestPrice:             e.BestPrice.String(),
+		AvgPrice:              e.AvgPrice.String(),
+		FilledQty:             e.FilledQty.String(),
+		SlippageBps:           e.SlippageBps,
+		InsufficientLiquidity: e.InsufficientLiquidity,
+	}
+}
+
+// Quote is the top of the order book and the last trade price of a trading pair. Unknown is true if there is no
+// such trading pair, in which case the other fields are left empty.
+type Quote struct {
+	Symbol     string       `json:"symbol"`
+	Unknown    bool         `json:"unknown"`
+	BestBid    utils.Fixed8 `json:"bestBid"`
+	BestBidQty utils.Fixed8 `json:"bestBidQty"`
+	BestAsk    utils.Fixed8 `json:"bestAsk"`
+	BestAskQty utils.Fixed8 `json:"bestAskQty"`
+	LastPrice  utils.Fixed8 `json:"lastPrice"`
+}
+
+// TWAP is the time-weighted average of the last trade price of a trading pair over the recent window. The prices
+// are only known for CoveredSeconds of the window if InsufficientData is true, over which the average is taken.
+type TWAP struct {
+	Symbol           string       `json:"symbol"`
+	WindowSeconds    int64        `json:"windowSeconds"`
+	CoveredSeconds   int64        `json:"coveredSeconds"`
+	TWAP             utils.Fixed8 `json:"twap"`
+	InsufficientData bool         `json:"insufficientData"`
+}
+
+// BookImbalance is the imbalance of the order book of a trading pair as of the block at Height, over the price
+// levels published. Imbalance is (BidQty - AskQty) / (BidQty + AskQty), in [-1, 1].
+type BookImbalance struct {
+	Height    int64        `json:"height"`
+	BidQty    utils.Fixed8 `json:"bidQty"`
+	AskQty    utils.Fixed8 `json:"askQty"`
+	Imbalance utils.Fixed8 `json:"imbalance"`
+}
+
+// ImbalanceTrend is the imbalances of the order book of a trading pair as of the recent blocks, the oldest first.
+type ImbalanceTrend struct {
+	Symbol     string          `json:"symbol"`
+	Imbalances []BookImbalance `json:"imbalances"`
+}
+
+// TradeRate is the numbers of trades in the recent blocks, the oldest first, of the trading pair of Symbol, or of all
+// the pairs if Symbol is empty.
+type TradeRate struct {
+	Symbol string            `json:"symbol,omitempty"`
+	Blocks []BlockTradeCount `json:"blocks"`
+}
+
+// BlockTradeCount is the number of trades in the block at Height.
+type BlockTradeCount struct {
+	Height int64 `json:"height"`
+	Trades int64 `json:"trades"`
+}
+
+// LastMatch is the height of the last block in which the trading pair of Symbol produced trades, Height is 0 if it
+// never did since the node started.
+type LastMatch struct {
+	Symbol string `json:"symbol"`
+	Height int64  `json:"height"`
+}
+
+// OrderCount is the number of open orders of all the trading pairs against the cap, Max is 0 if there is no cap.
+// Height is the block as of which the orders are counted, and the Pairs having open orders are sorted by the
+// number of their open orders descending.
+type OrderCount struct {
+	Total  int64            `json:"total"`
+	Max    int64            `json:"max"`
+	Height int64            `json:"height"`
+	Pairs  []PairOrderCount `json:"pairs"`
+}
+
+// PairOrderCount is the number of open orders of a trading pair.
+type PairOrderCount struct {
+	Symbol string `json:"symbol"`
+	Count  int64  `json:"count"`
+}
+
+// EngineConfig is the configuration of the match engines being in effect.
+type EngineConfig struct {
+	Concurrency                 uint                `json:"concurrency"` // number of the order keeper channels
+	MatchingPaused              bool                `json:"matchingPaused"`
+	MaxOrdersPerAccountPerBlock int64               `json:"maxOrdersPerAccountPerBlock"`
+	MaxTotalOrders              int64               `json:"maxTotalOrders"`
+	MinBalanceToPlaceOrder      int64               `json:"minBalanceToPlaceOrder"`
+	PairMatchIntervals          []PairMatchInterval `json:"pairMatchIntervals"` // only the pairs not matched in every block, sorted by symbol
+	MatchBatchSize              int64               `json:"matchBatchSize"`
+	HaltSchedule                []types.HaltWindow  `json:"haltSchedule"`
+	ScheduledHalt               bool                `json:"scheduledHalt"` // whether the matching is halted by the schedule now
+}
+
+// MatchBacklog is the pairs whose round orders are deferred by the match batch size at the matching of the height,
+// in the order they are going to be matched.
+type MatchBacklog struct {
+	Height    int64            `json:"height"`
+	BatchSize int64            `json:"batchSize"`
+	Symbols   []DeferredSymbol `json:"symbols"`
+}
+
+type DeferredSymbol struct {
+	Symbol string `json:"symbol"`
+	Orders int64  `json:"orders"` // number of the round orders deferred
+	Since  int64  `json:"since"`  // height since which the pair is deferred
+}
+
+// PairMatchInterval is the number of blocks between the matches of a pair.
+type PairMatchInterval struct {
+	Symbol        string `json:"symbol"`
+	MatchInterval int64  `json:"matchInterval"`
+}
+
+// HistoricalOrderBookDateFormat is the format of the dates in the dex/history query.
+const HistoricalOrderBookDateFormat = "2006-01-02"
+
+// HistoricalOrderBook is the order book of a symbol saved in the breathe block of a day (UTC).
+type HistoricalOrderBook struct {
+	Symbol         string           `json:"symbol"`
+	Date           string           `json:"date"`
+	Height         int64            `json:"height"`
+	LastTradePrice utils.Fixed8     `json:"lastTradePrice"`
+	Levels         []OrderBookLevel `json:"levels"`
+}
+
+// BookUpdateRates are the numbers of the order book updates of the symbols over the blocks [StartHeight, EndHeight],
+// for the clients to tune how often they refresh the order books.
+type BookUpdateRates struct {
+	StartHeight int64            `json:"startHeight"`
+	EndHeight   int64            `json:"endHeight"`
+	Symbols     []BookUpdateRate `json:"symbols"`
+}
+
+// BookUpdateRate is the number of the orders added and cancelled and the trades of a symbol, Total is the sum of them.
+type BookUpdateRate struct {
+	Symbol  string `json:"symbol"`
+	Adds    int64  `json:"adds"`
+	Cancels int64  `json:"cancels"`
+	Fills   int64  `json:"fills"`
+	Total   int64  `json:"total"`
+}
+
+// PendingMatch is the would-be result of matching the current order book of a trading pair at Height, including
+// the orders not matched yet. Trades is empty if nothing would match, and the best bid and ask are the top of the
+// book after the matching.
+type PendingMatch struct {
+	Symbol     string         `json:"symbol"`
+	Height     int64          `json:"height"`
+	Trades     []PendingTrade `json:"trades"`
+	LastPrice  utils.Fixed8   `json:"lastPrice"`
+	BestBid    utils.Fixed8   `json:"bestBid"`
+	BestBidQty utils.Fixed8   `json:"bestBidQty"`
+	BestAsk    utils.Fixed8   `json:"bestAsk"`
+	BestAskQty utils.Fixed8   `json:"bestAskQty"`
+}
+
+// PendingTrade is a would-be trade of a buy order against a sell order.
+type PendingTrade struct {
+	BuyOrderId  string       `json:"buyOrderId"`
+	SellOrderId string       `json:"sellOrderId"`
+	Price       utils.Fixed8 `json:"price"`
+	Quantity    utils.Fixed8 `json:"quantity"`
+}
+
+// OrderSizeDistribution is the histogram of the remaining quantities of the resting orders of a trading pair, per
+// side. Bucket i counts the orders of the quantities in [Boundaries[i-1], Boundaries[i]), the first bucket is below
+// the first boundary and the last one is at or above the last boundary, so there is one more bucket than boundaries.
+type OrderSizeDistribution struct {
+	Symbol     string            `json:"symbol"`
+	Boundaries []utils.Fixed8    `json:"boundaries"`
+	Bids       []OrderSizeBucket `json:"bids"`
+	Asks       []OrderSizeBucket `json:"asks"`
+}
+
+// OrderSizeBucket is the number and the total remaining quantity of the orders in a bucket of OrderSizeDistribution.
+type OrderSizeBucket struct {
+	Orders   int64        `json:"orders"`
+	Quantity utils.Fixed8 `json:"quantity"`
+}
+
+// OpenInterest is the total resting quantity of each side of the order book of a trading pair, as of the end
+// of the block at Height.
+type OpenInterest struct {
+	Symbol string       `json:"symbol"`
+	Height int64        `json:"height"`
+	BidQty utils.Fixed8 `json:"bidQty"`
+	AskQty utils.Fixed8 `json:"askQty"`
+}
+
+// FeeRules is the complete rule set of the dex fees in effect, for the clients to compute the fees on their own.
+//
+// A trade is charged at FeeRateNative of its notional in the native asset if the account has enough of it
+// after the trade, otherwise at FeeRate of the received asset. The notional of a pair not quoted in the native
+// asset is converted by the last price against the native asset, or against BusdSymbol and then BUSD against
+// the native asset if there is no such pair. The rates are in 1/10^FeeRateDecimals. The fixed fees of expiring
+// and cancelling an unfilled order are charged the same way, by the *Native amount in the native asset or the
+// other amount converted into the received asset, both capped by the balance. The partially filled orders are
+// expired and cancelled for free. A trade fee below the MinTradeFees of the charged asset is raised to it, as
+// long as the balance of the asset allows.
+type FeeRules struct {
+	NativeAsset           string        `json:"nativeAsset"`
+	BusdSymbol            string        `json:"busdSymbol,omitempty"` // the bridge asset to price the pairs without the native asset, if any
+	FeeRateDecimals       int64         `json:"feeRateDecimals"`
+	FeeRate               int64         `json:"feeRate"`
+	FeeRateNative         int64         `json:"feeRateNative"`
+	ExpireFee             int64         `json:"expireFee"`
+	ExpireFeeNative       int64         `json:"expireFeeNative"`
+	IOCExpireFee          int64         `json:"iocExpireFee"`
+	IOCExpireFeeNative    int64         `json:"iocExpireFeeNative"`
+	CancelFee             int64         `json:"cancelFee"`
+	CancelFeeNative       int64         `json:"cancelFeeNative"`
+	AllocationOrdering    string        `json:"allocationOrdering"`    // how the fees of an account in a block are charged and rounded
+	MakerRebateRate       int64         `json:"makerRebateRate"`       // share of the taker's fee credited to an eligible maker, in bps
+	MakerRebateMaxSpread  int64         `json:"makerRebateMaxSpread"`  // max distance of an eligible maker from the mid, in bps
+	DelistFeeFree         bool          `json:"delistFeeFree"`         // the orders of the delisted pairs are cancelled for free
+	InsufficientFeePolicy string        `json:"insufficientFeePolicy"` // how the orders are treated if the native token can't cover the fees
+	MinTradeFees          []MinTradeFee `json:"minTradeFees"`
+}
+
+// MinTradeFee is the min fee of a trade charged in the asset.
+type MinTradeFee struct {
+	Asset string `json:"asset"`
+	Fee   int64  `json:"fee"`
+}
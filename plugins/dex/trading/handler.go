@@ -0,0 +1,63 @@
+package trading
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+
+	"github.com/bnb-chain/node/plugins/dex/order"
+	"github.com/bnb-chain/node/plugins/dex/types"
+	tokenStore "github.com/bnb-chain/node/plugins/tokens/store"
+)
+
+// NewHandler initialises the handler of the token trading msgs
+func NewHandler(keeper *order.DexKeeper, govKeeper gov.Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case types.SetTokenTradingMsg:
+			return handleSetTokenTrading(ctx, keeper, govKeeper, msg)
+		default:
+			errMsg := fmt.Sprintf("Unrecognized token trading msg type: %v", reflect.TypeOf(msg).Name())
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func checkTokenTradingProposal(ctx sdk.Context, govKeeper gov.Keeper, msg types.SetTokenTradingMsg) error {
+	proposal := govKeeper.GetProposal(ctx, msg.ProposalId)
+	if proposal == nil {
+		return fmt.Errorf("proposal %d does not exist", msg.ProposalId)
+	}
+	if proposal.GetProposalType() != gov.ProposalTypeText {
+		return fmt.Errorf("proposal type(%s) should be %s", proposal.GetProposalType(), gov.ProposalTypeText)
+	}
+	if proposal.GetStatus() != gov.StatusPassed {
+		return fmt.Errorf("proposal status(%s) should be Passed", proposal.GetStatus())
+	}
+	change, ok, err := tokenStore.GetTokenTradingChange(proposal.GetDescription())
+	if !ok {
+		return fmt.Errorf("proposal %d is not a token trading change", msg.ProposalId)
+	}
+	if err != nil {
+		return err
+	}
+	if change.Symbol != strings.ToUpper(msg.Symbol) || change.Disabled != msg.Disabled {
+		return fmt.Errorf("proposal %d does not set the trading of %s to disabled=%v", msg.ProposalId,
+			msg.Symbol, msg.Disabled)
+	}
+	return nil
+}
+
+func handleSetTokenTrading(ctx sdk.Context, keeper *order.DexKeeper, govKeeper gov.Keeper,
+	msg types.SetTokenTradingMsg) sdk.Result {
+	if err := checkTokenTradingProposal(ctx, govKeeper, msg); err != nil {
+		return types.ErrInvalidProposal(err.Error()).Result()
+	}
+	if err := keeper.SetTokenTrading(ctx, strings.ToUpper(msg.Symbol), msg.Disabled, msg.ProposalId); err != nil {
+		return types.ErrInvalidProposal(err.Error()).Result()
+	}
+	return sdk.Result{}
+}
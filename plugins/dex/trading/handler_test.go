@@ -0,0 +1,151 @@
+package trading
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdkStore "github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/dex/order"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	dexTypes "github.com/bnb-chain/node/plugins/dex/types"
+	tokenStore "github.com/bnb-chain/node/plugins/tokens/store"
+)
+
+func MakeCodec() *codec.Codec {
+	var cdc = codec.New()
+
+	bank.RegisterCodec(cdc)
+	sdk.RegisterCodec(cdc)
+	types.RegisterWire(cdc)
+	gov.RegisterCodec(cdc)
+
+	return cdc
+}
+
+func MakeKeepers(cdc *codec.Codec) (ms sdkStore.CommitMultiStore, dexKeeper *order.DexKeeper, tokenMapper tokenStore.Mapper, govKeeper gov.Keeper) {
+	accKey := sdk.NewKVStoreKey("acc")
+	pairKey := sdk.NewKVStoreKey("pair")
+	tokenKey := sdk.NewKVStoreKey("token")
+	paramKey := sdk.NewKVStoreKey("param")
+	paramTKey := sdk.NewTransientStoreKey("t_param")
+	stakeKey := sdk.NewKVStoreKey("stake")
+	stakeRewardKey := sdk.NewKVStoreKey("stake_reward")
+	stakeTKey := sdk.NewTransientStoreKey("t_stake")
+	govKey := sdk.NewKVStoreKey("gov")
+
+	memDB := db.NewMemDB()
+	ms = sdkStore.NewCommitMultiStore(memDB)
+	ms.MountStoreWithDB(accKey, sdk.StoreTypeIAVL, memDB)
+	ms.MountStoreWithDB(pairKey, sdk.StoreTypeIAVL, memDB)
+	ms.MountStoreWithDB(tokenKey, sdk.StoreTypeIAVL, memDB)
+	ms.MountStoreWithDB(common.DexStoreKey, sdk.StoreTypeIAVL, memDB)
+	ms.MountStoreWithDB(paramKey, sdk.StoreTypeIAVL, memDB)
+	ms.MountStoreWithDB(stakeKey, sdk.StoreTypeIAVL, memDB)
+	ms.MountStoreWithDB(stakeRewardKey, sdk.StoreTypeIAVL, memDB)
+	ms.MountStoreWithDB(govKey, sdk.StoreTypeIAVL, memDB)
+	ms.LoadLatestVersion()
+
+	accKeeper := auth.NewAccountKeeper(cdc, accKey, types.ProtoAppAccount)
+	codespacer := sdk.NewCodespacer()
+	pairMapper := store.NewTradingPairMapper(cdc, pairKey)
+	dexKeeper = order.NewDexKeeper(common.DexStoreKey, accKeeper, pairMapper, codespacer.RegisterNext(dexTypes.DefaultCodespace), 2, cdc, true)
+	tokenMapper = tokenStore.NewMapper(cdc, tokenKey)
+	dexKeeper.SetTokenMapper(tokenMapper)
+
+	paramsKeeper := params.NewKeeper(cdc, paramKey, paramTKey)
+	bankKeeper := bank.NewBaseKeeper(accKeeper)
+	stakeKeeper := stake.NewKeeper(
+		cdc,
+		stakeKey, stakeRewardKey, stakeTKey,
+		bankKeeper, nil, paramsKeeper.Subspace(stake.DefaultParamspace),
+		stake.DefaultCodespace,
+		sdk.ChainID(0),
+		"",
+	)
+	govKeeper = gov.NewKeeper(cdc, govKey,
+		paramsKeeper, paramsKeeper.Subspace(gov.DefaultParamSpace),
+		bankKeeper,
+		stakeKeeper,
+		gov.DefaultCodespace,
+		new(sdk.Pool))
+
+	return ms, dexKeeper, tokenMapper, govKeeper
+}
+
+func setProposal(ctx sdk.Context, govKeeper gov.Keeper, proposalId int64, change tokenStore.TokenTradingChange,
+	status gov.ProposalStatus) {
+	bz, _ := json.Marshal(map[string]tokenStore.TokenTradingChange{"token_trading": change})
+	govKeeper.SetProposal(ctx, &gov.TextProposal{
+		ProposalID:   proposalId,
+		Title:        "token trading",
+		Description:  string(bz),
+		ProposalType: gov.ProposalTypeText,
+		Status:       status,
+		TallyResult:  gov.EmptyTallyResult(),
+		TotalDeposit: sdk.Coins{},
+		SubmitTime:   time.Now(),
+	})
+}
+
+func TestTokenTradingHandler(t *testing.T) {
+	cdc := MakeCodec()
+	ms, dexKeeper, tokenMapper, govKeeper := MakeKeepers(cdc)
+	ctx := sdk.NewContext(ms, abci.Header{}, sdk.RunTxModeDeliver, log.NewNopLogger())
+	handler := NewHandler(dexKeeper, govKeeper)
+	from := sdk.AccAddress([]byte("from________________"))
+
+	token, err := types.NewToken("XYZ", "XYZ-000", 1e10, from, false)
+	require.NoError(t, err)
+	require.NoError(t, tokenMapper.NewToken(ctx, token))
+	pair := dexTypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	require.NoError(t, dexKeeper.PairMapper.AddTradingPair(ctx, pair))
+	dexKeeper.AddEngine(pair)
+
+	result := handler(ctx, dexTypes.NewSetTokenTradingMsg(from, 1, "XYZ-000", true))
+	require.Contains(t, result.Log, "proposal 1 does not exist")
+
+	setProposal(ctx, govKeeper, 1, tokenStore.TokenTradingChange{Symbol: "XYZ-000", Disabled: true}, gov.StatusVotingPeriod)
+	result = handler(ctx, dexTypes.NewSetTokenTradingMsg(from, 1, "XYZ-000", true))
+	require.Contains(t, result.Log, "proposal status(VotingPeriod) should be Passed")
+
+	setProposal(ctx, govKeeper, 1, tokenStore.TokenTradingChange{Symbol: "XYZ-000", Disabled: true}, gov.StatusPassed)
+	result = handler(ctx, dexTypes.NewSetTokenTradingMsg(from, 1, "XYZ-000", false))
+	require.Contains(t, result.Log, "does not set the trading of XYZ-000")
+	require.False(t, tokenMapper.IsTradingDisabled(ctx, "XYZ-000"))
+	require.Empty(t, dexKeeper.GetTokenTradingEvents())
+
+	result = handler(ctx, dexTypes.NewSetTokenTradingMsg(from, 1, "xyz-000", true))
+	require.True(t, result.IsOK(), result.Log)
+	require.True(t, tokenMapper.IsTradingDisabled(ctx, "XYZ-000"))
+	require.Equal(t, []order.TokenTradingEvent{{"XYZ-000_BNB", "XYZ-000", true}}, dexKeeper.GetTokenTradingEvents())
+	require.Empty(t, dexKeeper.GetSessionEvents())
+	dexKeeper.ClearTokenTradingEvents()
+
+	// the token is enabled by a later proposal, which the earlier one can't revert
+	setProposal(ctx, govKeeper, 2, tokenStore.TokenTradingChange{Symbol: "XYZ-000", Disabled: false}, gov.StatusPassed)
+	result = handler(ctx, dexTypes.NewSetTokenTradingMsg(from, 2, "XYZ-000", false))
+	require.True(t, result.IsOK(), result.Log)
+	require.False(t, tokenMapper.IsTradingDisabled(ctx, "XYZ-000"))
+	result = handler(ctx, dexTypes.NewSetTokenTradingMsg(from, 1, "XYZ-000", true))
+	require.Contains(t, result.Log, "has been changed by proposal 2")
+	require.False(t, tokenMapper.IsTradingDisabled(ctx, "XYZ-000"))
+	require.Equal(t, []order.TokenTradingEvent{{"XYZ-000_BNB", "XYZ-000", false}}, dexKeeper.GetTokenTradingEvents())
+
+	// the settings of the token are not listed as tokens
+	require.Len(t, tokenMapper.GetTokenList(ctx, true, false), 1)
+}
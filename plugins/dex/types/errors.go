@@ -18,6 +18,18 @@ const (
 	CodeFailLocateOrderToCancel sdk.CodeType = 405
 	CodeDuplicatedOrder         sdk.CodeType = 406
 	CodeInvalidProposal         sdk.CodeType = 407
+	CodeTooManyOrdersInBlock    sdk.CodeType = 408
+	CodeBalanceTooLowToOrder    sdk.CodeType = 409
+	CodeTooManyOrders           sdk.CodeType = 410
+	CodeInvalidOrderPrice       sdk.CodeType = 411
+	CodeInvalidOrderQuantity    sdk.CodeType = 412
+	CodeCancelInCooldown        sdk.CodeType = 413
+	CodeUnknownTradingPair      sdk.CodeType = 414
+	CodeRepeatedOrder           sdk.CodeType = 415
+	CodePairOutOfSession        sdk.CodeType = 416
+	CodeTokenTradingDisabled    sdk.CodeType = 417
+	CodeCancelBeforeMinLifetime sdk.CodeType = 418
+	CodeNotionalOverflow        sdk.CodeType = 419
 )
 
 // ErrIncorrectDexOperation - Error returned upon an incorrect guess
@@ -29,10 +41,49 @@ func ErrInvalidOrderParam(paraName string, err string) sdk.Error {
 	return sdk.NewError(DefaultCodespace, CodeInvalidOrderParam, fmt.Sprintf("Invalid order parameter value - %s:%s", paraName, err))
 }
 
+// ErrInvalidOrderPrice is returned for an order of zero or negative price.
+func ErrInvalidOrderPrice(price int64) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeInvalidOrderPrice, fmt.Sprintf("Invalid order price: %d, it should be positive", price))
+}
+
+// ErrInvalidOrderQuantity is returned for an order of zero or negative quantity.
+func ErrInvalidOrderQuantity(qty int64) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeInvalidOrderQuantity, fmt.Sprintf("Invalid order quantity: %d, it should be positive", qty))
+}
+
+// ErrNotionalOverflow is returned for an order whose notional, i.e. price * quantity / 1e8, doesn't fit in int64.
+func ErrNotionalOverflow(price, qty int64) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeNotionalOverflow,
+		fmt.Sprintf("Notional of the order of price %d and quantity %d overflows int64", price, qty))
+}
+
 func ErrInvalidTradeSymbol(err string) sdk.Error {
 	return sdk.NewError(DefaultCodespace, CodeInvalidTradeSymbol, fmt.Sprintf("Invalid trade symbol: %s", err))
 }
 
+// ErrUnknownTradingPair is returned for an order of a trading pair not listed.
+func ErrUnknownTradingPair(symbol string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeUnknownTradingPair, fmt.Sprintf("Unknown trading pair: %s", symbol))
+}
+
+// ErrRepeatedOrder is returned for an order of the same params as a recent order of the account.
+func ErrRepeatedOrder(symbol string, placedHeight int64) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeRepeatedOrder,
+		fmt.Sprintf("Repeated order: an order of the same symbol %s, side, price and quantity was placed at height %d", symbol, placedHeight))
+}
+
+// ErrPairOutOfSession is returned for an order of a trading pair outside its trading session.
+func ErrPairOutOfSession(symbol string, session TradingSession) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodePairOutOfSession,
+		fmt.Sprintf("Trading pair %s is out of its session [%d, %d) of the UTC day", symbol, session.Open, session.Close))
+}
+
+// ErrTokenTradingDisabled is returned for an order of a trading pair whose base or quote token is disabled from trading.
+func ErrTokenTradingDisabled(token, symbol string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeTokenTradingDisabled,
+		fmt.Sprintf("Trading of token %s is disabled, trading pair %s takes no new orders", token, symbol))
+}
+
 func ErrInvalidProposal(err string) sdk.Error {
 	return sdk.NewError(DefaultCodespace, CodeInvalidProposal, fmt.Sprintf("Invalid proposal: %s", err))
 }
@@ -0,0 +1,102 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	FeeExemptionRoute         = "dexFeeExemption"
+	AddFeeExemptionMsgType    = "addFeeExemption"
+	RemoveFeeExemptionMsgType = "removeFeeExemption"
+)
+
+var _ sdk.Msg = AddFeeExemptionMsg{}
+
+// AddFeeExemptionMsg exempts the account from the trade fees and tx fees, per a passed fee exemptions change
+// proposal adding it. Anyone can send it once the proposal passes.
+type AddFeeExemptionMsg struct {
+	From       sdk.AccAddress `json:"from"`
+	ProposalId int64          `json:"proposal_id"`
+	Account    sdk.AccAddress `json:"account"`
+}
+
+func NewAddFeeExemptionMsg(from sdk.AccAddress, proposalId int64, account sdk.AccAddress) AddFeeExemptionMsg {
+	return AddFeeExemptionMsg{
+		From:       from,
+		ProposalId: proposalId,
+		Account:    account,
+	}
+}
+
+func (msg AddFeeExemptionMsg) Route() string                { return FeeExemptionRoute }
+func (msg AddFeeExemptionMsg) Type() string                 { return AddFeeExemptionMsgType }
+func (msg AddFeeExemptionMsg) String() string               { return fmt.Sprintf("AddFeeExemption{%#v}", msg) }
+func (msg AddFeeExemptionMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.From} }
+
+func (msg AddFeeExemptionMsg) ValidateBasic() sdk.Error {
+	return validateFeeExemptionMsg(msg.ProposalId, msg.Account)
+}
+
+func (msg AddFeeExemptionMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (msg AddFeeExemptionMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.From, msg.Account}
+}
+
+var _ sdk.Msg = RemoveFeeExemptionMsg{}
+
+// RemoveFeeExemptionMsg removes the fee exemption of the account, per a passed fee exemptions change proposal
+// removing it. Anyone can send it once the proposal passes.
+type RemoveFeeExemptionMsg struct {
+	From       sdk.AccAddress `json:"from"`
+	ProposalId int64          `json:"proposal_id"`
+	Account    sdk.AccAddress `json:"account"`
+}
+
+func NewRemoveFeeExemptionMsg(from sdk.AccAddress, proposalId int64, account sdk.AccAddress) RemoveFeeExemptionMsg {
+	return RemoveFeeExemptionMsg{
+		From:       from,
+		ProposalId: proposalId,
+		Account:    account,
+	}
+}
+
+func (msg RemoveFeeExemptionMsg) Route() string                { return FeeExemptionRoute }
+func (msg RemoveFeeExemptionMsg) Type() string                 { return RemoveFeeExemptionMsgType }
+func (msg RemoveFeeExemptionMsg) String() string               { return fmt.Sprintf("RemoveFeeExemption{%#v}", msg) }
+func (msg RemoveFeeExemptionMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.From} }
+
+func (msg RemoveFeeExemptionMsg) ValidateBasic() sdk.Error {
+	return validateFeeExemptionMsg(msg.ProposalId, msg.Account)
+}
+
+func (msg RemoveFeeExemptionMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (msg RemoveFeeExemptionMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.From, msg.Account}
+}
+
+func validateFeeExemptionMsg(proposalId int64, account sdk.AccAddress) sdk.Error {
+	if proposalId <= 0 {
+		return ErrInvalidProposal("proposal id should be positive")
+	}
+	if len(account) != sdk.AddrLen {
+		return sdk.ErrInvalidAddress(fmt.Sprintf("account length should be %d", sdk.AddrLen))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	TokenTradingRoute      = "dexTokenTrading"
+	SetTokenTradingMsgType = "setTokenTrading"
+)
+
+var _ sdk.Msg = SetTokenTradingMsg{}
+
+// SetTokenTradingMsg disables or enables the trading of the token on all its trading pairs, per a passed token
+// trading change proposal. Anyone can send it once the proposal passes.
+type SetTokenTradingMsg struct {
+	From       sdk.AccAddress `json:"from"`
+	ProposalId int64          `json:"proposal_id"`
+	Symbol     string         `json:"symbol"`
+	Disabled   bool           `json:"disabled"`
+}
+
+func NewSetTokenTradingMsg(from sdk.AccAddress, proposalId int64, symbol string, disabled bool) SetTokenTradingMsg {
+	return SetTokenTradingMsg{
+		From:       from,
+		ProposalId: proposalId,
+		Symbol:     symbol,
+		Disabled:   disabled,
+	}
+}
+
+func (msg SetTokenTradingMsg) Route() string                { return TokenTradingRoute }
+func (msg SetTokenTradingMsg) Type() string                 { return SetTokenTradingMsgType }
+func (msg SetTokenTradingMsg) String() string               { return fmt.Sprintf("SetTokenTrading{%#v}", msg) }
+func (msg SetTokenTradingMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.From} }
+
+func (msg SetTokenTradingMsg) ValidateBasic() sdk.Error {
+	if msg.ProposalId <= 0 {
+		return ErrInvalidProposal("proposal id should be positive")
+	}
+	if len(strings.TrimSpace(msg.Symbol)) == 0 {
+		return sdk.ErrInvalidCoins("symbol of the token is missing")
+	}
+	return nil
+}
+
+func (msg SetTokenTradingMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (msg SetTokenTradingMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return msg.GetSigners()
+}
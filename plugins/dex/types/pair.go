@@ -11,6 +11,23 @@ type TradingPair struct {
 	ListPrice        ctuils.Fixed8 `json:"list_price"`
 	TickSize         ctuils.Fixed8 `json:"tick_size"`
 	LotSize          ctuils.Fixed8 `json:"lot_size"`
+	// MatchInterval is the number of blocks between two matchings of the pair, which overrides matching
+	// in every block. 0 or 1 means matching in every block. All the pairs are matched in the breathe blocks.
+	MatchInterval int64 `json:"match_interval"`
+	// SizesFixed is set once the tick size or lot size is changed by governance, since then they are no longer
+	// adjusted by the price in the breathe blocks.
+	SizesFixed bool `json:"sizes_fixed"`
+	// SessionOpen and SessionClose are the daily trading session of the pair, in seconds of the UTC day. The pair
+	// only takes new orders and is matched in [SessionOpen, SessionClose), which wraps around the midnight if
+	// SessionOpen is after SessionClose. The pair trades all day if they are equal.
+	SessionOpen  int64 `json:"session_open"`
+	SessionClose int64 `json:"session_close"`
+	// PublicationDepth is the number of the price levels of each side of the order book of the pair published,
+	// which overrides the depth of the publication for all the pairs. 0 means the depth for all the pairs.
+	PublicationDepth int64 `json:"publication_depth"`
+	// GTCTTLDays is the number of days after which the GTE orders of the pair are expired by the breathe blocks,
+	// which overrides the TTL for all the pairs. 0 means the TTL for all the pairs.
+	GTCTTLDays int64 `json:"gtc_ttl_days"`
 }
 
 // NOTE: only for test use
@@ -30,6 +47,39 @@ func NewTradingPairWithLotSize(baseAsset, quoteAsset string, listPrice, lotSize
 	}
 }
 
+// SecondsPerDay is the length of a day, which the trading sessions of the pairs repeat by.
+const SecondsPerDay = 24 * 60 * 60
+
+// TradingSession is the daily trading session of a pair, see TradingPair.SessionOpen.
+type TradingSession struct {
+	Open  int64 `json:"open"`
+	Close int64 `json:"close"`
+}
+
+// IsAllDay tells whether the pair trades all day, i.e. it has no session.
+func (s TradingSession) IsAllDay() bool {
+	return s.Open == s.Close
+}
+
+// IsOpenAt tells whether the session is open at the time in unix seconds.
+func (s TradingSession) IsOpenAt(t int64) bool {
+	if s.IsAllDay() {
+		return true
+	}
+	sec := t % SecondsPerDay
+	if sec < 0 {
+		sec += SecondsPerDay
+	}
+	if s.Open < s.Close {
+		return sec >= s.Open && sec < s.Close
+	}
+	return sec >= s.Open || sec < s.Close
+}
+
+func (pair *TradingPair) GetSession() TradingSession {
+	return TradingSession{Open: pair.SessionOpen, Close: pair.SessionClose}
+}
+
 func (pair *TradingPair) GetSymbol() string {
 	return utils.Assets2TradingPair(pair.BaseAssetSymbol, pair.QuoteAssetSymbol)
 }
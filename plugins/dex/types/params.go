@@ -0,0 +1,417 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramTypes "github.com/cosmos/cosmos-sdk/x/paramHub/types"
+
+	"github.com/bnb-chain/node/common/upgrade"
+	cmnUtils "github.com/bnb-chain/node/common/utils"
+)
+
+// DexParamsType and PairParamsType are the param types of the DexParams and PairParamsChange in the fee change
+// proposals.
+const (
+	DexParamsType  = "dex_params"
+	PairParamsType = "pair_params"
+)
+
+// MakerRebateRateBase is the denominator of the maker rebate rate, i.e. the rate is in bps of the taker's fee.
+const MakerRebateRateBase = 10000
+
+// The orderings of charging the trade fees of an account in a block. The fee of each trade is truncated to int64,
+// and once the account runs out of the native token, the following trades are charged by the received tokens, so
+// the ordering decides who absorbs the truncation and which trades pay in the native token.
+const (
+	// AllocationOrderingMatch charges the trades in the sequence of matching, with the fees truncated.
+	AllocationOrderingMatch = "match"
+	// AllocationOrderingOrderId charges the trades by the order ids, with the fees truncated.
+	AllocationOrderingOrderId = "order_id"
+	// AllocationOrderingAlternateRounding charges the trades in the sequence of matching, and in every trade
+	// exactly one side has its fee rounded up while the other has it truncated. The side is picked by the hash
+	// of the trade, so over many trades every party rounds up about half of the time and the truncation is not
+	// systematically in favor of (or against) any party, including the fee collector.
+	AllocationOrderingAlternateRounding = "alternate_rounding"
+)
+
+// The policies of the orders whose owners can afford the locked principal but not the trade fee in the native token.
+const (
+	// InsufficientFeePolicyChargeReceived accepts the order, and the fee of a trade the account can't pay in the
+	// native token is charged from the asset it receives in the trade instead, by the FeeRate. It's the default.
+	InsufficientFeePolicyChargeReceived = "charge_received"
+	// InsufficientFeePolicyReject rejects the order at placement if the free balance of the native token left after
+	// locking the order can't cover the worst-case trade fee of filling the whole order at its price, i.e. rounded
+	// up and raised to the min trade fee. The orders receiving the native token are never rejected, as their fees
+	// are charged from it.
+	InsufficientFeePolicyReject = "reject"
+)
+
+// DexParams are the dex parameters under governance. Since the GovernedParams upgrade, they are carried as a whole
+// by a fee change proposal of the param hub, e.g.
+//
+//	{"fee_params":[{"type":"dex/DexParams","value":{"matching_paused":true,...}}],"description":"pause the matching"}
+//
+// and replace the current ones at the end of the next breathe block, so the fields left out are reset to their zero
+// values. As with the other fee params, only the last fee change proposal passed since the last breathe block is
+// applied.
+type DexParams struct {
+	// MatchingPaused halts the matching of all the trading pairs, orders are still accepted meanwhile.
+	MatchingPaused bool `json:"matching_paused"`
+	// MaxOrdersPerAccountPerBlock is the max number of orders an account can place in a block, 0 means unlimited.
+	MaxOrdersPerAccountPerBlock int64 `json:"max_orders_per_account_per_block"`
+	// AllocationOrdering is the ordering of charging the trade fees, see AllocationOrderingMatch etc.
+	AllocationOrdering string `json:"allocation_ordering"`
+	// MinBalanceToPlaceOrder is the min free balance of the native token an account needs to place orders, 0 means no limit.
+	MinBalanceToPlaceOrder int64 `json:"min_balance_to_place_order"`
+	// MaxTotalOrders is the max number of open orders of all the trading pairs, new orders are rejected once
+	// it's reached, 0 means unlimited. It bounds the memory of the order books.
+	MaxTotalOrders int64 `json:"max_total_orders"`
+	// CancelCooldownBlocks is the min number of blocks between placing an order and cancelling it, to discourage
+	// quote stuffing, 0 means no cooldown. The orders are still filled and expired as usual meanwhile.
+	CancelCooldownBlocks int64 `json:"cancel_cooldown_blocks"`
+	// MatchBatchSize is the max number of incoming orders matched in a block, 0 means unlimited. The pairs beyond
+	// it are deferred to the next blocks, which bounds the time of matching at the cost of latency. It takes
+	// effect at the next breathe block.
+	MatchBatchSize int64 `json:"match_batch_size"`
+	// MakerRebateRate is the share of the taker's fee of a trade credited to the maker, in 1/MakerRebateRateBase,
+	// 0 disables the maker rebate. The maker is eligible if its order was resting within MakerRebateMaxSpread
+	// of the mid of the resting book at the match, its fee on the trade is waived and the rebate is published
+	// as its negative fee.
+	MakerRebateRate int64 `json:"maker_rebate_rate"`
+	// MakerRebateMaxSpread is the max distance of the maker's price from the mid for the maker rebate, in bps of the mid.
+	MakerRebateMaxSpread int64 `json:"maker_rebate_max_spread"`
+	// DelistFeeFree cancels the open orders of a delisted trading pair without any fee, i.e. all the locked coins
+	// are refunded. Otherwise the orders are expired with the expire fee as in the breathe blocks.
+	DelistFeeFree bool `json:"delist_fee_free"`
+	// HaltSchedule is the maintenance windows in which the matching of all the trading pairs is halted, orders are
+	// still accepted meanwhile and matched once the window ends. It's checked against the block time, so all the
+	// nodes agree on it. A change replaces the whole schedule.
+	HaltSchedule []HaltWindow `json:"halt_schedule"`
+	// DuplicateOrderWindowBlocks rejects an order of the same symbol, side, price and quantity as one the account
+	// placed in the recent blocks of the number, to guard the clients against the double submissions of their
+	// retries, 0 disables the check. Only the last 100 orders of an account in the window are checked.
+	DuplicateOrderWindowBlocks int64 `json:"duplicate_order_window_blocks"`
+	// InsufficientFeePolicy is how the orders are treated if their owners can't afford the trade fees in the native
+	// token, see InsufficientFeePolicyChargeReceived etc.
+	InsufficientFeePolicy string `json:"insufficient_fee_policy"`
+	// MinOrderLifetimeBlocks is the min number of blocks an order has to rest in the order book before it can be
+	// cancelled, to discourage flickering quotes, 0 means no minimum. Unlike CancelCooldownBlocks, an order waiting
+	// for its first matching, e.g. of a deferred pair, hasn't rested at all. The orders are still filled as usual.
+	MinOrderLifetimeBlocks int64 `json:"min_order_lifetime_blocks"`
+	// DustFeeConversionThreshold converts the assets of the fees accumulated for all the validators into the native
+	// token in the breathe blocks, if they are worth no more than the threshold in the native token at the best bid
+	// of their pairs against it, 0 disables the conversion. They are sold by IOC orders matched with the other
+	// orders of the breathe block, and no trade fee is charged.
+	DustFeeConversionThreshold int64 `json:"dust_fee_conversion_threshold"`
+	// ExpireDisabledTokenOrders expires the open orders of the pairs whose base or quote token is disabled from
+	// trading in the breathe blocks, without any fee, i.e. all the locked coins are refunded. Otherwise disabling
+	// a token only rejects the new orders of its pairs, and the open orders are left in the order books.
+	ExpireDisabledTokenOrders bool `json:"expire_disabled_token_orders"`
+}
+
+// MaxDuplicateOrderWindowBlocks is the max window of the duplicate order check.
+const MaxDuplicateOrderWindowBlocks = 10000
+
+// MaxHaltWindows is the max number of the windows in the halt schedule.
+const MaxHaltWindows = 64
+
+// HaltWindow is the range of time [Start, End) in unix seconds (UTC) in which the matching is halted.
+type HaltWindow struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// IsHaltedAt tells whether the time in unix seconds is within any window of the halt schedule.
+func (p DexParams) IsHaltedAt(t int64) bool {
+	for _, w := range p.HaltSchedule {
+		if w.Start <= t && t < w.End {
+			return true
+		}
+	}
+	return false
+}
+
+func DefaultDexParams() DexParams {
+	return DexParams{
+		MatchingPaused:              false,
+		MaxOrdersPerAccountPerBlock: 0,
+		AllocationOrdering:          AllocationOrderingMatch,
+		MinBalanceToPlaceOrder:      0,
+		MaxTotalOrders:              0,
+		CancelCooldownBlocks:        0,
+		MatchBatchSize:              0,
+		MakerRebateRate:             0,
+		MakerRebateMaxSpread:        0,
+		DelistFeeFree:               false,
+		HaltSchedule:                nil,
+		DuplicateOrderWindowBlocks:  0,
+		InsufficientFeePolicy:       InsufficientFeePolicyChargeReceived,
+		MinOrderLifetimeBlocks:      0,
+		DustFeeConversionThreshold:  0,
+		ExpireDisabledTokenOrders:   false,
+	}
+}
+
+var _ paramTypes.FeeParam = (*DexParams)(nil)
+
+// GetParamType implements the FeeParam of the param hub.
+func (p *DexParams) GetParamType() string {
+	return DexParamsType
+}
+
+// Check implements the FeeParam of the param hub, it's called when the fee change proposal is submitted and again
+// when it's applied.
+func (p *DexParams) Check() error {
+	if !sdk.IsUpgrade(upgrade.GovernedParams) {
+		return fmt.Errorf("dex params can not be changed before the %s upgrade", upgrade.GovernedParams)
+	}
+	return p.validate()
+}
+
+func (p DexParams) validate() error {
+	if p.MaxOrdersPerAccountPerBlock < 0 {
+		return fmt.Errorf("max_orders_per_account_per_block should not be negative, got %d", p.MaxOrdersPerAccountPerBlock)
+	}
+	if p.MinBalanceToPlaceOrder < 0 {
+		return fmt.Errorf("min_balance_to_place_order should not be negative, got %d", p.MinBalanceToPlaceOrder)
+	}
+	if p.MaxTotalOrders < 0 {
+		return fmt.Errorf("max_total_orders should not be negative, got %d", p.MaxTotalOrders)
+	}
+	if p.CancelCooldownBlocks < 0 {
+		return fmt.Errorf("cancel_cooldown_blocks should not be negative, got %d", p.CancelCooldownBlocks)
+	}
+	if p.MinOrderLifetimeBlocks < 0 {
+		return fmt.Errorf("min_order_lifetime_blocks should not be negative, got %d", p.MinOrderLifetimeBlocks)
+	}
+	if p.DustFeeConversionThreshold < 0 {
+		return fmt.Errorf("dust_fee_conversion_threshold should not be negative, got %d", p.DustFeeConversionThreshold)
+	}
+	if p.MatchBatchSize < 0 {
+		return fmt.Errorf("match_batch_size should not be negative, got %d", p.MatchBatchSize)
+	}
+	if p.MakerRebateRate < 0 || p.MakerRebateRate > MakerRebateRateBase {
+		return fmt.Errorf("maker_rebate_rate should be in [0, %d], got %d", MakerRebateRateBase, p.MakerRebateRate)
+	}
+	if p.MakerRebateMaxSpread < 0 {
+		return fmt.Errorf("maker_rebate_max_spread should not be negative, got %d", p.MakerRebateMaxSpread)
+	}
+	if p.DuplicateOrderWindowBlocks < 0 || p.DuplicateOrderWindowBlocks > MaxDuplicateOrderWindowBlocks {
+		return fmt.Errorf("duplicate_order_window_blocks should be in [0, %d], got %d", MaxDuplicateOrderWindowBlocks, p.DuplicateOrderWindowBlocks)
+	}
+	if len(p.HaltSchedule) > MaxHaltWindows {
+		return fmt.Errorf("halt_schedule should have at most %d windows, got %d", MaxHaltWindows, len(p.HaltSchedule))
+	}
+	for _, w := range p.HaltSchedule {
+		if w.Start < 0 || w.Start >= w.End {
+			return fmt.Errorf("window of halt_schedule should have 0 <= start < end, got [%d, %d)", w.Start, w.End)
+		}
+	}
+	switch p.AllocationOrdering {
+	case "", AllocationOrderingMatch, AllocationOrderingOrderId, AllocationOrderingAlternateRounding:
+	default:
+		return fmt.Errorf("unknown allocation_ordering %s", p.AllocationOrdering)
+	}
+	switch p.InsufficientFeePolicy {
+	case "", InsufficientFeePolicyChargeReceived, InsufficientFeePolicyReject:
+	default:
+		return fmt.Errorf("unknown insufficient_fee_policy %s", p.InsufficientFeePolicy)
+	}
+	return nil
+}
+
+// MaxPairMatchInterval is the max number of blocks between two matchings of a trading pair.
+const MaxPairMatchInterval = 10000
+
+// MaxPairPublicationDepth is the max number of the price levels of a side of the order book of a pair published.
+const MaxPairPublicationDepth = 1000
+
+// MaxPairGTCTTLDays is the max number of days the GTE orders of a pair live, which is the TTL of the orders on the
+// preferred price levels (BEP67).
+const MaxPairGTCTTLDays = 30
+
+// MaxPairTickSize and MaxPairLotSize are the max tick size and lot size a trading pair can be changed to.
+const (
+	MaxPairTickSize = 1e13
+	MaxPairLotSize  = 1e13
+)
+
+// PairParamsChange changes the params of a listed trading pair. Since the GovernedParams upgrade, it's carried by a
+// fee change proposal of the param hub, e.g.
+//
+//	{"fee_params":[{"type":"dex/PairParamsChange","value":{"symbol":"XYZ-000_BNB","match_interval":"4"}}],...}
+//
+// and takes effect at the end of the next breathe block. Only the fields present in the change are updated, and a
+// proposal may carry the changes of several pairs. As with the other fee params, only the last fee change proposal
+// passed since the last breathe block is applied.
+//
+// The tick size and lot size must be powers of 10. Changing them is rejected when it's applied if any resting
+// order of the pair is not on the new sizes, as its price or remaining quantity would never fit a match, so
+// markets are usually refined, e.g. the new sizes divide the old ones.
+//
+// The session open and close are in seconds of the UTC day, see TradingPair.SessionOpen. The publication depth
+// overrides the depth of the order book published for all the pairs, see TradingPair.PublicationDepth. The GTC TTL
+// overrides the days after which the GTE orders are expired for all the pairs, see TradingPair.GTCTTLDays.
+type PairParamsChange struct {
+	Symbol           string `json:"symbol"`
+	MatchInterval    *int64 `json:"match_interval,omitempty"`
+	TickSize         *int64 `json:"tick_size,omitempty"`
+	LotSize          *int64 `json:"lot_size,omitempty"`
+	SessionOpen      *int64 `json:"session_open,omitempty"`
+	SessionClose     *int64 `json:"session_close,omitempty"`
+	PublicationDepth *int64 `json:"publication_depth,omitempty"`
+	GTCTTLDays       *int64 `json:"gtc_ttl_days,omitempty"`
+}
+
+var _ paramTypes.FeeParam = (*PairParamsChange)(nil)
+
+// GetParamType implements the FeeParam of the param hub.
+func (c *PairParamsChange) GetParamType() string {
+	return PairParamsType
+}
+
+// Check implements the FeeParam of the param hub. The pair is only looked up when the change is applied, as it
+// may be delisted meanwhile.
+func (c *PairParamsChange) Check() error {
+	if !sdk.IsUpgrade(upgrade.GovernedParams) {
+		return fmt.Errorf("pair params can not be changed before the %s upgrade", upgrade.GovernedParams)
+	}
+	return c.validate()
+}
+
+func (c PairParamsChange) validate() error {
+	if c.Symbol == "" {
+		return fmt.Errorf("symbol of the trading pair is missing")
+	}
+	if c.MatchInterval != nil && (*c.MatchInterval < 0 || *c.MatchInterval > MaxPairMatchInterval) {
+		return fmt.Errorf("match_interval should be in [0, %d], got %d", MaxPairMatchInterval, *c.MatchInterval)
+	}
+	if c.TickSize != nil && !isPowerOf10(*c.TickSize, MaxPairTickSize) {
+		return fmt.Errorf("tick_size should be a power of 10 in [1, %d], got %d", int64(MaxPairTickSize), *c.TickSize)
+	}
+	if c.LotSize != nil && !isPowerOf10(*c.LotSize, MaxPairLotSize) {
+		return fmt.Errorf("lot_size should be a power of 10 in [1, %d], got %d", int64(MaxPairLotSize), *c.LotSize)
+	}
+	if c.SessionOpen != nil && (*c.SessionOpen < 0 || *c.SessionOpen >= SecondsPerDay) {
+		return fmt.Errorf("session_open should be in [0, %d), got %d", SecondsPerDay, *c.SessionOpen)
+	}
+	if c.SessionClose != nil && (*c.SessionClose < 0 || *c.SessionClose >= SecondsPerDay) {
+		return fmt.Errorf("session_close should be in [0, %d), got %d", SecondsPerDay, *c.SessionClose)
+	}
+	if c.PublicationDepth != nil && (*c.PublicationDepth < 0 || *c.PublicationDepth > MaxPairPublicationDepth) {
+		return fmt.Errorf("publication_depth should be in [0, %d], got %d", MaxPairPublicationDepth, *c.PublicationDepth)
+	}
+	if c.GTCTTLDays != nil && (*c.GTCTTLDays < 0 || *c.GTCTTLDays > MaxPairGTCTTLDays) {
+		return fmt.Errorf("gtc_ttl_days should be in [0, %d], got %d", MaxPairGTCTTLDays, *c.GTCTTLDays)
+	}
+	return nil
+}
+
+// ChangesSizes tells whether the change is about the tick size or lot size.
+func (c PairParamsChange) ChangesSizes() bool {
+	return c.TickSize != nil || c.LotSize != nil
+}
+
+// Apply returns a copy of the trading pair with the change applied.
+func (c PairParamsChange) Apply(pair TradingPair) TradingPair {
+	if c.MatchInterval != nil {
+		pair.MatchInterval = *c.MatchInterval
+	}
+	if c.TickSize != nil {
+		pair.TickSize = cmnUtils.Fixed8(*c.TickSize)
+	}
+	if c.LotSize != nil {
+		pair.LotSize = cmnUtils.Fixed8(*c.LotSize)
+	}
+	if c.ChangesSizes() {
+		pair.SizesFixed = true
+	}
+	if c.SessionOpen != nil {
+		pair.SessionOpen = *c.SessionOpen
+	}
+	if c.SessionClose != nil {
+		pair.SessionClose = *c.SessionClose
+	}
+	if c.PublicationDepth != nil {
+		pair.PublicationDepth = *c.PublicationDepth
+	}
+	if c.GTCTTLDays != nil {
+		pair.GTCTTLDays = *c.GTCTTLDays
+	}
+	return pair
+}
+
+func isPowerOf10(n, max int64) bool {
+	for p := int64(1); p <= max; p *= 10 {
+		if n == p {
+			return true
+		}
+	}
+	return false
+}
+
+const feeExemptionsChangeKey = "fee_exemptions"
+
+// MaxFeeExemptAccounts is the max number of the accounts a fee exemptions change adds or removes.
+const MaxFeeExemptAccounts = 100
+
+// FeeExemptionsChange adds the accounts to or removes them from the fee exempt accounts, which pay no trade fees
+// nor tx fees. It's carried by a text proposal whose description is a FeeExemptionsChange in json, e.g.
+// {"fee_exemptions":{"add":["bnb1..."],"remove":["bnb1..."]}}. Once the proposal passes, each account is added by
+// an AddFeeExemptionMsg or removed by a RemoveFeeExemptionMsg referring to it.
+type FeeExemptionsChange struct {
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+func (c FeeExemptionsChange) Check() error {
+	if len(c.Add) == 0 && len(c.Remove) == 0 {
+		return fmt.Errorf("fee exemptions change should add or remove accounts")
+	}
+	if len(c.Add)+len(c.Remove) > MaxFeeExemptAccounts {
+		return fmt.Errorf("fee exemptions change should add or remove at most %d accounts, got %d",
+			MaxFeeExemptAccounts, len(c.Add)+len(c.Remove))
+	}
+	for _, addr := range append(append([]string(nil), c.Add...), c.Remove...) {
+		if _, err := sdk.AccAddressFromBech32(addr); err != nil {
+			return fmt.Errorf("invalid fee exempt account %s, err=%s", addr, err.Error())
+		}
+	}
+	return nil
+}
+
+// Lists tells whether the change adds the account if exempt is true, or removes it otherwise.
+func (c FeeExemptionsChange) Lists(addr sdk.AccAddress, exempt bool) bool {
+	accounts := c.Remove
+	if exempt {
+		accounts = c.Add
+	}
+	for _, s := range accounts {
+		if listed, err := sdk.AccAddressFromBech32(s); err == nil && listed.Equals(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetFeeExemptionsChange returns the fee exemptions change in the description of a text proposal,
+// ok is false if the proposal is not about the fee exemptions.
+func GetFeeExemptionsChange(description string) (change FeeExemptionsChange, ok bool, err error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(description), &fields); err != nil {
+		return change, false, nil
+	}
+	raw, ok := fields[feeExemptionsChangeKey]
+	if !ok {
+		return change, false, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&change); err != nil {
+		return change, true, fmt.Errorf("illegal fee exemptions change %s, err=%s", string(raw), err.Error())
+	}
+	return change, true, change.Check()
+}
@@ -25,6 +25,19 @@ func CalBigNotional(price, qty int64) *big.Int {
 	return bi.Div(bi.Mul(big.NewInt(qty), big.NewInt(price)), big.NewInt(1e8))
 }
 
+// CalNotionalInt64Checked returns the notional of the price and quantity, ok is false if it doesn't fit in int64,
+// rather than wrapping around like CalBigNotionalInt64.
+func CalNotionalInt64Checked(price, qty int64) (notional int64, ok bool) {
+	if res, ok := utils.Mul64(price, qty); ok {
+		return res / 1e8, true
+	}
+	bi := CalBigNotional(price, qty)
+	if !bi.IsInt64() {
+		return 0, false
+	}
+	return bi.Int64(), true
+}
+
 // IsExceedMaxNotional return the result that is the product of price and quantity exceeded max notional
 func IsExceedMaxNotional(price, qty int64) bool {
 	// The four short-cuts can cover most of the cases.
@@ -25,6 +25,26 @@ func TestIsExceedMaxNotional(t *testing.T) {
 	assert.Equal(false, utils.IsExceedMaxNotional(1, 1))
 }
 
+func TestCalNotionalInt64Checked(t *testing.T) {
+	assert := assert.New(t)
+	notional, ok := utils.CalNotionalInt64Checked(1e8, 1e8)
+	assert.True(ok)
+	assert.Equal(int64(1e8), notional)
+	// the max notional at the boundary
+	notional, ok = utils.CalNotionalInt64Checked(math.MaxInt64, 1e8)
+	assert.True(ok)
+	assert.Equal(int64(math.MaxInt64), notional)
+	notional, ok = utils.CalNotionalInt64Checked(1e8, math.MaxInt64)
+	assert.True(ok)
+	assert.Equal(int64(math.MaxInt64), notional)
+	_, ok = utils.CalNotionalInt64Checked(math.MaxInt64, 1e8+1)
+	assert.False(ok)
+	_, ok = utils.CalNotionalInt64Checked(math.MaxInt64/2+1, 2e8)
+	assert.False(ok)
+	_, ok = utils.CalNotionalInt64Checked(math.MaxInt64, math.MaxInt64)
+	assert.False(ok)
+}
+
 func TestIsUnderMinNotional(t *testing.T) {
 	assert := assert.New(t)
 	assert.Equal(false, utils.IsUnderMinNotional(math.MaxInt64, math.MaxInt64))
@@ -18,9 +18,14 @@ func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(types.TradingPair{}, "dex/TradingPair", nil)
 
 	cdc.RegisterConcrete(types.ListMiniMsg{}, "dex/ListMiniMsg", nil)
+	cdc.RegisterConcrete(types.AddFeeExemptionMsg{}, "dex/AddFeeExemptionMsg", nil)
+	cdc.RegisterConcrete(types.RemoveFeeExemptionMsg{}, "dex/RemoveFeeExemptionMsg", nil)
+	cdc.RegisterConcrete(types.SetTokenTradingMsg{}, "dex/SetTokenTradingMsg", nil)
 
 	cdc.RegisterConcrete(order.FeeConfig{}, "dex/FeeConfig", nil)
 	cdc.RegisterConcrete(order.OrderBookSnapshot{}, "dex/OrderBookSnapshot", nil)
 	cdc.RegisterConcrete(order.ActiveOrders{}, "dex/ActiveOrders", nil)
 	cdc.RegisterConcrete(store.RecentPrice{}, "dex/RecentPrice", nil)
+	cdc.RegisterConcrete(&types.DexParams{}, "dex/DexParams", nil)
+	cdc.RegisterConcrete(&types.PairParamsChange{}, "dex/PairParamsChange", nil)
 }
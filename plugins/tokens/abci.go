@@ -10,8 +10,13 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/tokens/store"
 )
 
+// MaxTokenListLimit is the max page size of the tokens/list query, the larger limits are capped to it.
+// It's only meant to be lowered by the tests, which don't create that many tokens.
+var MaxTokenListLimit = 1000
+
 func createAbciQueryHandler(mapper Mapper, prefix string) types.AbciQueryHandler {
 	queryPrefix := prefix
 	var isMini bool
@@ -48,6 +53,8 @@ func createAbciQueryHandler(mapper Mapper, prefix string) types.AbciQueryHandler
 			}
 			return queryAndMarshallToken(app, mapper, ctx, symbol)
 		case "list": // args: ["tokens", "list", <offset>, <limit>, <showZeroSupplyTokens>]
+			// returns the tokens in the order of the symbols, each with its name, symbol, total supply, owner and
+			// whether it's mintable. All the tokens can be burnt by their owners.
 			if len(path) < 4 {
 				return &abci.ResponseQuery{
 					Code: uint32(sdk.CodeUnknownRequest),
@@ -78,6 +85,9 @@ func createAbciQueryHandler(mapper Mapper, prefix string) types.AbciQueryHandler
 					Log:  "unable to parse limit",
 				}
 			}
+			if limit > MaxTokenListLimit {
+				limit = MaxTokenListLimit
+			}
 			end := offset + limit
 			if end > len(tokens) {
 				end = len(tokens)
@@ -116,6 +126,40 @@ func createAbciQueryHandler(mapper Mapper, prefix string) types.AbciQueryHandler
 				Code:  uint32(sdk.ABCICodeOK),
 				Value: bz,
 			}
+		case "meta": // args: ["tokens", "meta", <symbol>]
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log: fmt.Sprintf(
+						"%s %s query requires a symbol path arg",
+						queryPrefix, path[1]),
+				}
+			}
+			ctx := app.GetContextForCheckState()
+			symbol := strings.ToUpper(path[2])
+			if _, err := mapper.GetToken(ctx, symbol); err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			meta := store.TokenMeta{
+				Symbol:               symbol,
+				TransferMemoRequired: mapper.IsTransferMemoRequired(ctx, symbol),
+				TransferFeeRate:      mapper.GetTransferFeeRate(ctx, symbol),
+				TradingDisabled:      mapper.IsTradingDisabled(ctx, symbol),
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(meta)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
 		default:
 			return &abci.ResponseQuery{
 				Code: uint32(sdk.ABCICodeOK),
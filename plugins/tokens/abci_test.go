@@ -15,6 +15,7 @@ import (
 
 	bca "github.com/bnb-chain/node/app"
 	common "github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/tokens"
 )
 
 // util objects
@@ -119,6 +120,40 @@ func Test_Tokens_ABCI_GetTokens_Success(t *testing.T) {
 	}, actual)
 }
 
+func Test_Tokens_ABCI_GetTokens_Success_LimitCapped(t *testing.T) {
+	path := "/tokens/list/0/9223372036854775807"
+
+	defer func(limit int) { tokens.MaxTokenListLimit = limit }(tokens.MaxTokenListLimit)
+	tokens.MaxTokenListLimit = 1
+
+	ctx := app.NewContext(sdk.RunTxModeCheck, abci.Header{})
+	err := app.TokenMapper.NewToken(ctx, token1)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	err = app.TokenMapper.NewToken(ctx, token2)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	query := abci.RequestQuery{
+		Path: path,
+		Data: []byte(""),
+	}
+	res := app.Query(query)
+
+	cdc := app.GetCodec()
+	actual := make([]common.Token, 1)
+	err = cdc.UnmarshalBinaryLengthPrefixed(res.Value, &actual)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// only the first of the two tokens is returned
+	assert.True(t, sdk.ABCICodeType(res.Code).IsOK())
+	assert.Equal(t, []common.Token{*token1}, actual)
+}
+
 func Test_Tokens_ABCI_GetTokens_Success_WithOffset(t *testing.T) {
 	path := "/tokens/list/1/5"
 
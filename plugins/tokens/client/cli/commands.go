@@ -37,6 +37,8 @@ func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
 			claimHTLTCmd(cmdr),
 			refundHTLTCmd(cmdr),
 			transferOwnershipCmd(cmdr),
+			setTransferMemoRequiredCmd(cmdr),
+			setTransferFeeCmd(cmdr),
 		)...)
 
 	tokenCmd.AddCommand(
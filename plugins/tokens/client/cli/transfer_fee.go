@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bnb-chain/node/common/client"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/tokens/transferfee"
+)
+
+const flagRate = "rate"
+
+func setTransferFeeCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-transfer-fee",
+		Short: "set the fee charged on the transfers of the token",
+		RunE:  cmdr.setTransferFee,
+	}
+
+	cmd.Flags().StringP(flagSymbol, "s", "", "symbol of the token")
+	cmd.Flags().Int64(flagRate, 0, "rate of the fee in bps of the amount transferred, 0 removes the fee")
+
+	return cmd
+}
+
+func (c Commander) setTransferFee(cmd *cobra.Command, args []string) error {
+	cliCtx, txBldr := client.PrepareCtx(c.Cdc)
+	from, err := cliCtx.GetFromAddress()
+	if err != nil {
+		return err
+	}
+
+	symbol := viper.GetString(flagSymbol)
+	if !types.IsValidMiniTokenSymbol(symbol) {
+		err = types.ValidateTokenSymbol(symbol)
+		if err != nil {
+			return err
+		}
+	}
+	symbol = strings.ToUpper(symbol)
+
+	msg := transferfee.NewSetTransferFeeMsg(from, symbol, viper.GetInt64(flagRate))
+	err = msg.ValidateBasic()
+	if err != nil {
+		return err
+	}
+
+	return client.SendOrPrintTx(cliCtx, txBldr, msg)
+}
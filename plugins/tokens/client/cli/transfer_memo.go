@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bnb-chain/node/common/client"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/tokens/transfermemo"
+)
+
+const flagRequired = "required"
+
+func setTransferMemoRequiredCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-transfer-memo",
+		Short: "set whether the transfers of the token require a memo",
+		RunE:  cmdr.setTransferMemoRequired,
+	}
+
+	cmd.Flags().StringP(flagSymbol, "s", "", "symbol of the token")
+	cmd.Flags().Bool(flagRequired, true, "whether the transfers of the token require a memo")
+
+	return cmd
+}
+
+func (c Commander) setTransferMemoRequired(cmd *cobra.Command, args []string) error {
+	cliCtx, txBldr := client.PrepareCtx(c.Cdc)
+	from, err := cliCtx.GetFromAddress()
+	if err != nil {
+		return err
+	}
+
+	symbol := viper.GetString(flagSymbol)
+	if !types.IsValidMiniTokenSymbol(symbol) {
+		err = types.ValidateTokenSymbol(symbol)
+		if err != nil {
+			return err
+		}
+	}
+	symbol = strings.ToUpper(symbol)
+
+	msg := transfermemo.NewSetTransferMemoRequiredMsg(from, symbol, viper.GetBool(flagRequired))
+
+	return client.SendOrPrintTx(cliCtx, txBldr, msg)
+}
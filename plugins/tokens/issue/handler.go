@@ -41,6 +41,10 @@ func handleIssueToken(ctx sdk.Context, tokenMapper store.Mapper, bankKeeper bank
 	errLogMsg := "issue token failed"
 	symbol := strings.ToUpper(msg.Symbol)
 	logger := log.With("module", "token", "symbol", symbol, "name", msg.Name, "total_supply", msg.TotalSupply, "issuer", msg.From)
+	if err := tokenMapper.GetParams(ctx).ValidateSymbol(symbol); err != nil {
+		logger.Info(errLogMsg, "reason", err.Error())
+		return sdk.ErrInvalidCoins(err.Error()).Result()
+	}
 	suffix, err := getTokenSuffix(ctx)
 	if err != nil {
 		logger.Error(errLogMsg, "reason", err.Error())
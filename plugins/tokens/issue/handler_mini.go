@@ -16,6 +16,10 @@ func handleIssueMiniToken(ctx sdk.Context, tokenMapper store.Mapper, bankKeeper
 	errLogMsg := "issue miniToken failed"
 	origSymbol := strings.ToUpper(msg.Symbol)
 	logger := log.With("module", "mini-token", "symbol", origSymbol, "name", msg.Name, "total_supply", msg.TotalSupply, "issuer", msg.From)
+	if err := tokenMapper.GetParams(ctx).ValidateSymbol(origSymbol); err != nil {
+		logger.Info(errLogMsg, "reason", err.Error())
+		return sdk.ErrInvalidCoins(err.Error()).Result()
+	}
 
 	suffix, err := getTokenSuffix(ctx)
 	if err != nil {
@@ -37,6 +41,10 @@ func handleIssueTinyToken(ctx sdk.Context, tokenMapper store.Mapper, bankKeeper
 	errLogMsg := "issue tinyToken failed"
 	origSymbol := strings.ToUpper(msg.Symbol)
 	logger := log.With("module", "mini-token", "symbol", origSymbol, "name", msg.Name, "total_supply", msg.TotalSupply, "issuer", msg.From)
+	if err := tokenMapper.GetParams(ctx).ValidateSymbol(origSymbol); err != nil {
+		logger.Info(errLogMsg, "reason", err.Error())
+		return sdk.ErrInvalidCoins(err.Error()).Result()
+	}
 
 	suffix, err := getTokenSuffix(ctx)
 	if err != nil {
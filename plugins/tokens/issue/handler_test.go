@@ -106,3 +106,53 @@ func TestHandleMintToken(t *testing.T) {
 	invalidMintMsg = NewMintMsg(acc.GetAddress(), "BNB", 10000e8)
 	require.Contains(t, invalidMintMsg.ValidateBasic().Error(), "cannot mint native token")
 }
+
+func TestHandleIssueToken_SymbolParams(t *testing.T) {
+	ctx, handler, accountKeeper, tokenMapper := setup()
+	_, acc := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "000")
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.GovernedParams, -1)
+	defer resetChainVersion()
+	params := store.TokenParams{MaxSymbolLength: 4, SymbolCharset: "ABCDEFGHIJKLMNOPQRSTUVWXYZ"}
+	require.NoError(t, params.Check())
+	tokenMapper.SetParams(ctx, params)
+
+	// at the max length
+	sdkResult := handler(ctx, NewIssueMsg(acc.GetAddress(), "New BNB", "NNBB", 100000e8, false))
+	require.True(t, sdkResult.Code.IsOK(), sdkResult.Log)
+
+	sdkResult = handler(ctx, NewIssueMsg(acc.GetAddress(), "New BNB", "NNBBB", 100000e8, false))
+	require.False(t, sdkResult.Code.IsOK())
+	require.Contains(t, sdkResult.Log, "length of token symbol is limited to 4")
+	_, err := tokenMapper.GetToken(ctx, "NNBBB-000")
+	require.Error(t, err)
+
+	sdkResult = handler(ctx, NewIssueMsg(acc.GetAddress(), "New BNB", "NNB1", 100000e8, false))
+	require.False(t, sdkResult.Code.IsOK())
+	require.Contains(t, sdkResult.Log, "token symbol should only contain the characters")
+
+	sdkResult = handler(ctx, NewIssueMiniMsg(acc.GetAddress(), "New BNB", "NNB1", 10000e8, false, "http://www.xyz.com/nnb.json"))
+	require.False(t, sdkResult.Code.IsOK())
+	require.Contains(t, sdkResult.Log, "token symbol should only contain the characters")
+}
+
+func TestTokenParams_Check(t *testing.T) {
+	params := store.DefaultTokenParams()
+	require.Error(t, params.Check())
+	upgrade.Mgr.AddUpgradeHeight(upgrade.GovernedParams, -1)
+	defer resetChainVersion()
+	require.NoError(t, params.Check())
+
+	for _, invalid := range []store.TokenParams{
+		{MaxSymbolLength: 9, SymbolCharset: store.DefaultSymbolCharset},
+		{MaxSymbolLength: 1, SymbolCharset: store.DefaultSymbolCharset},
+		{MaxSymbolLength: 2, SymbolCharset: "abc"},
+		{MaxSymbolLength: 2, SymbolCharset: ""},
+		{MaxSymbolLength: 2, SymbolCharset: store.DefaultSymbolCharset, MaxAssetsPerAccount: -1},
+	} {
+		require.Error(t, invalid.Check())
+	}
+	valid := store.TokenParams{MaxSymbolLength: 2, SymbolCharset: store.DefaultSymbolCharset}
+	require.NoError(t, valid.Check())
+}
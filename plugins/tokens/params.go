@@ -0,0 +1,70 @@
+package tokens
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+	paramhub "github.com/cosmos/cosmos-sdk/x/paramHub/keeper"
+	paramTypes "github.com/cosmos/cosmos-sdk/x/paramHub/types"
+
+	bnclog "github.com/bnb-chain/node/common/log"
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/tokens/store"
+)
+
+// ParamsChangeHooks validates the token trading changes carried by text proposals since the GovernedParams upgrade,
+// which are applied by the SetTokenTradingMsg once they pass. The other text proposals are left untouched. The token
+// params are changed by the fee change proposals instead, see TokenParams.
+type ParamsChangeHooks struct {
+	mapper store.Mapper
+}
+
+func NewParamsChangeHooks(mapper store.Mapper) ParamsChangeHooks {
+	return ParamsChangeHooks{
+		mapper: mapper,
+	}
+}
+
+var _ gov.GovHooks = ParamsChangeHooks{}
+
+func (hooks ParamsChangeHooks) OnProposalSubmitted(ctx sdk.Context, proposal gov.Proposal) error {
+	if proposal.GetProposalType() != gov.ProposalTypeText {
+		panic(fmt.Sprintf("received wrong type of proposal %x", proposal.GetProposalType()))
+	}
+	if !sdk.IsUpgrade(upgrade.GovernedParams) {
+		return nil
+	}
+
+	tradingChange, ok, err := store.GetTokenTradingChange(proposal.GetDescription())
+	if !ok {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := hooks.mapper.GetToken(ctx, tradingChange.Symbol); err != nil {
+		return fmt.Errorf("token %s does not exist", tradingChange.Symbol)
+	}
+	return nil
+}
+
+// SubscribeParamChange puts the token params of the passed fee change proposals in effect, at the end of the
+// breathe blocks.
+func SubscribeParamChange(hub *paramhub.Keeper, mapper store.Mapper) {
+	logger := bnclog.With("module", "tokens")
+	hub.SubscribeParamChange(
+		func(ctx sdk.Context, iChange interface{}) {
+			changes, ok := iChange.([]paramTypes.FeeParam)
+			if !ok {
+				return
+			}
+			for _, p := range changes {
+				if params, ok := p.(*store.TokenParams); ok {
+					logger.Info("apply token params change", "params", *params)
+					mapper.SetParams(ctx, *params)
+				}
+			}
+		},
+		nil, nil, nil)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/plugins/tokens/swap"
 	"github.com/bnb-chain/node/plugins/tokens/timelock"
+	"github.com/bnb-chain/node/plugins/tokens/transfermemo"
 )
 
 const abciQueryPrefix = "tokens"
@@ -35,6 +36,7 @@ func InitPlugin(
 	appp.RegisterQueryHandler(abciQueryPrefix, tokenHandler)
 	appp.RegisterQueryHandler(miniAbciQueryPrefix, miniTokenHandler)
 	RegisterUpgradeBeginBlocker(mapper)
+	transfermemo.RegisterTransferMemoCheckScript(mapper)
 }
 
 func RegisterUpgradeBeginBlocker(mapper Mapper) {
@@ -13,6 +13,8 @@ import (
 	"github.com/bnb-chain/node/plugins/tokens/store"
 	"github.com/bnb-chain/node/plugins/tokens/swap"
 	"github.com/bnb-chain/node/plugins/tokens/timelock"
+	"github.com/bnb-chain/node/plugins/tokens/transferfee"
+	"github.com/bnb-chain/node/plugins/tokens/transfermemo"
 )
 
 func Routes(tokenMapper store.Mapper, accKeeper auth.AccountKeeper, keeper bank.Keeper,
@@ -25,5 +27,7 @@ func Routes(tokenMapper store.Mapper, accKeeper auth.AccountKeeper, keeper bank.
 	routes[swap.AtomicSwapRoute] = swap.NewHandler(swapKeeper)
 	routes[seturi.SetURIRoute] = seturi.NewHandler(tokenMapper)
 	routes[ownership.Route] = ownership.NewHandler(tokenMapper, keeper)
+	routes[transfermemo.Route] = transfermemo.NewHandler(tokenMapper)
+	routes[transferfee.Route] = transferfee.NewHandler(tokenMapper)
 	return routes
 }
@@ -41,6 +41,14 @@ type Mapper interface {
 	UpdateBind(ctx sdk.Context, symbol string, contractAddress string, decimals int8) error
 	UpdateMiniTokenURI(ctx sdk.Context, symbol string, uri string) error
 	UpdateOwner(ctx sdk.Context, symbol string, newOwner sdk.AccAddress) error
+	SetTransferMemoRequired(ctx sdk.Context, symbol string, required bool) error
+	IsTransferMemoRequired(ctx sdk.Context, symbol string) bool
+	SetTransferFeeRate(ctx sdk.Context, symbol string, rate int64) error
+	GetTransferFeeRate(ctx sdk.Context, symbol string) int64
+	SetTradingDisabled(ctx sdk.Context, symbol string, disabled bool, proposalId int64) error
+	IsTradingDisabled(ctx sdk.Context, symbol string) bool
+	GetParams(ctx sdk.Context) TokenParams
+	SetParams(ctx sdk.Context, params TokenParams)
 }
 
 var _ Mapper = mapper{}
@@ -77,7 +85,7 @@ func (m mapper) GetToken(ctx sdk.Context, symbol string) (types.IToken, error) {
 func (m mapper) GetTokenList(ctx sdk.Context, showZeroSupplyTokens bool, isMini bool) ITokens {
 	var res ITokens
 	store := ctx.KVStore(m.key)
-	iter := store.Iterator(nil, nil)
+	iter := store.Iterator(sdk.PrefixEndBytes(tokenMetaKeyPrefix), nil)
 	defer iter.Close()
 	for ; iter.Valid(); iter.Next() {
 		isValid := isMini == bytes.HasPrefix(iter.Key(), []byte(miniTokenKeyPrefix))
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const transferMemoRequiredKeyPrefix = "transferMemoRequired:"
+
+// TokenMeta is the settings of a token kept apart from the token itself.
+type TokenMeta struct {
+	Symbol               string `json:"symbol"`
+	TransferMemoRequired bool   `json:"transfer_memo_required"`
+	TransferFeeRate      int64  `json:"transfer_fee_rate"`
+	TradingDisabled      bool   `json:"trading_disabled"`
+}
+
+// SetTransferMemoRequired sets whether the transfers of the token require a memo.
+func (m mapper) SetTransferMemoRequired(ctx sdk.Context, symbol string, required bool) error {
+	if len(symbol) == 0 {
+		return errors.New("symbol cannot be empty")
+	}
+	symbol = strings.ToUpper(symbol)
+	if _, err := m.GetToken(ctx, symbol); err != nil {
+		return errors.New("token does not exist")
+	}
+
+	store := ctx.KVStore(m.key)
+	key := calcTokenMetaKey(transferMemoRequiredKeyPrefix, symbol)
+	if required {
+		store.Set(key, []byte{1})
+	} else {
+		store.Delete(key)
+	}
+	return nil
+}
+
+func (m mapper) IsTransferMemoRequired(ctx sdk.Context, symbol string) bool {
+	return ctx.KVStore(m.key).Has(calcTokenMetaKey(transferMemoRequiredKeyPrefix, strings.ToUpper(symbol)))
+}
+
+// tokenMetaKeyPrefix is the prefix of all the token settings and the token params. It sorts before the symbols of
+// all the tokens and the mini token prefix, so that the tokens are iterated from its end without ever reading
+// them, and no symbol can collide with their keys.
+var tokenMetaKeyPrefix = []byte{0x00}
+
+func calcTokenMetaKey(kind string, name string) []byte {
+	var buf bytes.Buffer
+	buf.Write(tokenMetaKeyPrefix)
+	buf.WriteString(kind)
+	buf.WriteString(name)
+	return buf.Bytes()
+}
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramTypes "github.com/cosmos/cosmos-sdk/x/paramHub/types"
+
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
+)
+
+const (
+	// TokenParamsType is the param type of the TokenParams in the fee change proposals.
+	TokenParamsType = "token_params"
+
+	paramsKeyPrefix = "params:"
+
+	// DefaultSymbolCharset is the uppercase alphanumeric characters, which every token symbol is limited to
+	DefaultSymbolCharset = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+var (
+	paramsKey = calcTokenMetaKey(paramsKeyPrefix, "tokenParams")
+)
+
+// TokenParams are the token parameters under governance, the symbol ones only apply to the newly issued tokens.
+// Since the GovernedParams upgrade, they are carried as a whole by a fee change proposal of the param hub, e.g.
+//
+//	{"fee_params":[{"type":"tokens/TokenParams","value":{"max_symbol_length":"6",...}}],...}
+//
+// and replace the current ones at the end of the next breathe block, so the fields left out are reset to their
+// zero values.
+type TokenParams struct {
+	// MaxSymbolLength is the max length of the symbol of a new token, without the suffixes.
+	MaxSymbolLength int `json:"max_symbol_length"`
+	// SymbolCharset is the characters allowed in the symbol of a new token, a subset of DefaultSymbolCharset.
+	SymbolCharset string `json:"symbol_charset"`
+	// MaxAssetsPerAccount is the max number of distinct assets an account may hold, the transfers giving an account
+	// a new asset beyond it are rejected. 0 is unlimited. Only the bank transfers are capped, see
+	// transferfee.Keeper.
+	MaxAssetsPerAccount int `json:"max_assets_per_account"`
+}
+
+func DefaultTokenParams() TokenParams {
+	return TokenParams{
+		MaxSymbolLength: types.TokenSymbolMaxLen,
+		SymbolCharset:   DefaultSymbolCharset,
+	}
+}
+
+var _ paramTypes.FeeParam = (*TokenParams)(nil)
+
+// GetParamType implements the FeeParam of the param hub.
+func (p *TokenParams) GetParamType() string {
+	return TokenParamsType
+}
+
+// Check implements the FeeParam of the param hub, it's called when the fee change proposal is submitted and again
+// when it's applied.
+func (p *TokenParams) Check() error {
+	if !sdk.IsUpgrade(upgrade.GovernedParams) {
+		return fmt.Errorf("token params can not be changed before the %s upgrade", upgrade.GovernedParams)
+	}
+	return p.validate()
+}
+
+func (p TokenParams) validate() error {
+	if p.MaxSymbolLength < types.TokenSymbolNewMinLen || p.MaxSymbolLength > types.TokenSymbolMaxLen {
+		return fmt.Errorf("max_symbol_length should be in [%d, %d], got %d",
+			types.TokenSymbolNewMinLen, types.TokenSymbolMaxLen, p.MaxSymbolLength)
+	}
+	if len(p.SymbolCharset) == 0 {
+		return fmt.Errorf("symbol_charset should not be empty")
+	}
+	for _, c := range p.SymbolCharset {
+		if !strings.ContainsRune(DefaultSymbolCharset, c) {
+			return fmt.Errorf("symbol_charset should only contain the characters in %s, got %q", DefaultSymbolCharset, c)
+		}
+	}
+	if p.MaxAssetsPerAccount < 0 {
+		return fmt.Errorf("max_assets_per_account should not be negative, got %d", p.MaxAssetsPerAccount)
+	}
+	return nil
+}
+
+// ValidateSymbol checks the original symbol of a new token, i.e. without the tx hash suffix, against the params.
+func (p TokenParams) ValidateSymbol(symbol string) error {
+	symbol = strings.TrimSuffix(strings.ToUpper(symbol), types.TokenSymbolDotBSuffix)
+	if len(symbol) > p.MaxSymbolLength {
+		return fmt.Errorf("length of token symbol is limited to %d, got %d", p.MaxSymbolLength, len(symbol))
+	}
+	for _, c := range symbol {
+		if !strings.ContainsRune(p.SymbolCharset, c) {
+			return fmt.Errorf("token symbol should only contain the characters in %s, got %q", p.SymbolCharset, c)
+		}
+	}
+	return nil
+}
+
+func (m mapper) GetParams(ctx sdk.Context) TokenParams {
+	bz := ctx.KVStore(m.key).Get(paramsKey)
+	if bz == nil {
+		return DefaultTokenParams()
+	}
+	var params TokenParams
+	m.cdc.MustUnmarshalBinaryBare(bz, &params)
+	return params
+}
+
+func (m mapper) SetParams(ctx sdk.Context, params TokenParams) {
+	ctx.KVStore(m.key).Set(paramsKey, m.cdc.MustMarshalBinaryBare(params))
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	tokenTradingChangeKey = "token_trading"
+
+	tradingDisabledKeyPrefix = "tradingDisabled:"
+	tradingProposalKeyPrefix = "tradingProposal:"
+)
+
+// TokenTradingChange disables or enables the trading of a token on all the trading pairs it's the base or quote
+// asset of, e.g. a compromised token. It's carried by a text proposal whose description is a
+// TokenTradingChange in json, e.g. {"token_trading":{"symbol":"XYZ-000","disabled":true}}, and applied by a
+// SetTokenTradingMsg once the proposal passes. The new orders of the pairs are rejected while it's disabled, the
+// open orders can still be cancelled.
+type TokenTradingChange struct {
+	Symbol   string `json:"symbol"`
+	Disabled bool   `json:"disabled"`
+}
+
+func (c TokenTradingChange) Check() error {
+	if len(c.Symbol) == 0 {
+		return errors.New("symbol of the token is missing")
+	}
+	return nil
+}
+
+// GetTokenTradingChange returns the token trading change in the description of a text proposal,
+// ok is false if the proposal is not about the token trading.
+func GetTokenTradingChange(description string) (change TokenTradingChange, ok bool, err error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(description), &fields); err != nil {
+		return change, false, nil
+	}
+	raw, ok := fields[tokenTradingChangeKey]
+	if !ok {
+		return change, false, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&change); err != nil {
+		return change, true, fmt.Errorf("illegal token trading change %s, err=%s", string(raw), err.Error())
+	}
+	change.Symbol = strings.ToUpper(change.Symbol)
+	return change, true, change.Check()
+}
+
+// SetTradingDisabled disables or enables the trading of the token, per the passed token trading change proposal.
+// The proposals of a token are applied in the order of their ids, so an older proposal can't be replayed to
+// revert a newer one.
+func (m mapper) SetTradingDisabled(ctx sdk.Context, symbol string, disabled bool, proposalId int64) error {
+	if len(symbol) == 0 {
+		return errors.New("symbol cannot be empty")
+	}
+	symbol = strings.ToUpper(symbol)
+	if _, err := m.GetToken(ctx, symbol); err != nil {
+		return errors.New("token does not exist")
+	}
+
+	store := ctx.KVStore(m.key)
+	proposalKey := calcTokenMetaKey(tradingProposalKeyPrefix, symbol)
+	if bz := store.Get(proposalKey); bz != nil {
+		var last int64
+		m.cdc.MustUnmarshalBinaryBare(bz, &last)
+		if proposalId <= last {
+			return fmt.Errorf("the trading of %s has been changed by proposal %d", symbol, last)
+		}
+	}
+	key := calcTokenMetaKey(tradingDisabledKeyPrefix, symbol)
+	if disabled {
+		store.Set(key, []byte{1})
+	} else {
+		store.Delete(key)
+	}
+	store.Set(proposalKey, m.cdc.MustMarshalBinaryBare(proposalId))
+	return nil
+}
+
+func (m mapper) IsTradingDisabled(ctx sdk.Context, symbol string) bool {
+	return ctx.KVStore(m.key).Has(calcTokenMetaKey(tradingDisabledKeyPrefix, strings.ToUpper(symbol)))
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	transferFeeRateKeyPrefix = "transferFeeRate:"
+
+	// TransferFeeRateBase is the denominator of the transfer fee rates, i.e. the rates are in bps
+	TransferFeeRateBase = 10000
+	// MaxTransferFeeRate is the max transfer fee rate of a token
+	MaxTransferFeeRate = 1000
+)
+
+// SetTransferFeeRate sets the rate of the fee charged on the transfers of the token, 0 removes the fee.
+func (m mapper) SetTransferFeeRate(ctx sdk.Context, symbol string, rate int64) error {
+	if len(symbol) == 0 {
+		return errors.New("symbol cannot be empty")
+	}
+	if rate < 0 || rate > MaxTransferFeeRate {
+		return fmt.Errorf("transfer fee rate should be in [0, %d], got %d", MaxTransferFeeRate, rate)
+	}
+	symbol = strings.ToUpper(symbol)
+	if _, err := m.GetToken(ctx, symbol); err != nil {
+		return errors.New("token does not exist")
+	}
+
+	store := ctx.KVStore(m.key)
+	key := calcTokenMetaKey(transferFeeRateKeyPrefix, symbol)
+	if rate == 0 {
+		store.Delete(key)
+	} else {
+		store.Set(key, m.cdc.MustMarshalBinaryBare(rate))
+	}
+	return nil
+}
+
+// GetTransferFeeRate returns the rate of the fee charged on the transfers of the token, in 1/TransferFeeRateBase.
+func (m mapper) GetTransferFeeRate(ctx sdk.Context, symbol string) int64 {
+	bz := ctx.KVStore(m.key).Get(calcTokenMetaKey(transferFeeRateKeyPrefix, strings.ToUpper(symbol)))
+	if bz == nil {
+		return 0
+	}
+	var rate int64
+	m.cdc.MustUnmarshalBinaryBare(bz, &rate)
+	return rate
+}
@@ -0,0 +1,18 @@
+package transferfee
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	DefaultCodespace sdk.CodespaceType = 9
+
+	CodeTooManyAssets sdk.CodeType = 1
+)
+
+func ErrTooManyAssets(addr sdk.AccAddress, maxAssets int) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeTooManyAssets,
+		fmt.Sprintf("account %s would hold more than %d distinct assets", addr, maxAssets))
+}
@@ -0,0 +1,142 @@
+package transferfee
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/fees"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	"github.com/bnb-chain/node/common/audit"
+	bncfees "github.com/bnb-chain/node/common/fees"
+	"github.com/bnb-chain/node/common/log"
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/tokens/store"
+)
+
+func NewHandler(tokenMapper store.Mapper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case SetTransferFeeMsg:
+			return handleSetTransferFee(ctx, tokenMapper, msg)
+		default:
+			errMsg := "Unrecognized msg type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleSetTransferFee(ctx sdk.Context, tokenMapper store.Mapper, msg SetTransferFeeMsg) sdk.Result {
+	symbol := strings.ToUpper(msg.Symbol)
+	logger := log.With("module", "token", "symbol", symbol, "from", msg.From, "rate", msg.Rate)
+
+	token, err := tokenMapper.GetToken(ctx, symbol)
+	if err != nil {
+		logger.Info("set transfer fee failed", "reason", "invalid token symbol")
+		return sdk.ErrInvalidCoins(err.Error()).Result()
+	}
+
+	if !token.IsOwner(msg.From) {
+		logger.Info("set transfer fee failed", "reason", "not token's owner")
+		return sdk.ErrUnauthorized("only the owner of the token can set the fee on its transfers").Result()
+	}
+
+	err = tokenMapper.SetTransferFeeRate(ctx, symbol, msg.Rate)
+	if err != nil {
+		logger.Error("set transfer fee failed", "reason", "update token failed: "+err.Error())
+		return sdk.ErrInternal(err.Error()).Result()
+	}
+
+	logger.Info("finished setting transfer fee")
+	return sdk.Result{}
+}
+
+// NewBankHandler wraps the bank handler to charge the transfer fees of the tokens: after a transfer, the fee
+// of each output is deducted from the amount received and added to the fees of the tx in the fee pool, so that
+// it's collected and distributed along with the tx fees of the block.
+// The transfers are made by the Keeper capping the distinct assets of the recipients.
+func NewBankHandler(bankKeeper bank.Keeper, tokenMapper store.Mapper) sdk.Handler {
+	bankHandler := bank.NewHandler(NewKeeper(bankKeeper, tokenMapper))
+	handler := func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		result := bankHandler(ctx, msg)
+		sendMsg, ok := msg.(bank.MsgSend)
+		if !ok || !result.IsOK() || !sdk.IsUpgrade(upgrade.TokenTransferFee) {
+			return result
+		}
+
+		var charged sdk.Coins
+		for _, out := range sendMsg.Outputs {
+			fee := calcTransferFee(ctx, tokenMapper, out.Coins)
+			if fee.IsZero() {
+				continue
+			}
+			_, tags, err := bankKeeper.SubtractCoins(ctx, out.Address, fee)
+			if err != nil {
+				return err.Result()
+			}
+			result.Tags = result.Tags.AppendTags(tags)
+			charged = charged.Plus(fee)
+			if ctx.IsDeliverTx() {
+				bncfees.Tracker.Add(txHashOf(ctx), out.Address, fee)
+			}
+		}
+		if !charged.IsZero() && ctx.IsDeliverTx() {
+			addTransferFeeToPool(ctx, charged)
+		}
+		return result
+	}
+	// the rejected transfers are recorded in the audit log
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		result := handler(ctx, msg)
+		if !result.IsOK() {
+			var sender sdk.AccAddress
+			if signers := msg.GetSigners(); len(signers) > 0 {
+				sender = signers[0]
+			}
+			audit.Rejections.Record(ctx, sender, msg, result)
+		}
+		return result
+	}
+}
+
+// addTransferFeeToPool adds the transfer fees to the fees of the tx in the fee pool, which the tx fee is already
+// put in by the ante handler. They are committed only if the tx succeeds, and distributed at the end of the block.
+func addTransferFeeToPool(ctx sdk.Context, transferFee sdk.Coins) {
+	txHash := txHashOf(ctx)
+	var fee sdk.Fee
+	if txFee := fees.Pool.GetFee(txHash); txFee != nil {
+		fee = *txFee
+	}
+	fee.AddFee(sdk.NewFee(transferFee, sdk.FeeForProposer))
+	fees.Pool.AddFee(txHash, fee)
+}
+
+func txHashOf(ctx sdk.Context) string {
+	txHash, ok := ctx.Value(baseapp.TxHashKey).(string)
+	if !ok {
+		panic("cannot get txHash from ctx")
+	}
+	return txHash
+}
+
+// calcTransferFee returns the fees charged on the coins transferred, the fees are rounded down.
+func calcTransferFee(ctx sdk.Context, tokenMapper store.Mapper, coins sdk.Coins) sdk.Coins {
+	var fee sdk.Coins
+	for _, coin := range coins {
+		rate := tokenMapper.GetTransferFeeRate(ctx, coin.Denom)
+		if rate == 0 {
+			continue
+		}
+		var amount big.Int
+		amount.Mul(big.NewInt(coin.Amount), big.NewInt(rate))
+		amount.Quo(&amount, big.NewInt(store.TransferFeeRateBase))
+		if amount.Int64() == 0 {
+			continue
+		}
+		fee = append(fee, sdk.NewCoin(coin.Denom, amount.Int64()))
+	}
+	return fee
+}
@@ -0,0 +1,215 @@
+package transferfee
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/fees"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	bankclient "github.com/cosmos/cosmos-sdk/x/bank/client"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/tokens/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+func setup() (sdk.Context, sdk.Handler, sdk.Handler, auth.AccountKeeper, store.Mapper) {
+	ms, capKey1, capKey2 := testutils.SetupMultiStoreForUnitTest()
+	cdc := wire.NewCodec()
+	cdc.RegisterInterface((*types.IToken)(nil), nil)
+	cdc.RegisterConcrete(&types.Token{}, "bnbchain/Token", nil)
+	cdc.RegisterConcrete(&types.MiniToken{}, "bnbchain/MiniToken", nil)
+	tokenMapper := store.NewMapper(cdc, capKey1)
+	accountKeeper := auth.NewAccountKeeper(cdc, capKey2, types.ProtoAppAccount)
+	handler := NewHandler(tokenMapper)
+	bankHandler := NewBankHandler(bank.NewBaseKeeper(accountKeeper), tokenMapper)
+
+	accountStore := ms.GetKVStore(capKey2)
+	accountStoreCache := auth.NewAccountStoreCache(cdc, accountStore, 10)
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid", Height: 1},
+		sdk.RunTxModeDeliver, log.NewNopLogger()).
+		WithAccountCache(auth.NewAccountCache(accountStoreCache)).WithValue(baseapp.TxHashKey, "000")
+	return ctx, handler, bankHandler, accountKeeper, tokenMapper
+}
+
+func TestHandleSetTransferFee(t *testing.T) {
+	ctx, handler, _, accountKeeper, tokenMapper := setup()
+	_, owner := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	_, acc := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	token, err := types.NewToken("New BNB", "NNB-000", 10000e8, owner.GetAddress(), false)
+	require.NoError(t, err)
+	require.NoError(t, tokenMapper.NewToken(ctx, token))
+
+	// out of bounds
+	require.Error(t, NewSetTransferFeeMsg(owner.GetAddress(), "NNB-000", -1).ValidateBasic())
+	require.Error(t, NewSetTransferFeeMsg(owner.GetAddress(), "NNB-000", store.MaxTransferFeeRate+1).ValidateBasic())
+	require.Error(t, NewSetTransferFeeMsg(owner.GetAddress(), types.NativeTokenSymbol, 10).ValidateBasic())
+	require.NoError(t, NewSetTransferFeeMsg(owner.GetAddress(), "NNB-000", store.MaxTransferFeeRate).ValidateBasic())
+	require.Error(t, tokenMapper.SetTransferFeeRate(ctx, "NNB-000", store.MaxTransferFeeRate+1))
+
+	// not existing token
+	result := handler(ctx, NewSetTransferFeeMsg(owner.GetAddress(), "NNB-001", 10))
+	require.False(t, result.IsOK())
+
+	// not the owner
+	result = handler(ctx, NewSetTransferFeeMsg(acc.GetAddress(), "NNB-000", 10))
+	require.False(t, result.IsOK())
+	require.Equal(t, int64(0), tokenMapper.GetTransferFeeRate(ctx, "NNB-000"))
+
+	result = handler(ctx, NewSetTransferFeeMsg(owner.GetAddress(), "nnb-000", 10))
+	require.True(t, result.IsOK())
+	require.Equal(t, int64(10), tokenMapper.GetTransferFeeRate(ctx, "NNB-000"))
+	// the rate is not listed as a token
+	require.Len(t, tokenMapper.GetTokenList(ctx, true, false), 1)
+
+	result = handler(ctx, NewSetTransferFeeMsg(owner.GetAddress(), "NNB-000", 0))
+	require.True(t, result.IsOK())
+	require.Equal(t, int64(0), tokenMapper.GetTransferFeeRate(ctx, "NNB-000"))
+}
+
+func TestTransferFee(t *testing.T) {
+	ctx, handler, bankHandler, accountKeeper, tokenMapper := setup()
+	_, owner := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	_, acc := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	token, err := types.NewToken("New BNB", "NNB-000", 10000e8, owner.GetAddress(), false)
+	require.NoError(t, err)
+	require.NoError(t, tokenMapper.NewToken(ctx, token))
+	_ = owner.SetCoins(owner.GetCoins().Plus(sdk.Coins{sdk.NewCoin("NNB-000", 10000e8)}))
+	accountKeeper.SetAccount(ctx, owner)
+	// 1%
+	require.True(t, handler(ctx, NewSetTransferFeeMsg(owner.GetAddress(), "NNB-000", 100)).IsOK())
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.TokenTransferFee, 10)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+		fees.Pool.Clear()
+	}()
+	feeMsg := bankclient.CreateMsg(owner.GetAddress(), acc.GetAddress(), sdk.Coins{sdk.NewCoin("NNB-000", 1e8)})
+	nativeMsg := bankclient.CreateMsg(owner.GetAddress(), acc.GetAddress(), testutils.NewNativeTokens(1e8))
+
+	// before the upgrade
+	upgrade.Mgr.SetHeight(5)
+	require.True(t, bankHandler(ctx, feeMsg).IsOK())
+	require.Equal(t, int64(1e8), accountKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("NNB-000"))
+	require.Nil(t, fees.Pool.GetFee("000"))
+
+	// the fee is added to the tx fee already in the pool, and goes nowhere until the block's fees are distributed
+	fees.Pool.AddFee("000", sdk.NewFee(testutils.NewNativeTokens(1e4), sdk.FeeForProposer))
+	upgrade.Mgr.SetHeight(11)
+	require.True(t, bankHandler(ctx, feeMsg).IsOK())
+	// the recipient receives the amount net of the fee
+	require.Equal(t, int64(2e8-1e6), accountKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("NNB-000"))
+	require.Equal(t, int64(10000e8-2e8), accountKeeper.GetAccount(ctx, owner.GetAddress()).GetCoins().AmountOf("NNB-000"))
+	require.Nil(t, accountKeeper.GetAccount(ctx, stake.FeeCollectorAddr))
+	fee := fees.Pool.GetFee("000")
+	require.Equal(t, int64(1e6), fee.Tokens.AmountOf("NNB-000"))
+	require.Equal(t, int64(1e4), fee.Tokens.AmountOf(types.NativeTokenSymbol))
+
+	// no fee on the native token
+	require.True(t, bankHandler(ctx, nativeMsg).IsOK())
+	require.Equal(t, int64(101e8), accountKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf(types.NativeTokenSymbol))
+
+	// the fee is rounded down
+	dustMsg := bankclient.CreateMsg(owner.GetAddress(), acc.GetAddress(), sdk.Coins{sdk.NewCoin("NNB-000", 99)})
+	require.True(t, bankHandler(ctx, dustMsg).IsOK())
+	require.Equal(t, int64(2e8-1e6+99), accountKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("NNB-000"))
+	require.Equal(t, int64(1e6), fees.Pool.GetFee("000").Tokens.AmountOf("NNB-000"))
+}
+
+func TestMaxAssetsPerAccount(t *testing.T) {
+	ctx, _, bankHandler, accountKeeper, tokenMapper := setup()
+	_, owner := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	_, acc := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	_ = owner.SetCoins(owner.GetCoins().Plus(sdk.Coins{sdk.NewCoin("AAA-000", 100e8), sdk.NewCoin("BBB-000", 100e8)}))
+	accountKeeper.SetAccount(ctx, owner)
+	aaaMsg := bankclient.CreateMsg(owner.GetAddress(), acc.GetAddress(), sdk.Coins{sdk.NewCoin("AAA-000", 1e8)})
+	bbbMsg := bankclient.CreateMsg(owner.GetAddress(), acc.GetAddress(), sdk.Coins{sdk.NewCoin("BBB-000", 1e8)})
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.GovernedParams, -1)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+	}()
+	params := tokenMapper.GetParams(ctx)
+	params.MaxAssetsPerAccount = -1
+	require.Error(t, params.Check())
+	// the recipient holds BNB only, so it can get one more asset
+	params.MaxAssetsPerAccount = 2
+	require.NoError(t, params.Check())
+	tokenMapper.SetParams(ctx, params)
+
+	require.True(t, bankHandler(ctx, aaaMsg).IsOK())
+	result := bankHandler(ctx, bbbMsg)
+	require.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeTooManyAssets), result.Code, result.Log)
+	require.Equal(t, int64(0), accountKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("BBB-000"))
+	// the assets held can still be received
+	require.True(t, bankHandler(ctx, aaaMsg).IsOK())
+	require.Equal(t, int64(2e8), accountKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("AAA-000"))
+	// only the bank transfers are capped, the coins credited by the other modules are not
+	_, _, err := bank.NewBaseKeeper(accountKeeper).AddCoins(ctx, acc.GetAddress(), sdk.Coins{sdk.NewCoin("CCC-000", 1e8)})
+	require.NoError(t, err)
+	require.Equal(t, int64(1e8), accountKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("CCC-000"))
+	result = bankHandler(ctx, bbbMsg)
+	require.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeTooManyAssets), result.Code, result.Log)
+
+	// unlimited
+	params.MaxAssetsPerAccount = 0
+	tokenMapper.SetParams(ctx, params)
+	require.True(t, bankHandler(ctx, bbbMsg).IsOK())
+	require.Equal(t, int64(1e8), accountKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("BBB-000"))
+}
+
+func TestKeeper_MaxAssetsPerAccount(t *testing.T) {
+	ctx, _, _, accountKeeper, tokenMapper := setup()
+	keeper := NewKeeper(bank.NewBaseKeeper(accountKeeper), tokenMapper)
+	_, owner := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	_, acc := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	_ = owner.SetCoins(owner.GetCoins().Plus(sdk.Coins{sdk.NewCoin("AAA-000", 100e8), sdk.NewCoin("BBB-000", 100e8)}))
+	accountKeeper.SetAccount(ctx, owner)
+	// the locked and frozen assets are held as well
+	acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{sdk.NewCoin("CCC-000", 1e8)})
+	acc.(types.NamedAccount).SetFrozenCoins(sdk.Coins{sdk.NewCoin("DDD-000", 1e8)})
+	accountKeeper.SetAccount(ctx, acc)
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.GovernedParams, -1)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+	}()
+	params := tokenMapper.GetParams(ctx)
+	params.MaxAssetsPerAccount = 3
+	tokenMapper.SetParams(ctx, params)
+
+	aaa := sdk.Coins{sdk.NewCoin("AAA-000", 1e8)}
+	_, err := keeper.SendCoins(ctx, owner.GetAddress(), acc.GetAddress(), aaa)
+	require.Equal(t, CodeTooManyAssets, err.Code())
+	_, _, err = keeper.AddCoins(ctx, acc.GetAddress(), aaa)
+	require.Equal(t, CodeTooManyAssets, err.Code())
+	require.Equal(t, int64(0), accountKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("AAA-000"))
+	// the assets held can still be received, even if only locked or frozen
+	_, err = keeper.SendCoins(ctx, owner.GetAddress(), acc.GetAddress(), testutils.NewNativeTokens(1e8))
+	require.NoError(t, err)
+	_, _, err = keeper.AddCoins(ctx, acc.GetAddress(), sdk.Coins{sdk.NewCoin("CCC-000", 1e8)})
+	require.NoError(t, err)
+
+	// all the outputs of a recipient are counted together
+	params.MaxAssetsPerAccount = 4
+	tokenMapper.SetParams(ctx, params)
+	bbb := sdk.Coins{sdk.NewCoin("BBB-000", 1e8)}
+	inputs := []bank.Input{bank.NewInput(owner.GetAddress(), aaa.Plus(bbb))}
+	outputs := []bank.Output{bank.NewOutput(acc.GetAddress(), aaa), bank.NewOutput(acc.GetAddress(), bbb)}
+	_, err = keeper.InputOutputCoins(ctx, inputs, outputs)
+	require.Equal(t, CodeTooManyAssets, err.Code())
+	inputs = []bank.Input{bank.NewInput(owner.GetAddress(), aaa)}
+	_, err = keeper.InputOutputCoins(ctx, inputs, outputs[:1])
+	require.NoError(t, err)
+	require.Equal(t, int64(1e8), accountKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("AAA-000"))
+}
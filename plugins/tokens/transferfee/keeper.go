@@ -0,0 +1,92 @@
+package transferfee
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/tokens/store"
+)
+
+var _ bank.Keeper = Keeper{}
+
+// Keeper wraps the bank keeper to cap the number of distinct assets an account may hold at MaxAssetsPerAccount of
+// the token params. The coins received by an account are rejected if they bring it a new asset while it already
+// holds the max number of assets or more, counting its free, frozen and locked balances. The accounts holding more
+// assets than the max, e.g. after it's lowered, can still receive the assets they hold.
+// It's only used by the bank handler, so only the transfers, by which anyone can spam any account with dust assets,
+// are capped. The coins credited by the other modules, e.g. the trades, the swaps, the timelock unlocks and the cross
+// chain transfers, are not.
+type Keeper struct {
+	bank.Keeper
+	tokenMapper store.Mapper
+}
+
+func NewKeeper(bankKeeper bank.Keeper, tokenMapper store.Mapper) Keeper {
+	return Keeper{
+		Keeper:      bankKeeper,
+		tokenMapper: tokenMapper,
+	}
+}
+
+func (k Keeper) AddCoins(ctx sdk.Context, addr sdk.AccAddress, amt sdk.Coins) (sdk.Coins, sdk.Tags, sdk.Error) {
+	if err := k.checkMaxAssets(ctx, []bank.Output{bank.NewOutput(addr, amt)}); err != nil {
+		return nil, nil, err
+	}
+	return k.Keeper.AddCoins(ctx, addr, amt)
+}
+
+func (k Keeper) SendCoins(ctx sdk.Context, fromAddr sdk.AccAddress, toAddr sdk.AccAddress, amt sdk.Coins) (sdk.Tags, sdk.Error) {
+	if err := k.checkMaxAssets(ctx, []bank.Output{bank.NewOutput(toAddr, amt)}); err != nil {
+		return nil, err
+	}
+	return k.Keeper.SendCoins(ctx, fromAddr, toAddr, amt)
+}
+
+func (k Keeper) InputOutputCoins(ctx sdk.Context, inputs []bank.Input, outputs []bank.Output) (sdk.Tags, sdk.Error) {
+	if err := k.checkMaxAssets(ctx, outputs); err != nil {
+		return nil, err
+	}
+	return k.Keeper.InputOutputCoins(ctx, inputs, outputs)
+}
+
+// checkMaxAssets checks the assets of all the outputs of a recipient at once, as they are received together.
+func (k Keeper) checkMaxAssets(ctx sdk.Context, outputs []bank.Output) sdk.Error {
+	maxAssets := k.tokenMapper.GetParams(ctx).MaxAssetsPerAccount
+	if maxAssets <= 0 {
+		return nil
+	}
+	received := make(map[string]map[string]struct{})
+	var recipients []sdk.AccAddress
+	for _, out := range outputs {
+		denoms, ok := received[string(out.Address)]
+		if !ok {
+			denoms = make(map[string]struct{})
+			received[string(out.Address)] = denoms
+			recipients = append(recipients, out.Address)
+		}
+		for _, coin := range out.Coins {
+			if coin.IsPositive() {
+				denoms[coin.Denom] = struct{}{}
+			}
+		}
+	}
+	for _, addr := range recipients {
+		held := make(map[string]struct{})
+		if acc := k.GetAccountKeeper().GetAccount(ctx, addr); acc != nil {
+			for _, denom := range types.GetAssets(acc) {
+				held[denom] = struct{}{}
+			}
+		}
+		newAssets := 0
+		for denom := range received[string(addr)] {
+			if _, ok := held[denom]; !ok {
+				newAssets++
+			}
+		}
+		if newAssets > 0 && len(held)+newAssets > maxAssets {
+			return ErrTooManyAssets(addr, maxAssets)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package transferfee
+
+import (
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/tokens/store"
+)
+
+const (
+	Route                 = "tokensTransferFee"
+	SetTransferFeeMsgType = "setTransferFee"
+)
+
+var _ sdk.Msg = SetTransferFeeMsg{}
+
+// SetTransferFeeMsg sets the rate of the fee charged on the transfers of a token. The fee is deducted from
+// the amounts received and distributed along with the tx fees, the rate is in 1/store.TransferFeeRateBase of the
+// amounts.
+type SetTransferFeeMsg struct {
+	From   sdk.AccAddress `json:"from"`
+	Symbol string         `json:"symbol"`
+	Rate   int64          `json:"rate"`
+}
+
+func NewSetTransferFeeMsg(from sdk.AccAddress, symbol string, rate int64) SetTransferFeeMsg {
+	return SetTransferFeeMsg{
+		From:   from,
+		Symbol: symbol,
+		Rate:   rate,
+	}
+}
+
+func (msg SetTransferFeeMsg) Route() string { return Route }
+func (msg SetTransferFeeMsg) Type() string  { return SetTransferFeeMsgType }
+func (msg SetTransferFeeMsg) String() string {
+	return fmt.Sprintf("SetTransferFeeMsg{%#v}", msg)
+}
+
+func (msg SetTransferFeeMsg) ValidateBasic() sdk.Error {
+	if len(msg.From) != sdk.AddrLen {
+		return sdk.ErrInvalidAddress(fmt.Sprintf("Invalid from address, expected address length is %d, actual length is %d", sdk.AddrLen, len(msg.From)))
+	}
+
+	if !types.IsValidMiniTokenSymbol(msg.Symbol) {
+		err := types.ValidateTokenSymbol(msg.Symbol)
+		if err != nil {
+			return sdk.ErrInvalidCoins(err.Error())
+		}
+	}
+	if msg.Symbol == types.NativeTokenSymbol {
+		return sdk.ErrInvalidCoins("the transfers of the native token cannot be charged a fee")
+	}
+
+	if msg.Rate < 0 || msg.Rate > store.MaxTransferFeeRate {
+		return sdk.ErrInvalidCoins(fmt.Sprintf("transfer fee rate should be in [0, %d], got %d", store.MaxTransferFeeRate, msg.Rate))
+	}
+	return nil
+}
+
+func (msg SetTransferFeeMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (msg SetTransferFeeMsg) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.From}
+}
+
+func (msg SetTransferFeeMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return msg.GetSigners()
+}
@@ -0,0 +1,48 @@
+package transfermemo
+
+import (
+	"reflect"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/log"
+	"github.com/bnb-chain/node/plugins/tokens/store"
+)
+
+func NewHandler(tokenMapper store.Mapper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case SetTransferMemoRequiredMsg:
+			return handleSetTransferMemoRequired(ctx, tokenMapper, msg)
+		default:
+			errMsg := "Unrecognized msg type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleSetTransferMemoRequired(ctx sdk.Context, tokenMapper store.Mapper, msg SetTransferMemoRequiredMsg) sdk.Result {
+	symbol := strings.ToUpper(msg.Symbol)
+	logger := log.With("module", "token", "symbol", symbol, "from", msg.From, "required", msg.Required)
+
+	token, err := tokenMapper.GetToken(ctx, symbol)
+	if err != nil {
+		logger.Info("set transfer memo required failed", "reason", "invalid token symbol")
+		return sdk.ErrInvalidCoins(err.Error()).Result()
+	}
+
+	if !token.IsOwner(msg.From) {
+		logger.Info("set transfer memo required failed", "reason", "not token's owner")
+		return sdk.ErrUnauthorized("only the owner of the token can set whether its transfers require a memo").Result()
+	}
+
+	err = tokenMapper.SetTransferMemoRequired(ctx, symbol, msg.Required)
+	if err != nil {
+		logger.Error("set transfer memo required failed", "reason", "update token failed: "+err.Error())
+		return sdk.ErrInternal(err.Error()).Result()
+	}
+
+	logger.Info("finished setting transfer memo required")
+	return sdk.Result{}
+}
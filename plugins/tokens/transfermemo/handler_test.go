@@ -0,0 +1,99 @@
+package transfermemo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	bankclient "github.com/cosmos/cosmos-sdk/x/bank/client"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/tokens/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+func setup() (sdk.Context, sdk.Handler, auth.AccountKeeper, store.Mapper) {
+	ms, capKey1, capKey2 := testutils.SetupMultiStoreForUnitTest()
+	cdc := wire.NewCodec()
+	cdc.RegisterInterface((*types.IToken)(nil), nil)
+	cdc.RegisterConcrete(&types.Token{}, "bnbchain/Token", nil)
+	cdc.RegisterConcrete(&types.MiniToken{}, "bnbchain/MiniToken", nil)
+	tokenMapper := store.NewMapper(cdc, capKey1)
+	accountKeeper := auth.NewAccountKeeper(cdc, capKey2, types.ProtoAppAccount)
+	handler := NewHandler(tokenMapper)
+
+	accountStore := ms.GetKVStore(capKey2)
+	accountStoreCache := auth.NewAccountStoreCache(cdc, accountStore, 10)
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "mychainid", Height: 1},
+		sdk.RunTxModeDeliver, log.NewNopLogger()).
+		WithAccountCache(auth.NewAccountCache(accountStoreCache))
+	return ctx, handler, accountKeeper, tokenMapper
+}
+
+func TestHandleSetTransferMemoRequired(t *testing.T) {
+	ctx, handler, accountKeeper, tokenMapper := setup()
+	_, owner := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	_, acc := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	token, err := types.NewToken("New BNB", "NNB-000", 10000e8, owner.GetAddress(), false)
+	require.NoError(t, err)
+	require.NoError(t, tokenMapper.NewToken(ctx, token))
+
+	// not existing token
+	result := handler(ctx, NewSetTransferMemoRequiredMsg(owner.GetAddress(), "NNB-001", true))
+	require.False(t, result.IsOK())
+
+	// not the owner
+	result = handler(ctx, NewSetTransferMemoRequiredMsg(acc.GetAddress(), "NNB-000", true))
+	require.False(t, result.IsOK())
+	require.False(t, tokenMapper.IsTransferMemoRequired(ctx, "NNB-000"))
+
+	result = handler(ctx, NewSetTransferMemoRequiredMsg(owner.GetAddress(), "nnb-000", true))
+	require.True(t, result.IsOK())
+	require.True(t, tokenMapper.IsTransferMemoRequired(ctx, "NNB-000"))
+	// the flag is not listed as a token
+	require.Len(t, tokenMapper.GetTokenList(ctx, true, false), 1)
+
+	result = handler(ctx, NewSetTransferMemoRequiredMsg(owner.GetAddress(), "NNB-000", false))
+	require.True(t, result.IsOK())
+	require.False(t, tokenMapper.IsTransferMemoRequired(ctx, "NNB-000"))
+}
+
+func TestTransferMemoScript(t *testing.T) {
+	ctx, handler, accountKeeper, tokenMapper := setup()
+	_, owner := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	_, acc := testutils.NewAccount(ctx, accountKeeper, 100e8)
+	token, err := types.NewToken("New BNB", "NNB-000", 10000e8, owner.GetAddress(), false)
+	require.NoError(t, err)
+	require.NoError(t, tokenMapper.NewToken(ctx, token))
+	require.True(t, handler(ctx, NewSetTransferMemoRequiredMsg(owner.GetAddress(), "NNB-000", true)).IsOK())
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.TokenTransferMemo, 10)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+	}()
+	script := generateTransferMemoCheckScript(tokenMapper)
+	memoRequiredMsg := bankclient.CreateMsg(owner.GetAddress(), acc.GetAddress(), sdk.Coins{sdk.NewCoin("NNB-000", 1e8)})
+	nativeMsg := bankclient.CreateMsg(owner.GetAddress(), acc.GetAddress(), testutils.NewNativeTokens(1e8))
+
+	// before the upgrade
+	upgrade.Mgr.SetHeight(5)
+	ctx = ctx.WithTx(auth.StdTx{Memo: "", Msgs: []sdk.Msg{memoRequiredMsg}})
+	require.NoError(t, script(ctx, memoRequiredMsg))
+
+	upgrade.Mgr.SetHeight(11)
+	// without memo
+	require.Error(t, script(ctx, memoRequiredMsg))
+	ctx = ctx.WithTx(auth.StdTx{Memo: "", Msgs: []sdk.Msg{nativeMsg}})
+	require.NoError(t, script(ctx, nativeMsg))
+
+	// with memo
+	ctx = ctx.WithTx(auth.StdTx{Memo: "deposit 123", Msgs: []sdk.Msg{memoRequiredMsg}})
+	require.NoError(t, script(ctx, memoRequiredMsg))
+}
@@ -0,0 +1,69 @@
+package transfermemo
+
+import (
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+)
+
+const (
+	Route                          = "tokensTransferMemo"
+	SetTransferMemoRequiredMsgType = "setTransferMemoRequired"
+)
+
+var _ sdk.Msg = SetTransferMemoRequiredMsg{}
+
+// SetTransferMemoRequiredMsg sets whether the transfers of a token require a memo, e.g. for the routing
+// of the deposits of the token to a custodial exchange.
+type SetTransferMemoRequiredMsg struct {
+	From     sdk.AccAddress `json:"from"`
+	Symbol   string         `json:"symbol"`
+	Required bool           `json:"required"`
+}
+
+func NewSetTransferMemoRequiredMsg(from sdk.AccAddress, symbol string, required bool) SetTransferMemoRequiredMsg {
+	return SetTransferMemoRequiredMsg{
+		From:     from,
+		Symbol:   symbol,
+		Required: required,
+	}
+}
+
+func (msg SetTransferMemoRequiredMsg) Route() string { return Route }
+func (msg SetTransferMemoRequiredMsg) Type() string  { return SetTransferMemoRequiredMsgType }
+func (msg SetTransferMemoRequiredMsg) String() string {
+	return fmt.Sprintf("SetTransferMemoRequiredMsg{%#v}", msg)
+}
+
+func (msg SetTransferMemoRequiredMsg) ValidateBasic() sdk.Error {
+	if len(msg.From) != sdk.AddrLen {
+		return sdk.ErrInvalidAddress(fmt.Sprintf("Invalid from address, expected address length is %d, actual length is %d", sdk.AddrLen, len(msg.From)))
+	}
+
+	if !types.IsValidMiniTokenSymbol(msg.Symbol) {
+		err := types.ValidateTokenSymbol(msg.Symbol)
+		if err != nil {
+			return sdk.ErrInvalidCoins(err.Error())
+		}
+	}
+	return nil
+}
+
+func (msg SetTransferMemoRequiredMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (msg SetTransferMemoRequiredMsg) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.From}
+}
+
+func (msg SetTransferMemoRequiredMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return msg.GetSigners()
+}
@@ -0,0 +1,51 @@
+package transfermemo
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/tokens/store"
+)
+
+func RegisterTransferMemoCheckScript(tokenMapper store.Mapper) {
+	msgType := bank.MsgSend{}.Type()
+	sdk.RegisterScripts(msgType, generateTransferMemoCheckScript(tokenMapper))
+}
+
+// generate script for checking the memo of the transfers of the tokens requiring one
+func generateTransferMemoCheckScript(tokenMapper store.Mapper) sdk.Script {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Error {
+		if !sdk.IsUpgrade(upgrade.TokenTransferMemo) {
+			return nil
+		}
+
+		sendMsg, ok := msg.(bank.MsgSend)
+		if !ok {
+			return nil
+		}
+
+		tx := ctx.Tx()
+		if tx == nil {
+			return sdk.ErrInternal("missing Tx in context")
+		}
+		stdTx, ok := tx.(auth.StdTx)
+		if !ok {
+			return sdk.ErrInternal("tx must be StdTx")
+		}
+		if len(stdTx.Memo) != 0 {
+			return nil
+		}
+		for _, in := range sendMsg.Inputs {
+			for _, coin := range in.Coins {
+				if tokenMapper.IsTransferMemoRequired(ctx, coin.Denom) {
+					return sdk.ErrInvalidTxMemo(fmt.Sprintf("token %s requires non-empty memo in transfer transaction", coin.Denom))
+				}
+			}
+		}
+		return nil
+	}
+}
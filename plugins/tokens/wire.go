@@ -6,8 +6,11 @@ import (
 	"github.com/bnb-chain/node/plugins/tokens/issue"
 	"github.com/bnb-chain/node/plugins/tokens/ownership"
 	"github.com/bnb-chain/node/plugins/tokens/seturi"
+	"github.com/bnb-chain/node/plugins/tokens/store"
 	"github.com/bnb-chain/node/plugins/tokens/swap"
 	"github.com/bnb-chain/node/plugins/tokens/timelock"
+	"github.com/bnb-chain/node/plugins/tokens/transferfee"
+	"github.com/bnb-chain/node/plugins/tokens/transfermemo"
 	"github.com/bnb-chain/node/wire"
 )
 
@@ -29,4 +32,7 @@ func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(issue.IssueTinyMsg{}, "tokens/IssueTinyMsg", nil)
 	cdc.RegisterConcrete(seturi.SetURIMsg{}, "tokens/SetURIMsg", nil)
 	cdc.RegisterConcrete(ownership.TransferOwnershipMsg{}, "tokens/TransferOwnershipMsg", nil)
+	cdc.RegisterConcrete(transfermemo.SetTransferMemoRequiredMsg{}, "tokens/SetTransferMemoRequiredMsg", nil)
+	cdc.RegisterConcrete(transferfee.SetTransferFeeMsg{}, "tokens/SetTransferFeeMsg", nil)
+	cdc.RegisterConcrete(&store.TokenParams{}, "tokens/TokenParams", nil)
 }